@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/protohuf/unhexed/internal/diff"
+)
+
+// runDiff implements "unhexed diff [flags] OLD NEW", the command-line
+// companion of the compare view: it prints the ranges that differ with
+// the same engine and exits 1 when there are any, 0 when the files are
+// equal and 2 on errors, as diff(1) does.
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s diff [flags] OLD NEW\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	rows := flags.Int("context", 0, "show `rows` of 16 bytes around each difference")
+	summary := flags.Bool("summary", false, "only print the number of differing ranges")
+	colors := flags.String("color", "auto", "color the output: auto, always or never")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || *rows < 0 {
+		flags.Usage()
+		return 2
+	}
+
+	opts := diff.PrintOptions{Context: *rows}
+	switch *colors {
+	case "auto":
+		opts.Color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	case "always":
+		opts.Color = true
+	case "never":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --color value %q\n", *colors)
+		return 2
+	}
+
+	ranges, err := diffFiles(flags.Arg(0), flags.Arg(1), func(a, b diff.Source, ranges []diff.Range) error {
+		if *summary {
+			_, err := fmt.Printf("%d differing ranges, %d bytes\n", len(ranges), diff.Bytes(ranges))
+			return err
+		}
+		return diff.Print(os.Stdout, a, b, ranges, opts)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if ranges > 0 {
+		return 1
+	}
+	return 0
+}
+
+// diffFiles compares the files at oldPath and newPath, streaming both,
+// and passes the differences to report. It returns how many there were.
+func diffFiles(oldPath, newPath string, report func(a, b diff.Source, ranges []diff.Range) error) (int, error) {
+	a, closeA, err := openSource(oldPath)
+	if err != nil {
+		return 0, err
+	}
+	defer closeA()
+	b, closeB, err := openSource(newPath)
+	if err != nil {
+		return 0, err
+	}
+	defer closeB()
+
+	ranges, err := diff.Compare(context.Background(), a, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(ranges), report(a, b, ranges)
+}
+
+func openSource(path string) (diff.Source, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Seeking works for devices too, whose Stat size is 0
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return io.NewSectionReader(f, 0, size), f.Close, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,203 @@
+// Package bitfield describes named bit ranges within a u8, u16 or u32,
+// loaded from TOML templates such as:
+//
+//	[[bitfield]]
+//	name = "flags"
+//	size = 1
+//	fields = "bit 7: compressed, bits 4-6: version, bits 0-3: type"
+//
+// Fields listed in pointers hold a file offset, and points_to names the
+// template that describes what they point at:
+//
+//	[[bitfield]]
+//	name = "chunk"
+//	size = 4
+//	fields = "bits 24-31: tag, bits 0-23: next"
+//	pointers = "next"
+//	points_to = "chunk"
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A Field is bits Lo..Hi (inclusive, 0 the least significant) of a value.
+type Field struct {
+	Name    string
+	Lo, Hi  int
+	Pointer bool // the field holds a file offset
+}
+
+type Template struct {
+	Name     string
+	Size     int // bytes: 1, 2 or 4
+	Fields   []Field
+	PointsTo string // the template for what pointer fields point at, if any
+}
+
+func (f Field) mask() uint64 {
+	return (1<<(f.Hi-f.Lo+1) - 1) << f.Lo
+}
+
+// Max returns the largest value the field holds.
+func (f Field) Max() uint64 {
+	return f.mask() >> f.Lo
+}
+
+// Get extracts the field from v.
+func (f Field) Get(v uint64) uint64 {
+	return v & f.mask() >> f.Lo
+}
+
+// Set returns v with the field set to x, which must fit.
+func (f Field) Set(v, x uint64) (uint64, error) {
+	if x > f.Max() {
+		return v, fmt.Errorf("%s holds 0 to %d", f.Name, f.Max())
+	}
+	return v&^f.mask() | x<<f.Lo, nil
+}
+
+// Bits describes the field's bits, e.g. "bits 4-6".
+func (f Field) Bits() string {
+	if f.Lo == f.Hi {
+		return fmt.Sprintf("bit %d", f.Lo)
+	}
+	return fmt.Sprintf("bits %d-%d", f.Lo, f.Hi)
+}
+
+// Parse builds a template from a field list such as "bit 7: compressed,
+// bits 4-6: version". Fields must fit in size bytes and not overlap.
+func Parse(name string, size int, fields string) (*Template, error) {
+	if size != 1 && size != 2 && size != 4 {
+		return nil, fmt.Errorf("size must be 1, 2 or 4 bytes, not %d", size)
+	}
+	t := &Template{Name: name, Size: size}
+	for _, spec := range strings.Split(fields, ",") {
+		f, err := parseField(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		if f.Hi >= size*8 {
+			return nil, fmt.Errorf("%s: %s is outside a %d-bit value", f.Name, f.Bits(), size*8)
+		}
+		for _, other := range t.Fields {
+			if other.mask()&f.mask() != 0 {
+				return nil, fmt.Errorf("%s: %s overlap %s (%s)", f.Name, f.Bits(), other.Name, other.Bits())
+			}
+			if other.Name == f.Name {
+				return nil, fmt.Errorf("field %s is defined twice", f.Name)
+			}
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	return t, nil
+}
+
+// parseField parses "bits LO-HI: name" or "bit N: name"; the word bit(s)
+// is optional.
+func parseField(spec string) (Field, error) {
+	bits, name, ok := strings.Cut(spec, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Field{}, fmt.Errorf("%q: expected BITS: NAME", spec)
+	}
+	bits = strings.TrimSpace(bits)
+	bits = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(bits, "bits"), "bit"))
+	bits = strings.ReplaceAll(bits, "–", "-")
+
+	a, b, isRange := strings.Cut(bits, "-")
+	lo, err := strconv.Atoi(strings.TrimSpace(a))
+	hi := lo
+	if err == nil && isRange {
+		hi, err = strconv.Atoi(strings.TrimSpace(b))
+	}
+	if err != nil || lo < 0 || hi < 0 {
+		return Field{}, fmt.Errorf("%s: invalid bits %q", name, bits)
+	}
+	// Ranges may be written high to low, as in datasheets
+	return Field{Name: name, Lo: min(lo, hi), Hi: max(lo, hi)}, nil
+}
+
+// SetPointers marks the fields named in the comma-separated list as
+// pointers.
+func (t *Template) SetPointers(names string) error {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		i := slices.IndexFunc(t.Fields, func(f Field) bool { return f.Name == name })
+		if i < 0 {
+			return fmt.Errorf("pointer %s is not a field", name)
+		}
+		t.Fields[i].Pointer = true
+	}
+	return nil
+}
+
+type file struct {
+	Bitfields []struct {
+		Name     string `toml:"name"`
+		Size     int    `toml:"size"`
+		Fields   string `toml:"fields"`
+		Pointers string `toml:"pointers"`
+		PointsTo string `toml:"points_to"`
+	} `toml:"bitfield"`
+}
+
+// Load reads the templates of every .toml file in dir. Templates that fail
+// to parse are left out and reported in the returned error, naming their
+// file; a missing dir has no templates.
+func Load(dir string) ([]*Template, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var templates []*Template
+	var errs []error
+	for _, path := range paths {
+		t, err := LoadFile(path)
+		templates = append(templates, t...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return templates, errors.Join(errs...)
+}
+
+// LoadFile reads the templates of one file, in the order they are written.
+// Like Load, it leaves out templates that fail to parse and reports them.
+func LoadFile(path string) ([]*Template, error) {
+	var f file
+	if _, err := toml.DecodeFile(path, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	var templates []*Template
+	var errs []error
+	for _, b := range f.Bitfields {
+		t, err := Parse(b.Name, b.Size, b.Fields)
+		if err == nil && b.Name == "" {
+			err = errors.New("missing name")
+		}
+		if err == nil {
+			err = t.SetPointers(b.Pointers)
+			t.PointsTo = b.PointsTo
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: bitfield %q: %w", filepath.Base(path), b.Name, err))
+			continue
+		}
+		templates = append(templates, t)
+	}
+	return templates, errors.Join(errs...)
+}
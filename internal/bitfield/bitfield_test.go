@@ -0,0 +1,115 @@
+package bitfield
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAndAccess(t *testing.T) {
+	tmpl, err := Parse("flags", 1, "bit 7: compressed, bits 4–6: version, bits 3-0: type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{{"compressed", 7, 7, false}, {"version", 4, 6, false}, {"type", 0, 3, false}}
+	for i, f := range tmpl.Fields {
+		if f != want[i] {
+			t.Errorf("field %d: got %+v, want %+v", i, f, want[i])
+		}
+	}
+
+	v := uint64(0b1_011_0010)
+	if got := tmpl.Fields[1].Get(v); got != 3 {
+		t.Errorf("version = %d, want 3", got)
+	}
+	v, err = tmpl.Fields[1].Set(v, 5)
+	if err != nil || v != 0b1_101_0010 {
+		t.Errorf("setting version gave %08b, %v", v, err)
+	}
+	if _, err := tmpl.Fields[1].Set(v, 8); err == nil {
+		t.Error("8 should not fit in 3 bits")
+	}
+}
+
+func TestParseRejectsBadFields(t *testing.T) {
+	tests := []struct {
+		size   int
+		fields string
+		want   string
+	}{
+		{1, "bits 4-6: a, bit 5: b", "overlap"},
+		{1, "bit 8: a", "outside"},
+		{2, "bits 0-3: a, bits 4-7: a", "twice"},
+		{1, "bits x: a", "invalid bits"},
+		{1, "bit 1", "expected"},
+		{3, "bit 1: a", "size"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse("t", tt.size, tt.fields); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: got %v, want an error mentioning %q", tt.fields, err, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+[[bitfield]]
+name = "flags"
+size = 1
+fields = "bit 7: compressed, bits 0-6: type"
+
+[[bitfield]]
+name = "broken"
+size = 2
+fields = "bits 0-8: a, bits 8-15: b"
+`
+	if err := os.WriteFile(filepath.Join(dir, "fs.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := Load(dir)
+	if len(templates) != 1 || templates[0].Name != "flags" {
+		t.Errorf("expected only the valid template, got %+v", templates)
+	}
+	if err == nil || !strings.Contains(err.Error(), `fs.toml: bitfield "broken"`) {
+		t.Errorf("expected an error naming the broken template, got %v", err)
+	}
+
+	if templates, err := Load(filepath.Join(dir, "missing")); len(templates) != 0 || err != nil {
+		t.Errorf("a missing directory should have no templates, got %v, %v", templates, err)
+	}
+}
+
+func TestLoadPointers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chunks.toml")
+	content := `
+[[bitfield]]
+name = "chunk"
+size = 4
+fields = "bits 24-31: tag, bits 0-23: next"
+pointers = "next"
+points_to = "chunk"
+
+[[bitfield]]
+name = "typo"
+size = 1
+fields = "bits 0-7: offset"
+pointers = "ofset"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadFile(path)
+	if len(templates) != 1 {
+		t.Fatalf("expected only the valid template, got %+v", templates)
+	}
+	chunk := templates[0]
+	if chunk.Fields[0].Pointer || !chunk.Fields[1].Pointer || chunk.PointsTo != "chunk" {
+		t.Errorf("got %+v", chunk)
+	}
+	if err == nil || !strings.Contains(err.Error(), "pointer ofset is not a field") {
+		t.Errorf("got %v", err)
+	}
+}
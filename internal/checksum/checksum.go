@@ -0,0 +1,124 @@
+// Package checksum implements the checksums commonly stored inside binary
+// formats: CRC32, the usual CRC16 variants and plain additive sums.
+package checksum
+
+import "hash/crc32"
+
+// A Summer accumulates data written to it.
+type Summer interface {
+	Write(p []byte)
+	Sum() uint64
+}
+
+type Algorithm struct {
+	Name  string
+	Width int // bytes
+	New   func() Summer
+}
+
+var Algorithms = []Algorithm{
+	{"CRC32", 4, func() Summer { return &crc32Summer{} }},
+	{"CRC16/CCITT-FALSE", 2, crc16(0x1021, 0xFFFF, false)},
+	{"CRC16/XMODEM", 2, crc16(0x1021, 0x0000, false)},
+	{"CRC16/ARC", 2, crc16(0x8005, 0x0000, true)},
+	{"CRC16/MODBUS", 2, crc16(0x8005, 0xFFFF, true)},
+	{"SUM8", 1, sum(1)},
+	{"SUM16", 2, sum(2)},
+	{"SUM32", 4, sum(4)},
+}
+
+// Lookup returns the algorithm called name.
+func Lookup(name string) (Algorithm, bool) {
+	for _, a := range Algorithms {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+type crc32Summer struct {
+	crc uint32
+}
+
+func (s *crc32Summer) Write(p []byte) { s.crc = crc32.Update(s.crc, crc32.IEEETable, p) }
+func (s *crc32Summer) Sum() uint64    { return uint64(s.crc) }
+
+type crc16Summer struct {
+	table     *[256]uint16
+	crc       uint16
+	reflected bool
+}
+
+// crc16 builds a CRC-16 with no final XOR. Reflected variants process bits
+// LSB first, using the reversed polynomial.
+func crc16(poly, init uint16, reflected bool) func() Summer {
+	var table [256]uint16
+	for i := range table {
+		if reflected {
+			rev := reverse16(poly)
+			crc := uint16(i)
+			for range 8 {
+				if crc&1 != 0 {
+					crc = crc>>1 ^ rev
+				} else {
+					crc >>= 1
+				}
+			}
+			table[i] = crc
+		} else {
+			crc := uint16(i) << 8
+			for range 8 {
+				if crc&0x8000 != 0 {
+					crc = crc<<1 ^ poly
+				} else {
+					crc <<= 1
+				}
+			}
+			table[i] = crc
+		}
+	}
+	return func() Summer {
+		return &crc16Summer{table: &table, crc: init, reflected: reflected}
+	}
+}
+
+func reverse16(v uint16) uint16 {
+	var r uint16
+	for range 16 {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+	return r
+}
+
+func (s *crc16Summer) Write(p []byte) {
+	for _, b := range p {
+		if s.reflected {
+			s.crc = s.crc>>8 ^ s.table[byte(s.crc)^b]
+		} else {
+			s.crc = s.crc<<8 ^ s.table[byte(s.crc>>8)^b]
+		}
+	}
+}
+
+func (s *crc16Summer) Sum() uint64 { return uint64(s.crc) }
+
+type sumSummer struct {
+	sum  uint64
+	mask uint64
+}
+
+func sum(width int) func() Summer {
+	mask := uint64(1)<<(8*width) - 1
+	return func() Summer { return &sumSummer{mask: mask} }
+}
+
+func (s *sumSummer) Write(p []byte) {
+	for _, b := range p {
+		s.sum += uint64(b)
+	}
+	s.sum &= s.mask
+}
+
+func (s *sumSummer) Sum() uint64 { return s.sum }
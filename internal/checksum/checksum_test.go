@@ -0,0 +1,29 @@
+package checksum
+
+import "testing"
+
+func TestCheckValues(t *testing.T) {
+	// The standard "123456789" check values from the CRC catalogue
+	want := map[string]uint64{
+		"CRC32":             0xCBF43926,
+		"CRC16/CCITT-FALSE": 0x29B1,
+		"CRC16/XMODEM":      0x31C3,
+		"CRC16/ARC":         0xBB3D,
+		"CRC16/MODBUS":      0x4B37,
+		"SUM8":              0xDD,
+		"SUM16":             0x01DD,
+		"SUM32":             0x01DD,
+	}
+	for _, a := range Algorithms {
+		s := a.New()
+		// Written in two parts to check that state carries over
+		s.Write([]byte("1234"))
+		s.Write([]byte("56789"))
+		if got := s.Sum(); got != want[a.Name] {
+			t.Errorf("%s = %X, want %X", a.Name, got, want[a.Name])
+		}
+	}
+	if _, ok := Lookup("CRC16/ARC"); !ok {
+		t.Error("expected to find CRC16/ARC")
+	}
+}
@@ -26,10 +26,84 @@ type Theme struct {
 	Bit32Background         string `toml:"bit32_background"`
 	Bit64Background         string `toml:"bit64_background"`
 	Bit128Background        string `toml:"bit128_background"`
+	SameByteBackground      string `toml:"same_byte_background"`
+	CrosshairBackground     string `toml:"crosshair_background"`
+	ControlColor            string `toml:"control_color"`
+	LockedBackground        string `toml:"locked_background"`
+	FlashBackground         string `toml:"flash_background"`
+}
+
+type Behavior struct {
+	// LargeEditThreshold is the number of bytes a paste or fill may modify
+	// before asking for confirmation. Zero or less disables the prompt.
+	LargeEditThreshold int64 `toml:"large_edit_threshold"`
+	// SetTitle shows the active file in the terminal window title.
+	SetTitle bool `toml:"set_title"`
+	// Crosshair softly highlights the cursor's whole row.
+	Crosshair bool `toml:"crosshair"`
+	// TextRegionMin is how many printable bytes in a row count as a text
+	// region for ( and ) navigation.
+	TextRegionMin int `toml:"text_region_min"`
+	// ScrollOff is how many rows of context to keep visible above and
+	// below the cursor when scrolling.
+	ScrollOff int `toml:"scroll_off"`
+	// BaseAddress sets how offsets in a window of a file are shown:
+	// "file" for absolute positions in the file, "window" for positions
+	// from the window's start.
+	BaseAddress string `toml:"base_address"`
+	// SectorSize is the unit of Goto's sN form and of the sector shown for
+	// devices.
+	SectorSize int64 `toml:"sector_size"`
+	// WrapSearch continues a search from the other end of the file when
+	// there are no more matches in its direction.
+	WrapSearch bool `toml:"wrap_search"`
+	// AutosaveSeconds saves modified files this often; zero or less never
+	// does.
+	AutosaveSeconds int `toml:"autosave_seconds"`
+	// ByteReadout shows the byte under the cursor in hex, decimal, text,
+	// binary and octal on the status line.
+	ByteReadout bool `toml:"byte_readout"`
+	// ConfirmSizeChange asks before saving a file whose size changed since
+	// it was opened or last saved.
+	ConfirmSizeChange bool `toml:"confirm_size_change"`
+	// TextSubstitute stands in the text column for bytes with no glyph. It
+	// must be one character, one cell wide.
+	TextSubstitute string `toml:"text_substitute"`
+	// ControlPictures shows control characters, DEL and 0xFF in the text
+	// column as their own glyphs, such as ␀ and ␊, instead of the
+	// substitute.
+	ControlPictures bool `toml:"control_pictures"`
+	// VisualBell flashes the status line when a key can't do anything,
+	// such as undo with nothing to undo.
+	VisualBell bool `toml:"visual_bell"`
+}
+
+// Decoder turns on optional rows of the decoder panel, each taking a row
+// from the hex view.
+type Decoder struct {
+	// Time2000 shows the big-endian u32 at the cursor as seconds since
+	// 2000-01-01 UTC, as several RTOS and logging formats store times.
+	Time2000 bool `toml:"time2000"`
+	// PascalStrings shows the strings at the cursor with a 1-byte and a
+	// big-endian 2-byte length prefix.
+	PascalStrings bool `toml:"pascal_strings"`
+}
+
+// Keys binds editor commands to keys, named as Bubble Tea names them
+// ("u", "ctrl+z"). Letters match either case.
+type Keys struct {
+	Undo    []string `toml:"undo"`
+	Redo    []string `toml:"redo"`
+	Suspend []string `toml:"suspend"`
 }
 
 type Config struct {
-	Theme Theme `toml:"theme"`
+	Theme    Theme    `toml:"theme"`
+	Behavior Behavior `toml:"behavior"`
+	Decoder  Decoder  `toml:"decoder"`
+	Keys     Keys     `toml:"keys"`
+	// Themes are the imported themes, by name (see theme.go).
+	Themes map[string]Theme `toml:"themes,omitempty"`
 }
 
 func DefaultConfig() *Config {
@@ -52,6 +126,26 @@ func DefaultConfig() *Config {
 			Bit32Background:         "#440044",
 			Bit64Background:         "#004444",
 			Bit128Background:        "#444400",
+			SameByteBackground:      "#303030",
+			CrosshairBackground:     "#1A1A2A",
+			ControlColor:            "#5F87AF",
+			LockedBackground:        "#2A2020",
+			FlashBackground:         "#5FAFAF",
+		},
+		Behavior: Behavior{
+			LargeEditThreshold: 1 << 20,
+			SetTitle:           true,
+			TextRegionMin:      8,
+			ScrollOff:          3,
+			BaseAddress:        "file",
+			SectorSize:         512,
+			WrapSearch:         true,
+			TextSubstitute:     ".",
+		},
+		Keys: Keys{
+			Undo:    []string{"u", "ctrl+z"},
+			Redo:    []string{"d", "ctrl+y"},
+			Suspend: []string{"ctrl+_"},
 		},
 	}
 }
@@ -64,6 +158,12 @@ func ConfigPath() string {
 	return filepath.Join(home, ".config", "unhexed", "unhexed.toml")
 }
 
+// TemplateDir returns the directory holding template files, such as the
+// bitfield templates.
+func TemplateDir() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "templates")
+}
+
 func Load() (*Config, error) {
 	cfg := DefaultConfig()
 	path := ConfigPath()
@@ -75,6 +175,11 @@ func Load() (*Config, error) {
 	if _, err := toml.DecodeFile(path, cfg); err != nil {
 		return cfg, err
 	}
+	cfg.Theme.fillDefaults()
+	for name, t := range cfg.Themes {
+		t.fillDefaults()
+		cfg.Themes[name] = t
+	}
 
 	return cfg, nil
 }
@@ -101,6 +206,7 @@ type Styles struct {
 	MarkerNormal    lipgloss.Style
 	MarkerInsert    lipgloss.Style
 	MarkerReplace   lipgloss.Style
+	MarkerHollow    lipgloss.Style // the cursor in the pane without focus
 	IndexMarker     lipgloss.Style
 	Legend          lipgloss.Style
 	LegendHighlight lipgloss.Style
@@ -121,6 +227,14 @@ type Styles struct {
 	Bit32           lipgloss.Style
 	Bit64           lipgloss.Style
 	Bit128          lipgloss.Style
+	SameByte        lipgloss.Style
+	Crosshair       lipgloss.Style
+	Flash           lipgloss.Style
+	Control         lipgloss.Style // text-column stand-ins for bytes with no glyph
+	Locked          lipgloss.Style // bytes in a locked range
+	StatusInfo      lipgloss.Style
+	StatusWarning   lipgloss.Style
+	StatusError     lipgloss.Style
 }
 
 func NewStyles(theme *Theme) *Styles {
@@ -136,6 +250,9 @@ func NewStyles(theme *Theme) *Styles {
 		MarkerReplace: lipgloss.NewStyle().
 			Background(lipgloss.Color(theme.MarkerReplaceBackground)).
 			Foreground(lipgloss.Color("#000000")),
+		MarkerHollow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MarkerBackground)).
+			Underline(true),
 		IndexMarker: lipgloss.NewStyle().
 			Background(lipgloss.Color(theme.IndexMarkerBackground)).
 			Foreground(lipgloss.Color("#FFFFFF")),
@@ -187,5 +304,65 @@ func NewStyles(theme *Theme) *Styles {
 		Bit128: lipgloss.NewStyle().
 			Background(lipgloss.Color(theme.Bit128Background)).
 			Foreground(lipgloss.Color("#FFFFFF")),
+		SameByte: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.SameByteBackground)),
+		Crosshair: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.CrosshairBackground)),
+		Flash: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.FlashBackground)).
+			Foreground(lipgloss.Color("#000000")),
+		Control: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.ControlColor)),
+		Locked: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.LockedBackground)).
+			Underline(true),
+		StatusInfo: lipgloss.NewStyle(),
+		StatusWarning: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFAA00")),
+		StatusError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF5555")).
+			Bold(true),
+	}
+}
+
+// NewMonochromeStyles builds styles that use no colors at all, for NO_COLOR
+// and terminals without color support. Markers rely on reverse video and
+// text attributes so the cursor and selection stay distinguishable.
+func NewMonochromeStyles() *Styles {
+	plain := lipgloss.NewStyle()
+	return &Styles{
+		Background:      plain,
+		MarkerNormal:    plain.Reverse(true),
+		MarkerInsert:    plain.Reverse(true).Bold(true),
+		MarkerReplace:   plain.Reverse(true).Underline(true),
+		MarkerHollow:    plain.Reverse(true).Faint(true),
+		IndexMarker:     plain.Bold(true),
+		Legend:          plain.Reverse(true),
+		LegendHighlight: plain.Reverse(true).Bold(true).Underline(true),
+		Border:          plain,
+		Endian:          plain.Faint(true),
+		ActiveTab:       plain.Bold(true).Underline(true),
+		InactiveTab:     plain,
+		Selection:       plain.Underline(true),
+		UnsavedFile:     plain.Italic(true),
+		Disabled:        plain.Faint(true),
+		Normal:          plain,
+		DecoderLabel:    plain.Faint(true),
+		DecoderValue:    plain,
+		HelpTitle:       plain.Bold(true),
+		HelpKey:         plain.Bold(true),
+		HelpDesc:        plain,
+		Bit16:           plain,
+		Bit32:           plain,
+		Bit64:           plain,
+		Bit128:          plain,
+		SameByte:        plain.Bold(true),
+		Crosshair:       plain,
+		Flash:           plain.Reverse(true).Italic(true),
+		Control:         plain.Faint(true),
+		Locked:          plain.Italic(true),
+		StatusInfo:      plain,
+		StatusWarning:   plain.Bold(true),
+		StatusError:     plain.Reverse(true).Bold(true),
 	}
 }
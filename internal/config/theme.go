@@ -0,0 +1,132 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Themes are shared as TOML files holding a [theme] table, the same one
+// the config file has. Importing one keeps it in the config under a name
+// and makes it the current theme.
+
+type themeFile struct {
+	Theme Theme `toml:"theme"`
+}
+
+// fillDefaults sets the colors left empty to the default theme's, so a
+// partial theme never renders as invisible colors.
+func (t *Theme) fillDefaults() {
+	def := reflect.ValueOf(DefaultConfig().Theme)
+	v := reflect.ValueOf(t).Elem()
+	for i := range v.NumField() {
+		if f := v.Field(i); f.String() == "" {
+			f.SetString(def.Field(i).String())
+		}
+	}
+}
+
+// LoadTheme reads a theme file. Colors it leaves out are the defaults.
+func LoadTheme(path string) (Theme, error) {
+	f := themeFile{Theme: DefaultConfig().Theme}
+	md, err := toml.DecodeFile(path, &f)
+	if err != nil {
+		return Theme{}, err
+	}
+	if !md.IsDefined("theme") {
+		return Theme{}, fmt.Errorf("%s has no [theme] table", path)
+	}
+	f.Theme.fillDefaults()
+	return f.Theme, nil
+}
+
+// ExportTheme writes t as a theme file LoadTheme reads back unchanged.
+func ExportTheme(t Theme, path string) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(themeFile{Theme: t}); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ImportTheme keeps t under name and makes it the current theme.
+func (c *Config) ImportTheme(name string, t Theme) {
+	if c.Themes == nil {
+		c.Themes = make(map[string]Theme)
+	}
+	c.Themes[name] = t
+	c.Theme = t
+}
+
+// keyComments document the settings in the default config file.
+var keyComments = map[string]string{
+	"theme":                "Colors, as #RRGGBB. Missing colors use these defaults.",
+	"behavior":             "Editor behavior.",
+	"large_edit_threshold": "Bytes a paste or fill may change before asking to confirm; 0 never asks.",
+	"set_title":            "Show the active file in the terminal window title.",
+	"crosshair":            "Softly highlight the cursor's whole row.",
+	"text_region_min":      "Printable bytes in a row that count as a text region for ( and ).",
+	"scroll_off":           "Rows of context kept above and below the cursor.",
+	"base_address":         "Offsets in a window of a file: \"file\" (absolute) or \"window\".",
+	"sector_size":          "Bytes per sector, for Goto's sN form and device positions.",
+	"wrap_search":          "Continue a search from the other end of the file.",
+	"autosave_seconds":     "Save modified files this often, in seconds; 0 never does.",
+	"byte_readout":         "Show the byte under the cursor in every base on the status line.",
+	"confirm_size_change":  "Ask before saving a file whose size changed, e.g. after typing in Insert mode.",
+	"text_substitute":      "Shown in the text column for bytes with no glyph; one character, one cell wide.",
+	"control_pictures":     "Show control characters, DEL and 0xFF in the text column as glyphs like ␀ and ␊.",
+	"visual_bell":          "Flash the status line when a key can't do anything, e.g. undo with nothing to undo.",
+	"decoder":              "Optional decoder panel rows, each taking a row from the hex view.",
+	"time2000":             "Show the big-endian u32 at the cursor as seconds since 2000-01-01 UTC.",
+	"pascal_strings":       "Show the strings at the cursor with a 1-byte or big-endian 2-byte length prefix.",
+	"keys":                 "Keys for commands, e.g. \"ctrl+z\"; letters match either case.",
+	"undo":                 "Undo the last edit.",
+	"redo":                 "Redo the last undone edit.",
+	"suspend":              "Suspend to the shell; fg resumes.",
+}
+
+// DefaultTOML returns the default configuration as a commented config
+// file.
+func DefaultTOML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(DefaultConfig()); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("# unhexed configuration, normally " + ConfigPath() + "\n")
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		line := scanner.Text()
+		key := strings.TrimSpace(line)
+		if key == "" {
+			continue
+		}
+		if strings.HasPrefix(key, "[") {
+			key = strings.Trim(key, "[]")
+			out.WriteString("\n")
+		} else {
+			key, _, _ = strings.Cut(key, " =")
+		}
+		if comment, ok := keyComments[key]; ok {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			out.WriteString(indent + "# " + comment + "\n")
+		}
+		out.WriteString(line + "\n")
+	}
+	return out.Bytes(), scanner.Err()
+}
+
+// WriteDefault writes DefaultTOML to path.
+func WriteDefault(path string) error {
+	data, err := DefaultTOML()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestThemeExportImportRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.toml")
+	theme := DefaultConfig().Theme
+	theme.Background = "#101010"
+	theme.CrosshairBackground = "#202020"
+	if err := ExportTheme(theme, path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadTheme(path)
+	if err != nil || got != theme {
+		t.Errorf("round trip gave %+v, %v", got, err)
+	}
+}
+
+func TestPartialThemeInheritsDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "partial.toml")
+	content := "[theme]\nbackground = \"#123456\"\nborder_color = \"\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadTheme(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultConfig().Theme
+	want.Background = "#123456"
+	if got != want {
+		t.Errorf("got %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("[behavior]\nscroll_off = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTheme(path); err == nil {
+		t.Error("a file without a [theme] table is not a theme")
+	}
+}
+
+func TestFlashBackgroundIsThemed(t *testing.T) {
+	theme := DefaultConfig().Theme
+	if got := NewStyles(&theme).Flash.GetBackground(); got != lipgloss.Color("#5FAFAF") {
+		t.Errorf("expected the default flash background, got %v", got)
+	}
+	theme.FlashBackground = "#123456"
+	if got := NewStyles(&theme).Flash.GetBackground(); got != lipgloss.Color("#123456") {
+		t.Errorf("expected the theme's flash background, got %v", got)
+	}
+}
+
+func TestImportedThemesAreSaved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := DefaultConfig()
+	theme := DefaultConfig().Theme
+	theme.ActiveTab = "#ABCDEF"
+	cfg.ImportTheme("shared", theme)
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Theme != theme || loaded.Themes["shared"] != theme {
+		t.Errorf("imported theme not kept: %+v", loaded.Themes)
+	}
+}
+
+func TestDefaultTOML(t *testing.T) {
+	data, err := DefaultTOML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&cfg, DefaultConfig()) {
+		t.Errorf("the default file should decode to the defaults, got %+v", cfg)
+	}
+
+	for _, section := range []reflect.Type{reflect.TypeOf(Behavior{}), reflect.TypeOf(Decoder{})} {
+		for i := range section.NumField() {
+			key := section.Field(i).Tag.Get("toml")
+			if !strings.Contains(string(data), "# "+keyComments[key]+"\n  "+key+" =") || keyComments[key] == "" {
+				t.Errorf("%s should be documented", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package diff compares two byte sequences and reports the ranges that
+// differ. It works on anything that can be read at an offset, so one side
+// can be an in-memory buffer and the other a file streamed from disk.
+//
+// The comparison walks both sides in step. At a mismatch it looks ahead a
+// bounded window for the nearest point where the two line up again, which
+// classifies the difference as bytes changed in place, inserted into the
+// new side or deleted from the old one. Differences it can't resynchronize
+// within the window are reported as changed and the walk continues.
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+type Source interface {
+	Size() int64
+	ReadAt(p []byte, off int64) (int, error)
+}
+
+type Kind int
+
+const (
+	Changed Kind = iota
+	Inserted
+	Deleted
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Inserted:
+		return "inserted"
+	case Deleted:
+		return "deleted"
+	default:
+		return "changed"
+	}
+}
+
+// Range is one difference: ALen bytes at AOff in the old side became BLen
+// bytes at BOff in the new side.
+type Range struct {
+	Kind Kind
+	AOff int64
+	ALen int64
+	BOff int64
+	BLen int64
+}
+
+const (
+	blockSize = 64 * 1024
+	// resyncWindow is how far past a mismatch an insertion, deletion or
+	// changed run is looked for.
+	resyncWindow = 4096
+	// anchorLen bytes must match for the sides to count as lined up again.
+	anchorLen = 8
+)
+
+// Compare returns the differences from a (old) to b (new) in order.
+func Compare(ctx context.Context, a, b Source) ([]Range, error) {
+	var ranges []Range
+	add := func(r Range) {
+		if n := len(ranges); n > 0 {
+			last := &ranges[n-1]
+			if last.Kind == r.Kind && last.AOff+last.ALen == r.AOff && last.BOff+last.BLen == r.BOff {
+				last.ALen += r.ALen
+				last.BLen += r.BLen
+				return
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	na, nb := a.Size(), b.Size()
+	var i, j int64
+	for i < na && j < nb {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := min(blockSize, na-i, nb-j)
+		ab, err := read(a, i, n)
+		if err != nil {
+			return nil, err
+		}
+		bb, err := read(b, j, n)
+		if err != nil {
+			return nil, err
+		}
+		k := commonPrefix(ab, bb)
+		i += int64(k)
+		j += int64(k)
+		if k == len(ab) {
+			continue
+		}
+
+		r, err := resync(a, b, i, j)
+		if err != nil {
+			return nil, err
+		}
+		add(r)
+		i += r.ALen
+		j += r.BLen
+	}
+	if i < na {
+		add(Range{Kind: Deleted, AOff: i, ALen: na - i, BOff: j})
+	}
+	if j < nb {
+		add(Range{Kind: Inserted, AOff: i, BOff: j, BLen: nb - j})
+	}
+	return ranges, nil
+}
+
+// resync classifies the difference starting at a[i] != b[j] by the nearest
+// point where the sides line up again.
+func resync(a, b Source, i, j int64) (Range, error) {
+	wa, err := read(a, i, resyncWindow+anchorLen)
+	if err != nil {
+		return Range{}, err
+	}
+	wb, err := read(b, j, resyncWindow+anchorLen)
+	if err != nil {
+		return Range{}, err
+	}
+	aEOF := i+int64(len(wa)) == a.Size()
+	bEOF := j+int64(len(wb)) == b.Size()
+
+	// anchored reports whether wa from x and wb from y match for anchorLen
+	// bytes, or up to the end of both sides.
+	anchored := func(x, y int) bool {
+		m := min(anchorLen, len(wa)-x, len(wb)-y)
+		if m < 0 || !bytes.Equal(wa[x:x+m], wb[y:y+m]) {
+			return false
+		}
+		return m == anchorLen || (aEOF && bEOF && x+m == len(wa) && y+m == len(wb))
+	}
+
+	for s := 1; s <= resyncWindow; s++ {
+		switch {
+		case anchored(s, s):
+			return Range{Kind: Changed, AOff: i, ALen: int64(s), BOff: j, BLen: int64(s)}, nil
+		case anchored(s, 0):
+			return Range{Kind: Deleted, AOff: i, ALen: int64(s), BOff: j}, nil
+		case anchored(0, s):
+			return Range{Kind: Inserted, AOff: i, BOff: j, BLen: int64(s)}, nil
+		}
+	}
+	s := int64(min(resyncWindow, len(wa), len(wb)))
+	return Range{Kind: Changed, AOff: i, ALen: s, BOff: j, BLen: s}, nil
+}
+
+func read(src Source, off, n int64) ([]byte, error) {
+	n = max(min(n, src.Size()-off), 0)
+	p := make([]byte, n)
+	got, err := src.ReadAt(p, off)
+	if err != nil && !(errors.Is(err, io.EOF) && int64(got) == n) {
+		return nil, err
+	}
+	return p[:got], nil
+}
+
+func commonPrefix(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// Bytes returns the total number of differing bytes on the larger side of
+// each range.
+func Bytes(ranges []Range) int64 {
+	var n int64
+	for _, r := range ranges {
+		n += max(r.ALen, r.BLen)
+	}
+	return n
+}
+
+// Conflicts counts the ranges of ours that touch a range of theirs, where
+// both are differences from the same old side: edits made on two sides
+// that can't both be kept as they are. An insertion touches a range that
+// contains or starts at its offset.
+func Conflicts(ours, theirs []Range) int {
+	n, j := 0, 0
+	for _, r := range ours {
+		rEnd := r.AOff + max(r.ALen, 1)
+		for j < len(theirs) && theirs[j].AOff+max(theirs[j].ALen, 1) <= r.AOff {
+			j++
+		}
+		if j < len(theirs) && theirs[j].AOff < rEnd {
+			n++
+		}
+	}
+	return n
+}
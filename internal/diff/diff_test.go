@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+type memSource []byte
+
+func (m memSource) Size() int64 { return int64(len(m)) }
+
+func (m memSource) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, m[off:]), nil
+}
+
+func random(n int, seed int64) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return data
+}
+
+func splice(data []byte, off, remove int, insert []byte) []byte {
+	out := append([]byte{}, data[:off]...)
+	out = append(out, insert...)
+	return append(out, data[off+remove:]...)
+}
+
+func TestCompare(t *testing.T) {
+	base := random(200000, 1)
+
+	tests := []struct {
+		name string
+		a, b []byte
+		want []Range
+	}{
+		{"equal", base, base, nil},
+		{"changed", base, splice(base, 1000, 3, []byte{^base[1000], ^base[1001], ^base[1002]}),
+			[]Range{{Kind: Changed, AOff: 1000, ALen: 3, BOff: 1000, BLen: 3}}},
+		{"inserted", base, splice(base, 70000, 0, []byte("new bytes")),
+			[]Range{{Kind: Inserted, AOff: 70000, BOff: 70000, BLen: 9}}},
+		{"deleted", base, splice(base, 5, 100, nil),
+			[]Range{{Kind: Deleted, AOff: 5, ALen: 100, BOff: 5}}},
+		{"appended", base[:100], base[:150],
+			[]Range{{Kind: Inserted, AOff: 100, BOff: 100, BLen: 50}}},
+		{"truncated", base[:150], base[:100],
+			[]Range{{Kind: Deleted, AOff: 100, ALen: 50, BOff: 100}}},
+		{"last byte", []byte("AB"), []byte("AC"),
+			[]Range{{Kind: Changed, AOff: 1, ALen: 1, BOff: 1, BLen: 1}}},
+		{"empty", nil, []byte("xyz"),
+			[]Range{{Kind: Inserted, BLen: 3}}},
+	}
+	for _, tt := range tests {
+		got, err := Compare(context.Background(), memSource(tt.a), memSource(tt.b))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompareSeveralEdits(t *testing.T) {
+	a := random(100000, 2)
+	b := splice(a, 90000, 10, nil)
+	b = splice(b, 50000, 0, []byte("INSERTED"))
+	b[10] ^= 0x55
+
+	got, err := Compare(context.Background(), memSource(a), memSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Range{
+		{Kind: Changed, AOff: 10, ALen: 1, BOff: 10, BLen: 1},
+		{Kind: Inserted, AOff: 50000, BOff: 50000, BLen: 8},
+		{Kind: Deleted, AOff: 90000, ALen: 10, BOff: 90008},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if n := Bytes(got); n != 19 {
+		t.Errorf("expected 19 differing bytes, got %d", n)
+	}
+}
+
+func TestCompareUnrelated(t *testing.T) {
+	a, b := random(10000, 3), random(10000, 4)
+	got, err := Compare(context.Background(), memSource(a), memSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Whatever the classification, applying the ranges must cover both sides
+	var aLen, bLen int64
+	for _, r := range got {
+		aLen += r.ALen
+		bLen += r.BLen
+	}
+	if aLen > int64(len(a)) || bLen > int64(len(b)) || len(got) == 0 {
+		t.Errorf("implausible ranges %+v", got)
+	}
+	if bytes.Equal(a, b) {
+		t.Fatal("test data should differ")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	ours := []Range{
+		{Kind: Changed, AOff: 10, ALen: 4},
+		{Kind: Inserted, AOff: 100},
+		{Kind: Deleted, AOff: 500, ALen: 10},
+	}
+	theirs := []Range{
+		{Kind: Changed, AOff: 12, ALen: 1},   // inside the first
+		{Kind: Deleted, AOff: 100, ALen: 2},  // where the insertion is
+		{Kind: Changed, AOff: 510, ALen: 10}, // just past the last
+	}
+	if n := Conflicts(ours, theirs); n != 2 {
+		t.Errorf("expected 2 conflicts, got %d", n)
+	}
+	if n := Conflicts(ours, nil); n != 0 {
+		t.Errorf("expected no conflicts, got %d", n)
+	}
+}
@@ -0,0 +1,123 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintOptions control the textual diff Print writes.
+type PrintOptions struct {
+	Context int  // rows of 16 bytes shown before and after each range
+	Color   bool // ANSI colors for the headers and both sides
+}
+
+const (
+	// printBytes is how many bytes of each side a range lists.
+	printBytes = 32
+	rowBytes   = 16
+
+	ansiHeader = "\x1b[36m"
+	ansiOld    = "\x1b[31m"
+	ansiNew    = "\x1b[32m"
+	ansiReset  = "\x1b[0m"
+)
+
+// Print writes ranges, the differences from a to b, as text: a header
+// with the offset, kind and length, then the old bytes after "-" and the
+// new ones after "+", with the bytes around them in xxd style rows.
+func Print(w io.Writer, a, b Source, ranges []Range, opts PrintOptions) error {
+	bw := bufio.NewWriter(w)
+	color := func(code, s string) string {
+		if !opts.Color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	for i, r := range ranges {
+		if i > 0 && opts.Context > 0 {
+			bw.WriteString("\n")
+		}
+		header := fmt.Sprintf("0x%08X %s, %s", r.AOff, r.Kind, byteCount(max(r.ALen, r.BLen)))
+		if r.BOff != r.AOff {
+			header += fmt.Sprintf(" (0x%08X in the new file)", r.BOff)
+		}
+		bw.WriteString(color(ansiHeader, header) + "\n")
+
+		context := int64(opts.Context) * rowBytes
+		if err := printRows(bw, a, max(r.AOff-context, 0), r.AOff); err != nil {
+			return err
+		}
+		if r.ALen > 0 {
+			line, err := sideBytes(a, r.AOff, r.ALen)
+			if err != nil {
+				return err
+			}
+			bw.WriteString(color(ansiOld, "- "+line) + "\n")
+		}
+		if r.BLen > 0 {
+			line, err := sideBytes(b, r.BOff, r.BLen)
+			if err != nil {
+				return err
+			}
+			bw.WriteString(color(ansiNew, "+ "+line) + "\n")
+		}
+		end := r.BOff + r.BLen
+		if err := printRows(bw, b, end, end+context); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func byteCount(n int64) string {
+	if n == 1 {
+		return "1 byte"
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// sideBytes lists up to printBytes of the n bytes at off in hex.
+func sideBytes(src Source, off, n int64) (string, error) {
+	data, err := read(src, off, min(n, printBytes))
+	if err != nil {
+		return "", err
+	}
+	line := fmt.Sprintf("% X", data)
+	if n > int64(len(data)) {
+		line += fmt.Sprintf(" … (%d more)", n-int64(len(data)))
+	}
+	return line, nil
+}
+
+// printRows writes the bytes of src from start to end as xxd does: rows
+// of the offset, the bytes in pairs and the printable ones as text.
+func printRows(w *bufio.Writer, src Source, start, end int64) error {
+	for off := start; off < end && off < src.Size(); off += rowBytes {
+		data, err := read(src, off, min(rowBytes, end-off))
+		if err != nil {
+			return err
+		}
+		var hex, text strings.Builder
+		for i := range rowBytes {
+			if i < len(data) {
+				fmt.Fprintf(&hex, "%02x", data[i])
+			} else {
+				hex.WriteString("  ")
+			}
+			if i%2 == 1 {
+				hex.WriteByte(' ')
+			}
+		}
+		for _, c := range data {
+			if c < 0x20 || c > 0x7E {
+				c = '.'
+			}
+			text.WriteByte(c)
+		}
+		fmt.Fprintf(w, "  %08x: %s %s\n", off, hex.String(), text.String())
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	a := []byte("0123456789abcdefGHIJKLMNOPQRSTUVwxyz")
+	b := splice(a, 18, 2, []byte{0x00, 0xFF})
+	ranges, err := Compare(context.Background(), memSource(a), memSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Print(&out, memSource(a), memSource(b), ranges, PrintOptions{Context: 1}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"0x00000012 changed, 2 bytes",
+		"  00000002: 3233 3435 3637 3839 6162 6364 6566 4748  23456789abcdefGH",
+		"- 49 4A",
+		"+ 00 FF",
+		"  00000014: 4b4c 4d4e 4f50 5152 5354 5556 7778 797a  KLMNOPQRSTUVwxyz",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrintLongRange(t *testing.T) {
+	a := random(100, 2)
+	b := a[:10]
+	ranges, err := Compare(context.Background(), memSource(a), memSource(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Print(&out, memSource(a), memSource(b), ranges, PrintOptions{Color: true}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 3 || lines[0] != "\x1b[36m0x0000000A deleted, 90 bytes\x1b[0m" {
+		t.Fatalf("got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[1], "\x1b[31m- ") || !strings.HasSuffix(lines[1], " … (58 more)\x1b[0m") {
+		t.Errorf("got %q", lines[1])
+	}
+}
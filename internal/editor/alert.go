@@ -0,0 +1,78 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An edit that can't be made says so on the status line, rather than
+// looking like a swallowed key. With visual_bell the status line also
+// flashes in reverse video for a moment.
+
+const bellDuration = 200 * time.Millisecond
+
+type bellExpireMsg struct {
+	seq int
+}
+
+// alert warns that the key did nothing.
+func (m *Model) alert(text string) {
+	m.setStatus(sevWarning, text)
+	if m.config.Behavior.VisualBell {
+		m.bellSeq = m.statusSeq
+	}
+}
+
+// refused alerts when a buffer edit failed, saying what couldn't be done,
+// and reports whether it did.
+func (m *Model) refused(what string, err error) bool {
+	var locked *buffer.LockedError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &locked):
+		var base int64
+		if tab := m.currentTab(); tab != nil {
+			base = m.displayBase(tab)
+		}
+		m.alert(fmt.Sprintf("Cannot %s: 0x%X-0x%X is locked (Alt+K unlocks)", what, locked.Range.Start+base, locked.Range.End+base))
+	case errors.Is(err, buffer.ErrOutOfRange):
+		m.alert(fmt.Sprintf("Cannot %s past the end of the file", what))
+	case errors.Is(err, buffer.ErrReadOnly):
+		m.alert(fmt.Sprintf("Cannot %s: the file is read-only", what))
+	case errors.Is(err, buffer.ErrFixedSize):
+		m.alert(fmt.Sprintf("Cannot %s: the tab's size can't change", what))
+	default:
+		m.alert(fmt.Sprintf("Cannot %s: %v", what, err))
+	}
+	return true
+}
+
+// bellTimer returns the tick that ends the bell, once per bell.
+func (m *Model) bellTimer() tea.Cmd {
+	if m.bellSeq == 0 || m.bellSeq == m.bellTimed {
+		return nil
+	}
+	m.bellTimed = m.bellSeq
+	seq := m.bellSeq
+	return m.clock.Tick(bellDuration, func(time.Time) tea.Msg {
+		return bellExpireMsg{seq: seq}
+	})
+}
+
+func (m *Model) handleBellExpire(msg bellExpireMsg) (tea.Model, tea.Cmd) {
+	if msg.seq == m.bellSeq {
+		m.bellSeq = 0
+	}
+	return m, nil
+}
+
+// ringing reports whether the status line shows the bell.
+func (m *Model) ringing() bool {
+	return m.bellSeq != 0 && m.bellSeq == m.statusSeq
+}
@@ -0,0 +1,52 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRefusedEditsSayWhy(t *testing.T) {
+	tests := []struct {
+		name string
+		keys func(m *Model)
+		want string
+	}{
+		{"undo", func(m *Model) { typeKeys(m, "u") }, "Nothing to undo"},
+		{"redo", func(m *Model) { typeKeys(m, "d") }, "Nothing to redo"},
+		{"paste", func(m *Model) { press(m, tea.KeyCtrlV) }, `Nothing to paste: register "" is empty`},
+		{"backspace", func(m *Model) { press(m, tea.KeyBackspace) }, "Nothing to delete before the start of the file"},
+		{"delete", func(m *Model) {
+			m.currentTab().Buffer.Delete(0, 2)
+			press(m, tea.KeyDelete)
+		}, "Nothing to delete at the end of the file"},
+		{"fill", func(m *Model) {
+			m.currentTab().Buffer.Delete(0, 2)
+			m.fillSelection(0xFF)
+		}, "Cannot fill past the end of the file"},
+	}
+	for _, tt := range tests {
+		m, _ := startEditor(t, writeTestFile(t, "\x01\x02"))
+		tt.keys(m)
+		if m.status.sev != sevWarning || m.status.text != tt.want {
+			t.Errorf("%s: got %v %q", tt.name, m.status.sev, m.status.text)
+		}
+	}
+}
+
+func TestVisualBell(t *testing.T) {
+	m, clock := startEditor(t, writeTestFile(t, "abc"))
+	m.config.Behavior.VisualBell = true
+	Drive(m, keys("u")...)
+	if !m.ringing() {
+		t.Fatal("expected the bell")
+	}
+	if got := m.renderStatus(); !strings.Contains(got, "Nothing to undo") || len(got) < m.width {
+		t.Errorf("expected the whole status line flashed, got %q", got)
+	}
+	Drive(m, clock.advance(bellDuration)...)
+	if m.ringing() || m.status.text != "Nothing to undo" {
+		t.Errorf("the bell should stop and the message stay, got %q", m.status.text)
+	}
+}
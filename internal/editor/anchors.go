@@ -0,0 +1,70 @@
+package editor
+
+import "github.com/protohuf/unhexed/pkg/buffer"
+
+// Offsets the editor keeps into a buffer (selections, the cursors of other
+// views on a shared buffer) follow the data through insertions and
+// deletions made anywhere, including by undo and redo. Every tab listens
+// to its buffer and maps its offsets through each change with shiftOffset
+// below and Change.ShiftRange; anything else that remembers offsets should
+// use them too.
+
+// shiftOffset maps off through c. Offsets inside a deleted span collapse to
+// its start; a swap of the whole contents keeps them, within the new size.
+func shiftOffset(off int64, c buffer.Change) int64 {
+	if c.Swapped {
+		return min(off, c.Inserted)
+	}
+	if off < c.Offset {
+		return off
+	}
+	if off < c.Offset+c.Removed {
+		return c.Offset
+	}
+	return off - c.Removed + c.Inserted
+}
+
+// newTab creates a tab on buf that tracks changes made to it.
+func (m *Model) newTab(buf *buffer.Buffer) *Tab {
+	tab := &Tab{Buffer: buf}
+	buf.SetClock(m.clock.Now)
+	tab.unlisten = buf.Listen(func(c buffer.Change) {
+		m.trackChange(tab, c)
+	})
+	return tab
+}
+
+func (m *Model) trackChange(tab *Tab, c buffer.Change) {
+	if tab.Selection.Active {
+		start, end, ok := c.ShiftRange(tab.Selection.Start, tab.Selection.End)
+		if ok {
+			tab.Selection.Start, tab.Selection.End = start, end
+		} else {
+			tab.Selection.Active = false
+			tab.Selection.Block = false
+			tab.lastSel = lastSelection{set: true, deleted: true}
+		}
+	}
+	trackLastSelection(tab, c)
+	trackFolds(tab, c)
+	trackMarks(tab, c)
+	trackJumps(tab, c)
+	// The tab making the edit positions its own cursor
+	if tab != m.currentTab() {
+		tab.Cursor = shiftOffset(tab.Cursor, c)
+	}
+}
+
+// dropTab stops tab from tracking its buffer once it is closed or replaced,
+// before it is taken out of m.tabs, and closes the buffer if no other tab
+// views it.
+func (m *Model) dropTab(tab *Tab) {
+	if tab.unlisten != nil {
+		tab.unlisten()
+		tab.unlisten = nil
+	}
+	if m.viewCount(tab) == 1 {
+		delete(m.editViews, tab.Buffer)
+		tab.Buffer.Close()
+	}
+}
@@ -0,0 +1,125 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func ins(off, n int64) buffer.Change { return buffer.Change{Offset: off, Inserted: n} }
+func del(off, n int64) buffer.Change { return buffer.Change{Offset: off, Removed: n} }
+
+func TestShiftOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		off  int64
+		c    buffer.Change
+		want int64
+	}{
+		{"insert after", 5, ins(6, 3), 5},
+		{"insert at", 5, ins(5, 3), 8},
+		{"insert before", 5, ins(2, 3), 8},
+		{"delete after", 5, del(6, 2), 5},
+		{"delete covering", 5, del(4, 3), 4},
+		{"delete at", 5, del(5, 1), 5},
+		{"delete before", 5, del(1, 2), 3},
+	}
+	for _, tt := range tests {
+		if got := shiftOffset(tt.off, tt.c); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestShiftRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end int64
+		c          buffer.Change
+		wantStart  int64
+		wantEnd    int64
+		wantOK     bool
+	}{
+		{"insert before", 10, 20, ins(5, 4), 14, 24, true},
+		{"insert at start", 10, 20, ins(10, 4), 14, 24, true},
+		{"insert inside", 10, 20, ins(15, 4), 10, 24, true},
+		{"insert after end", 10, 20, ins(21, 4), 10, 20, true},
+		{"delete before", 10, 20, del(2, 5), 5, 15, true},
+		{"delete after", 10, 20, del(21, 5), 10, 20, true},
+		{"delete inside", 10, 20, del(12, 3), 10, 17, true},
+		{"delete overlapping start", 10, 20, del(8, 5), 8, 15, true},
+		{"delete overlapping end", 10, 20, del(18, 5), 10, 17, true},
+		{"delete everything", 10, 20, del(10, 11), 0, 0, false},
+		{"delete everything and more", 10, 20, del(5, 30), 0, 0, false},
+		{"backwards range keeps direction", 20, 10, del(2, 5), 15, 5, true},
+	}
+	for _, tt := range tests {
+		start, end, ok := tt.c.ShiftRange(tt.start, tt.end)
+		if ok != tt.wantOK || (ok && (start != tt.wantStart || end != tt.wantEnd)) {
+			t.Errorf("%s: expected [%d,%d] %v, got [%d,%d] %v", tt.name, tt.wantStart, tt.wantEnd, tt.wantOK, start, end, ok)
+		}
+	}
+}
+
+func TestSelectionFollowsUndo(t *testing.T) {
+	m := newTestModel([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+	tab := m.currentTab()
+
+	// Insert two bytes at the front, then select the original bytes 4..5
+	tab.Buffer.Insert(0, []byte{0xAA, 0xBB})
+	tab.Selection.Active = true
+	tab.Selection.Start, tab.Selection.End = 6, 7
+
+	typeKeys(m, "u")
+	if !tab.Selection.Active || tab.Selection.Start != 4 || tab.Selection.End != 5 {
+		t.Fatalf("expected selection [4,5] after undo, got %v [%d,%d]", tab.Selection.Active, tab.Selection.Start, tab.Selection.End)
+	}
+	if got := tab.Buffer.GetBytes(tab.Selection.Start, 2); got[0] != 4 || got[1] != 5 {
+		t.Errorf("selection now covers %v", got)
+	}
+
+	typeKeys(m, "d")
+	if tab.Selection.Start != 6 || tab.Selection.End != 7 {
+		t.Errorf("expected selection [6,7] after redo, got [%d,%d]", tab.Selection.Start, tab.Selection.End)
+	}
+}
+
+func TestDeletedSelectionIsCleared(t *testing.T) {
+	m := newTestModel(make([]byte, 16))
+	tab := m.currentTab()
+	tab.Selection.Active = true
+	tab.Selection.Start, tab.Selection.End = 4, 6
+
+	tab.Buffer.Delete(2, 8)
+	if tab.Selection.Active {
+		t.Errorf("selection inside a deleted span should be cleared, got [%d,%d]", tab.Selection.Start, tab.Selection.End)
+	}
+}
+
+func TestOtherViewCursorFollowsEdits(t *testing.T) {
+	m := newTestModel(make([]byte, 32))
+	first := m.currentTab()
+	first.Cursor = 20
+
+	typeKeys(m, "v")
+	second := m.currentTab()
+	second.Cursor = 4
+	m.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	m.Update(tea.KeyMsg{Type: tea.KeyDelete})
+
+	if second.Cursor != 4 {
+		t.Errorf("editing view's cursor moved to %d", second.Cursor)
+	}
+	if first.Cursor != 18 {
+		t.Errorf("expected the other view's cursor to follow its byte to 18, got %d", first.Cursor)
+	}
+
+	// Closed tabs stop tracking
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	second.Buffer.Insert(0, []byte{1})
+	if second.Cursor != 4 {
+		t.Errorf("closed tab still tracks changes, cursor %d", second.Cursor)
+	}
+}
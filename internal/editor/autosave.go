@@ -0,0 +1,165 @@
+package editor
+
+import (
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// With autosave_seconds set, modified files are saved on a timer. Only
+// buffers a plain S would save without asking are: never new or read-only
+// ones, nothing while a dialog or a prompt is open or a large edit runs,
+// and not a file that changed on disk, which is left to the user once
+// they've been told, nor one whose size changed when confirm_size_change
+// asks about that. Files S would save in steps are left to S too. The
+// files on disk are compared in the background, and only the buffers
+// unedited since are saved once that's done.
+
+type autosaveMsg struct{}
+
+// autosaveCheck is whether a buffer's file changed on disk, as of version.
+type autosaveCheck struct {
+	tab     *Tab
+	version uint64
+	changed bool
+	err     error
+}
+
+type autosaveCheckMsg struct {
+	checks []autosaveCheck
+}
+
+// autosaveTimer schedules the next autosave, or a look at the setting
+// after a config reload when it's off.
+func (m *Model) autosaveTimer() tea.Cmd {
+	interval := configPollInterval
+	if s := m.config.Behavior.AutosaveSeconds; s > 0 {
+		interval = time.Duration(s) * time.Second
+	}
+	return m.clock.Tick(interval, func(time.Time) tea.Msg {
+		return autosaveMsg{}
+	})
+}
+
+func (m *Model) handleAutosave() (tea.Model, tea.Cmd) {
+	var check tea.Cmd
+	if m.config.Behavior.AutosaveSeconds > 0 && m.autosavePossible() {
+		check = m.autosave()
+	}
+	return m, tea.Batch(check, m.autosaveTimer())
+}
+
+// autosavePossible reports whether nothing is waiting on the user that a
+// save could pull the file out from under.
+func (m *Model) autosavePossible() bool {
+	if m.job != nil || m.saving != nil {
+		return false
+	}
+	switch m.view {
+	case ViewDialog, ViewSaveAs, ViewLoading:
+		return false
+	}
+	return true
+}
+
+// autosave starts comparing the files of the buffers to autosave with
+// what's on disk, or returns nil when there are none or the last
+// comparison is still running.
+func (m *Model) autosave() tea.Cmd {
+	if m.autosaveChecking {
+		return nil
+	}
+	var tabs []*Tab
+	var snapshots []*buffer.Buffer
+	seen := make(map[*buffer.Buffer]bool)
+	for _, tab := range m.tabs {
+		buf := tab.Buffer
+		if seen[buf] || !m.autosaveWanted(buf) {
+			continue
+		}
+		seen[buf] = true
+		tabs = append(tabs, tab)
+		snapshots = append(snapshots, buf.Snapshot())
+	}
+	if len(tabs) == 0 {
+		return nil
+	}
+	m.autosaveChecking = true
+	return func() tea.Msg {
+		checks := make([]autosaveCheck, len(tabs))
+		for i, s := range snapshots {
+			changed, err := s.HasChangedOnDisk()
+			checks[i] = autosaveCheck{tab: tabs[i], version: s.Version(), changed: changed, err: err}
+		}
+		return autosaveCheckMsg{checks: checks}
+	}
+}
+
+// autosaveWanted reports whether buf is one autosave saves.
+func (m *Model) autosaveWanted(buf *buffer.Buffer) bool {
+	if !buf.IsModified() || buf.IsNew() || buf.Filename() == "" || buf.ReadOnly() || m.sizeChangeGuarded(buf) {
+		return false
+	}
+	return buf.Size() < saveProgressSize || buf.FixedSize()
+}
+
+func (m *Model) handleAutosaveCheck(msg autosaveCheckMsg) (tea.Model, tea.Cmd) {
+	m.autosaveChecking = false
+	if !m.autosavePossible() {
+		// The next tick looks again
+		return m, nil
+	}
+	if m.autosaved == nil {
+		m.autosaved = make(map[*buffer.Buffer]time.Time)
+		m.autosaveConflicts = make(map[*buffer.Buffer]bool)
+	}
+	stale := false
+	for _, c := range msg.checks {
+		tab, buf := c.tab, c.tab.Buffer
+		if !slices.Contains(m.tabs, tab) || !m.autosaveWanted(buf) {
+			continue
+		}
+		if buf.Version() != c.version {
+			// Edited during the comparison: compare again before saving
+			stale = true
+			continue
+		}
+		if c.err != nil || c.changed {
+			if !m.autosaveConflicts[buf] {
+				why := "it changed on disk; S saves over it"
+				if c.err != nil {
+					why = "can't compare it with the file on disk: " + errReason(c.err)
+				}
+				m.setStatus(sevWarning, "Not autosaving "+filepath.Base(buf.Filename())+": "+why)
+			}
+			m.autosaveConflicts[buf] = true
+			continue
+		}
+		delete(m.autosaveConflicts, buf)
+		if err := buf.Save(); err != nil {
+			m.setStatus(sevError, "Autosave of "+buf.Filename()+" failed: "+err.Error())
+			continue
+		}
+		m.autosaved[buf] = m.clock.Now()
+		m.setStatus(sevInfo, "Autosaved "+buf.Filename())
+		m.savedRangeLocks(tab)
+	}
+	if stale {
+		return m, m.autosave()
+	}
+	return m, nil
+}
+
+// autosaveReadout is when tab's file was last autosaved, for the status
+// bar, or "".
+func (m *Model) autosaveReadout(tab *Tab) string {
+	at, ok := m.autosaved[tab.Buffer]
+	if !ok || m.config.Behavior.AutosaveSeconds <= 0 {
+		return ""
+	}
+	return "autosaved " + at.Format("15:04:05")
+}
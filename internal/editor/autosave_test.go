@@ -0,0 +1,127 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/sidecar"
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+func TestAutosave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("abcd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, clock := startEditor(t, path)
+	tab := m.currentTab()
+	tab.Buffer.Replace(0, 'X')
+
+	// Off by default
+	Drive(m, autosaveMsg{})
+	if !tab.Buffer.IsModified() {
+		t.Fatal("autosave should be off by default")
+	}
+
+	m.config.Behavior.AutosaveSeconds = 30
+	m.view = ViewDialog
+	Drive(m, autosaveMsg{})
+	if !tab.Buffer.IsModified() {
+		t.Error("nothing is autosaved while a dialog is open")
+	}
+
+	m.view = ViewMain
+	clock.timers = nil
+	Drive(m, autosaveMsg{})
+	scheduled := slices.ContainsFunc(clock.timers, func(t testTimer) bool { return t.fn(t.at) == autosaveMsg{} })
+	if data, _ := os.ReadFile(path); string(data) != "Xbcd" || tab.Buffer.IsModified() || !scheduled {
+		t.Fatalf("expected the file autosaved and another one scheduled, got %q", data)
+	}
+	if m.status.text != "Autosaved "+path {
+		t.Errorf("got %q", m.status.text)
+	}
+	m.status.text = ""
+	if !strings.Contains(m.renderStatus(), "autosaved ") {
+		t.Errorf("the status bar should say when, got %q", m.renderStatus())
+	}
+
+	// Changed on disk meanwhile: left alone, and said once
+	tab.Buffer.Replace(1, 'Y')
+	if err := os.WriteFile(path, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Drive(m, autosaveMsg{})
+	if data, _ := os.ReadFile(path); string(data) != "other" || !strings.Contains(m.status.text, "changed on disk") {
+		t.Fatalf("the changed file should be kept, got %q (%q)", data, m.status.text)
+	}
+	m.status.text = ""
+	Drive(m, autosaveMsg{})
+	if m.status.text != "" {
+		t.Errorf("the conflict should only be reported once, got %q", m.status.text)
+	}
+}
+
+func TestAutosaveSkipsNewAndReadOnlyBuffers(t *testing.T) {
+	m := newTestModel([]byte("new"))
+	m.currentTab().Buffer.Insert(0, []byte("x"))
+
+	path := filepath.Join(t.TempDir(), "ro.bin")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := buffer.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Replace(0, 'X')
+	buf.SetReadOnly(true)
+	m.tabs = append(m.tabs, m.newTab(buf))
+
+	m.config.Behavior.AutosaveSeconds = 1
+	m.Update(autosaveMsg{})
+	if data, _ := os.ReadFile(path); string(data) != "abc" || !m.tabs[0].Buffer.IsModified() {
+		t.Errorf("neither buffer should be saved, got %q", data)
+	}
+}
+
+func TestAutosaveStoresRangeLocks(t *testing.T) {
+	path := writeTestFile(t, strings.Repeat("\x00", 64))
+	m, _ := startEditor(t, path)
+	m.config.Behavior.AutosaveSeconds = 30
+	tab := m.currentTab()
+	tab.Buffer.Lock(4, 7)
+	tab.Buffer.Insert(0, []byte{1, 2})
+
+	Drive(m, autosaveMsg{})
+	if tab.Buffer.IsModified() {
+		t.Fatalf("expected an autosave, status %q", m.status.text)
+	}
+	if sc, _ := sidecar.Load(path); len(sc.Locks) != 1 || sc.Locks[0] != (sidecar.Lock{Start: 6, End: 9}) {
+		t.Errorf("expected the moved lock stored with the autosave, got %+v", sc.Locks)
+	}
+}
+
+func TestAutosaveComparesAgainAfterEdits(t *testing.T) {
+	path := writeTestFile(t, "abcd")
+	m, _ := startEditor(t, path)
+	m.config.Behavior.AutosaveSeconds = 30
+	tab := m.currentTab()
+	tab.Buffer.Replace(0, 'X')
+
+	check := m.autosave()
+	if check == nil || m.autosave() != nil {
+		t.Fatal("expected one comparison with the disk at a time")
+	}
+	tab.Buffer.Replace(1, 'Y')
+	_, again := m.Update(check())
+	if data, _ := os.ReadFile(path); string(data) != "abcd" || again == nil {
+		t.Fatalf("a buffer edited during the comparison should be compared again, got %q", data)
+	}
+	m.run(again)
+	if data, _ := os.ReadFile(path); string(data) != "XYcd" || tab.Buffer.IsModified() {
+		t.Errorf("expected the edits autosaved, got %q", data)
+	}
+}
@@ -0,0 +1,165 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Opening every file in a directory whose name matches a pattern, each in
+// a tab of its own: from the browser's Open all button, or with a
+// directory on the command line. Files that can't be opened don't stop the
+// others and are reported together at the end.
+
+// batchOpenConfirm is how many files open without asking first.
+const batchOpenConfirm = 20
+
+// matchingFiles returns the regular files in dir whose names match
+// pattern, in name order.
+func matchingFiles(fsys FS, dir, pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("bad pattern %q", pattern)
+	}
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if ok, _ := filepath.Match(pattern, e.Name()); !ok {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		// Stat follows symlinks to the files they name
+		if info, err := fsys.Stat(path); err == nil && info.Mode().IsRegular() {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// openAll opens paths in tabs and reports how that went in one message,
+// returning the files it couldn't open and why.
+func (m *Model) openAll(paths []string, pattern string) (failed []string) {
+	for _, path := range paths {
+		if err := m.openFile(path); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", filepath.Base(path), errReason(err)))
+		}
+	}
+	opened := len(paths) - len(failed)
+	if len(failed) == 0 {
+		m.setStatus(sevInfo, fmt.Sprintf("Opened %d files matching %s", opened, pattern))
+		return nil
+	}
+	m.setStatus(sevWarning, fmt.Sprintf("Opened %d of %d files matching %s; couldn't open %s",
+		opened, len(paths), pattern, strings.Join(failed, ", ")))
+	return failed
+}
+
+func (m *Model) openGlobPrompt(dir string) {
+	m.globDir = dir
+	if m.globInput == "" {
+		m.globInput = "*"
+	}
+	m.countGlobMatches()
+	m.view = ViewOpenGlob
+}
+
+// countGlobMatches updates the number of files the pattern would open, or
+// the reason it can't open any.
+func (m *Model) countGlobMatches() {
+	paths, err := matchingFiles(m.fs, m.globDir, m.globInput)
+	m.globCount, m.globErr = len(paths), ""
+	if err != nil {
+		m.globErr = errReason(err)
+	}
+}
+
+func (m *Model) handleOpenGlobKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewOpen
+	case tea.KeyEnter:
+		m.openGlob()
+	case tea.KeyBackspace:
+		if len(m.globInput) > 0 {
+			m.globInput = m.globInput[:len(m.globInput)-1]
+			m.countGlobMatches()
+		}
+	case tea.KeySpace:
+		m.globInput += " "
+		m.countGlobMatches()
+	case tea.KeyRunes:
+		m.globInput += string(msg.Runes)
+		m.countGlobMatches()
+	}
+	return m, nil
+}
+
+func (m *Model) openGlob() {
+	pattern := m.globInput
+	paths, err := matchingFiles(m.fs, m.globDir, pattern)
+	switch {
+	case err != nil:
+		m.setStatus(sevWarning, fmt.Sprintf("Can't open files in %s: %s", m.globDir, errReason(err)))
+		return
+	case len(paths) == 0:
+		m.setStatus(sevWarning, fmt.Sprintf("No files in %s match %s", m.globDir, pattern))
+		return
+	}
+
+	open := func() (tea.Model, tea.Cmd) {
+		m.view = ViewMain
+		m.openAll(paths, pattern)
+		if len(m.tabs) == 0 {
+			m.view = ViewOpen
+		}
+		return m, nil
+	}
+	if len(paths) > batchOpenConfirm {
+		m.openDialog(fmt.Sprintf("Open %d files matching %s, each in a new tab?", len(paths), pattern),
+			m.cancelButton(ViewOpenGlob),
+			dialogButton{label: "Open all", action: open})
+		return
+	}
+	open()
+}
+
+func (m *Model) renderOpenGlob() string {
+	var b strings.Builder
+	b.WriteString("\nOPEN ALL\n")
+	b.WriteString("========\n\n")
+	b.WriteString("Directory: " + m.globDir + "\n")
+	b.WriteString("Pattern:   " + m.globInput + "_\n\n")
+	if m.globErr != "" {
+		b.WriteString(m.styles.StatusWarning.Render(m.globErr) + "\n")
+	} else {
+		b.WriteString(fmt.Sprintf("%d matching files\n", m.globCount))
+	}
+	b.WriteString("\n(* matches any name, ? one character, [a-z] a range, e.g. *.bin)\n")
+	b.WriteString("\nPress Enter to open each in a new tab, ESC to go back\n")
+	return b.String()
+}
+
+// openDirArg opens the files in dir that match pattern, or asks for a
+// pattern when there is none.
+func (m *Model) openDirArg(dir, pattern string) error {
+	if pattern == "" {
+		m.browse(dir)
+		m.openGlobPrompt(dir)
+		return nil
+	}
+	paths, err := matchingFiles(m.fs, dir, pattern)
+	if err != nil {
+		return fmt.Errorf("failed to open files in %s: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no files in %s match %s", dir, pattern)
+	}
+	if failed := m.openAll(paths, pattern); len(failed) == len(paths) {
+		return fmt.Errorf("couldn't open any of the files in %s matching %s: %s", dir, pattern, strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOpenAllMatchingFromTheBrowser(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "a.bin", "b.bin", "notes.txt")
+	if err := os.Mkdir(filepath.Join(dir, "sub.bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(nil)
+	m.tabs = nil
+	m.view = ViewOpen
+	m.browse(dir)
+	m.browserFocus = 4
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewOpenGlob || m.globDir != dir || m.globCount != 3 {
+		t.Fatalf("expected the prompt for %s with 3 files, got view %d, %q, %d", dir, m.view, m.globDir, m.globCount)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeKeys(m, "*.bin")
+	if !strings.Contains(m.renderOpenGlob(), "2 matching files") {
+		t.Errorf("directories don't count, got %d", m.globCount)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain || len(m.tabs) != 2 || m.tabs[1].Buffer.Filename() != filepath.Join(dir, "b.bin") {
+		t.Fatalf("expected both .bin files open, got %d tabs", len(m.tabs))
+	}
+	if m.status.text != "Opened 2 files matching *.bin" {
+		t.Errorf("got %q", m.status.text)
+	}
+}
+
+func TestOpenAllAsksBeforeOpeningMany(t *testing.T) {
+	dir := t.TempDir()
+	for i := range batchOpenConfirm + 1 {
+		writeFiles(t, dir, fmt.Sprintf("%02d.bin", i))
+	}
+	m := newTestModel(nil)
+	m.tabs = nil
+	m.openGlobPrompt(dir)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewDialog || len(m.tabs) != 0 {
+		t.Fatalf("expected a confirmation, got view %d", m.view)
+	}
+	m.activateButton(0)
+	if m.view != ViewOpenGlob || len(m.tabs) != 0 {
+		t.Fatal("Cancel should go back to the prompt")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.activateButton(1)
+	if len(m.tabs) != batchOpenConfirm+1 {
+		t.Errorf("expected every file open, got %d tabs", len(m.tabs))
+	}
+}
+
+func TestOpenDirArg(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "a.bin", "b.dat")
+	m := newTestModel(nil)
+	m.tabs = nil
+
+	if err := m.openDirArg(dir, "*.img"); err == nil || !strings.Contains(err.Error(), "no files") {
+		t.Errorf("expected an error for no matches, got %v", err)
+	}
+	if err := m.openDirArg(dir, "[a-"); err == nil {
+		t.Error("expected an error for a bad pattern")
+	}
+	if err := m.openDirArg(dir, "*.dat"); err != nil || len(m.tabs) != 1 {
+		t.Errorf("expected b.dat open, got %v (%d tabs)", err, len(m.tabs))
+	}
+
+	// Without a pattern the editor asks for one
+	if err := m.openDirArg(dir, ""); err != nil || m.view != ViewOpenGlob || m.browserPath != dir {
+		t.Errorf("expected the prompt, got view %d (%v)", m.view, err)
+	}
+}
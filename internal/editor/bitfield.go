@@ -0,0 +1,221 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/bitfield"
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bitfield templates (see package bitfield) name the bits of the u8, u16
+// or u32 at the cursor. The template chosen in the bitfield view stays
+// active for the tab, and the decoder panel shows its fields as the
+// cursor moves; the view also sets a field by rewriting the whole value.
+
+func (m *Model) loadBitfields() {
+	m.bitfields, m.bitfieldErr = bitfield.Load(config.TemplateDir())
+	if m.bitfieldErr != nil {
+		m.setStatus(sevError, "Bitfield templates: "+strings.ReplaceAll(m.bitfieldErr.Error(), "\n", "; "))
+	}
+}
+
+// bitfieldWord reads the value tab's template applies to at the cursor.
+func (m *Model) bitfieldWord(tab *Tab) (uint64, bool) {
+	data := tab.Buffer.GetBytes(tab.Cursor, tab.Bitfield.Size)
+	if len(data) < tab.Bitfield.Size {
+		return 0, false
+	}
+	return decodeUint(data, m.bigEndian), true
+}
+
+func (m *Model) openBitfields() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if len(m.bitfields) == 0 {
+		msg := "No bitfield templates in " + config.TemplateDir()
+		if m.bitfieldErr != nil {
+			msg = "No valid bitfield templates: " + strings.ReplaceAll(m.bitfieldErr.Error(), "\n", "; ")
+		}
+		m.setStatus(sevWarning, msg)
+		return
+	}
+	if tab.Bitfield == nil {
+		tab.Bitfield = m.bitfields[0]
+	}
+	m.bitfieldField = 0
+	m.bitfieldInput = ""
+	m.view = ViewBitfield
+}
+
+// cycleBitfield switches to the next or previous template, passing through
+// none, which hides the fields from the decoder panel.
+func (m *Model) cycleBitfield(tab *Tab, delta int) {
+	i := -1
+	for j, t := range m.bitfields {
+		if t == tab.Bitfield {
+			i = j
+		}
+	}
+	n := len(m.bitfields) + 1
+	i = (i+1+delta+n)%n - 1
+	tab.Bitfield = nil
+	if i >= 0 {
+		tab.Bitfield = m.bitfields[i]
+	}
+	m.bitfieldField = 0
+}
+
+func (m *Model) handleBitfieldKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tab := m.currentTab()
+	if tab == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+	if msg.String() == "alt+o" {
+		m.jumpBack()
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewMain
+	case tea.KeyLeft:
+		m.cycleBitfield(tab, -1)
+	case tea.KeyRight:
+		m.cycleBitfield(tab, 1)
+	case tea.KeyUp:
+		if m.bitfieldField > 0 {
+			m.bitfieldField--
+		}
+	case tea.KeyDown:
+		if tab.Bitfield != nil && m.bitfieldField < len(tab.Bitfield.Fields)-1 {
+			m.bitfieldField++
+		}
+	case tea.KeyEnter:
+		if m.bitfieldInput == "" && tab.Bitfield != nil && tab.Bitfield.Fields[m.bitfieldField].Pointer {
+			if m.followPointer(tab, tab.Bitfield.Fields[m.bitfieldField]) {
+				m.bitfieldField = 0
+			}
+			break
+		}
+		m.writeBitfield(tab)
+	case tea.KeyBackspace:
+		if len(m.bitfieldInput) > 0 {
+			m.bitfieldInput = m.bitfieldInput[:len(m.bitfieldInput)-1]
+		}
+	default:
+		char := msg.String()
+		if isHexChar(char) || char == "x" || char == "X" || char == "o" || char == "O" {
+			m.bitfieldInput += char
+		}
+	}
+	return m, nil
+}
+
+// writeBitfield sets the selected field to the typed value and writes the
+// value containing it back.
+func (m *Model) writeBitfield(tab *Tab) {
+	if tab.Bitfield == nil || m.bitfieldInput == "" {
+		return
+	}
+	word, ok := m.bitfieldWord(tab)
+	if !ok {
+		m.setStatus(sevWarning, fmt.Sprintf("%s needs %d bytes at the cursor", tab.Bitfield.Name, tab.Bitfield.Size))
+		return
+	}
+	x, err := parseIntLiteral(m.bitfieldInput, 64, false)
+	if err != nil {
+		m.setStatus(sevWarning, fmt.Sprintf("Invalid value %q", m.bitfieldInput))
+		return
+	}
+	field := tab.Bitfield.Fields[m.bitfieldField]
+	word, err = field.Set(word, x)
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return
+	}
+	if m.editBlocked(tab) {
+		return
+	}
+	data := encodeUint(word, tab.Bitfield.Size, m.bigEndian)
+	if m.refused("set "+field.Name, tab.Buffer.ReplaceBytes(tab.Cursor, data)) {
+		return
+	}
+	m.bitfieldInput = ""
+	m.setStatus(sevInfo, fmt.Sprintf("Set %s to %d, wrote % X", field.Name, x, data))
+}
+
+// bitfieldReadout lists the active template's fields for the decoder
+// panel, as many as fit in width.
+func (m *Model) bitfieldReadout(tab *Tab, width int) string {
+	if tab.Bitfield == nil {
+		return ""
+	}
+	word, ok := m.bitfieldWord(tab)
+	if !ok {
+		return ""
+	}
+	s := tab.Bitfield.Name + ":"
+	for _, f := range tab.Bitfield.Fields {
+		item := fmt.Sprintf(" %s=%d", f.Name, f.Get(word))
+		if f.Pointer {
+			if _, ok := m.pointerTarget(tab, f, word); !ok {
+				item += "(past the end)"
+			}
+		}
+		if len(s)+len(item) > width {
+			break
+		}
+		s += item
+	}
+	return s
+}
+
+func (m *Model) renderBitfield() string {
+	var b strings.Builder
+	b.WriteString("\nBITFIELDS\n")
+	b.WriteString("=========\n\n")
+
+	tab := m.currentTab()
+	if tab == nil {
+		return b.String()
+	}
+	if tab.Bitfield == nil {
+		b.WriteString("Template: (none)\n")
+	} else {
+		t := tab.Bitfield
+		word, ok := m.bitfieldWord(tab)
+		if !ok {
+			b.WriteString(fmt.Sprintf("Template: %s (needs %d bytes at the cursor)\n\n", t.Name, t.Size))
+		} else {
+			b.WriteString(fmt.Sprintf("Template: %s (u%d at 0x%X = 0x%0*X, %s)\n\n",
+				t.Name, t.Size*8, tab.Cursor, t.Size*2, word, m.byteOrderName()))
+		}
+		for i, f := range t.Fields {
+			prefix := "  "
+			if i == m.bitfieldField {
+				prefix = "> "
+			}
+			value := "-"
+			if ok {
+				value = fmt.Sprintf("%d (0x%X)", f.Get(word), f.Get(word))
+				if f.Pointer {
+					value += " " + m.pointerValue(tab, f, word)
+				}
+			}
+			b.WriteString(fmt.Sprintf("%s%-16s %-10s %s\n", prefix, f.Name, f.Bits(), value))
+		}
+		b.WriteString("\nNew value: " + m.bitfieldInput + "_\n")
+		b.WriteString("(decimal, 0x hex or 0b binary)\n")
+	}
+	if m.bitfieldErr != nil {
+		b.WriteString("\nSome templates failed to load:\n")
+		b.WriteString(m.bitfieldErr.Error() + "\n")
+	}
+	b.WriteString("\nPress ←/→ to change template, ↑/↓ to choose a field, Enter to write (with no value typed, follow a → pointer; Alt+O back), ESC to close\n")
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/bitfield"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBitfieldView(t *testing.T) {
+	flags, err := bitfield.Parse("flags", 1, "bit 7: compressed, bits 4-6: version, bits 0-3: type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := bitfield.Parse("header", 2, "bits 12-15: kind, bits 0-11: length")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel([]byte{0xB2, 0x12, 0x34})
+	m.bitfields = []*bitfield.Template{flags, header}
+	tab := m.currentTab()
+
+	typeKeys(m, "j")
+	if m.view != ViewBitfield || tab.Bitfield != flags {
+		t.Fatalf("expected the first template to apply, view %v", m.view)
+	}
+	if panel := m.renderDecoder(); !strings.Contains(panel, "flags: compressed=1 version=3 type=2") {
+		t.Errorf("decoder should show the fields:\n%s", panel)
+	}
+
+	// Set version to 5 and check the byte is rewritten
+	press(m, tea.KeyDown)
+	typeKeys(m, "5")
+	press(m, tea.KeyEnter)
+	if b, _ := tab.Buffer.GetByte(0); b != 0xD2 {
+		t.Errorf("expected 0xD2 after setting version, got 0x%02X (status %q)", b, m.status.text)
+	}
+	typeKeys(m, "9")
+	press(m, tea.KeyEnter)
+	if b, _ := tab.Buffer.GetByte(0); b != 0xD2 || m.status.sev != sevWarning {
+		t.Errorf("a value too wide for the field should be refused, got 0x%02X", b)
+	}
+
+	// The u16 template reads the current endianness
+	press(m, tea.KeyBackspace, tea.KeyRight)
+	if tab.Bitfield != header {
+		t.Fatal("→ should switch to the next template")
+	}
+	if got := m.bitfieldReadout(tab, 80); got != "header: kind=13 length=530" {
+		t.Errorf("big endian readout %q", got)
+	}
+	typeKeys(m, "0x7")
+	press(m, tea.KeyEnter)
+	if got := tab.Buffer.GetBytes(0, 2); got[0] != 0x72 || got[1] != 0x12 {
+		t.Errorf("setting kind wrote % X", got)
+	}
+	// A leading zero doesn't make it octal
+	typeKeys(m, "010")
+	press(m, tea.KeyEnter)
+	if got, _ := tab.Buffer.GetByte(0); got != 0xA2 {
+		t.Errorf("setting kind to 010 wrote %02X", got)
+	}
+
+	press(m, tea.KeyRight)
+	if tab.Bitfield != nil || m.bitfieldReadout(tab, 80) != "" {
+		t.Error("cycling past the last template should turn fields off")
+	}
+}
@@ -0,0 +1,205 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A column (block) selection is a rectangle in (row, col) space over the
+// current bytes-per-row. Cells past EOF are not part of the rectangle, so
+// the last row of a block may be shorter than the others.
+
+func (m *Model) toggleBlockSelection() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+
+	if !tab.Selection.Active {
+		tab.Selection.Active = true
+		tab.Selection.Start = tab.Cursor
+		tab.Selection.End = tab.Cursor
+	}
+	tab.Selection.Block = !tab.Selection.Block
+	if tab.Selection.Block {
+		m.setStatus(sevInfo, "Column selection")
+	} else {
+		m.setStatus(sevInfo, "Linear selection")
+	}
+}
+
+func (m *Model) blockRect(tab *Tab) (rowStart, rowEnd, colStart, colEnd int64) {
+	rowStart, colStart = tab.Selection.Start/bytesPerRow, tab.Selection.Start%bytesPerRow
+	rowEnd, colEnd = tab.Selection.End/bytesPerRow, tab.Selection.End%bytesPerRow
+	if rowStart > rowEnd {
+		rowStart, rowEnd = rowEnd, rowStart
+	}
+	if colStart > colEnd {
+		colStart, colEnd = colEnd, colStart
+	}
+	return rowStart, rowEnd, colStart, colEnd
+}
+
+func (m *Model) inSelection(tab *Tab, offset int64) bool {
+	if !tab.Selection.Active {
+		return false
+	}
+	if tab.Selection.Block {
+		rowStart, rowEnd, colStart, colEnd := m.blockRect(tab)
+		row, col := offset/bytesPerRow, offset%bytesPerRow
+		return row >= rowStart && row <= rowEnd && col >= colStart && col <= colEnd
+	}
+	start, end := tab.selectedRange()
+	return offset >= start && offset <= end
+}
+
+// blockRows returns the selected slice of every row in the rectangle,
+// clipped to the end of the buffer.
+func (m *Model) blockRows(tab *Tab) [][]byte {
+	rowStart, rowEnd, colStart, colEnd := m.blockRect(tab)
+	width := int(colEnd - colStart + 1)
+
+	var rows [][]byte
+	for row := rowStart; row <= rowEnd; row++ {
+		data := tab.Buffer.GetBytes(row*bytesPerRow+colStart, width)
+		if data == nil {
+			break
+		}
+		rows = append(rows, data)
+	}
+	return rows
+}
+
+func (m *Model) copyBlock(tab *Tab) *register {
+	rows := m.blockRows(tab)
+	_, _, colStart, colEnd := m.blockRect(tab)
+
+	r := &register{width: int(colEnd - colStart + 1)}
+	for _, row := range rows {
+		r.data = append(r.data, row...)
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Copied %dx%d column selection", r.width, len(rows)))
+	return r
+}
+
+// pasteBlock writes a rectangular register back at the cursor, one row
+// slice per row, overwriting in place. Cells that would land past EOF are
+// dropped rather than extending the file.
+func (m *Model) pasteBlock(tab *Tab, r *register) {
+	col := tab.Cursor % bytesPerRow
+	if col+int64(r.width) > bytesPerRow {
+		m.setStatus(sevWarning, fmt.Sprintf("Column paste needs %d columns from column %d", r.width, col))
+		return
+	}
+
+	clipped := 0
+	version := tab.Buffer.Version()
+	var err error
+	tab.Buffer.BeginGroup()
+rows:
+	for i := 0; i*r.width < len(r.data); i++ {
+		end := (i + 1) * r.width
+		if end > len(r.data) {
+			end = len(r.data)
+		}
+		rowOffset := tab.Cursor + int64(i)*bytesPerRow
+		for j, d := range r.data[i*r.width : end] {
+			pos := rowOffset + int64(j)
+			if pos >= tab.Buffer.Size() {
+				clipped++
+				continue
+			}
+			if err = tab.Buffer.Replace(pos, d); err != nil {
+				break rows
+			}
+		}
+	}
+	tab.Buffer.EndGroup()
+	if err != nil {
+		// All or nothing, like other pastes
+		if tab.Buffer.Version() != version {
+			tab.Buffer.Undo()
+		}
+		m.refused("paste", err)
+		return
+	}
+
+	if clipped > 0 {
+		m.setStatus(sevWarning, fmt.Sprintf("Column paste: %d bytes clipped at end of file", clipped))
+	}
+}
+
+func (m *Model) fillSelection(value byte) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil || m.editBlocked(tab) {
+		return nil
+	}
+
+	if !tab.Selection.Active {
+		m.refused("fill", tab.Buffer.Replace(tab.Cursor, value))
+		return nil
+	}
+
+	if tab.Selection.Block {
+		return m.runEdit(m.blockFillJob(tab, value))
+	}
+
+	start, end := m.getSelectedRange()
+	if end >= tab.Buffer.Size() {
+		end = tab.Buffer.Size() - 1
+	}
+	if end < start {
+		return nil
+	}
+	return m.runEdit(m.fillJob(tab, start, end-start+1, value))
+}
+
+func (m *Model) blockFillJob(tab *Tab, value byte) *editJob {
+	rowStart, rowEnd, colStart, colEnd := m.blockRect(tab)
+	return m.rectFillJob(tab, "Column fill", rowStart*bytesPerRow+colStart,
+		colEnd-colStart+1, rowEnd-rowStart+1, bytesPerRow, value)
+}
+
+func (m *Model) handleFillKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewMain
+	case tea.KeyEnter:
+		input := strings.TrimPrefix(strings.ToLower(m.fillInput), "0x")
+		value, err := strconv.ParseUint(input, 16, 8)
+		if err != nil {
+			m.setStatus(sevWarning, "Fill value must be a hex byte (00-FF)")
+			return m, nil
+		}
+		m.view = ViewMain
+		fill := func() tea.Cmd { return m.fillSelection(byte(value)) }
+		m.recordEdit("fill", true, fill)
+		return m, fill()
+	case tea.KeyBackspace:
+		if len(m.fillInput) > 0 {
+			m.fillInput = m.fillInput[:len(m.fillInput)-1]
+		}
+	default:
+		char := msg.String()
+		if len(char) == 1 && (isHexChar(char) || char == "x" || char == "X") && len(m.fillInput) < 4 {
+			m.fillInput += char
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) renderFill() string {
+	var b strings.Builder
+	b.WriteString("\nFILL\n")
+	b.WriteString("====\n\n")
+	b.WriteString("Byte (hex): ")
+	b.WriteString(m.fillInput)
+	b.WriteString("_\n\n")
+	b.WriteString("Fills the selection, or the byte at the cursor\n")
+	b.WriteString("\nPress Enter to fill, ESC to cancel\n")
+
+	return b.String()
+}
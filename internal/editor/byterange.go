@@ -0,0 +1,197 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The byte range dialog finds bytes whose values fall in, or with "not"
+// outside, a hex range, optionally at least N in a row: "20-7E 16" is 16
+// or more printable ASCII bytes, "not 00" the next non-zero byte. Scans
+// run in the background over a snapshot, like the runs dialog.
+
+type byteRange struct {
+	lo, hi byte
+	not    bool
+	minLen int64
+}
+
+type byteRangeFoundMsg struct {
+	seq     int
+	tab     *Tab
+	version uint64 // of the tab's buffer when scanned
+	start   int64
+	length  int64
+}
+
+func parseHexByte(s string) (byte, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(s), "0x"), 16, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte %q", s)
+	}
+	return byte(v), nil
+}
+
+// parseByteRange parses "[not] LO[-HI] [LENGTH]", the bytes in hex and the
+// minimum length in decimal.
+func parseByteRange(input string) (byteRange, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	r := byteRange{minLen: 1}
+	if len(fields) > 0 && fields[0] == "not" {
+		r.not = true
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || len(fields) > 2 {
+		return byteRange{}, fmt.Errorf("enter a hex byte or range, optionally followed by a length")
+	}
+	loStr, hiStr, isRange := strings.Cut(fields[0], "-")
+	lo, err := parseHexByte(loStr)
+	if err != nil {
+		return byteRange{}, err
+	}
+	hi := lo
+	if isRange {
+		if hi, err = parseHexByte(hiStr); err != nil {
+			return byteRange{}, err
+		}
+	}
+	if hi < lo {
+		return byteRange{}, fmt.Errorf("range %s is backwards", fields[0])
+	}
+	r.lo, r.hi = lo, hi
+	if len(fields) == 2 {
+		n, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || n < 1 {
+			return byteRange{}, fmt.Errorf("invalid length %q", fields[1])
+		}
+		r.minLen = n
+	}
+	return r, nil
+}
+
+func (r byteRange) contains(b byte) bool {
+	return (b >= r.lo && b <= r.hi) != r.not
+}
+
+func (r byteRange) String() string {
+	s := fmt.Sprintf("0x%02X–0x%02X", r.lo, r.hi)
+	if r.lo == r.hi {
+		s = fmt.Sprintf("0x%02X", r.lo)
+	}
+	if r.not {
+		s = "not " + s
+	}
+	if r.minLen > 1 {
+		s = fmt.Sprintf("≥%d bytes %s", r.minLen, s)
+	}
+	return s
+}
+
+func (m *Model) handleByteRangeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.cancelByteRangeScan()
+		m.view = ViewMain
+	case tea.KeyEnter, tea.KeyDown:
+		return m, m.startByteRangeScan(true)
+	case tea.KeyUp:
+		return m, m.startByteRangeScan(false)
+	case tea.KeyCtrlU:
+		m.byteRangeInput = ""
+	case tea.KeyBackspace:
+		if len(m.byteRangeInput) > 0 {
+			m.byteRangeInput = m.byteRangeInput[:len(m.byteRangeInput)-1]
+		}
+	default:
+		char := strings.ToLower(msg.String())
+		if isHexChar(char) || strings.Contains(" -xnot", char) && len(char) == 1 {
+			m.byteRangeInput += char
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) cancelByteRangeScan() {
+	m.byteRangeSeq++
+	m.byteRangeScanning = false
+	if m.byteRangeCancel != nil {
+		m.byteRangeCancel()
+		m.byteRangeCancel = nil
+	}
+}
+
+func (m *Model) startByteRangeScan(forward bool) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	r, err := parseByteRange(m.byteRangeInput)
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return nil
+	}
+
+	m.cancelByteRangeScan()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.byteRangeCancel = cancel
+	m.byteRangeScanning = true
+	m.byteRangeForward = forward
+
+	snapshot := tab.Buffer.Snapshot()
+	seq, from := m.byteRangeSeq, tab.Cursor
+	return func() tea.Msg {
+		start, length, err := snapshot.FindSpanContext(ctx, from, r.minLen, r.contains, forward)
+		if err != nil {
+			return nil
+		}
+		return byteRangeFoundMsg{seq: seq, tab: tab, version: snapshot.Version(), start: start, length: length}
+	}
+}
+
+func (m *Model) handleByteRangeFound(msg byteRangeFoundMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.byteRangeSeq || msg.tab != m.currentTab() {
+		return m, nil
+	}
+	if msg.tab.Buffer.Version() != msg.version {
+		// The buffer changed during the scan; the offsets may be wrong
+		return m, m.startByteRangeScan(m.byteRangeForward)
+	}
+	m.byteRangeScanning = false
+	m.byteRangeCancel = nil
+
+	r, _ := parseByteRange(m.byteRangeInput)
+	if msg.start < 0 {
+		where := "after"
+		if !m.byteRangeForward {
+			where = "before"
+		}
+		m.setStatus(sevInfo, fmt.Sprintf("No %s %s the cursor", r, where))
+		return m, nil
+	}
+
+	m.view = ViewMain
+	m.setCursor(msg.start)
+	m.setStatus(sevInfo, fmt.Sprintf("%s at 0x%X–0x%X (%d bytes)", r, msg.start, msg.start+msg.length-1, msg.length))
+	return m, nil
+}
+
+func (m *Model) renderByteRange() string {
+	var b strings.Builder
+	b.WriteString("\nFIND BYTE RANGE\n")
+	b.WriteString("===============\n\n")
+	b.WriteString("Range: ")
+	b.WriteString(m.byteRangeInput)
+	b.WriteString("_\n\n")
+	b.WriteString("A hex byte or range, optionally preceded by \"not\" and followed by a minimum length:\n")
+	b.WriteString("\"20-7E 16\" finds 16 or more printable bytes, \"not 00\" the next non-zero byte\n")
+	if m.byteRangeScanning {
+		b.WriteString("\nScanning…\n")
+	}
+	b.WriteString("\nPress Enter or ↓ for the next match, ↑ for the previous, ESC to close\n")
+
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func byteRangeScan(t *testing.T, m *Model, key tea.KeyType) {
+	t.Helper()
+	_, cmd := m.handleByteRangeKey(tea.KeyMsg{Type: key})
+	if cmd == nil {
+		t.Fatalf("expected a scan to start, status %q", m.status.text)
+	}
+	m.Update(cmd())
+}
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  byteRange
+	}{
+		{"20-7E", byteRange{lo: 0x20, hi: 0x7E, minLen: 1}},
+		{"not 00", byteRange{lo: 0, hi: 0, not: true, minLen: 1}},
+		{"0x80-0xff 4", byteRange{lo: 0x80, hi: 0xFF, minLen: 4}},
+	}
+	for _, tt := range tests {
+		if got, err := parseByteRange(tt.input); err != nil || got != tt.want {
+			t.Errorf("%q: got %+v, %v", tt.input, got, err)
+		}
+	}
+	for _, input := range []string{"", "not", "7E-20", "100", "20-7E 0", "20 7E 3"} {
+		if _, err := parseByteRange(input); err == nil {
+			t.Errorf("%q should be rejected", input)
+		}
+	}
+}
+
+func TestByteRangeDialogNavigates(t *testing.T) {
+	data := make([]byte, 64)
+	copy(data[4:], "ab")
+	copy(data[20:], "hello")
+	data[40] = 0xF0
+	m := newTestModel(data)
+	tab := m.currentTab()
+
+	typeKeys(m, "y20-7e 3")
+	byteRangeScan(t, m, tea.KeyEnter)
+	if tab.Cursor != 20 || m.view != ViewMain {
+		t.Fatalf("expected the 3+ byte text at 20, got %d", tab.Cursor)
+	}
+
+	typeKeys(m, "y")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeKeys(m, "not 0")
+	byteRangeScan(t, m, tea.KeyDown)
+	if tab.Cursor != 40 {
+		t.Errorf("expected the next non-zero byte at 40, got %d", tab.Cursor)
+	}
+	typeKeys(m, "y")
+	byteRangeScan(t, m, tea.KeyUp)
+	if tab.Cursor != 20 {
+		t.Errorf("expected the previous non-zero span at 20, got %d", tab.Cursor)
+	}
+	typeKeys(m, "y")
+	byteRangeScan(t, m, tea.KeyUp)
+	if tab.Cursor != 4 {
+		t.Errorf("expected the previous non-zero span at 4, got %d", tab.Cursor)
+	}
+	typeKeys(m, "y")
+	byteRangeScan(t, m, tea.KeyUp)
+	if m.view != ViewByteRange || tab.Cursor != 4 {
+		t.Errorf("nothing before 4 should leave the dialog open, cursor at %d", tab.Cursor)
+	}
+}
@@ -0,0 +1,337 @@
+package editor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/checksum"
+	"github.com/protohuf/unhexed/internal/sidecar"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The checksum form (K) computes a checksum over the selection and writes
+// it at a destination offset, or verifies the value already there.
+// Definitions can be saved to the file's sidecar, after which Ctrl+K
+// rewrites all of them in one step.
+
+type checksumForm struct {
+	tab       *Tab
+	start     int64
+	end       int64
+	algo      int
+	dest      string
+	bigEndian bool
+	focus     int // checksumField*
+	button    int // checksumButton*
+}
+
+const (
+	checksumFieldAlgo = iota
+	checksumFieldDest
+	checksumFieldOrder
+	checksumFieldButtons
+)
+
+const (
+	checksumButtonWrite = iota
+	checksumButtonVerify
+	checksumButtonSave
+)
+
+var checksumButtons = []string{"Write", "Verify", "Save definition"}
+
+func (m *Model) openChecksum() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if !tab.Selection.Active || tab.Selection.Block {
+		m.setStatus(sevWarning, "Select the region to checksum first")
+		return
+	}
+	start, end := m.getSelectedRange()
+	m.checksum = &checksumForm{
+		tab:       tab,
+		start:     start,
+		end:       min(end, tab.Buffer.Size()-1),
+		bigEndian: m.bigEndian,
+		focus:     checksumFieldDest,
+	}
+	m.view = ViewChecksum
+}
+
+// definition returns the form as a checksum definition.
+func (f *checksumForm) definition() (sidecar.Checksum, error) {
+	dest, err := parseOffset(f.dest)
+	if err != nil {
+		return sidecar.Checksum{}, fmt.Errorf("invalid destination offset %q", f.dest)
+	}
+	return sidecar.Checksum{
+		Algorithm: checksum.Algorithms[f.algo].Name,
+		Start:     f.start,
+		End:       f.end,
+		Dest:      dest,
+		BigEndian: f.bigEndian,
+	}, nil
+}
+
+// checksumValues computes c over the buffer and returns the computed and
+// currently stored values.
+func checksumValues(tab *Tab, c sidecar.Checksum) (computed, stored uint64, algo checksum.Algorithm, err error) {
+	algo, ok := checksum.Lookup(c.Algorithm)
+	if !ok {
+		return 0, 0, algo, fmt.Errorf("unknown checksum %q", c.Algorithm)
+	}
+	size := tab.Buffer.Size()
+	if c.Start < 0 || c.End < c.Start || c.End >= size {
+		return 0, 0, algo, fmt.Errorf("region 0x%X–0x%X is outside the file", c.Start, c.End)
+	}
+	if c.Dest < 0 || c.Dest+int64(algo.Width) > size {
+		return 0, 0, algo, fmt.Errorf("%s needs %d bytes at 0x%X, past the end of the file", algo.Name, algo.Width, c.Dest)
+	}
+
+	s := algo.New()
+	tab.Buffer.Chunks(c.Start, c.End+1, jobChunk, func(data []byte) error {
+		s.Write(data)
+		return nil
+	})
+	stored = decodeUint(tab.Buffer.GetBytes(c.Dest, algo.Width), c.BigEndian)
+	return s.Sum(), stored, algo, nil
+}
+
+func encodeUint(v uint64, width int, bigEndian bool) []byte {
+	data := make([]byte, 8)
+	if bigEndian {
+		binary.BigEndian.PutUint64(data, v)
+		return data[8-width:]
+	}
+	binary.LittleEndian.PutUint64(data, v)
+	return data[:width]
+}
+
+func decodeUint(data []byte, bigEndian bool) uint64 {
+	var v uint64
+	for i := range data {
+		b := data[i]
+		if !bigEndian {
+			b = data[len(data)-1-i]
+		}
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+// writeChecksum computes c and writes it, returning a report of old vs
+// new value.
+func writeChecksum(tab *Tab, c sidecar.Checksum) (string, error) {
+	computed, stored, algo, err := checksumValues(tab, c)
+	if err != nil {
+		return "", err
+	}
+	digits := algo.Width * 2
+	if computed == stored {
+		return fmt.Sprintf("%s at 0x%X already up to date (0x%0*X)", algo.Name, c.Dest, digits, computed), nil
+	}
+	if err := tab.Buffer.ReplaceBytes(c.Dest, encodeUint(computed, algo.Width, c.BigEndian)); err != nil {
+		return "", fmt.Errorf("%s at 0x%X: %w", algo.Name, c.Dest, err)
+	}
+	return fmt.Sprintf("%s at 0x%X: 0x%0*X → 0x%0*X", algo.Name, c.Dest, digits, stored, digits, computed), nil
+}
+
+func (m *Model) handleChecksumKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.checksum
+	if f == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.checksum = nil
+		m.view = ViewMain
+	case "up", "shift+tab":
+		f.focus = (f.focus + checksumFieldButtons) % (checksumFieldButtons + 1)
+	case "down", "tab":
+		f.focus = (f.focus + 1) % (checksumFieldButtons + 1)
+	case "left", "right":
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		switch f.focus {
+		case checksumFieldAlgo:
+			n := len(checksum.Algorithms)
+			f.algo = (f.algo + step + n) % n
+		case checksumFieldOrder:
+			f.bigEndian = !f.bigEndian
+		case checksumFieldButtons:
+			n := len(checksumButtons)
+			f.button = (f.button + step + n) % n
+		}
+	case "backspace":
+		if f.focus == checksumFieldDest && len(f.dest) > 0 {
+			f.dest = f.dest[:len(f.dest)-1]
+		}
+	case "enter":
+		button := checksumButtonWrite
+		if f.focus == checksumFieldButtons {
+			button = f.button
+		}
+		m.runChecksumButton(button)
+	default:
+		char := msg.String()
+		if f.focus == checksumFieldDest && (isHexChar(char) || char == "x" || char == "X") {
+			f.dest += char
+		}
+	}
+	return m, nil
+}
+
+// runChecksumButton carries out a form action. Errors keep the form open.
+func (m *Model) runChecksumButton(button int) {
+	f := m.checksum
+	c, err := f.definition()
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return
+	}
+
+	switch button {
+	case checksumButtonWrite:
+		if m.editBlocked(f.tab) {
+			return
+		}
+		report, err := writeChecksum(f.tab, c)
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return
+		}
+		m.setStatus(sevInfo, report)
+	case checksumButtonVerify:
+		computed, stored, algo, err := checksumValues(f.tab, c)
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return
+		}
+		digits := algo.Width * 2
+		if computed == stored {
+			m.setStatus(sevInfo, fmt.Sprintf("%s at 0x%X matches (0x%0*X)", algo.Name, c.Dest, digits, computed))
+		} else {
+			m.setStatus(sevWarning, fmt.Sprintf("%s at 0x%X mismatch: stored 0x%0*X, computed 0x%0*X", algo.Name, c.Dest, digits, stored, digits, computed))
+		}
+	case checksumButtonSave:
+		if !m.saveChecksumDefinition(f.tab, c) {
+			return
+		}
+	}
+	m.checksum = nil
+	m.view = ViewMain
+}
+
+func (m *Model) saveChecksumDefinition(tab *Tab, c sidecar.Checksum) bool {
+	path := tab.Buffer.Filename()
+	if path == "" || tab.Buffer.IsNew() {
+		m.setStatus(sevWarning, "Save the file before saving checksum definitions")
+		return false
+	}
+	sc, err := sidecar.Load(path)
+	if err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Reading %s: %v", sidecar.Path(path), err))
+		return false
+	}
+	sc.AddChecksum(c)
+	if err := sc.Save(path); err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Writing %s: %v", sidecar.Path(path), err))
+		return false
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Saved %s definition to %s (Ctrl+K to rerun)", c.Algorithm, filepath.Base(sidecar.Path(path))))
+	return true
+}
+
+// rerunChecksums rewrites every checksum saved for the current file as a
+// single undo step.
+func (m *Model) rerunChecksums() {
+	tab := m.currentTab()
+	if tab == nil || m.editBlocked(tab) {
+		return
+	}
+	path := tab.Buffer.Filename()
+	if path == "" || tab.Buffer.IsNew() {
+		m.setStatus(sevWarning, "No checksum definitions for an unsaved file")
+		return
+	}
+	sc, err := sidecar.Load(path)
+	if err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Reading %s: %v", sidecar.Path(path), err))
+		return
+	}
+	if len(sc.Checksums) == 0 {
+		m.setStatus(sevWarning, "No checksum definitions saved for this file (K to add one)")
+		return
+	}
+
+	var reports []string
+	failed := false
+	tab.Buffer.BeginGroup()
+	for _, c := range sc.Checksums {
+		report, err := writeChecksum(tab, c)
+		if err != nil {
+			report, failed = err.Error(), true
+		}
+		reports = append(reports, report)
+	}
+	tab.Buffer.EndGroup()
+
+	sev := sevInfo
+	if failed {
+		sev = sevWarning
+	}
+	m.setStatus(sev, strings.Join(reports, "; "))
+}
+
+func (m *Model) renderChecksum() string {
+	f := m.checksum
+	if f == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nCHECKSUM\n")
+	b.WriteString("========\n\n")
+	b.WriteString(fmt.Sprintf("  Region:       0x%X–0x%X (%d bytes)\n\n", f.start, f.end, f.end-f.start+1))
+
+	field := func(i int, label, value string) {
+		prefix := "  "
+		if f.focus == i {
+			prefix = "> "
+		}
+		b.WriteString(fmt.Sprintf("%s%-13s %s\n", prefix, label, value))
+	}
+	field(checksumFieldAlgo, "Algorithm:", "◂ "+checksum.Algorithms[f.algo].Name+" ▸")
+	dest := f.dest
+	if f.focus == checksumFieldDest {
+		dest += "_"
+	}
+	field(checksumFieldDest, "Destination:", dest)
+	order := "Little endian"
+	if f.bigEndian {
+		order = "Big endian"
+	}
+	field(checksumFieldOrder, "Byte order:", "◂ "+order+" ▸")
+
+	var buttons []string
+	for i, label := range checksumButtons {
+		label = "[ " + label + " ]"
+		if f.focus == checksumFieldButtons && i == f.button {
+			label = m.styles.Legend.Render(label)
+		}
+		buttons = append(buttons, label)
+	}
+	b.WriteString("\n  " + strings.Join(buttons, "  ") + "\n")
+	b.WriteString("\n↑/↓ to choose a field, ←/→ to change it, Enter to write, ESC to cancel\n")
+	b.WriteString("(Destination: decimal, or hex with 0x)\n")
+	return b.String()
+}
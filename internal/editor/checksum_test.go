@@ -0,0 +1,129 @@
+package editor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/sidecar"
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func selectRange(tab *Tab, start, end int64) {
+	tab.Selection.Active = true
+	tab.Selection.Block = false
+	tab.Selection.Start, tab.Selection.End = start, end
+}
+
+func TestChecksumWriteAndVerify(t *testing.T) {
+	m := newTestModel(append([]byte("123456789"), 0, 0, 0, 0))
+	tab := m.currentTab()
+
+	typeKeys(m, "k")
+	if m.view == ViewChecksum {
+		t.Fatal("K without a selection should not open the form")
+	}
+
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k")
+	if m.view != ViewChecksum {
+		t.Fatal("expected the checksum form")
+	}
+	typeKeys(m, "0x9")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain {
+		t.Fatalf("write should close the form, status %q", m.status.text)
+	}
+	if got := tab.Buffer.GetBytes(9, 4); !bytes.Equal(got, []byte{0xCB, 0xF4, 0x39, 0x26}) {
+		t.Fatalf("CRC32 written as % X", got)
+	}
+
+	// Verify it, then break it and verify again
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k9")
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyRight, tea.KeyEnter)
+	if !strings.Contains(m.status.text, "matches") {
+		t.Errorf("expected a match, got %q", m.status.text)
+	}
+	tab.Buffer.Replace(0, '0')
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k9")
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyRight, tea.KeyEnter)
+	if !strings.Contains(m.status.text, "mismatch") || m.status.sev != sevWarning {
+		t.Errorf("expected a mismatch warning, got %q", m.status.text)
+	}
+
+	// A destination past the end keeps the form open
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k11")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewChecksum || !strings.Contains(m.status.text, "past the end") {
+		t.Errorf("expected an error with the form open, got view %v status %q", m.view, m.status.text)
+	}
+}
+
+func TestChecksumBigEndianSixteenBit(t *testing.T) {
+	m := newTestModel(append([]byte("123456789"), 0, 0))
+	tab := m.currentTab()
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k9")
+	// CRC16/CCITT-FALSE, big endian
+	press(m, tea.KeyUp, tea.KeyRight, tea.KeyDown, tea.KeyDown)
+	if !m.checksum.bigEndian {
+		press(m, tea.KeyRight)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := tab.Buffer.GetBytes(9, 2); !bytes.Equal(got, []byte{0x29, 0xB1}) {
+		t.Fatalf("CRC16/CCITT-FALSE written as % X (%s)", got, m.status.text)
+	}
+}
+
+func TestChecksumSidecarRerun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.bin")
+	if err := os.WriteFile(path, append([]byte("123456789"), 0, 0, 0, 0), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := buffer.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	tab := m.newTab(buf)
+	m.tabs = append(m.tabs, tab)
+	m.selectTab(len(m.tabs) - 1)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if !strings.Contains(m.status.text, "No checksum definitions") {
+		t.Errorf("expected a warning without definitions, got %q", m.status.text)
+	}
+
+	selectRange(tab, 0, 8)
+	typeKeys(m, "k9")
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyRight, tea.KeyRight, tea.KeyEnter)
+	sc, err := sidecar.Load(path)
+	if err != nil || len(sc.Checksums) != 1 || sc.Checksums[0].Dest != 9 {
+		t.Fatalf("sidecar not saved: %+v, %v", sc, err)
+	}
+	if tab.Buffer.IsModified() {
+		t.Error("saving a definition should not touch the buffer")
+	}
+
+	tab.Buffer.Replace(0, '0')
+	before := tab.Buffer.Data()
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	c, _, _, err := checksumValues(tab, sc.Checksums[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeUint(tab.Buffer.GetBytes(9, 4), sc.Checksums[0].BigEndian); got != c {
+		t.Fatalf("Ctrl+K wrote 0x%X, want 0x%X", got, c)
+	}
+	tab.Buffer.Undo()
+	if !bytes.Equal(tab.Buffer.Data(), before) {
+		t.Error("rerunning the checksums should be one undo step")
+	}
+}
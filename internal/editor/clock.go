@@ -0,0 +1,24 @@
+package editor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clock tells the editor the time and schedules its timers: status
+// messages expiring, autosave, polling the config file. A Clock that only
+// fires timers when told to makes a run repeatable (see Drive).
+type Clock interface {
+	Now() time.Time
+	// Tick returns a command delivering fn's message after d.
+	Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	return tea.Tick(d, fn)
+}
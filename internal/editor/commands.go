@@ -0,0 +1,255 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The command registry lists every key the main view understands. The help
+// screen is generated from it, and commands with a legend label are offered
+// in the legend, so a new command is added in one place.
+
+type command struct {
+	keys string // as listed in help
+	help string
+
+	// legend labels the command in the legend, with the key letter at
+	// index hl highlighted; with hl < 0 the short key name is shown,
+	// highlighted, before the label. Commands without a label are only
+	// listed in help.
+	legend   string
+	hl       int
+	short    string
+	key      string // the key pressed; hex digits are typed as data in insert/replace
+	action   string // a rebindable command (see keymap.go), listed with its bound keys
+	register bool   // uses the register named with the " prefix
+	repeat   string // names the edit when "." can repeat it (see repeat.go)
+	priority int    // lower survives a narrow legend longer
+	enabled  func(m *Model, tab *Tab) bool
+}
+
+type commandSection struct {
+	title    string
+	commands []command
+}
+
+func canUndo(m *Model, tab *Tab) bool { return tab != nil && tab.Buffer.CanUndo() }
+func canRedo(m *Model, tab *Tab) bool { return tab != nil && tab.Buffer.CanRedo() }
+
+var commandSections = []commandSection{
+	{"NAVIGATION", []command{
+		{keys: "Arrow keys", help: "Move cursor"},
+		{keys: "<count><key>", help: "Repeat a motion or delete, e.g. 32 Right, 4 PgDown"},
+		{keys: "Shift+Arrows", help: "Select bytes"},
+		{keys: "B", help: "Toggle column (block) selection"},
+		{keys: "Alt+R", help: "Reselect the last selection (adjusted for edits since)"},
+		{keys: "Alt+Z", help: "Fold runs of zero rows, or of rows without unsaved changes (Enter expands a fold)"},
+		{keys: "PgUp/PgDown", help: "Page up/down"},
+		{keys: "Home/End", help: "Start/end of line (Shift to select)"},
+		{keys: "Ctrl+Home/End", help: "Start/end of file (Shift to select)"},
+		{keys: "Ctrl+Left/Right", help: "Previous/next switch between text and binary (Shift to select)"},
+		{keys: "Ctrl+Up/Down", help: "Previous/next null-terminated string (Shift to select)"},
+	}},
+	{"FILE OPERATIONS", []command{
+		{keys: "O", help: "Open file", legend: "Open", key: "o", priority: 2},
+		{keys: "S / Ctrl+S", help: "Save file", legend: "Save", key: "s", priority: 1},
+		{keys: "A", help: "Save As", legend: "sAve As", hl: 1, key: "a", priority: 5},
+		{keys: "Ctrl+D", help: "Compare with the file on disk (what saving changes)"},
+		{keys: "N", help: "New file", legend: "New", key: "n", priority: 5},
+		{keys: "Ctrl+W", help: "Close tab"},
+		{keys: "V", help: "Duplicate view (second tab on the same buffer)"},
+		{keys: "Ctrl+N", help: "Duplicate buffer (new unsaved copy of the contents)"},
+		{keys: "TAB", help: "Next tab", legend: "Next tab", hl: -1, short: "TAB", key: "tab", priority: 5},
+		{keys: "Shift+TAB", help: "Previous tab"},
+		{keys: "Alt+S", help: "Split: show the next tab below this one, scrolling together (again to close)"},
+		{keys: "Alt+W", help: "Switch to the other pane of the split"},
+		{keys: "Alt+Y", help: "Unlink or relink the split's scrolling (relinking keeps the panes' current offset apart)"},
+	}},
+	{"EDITING", []command{
+		{keys: "I", help: "Enter Insert mode", legend: "Insert", key: "i", priority: 3, repeat: "typed byte"},
+		{keys: "R", help: "Enter Replace mode (8R advances 8 bytes after each byte)", legend: "Replace", key: "r", priority: 3},
+		{keys: "Alt+A", help: "Enter Replace mode advancing a given number of bytes after each byte"},
+		{keys: "ESC", help: "Exit Insert/Replace mode, then clear selection/count"},
+		{keys: "Alt+K", help: "Lock the selection against edits, kept in the file's sidecar (in a locked range: unlock it)"},
+		{keys: "Ctrl+T", help: "Switch between typing hex and typing text in Insert/Replace mode", repeat: "typed text"},
+		{keys: "Ctrl+X", help: "Cut", legend: "Cut", hl: -1, short: "^X", key: "ctrl+x", priority: 4, register: true},
+		{keys: "Ctrl+C", help: "Copy", legend: "Copy", hl: -1, short: "^C", key: "ctrl+c", priority: 4, register: true},
+		{keys: "Ctrl+V", help: "Paste (column copies paste back as a rectangle)", legend: "Paste", hl: -1, short: "^V", key: "ctrl+v", priority: 4, repeat: "paste", register: true},
+		{keys: "Alt+C", help: "Copy the selected 1/2/4/8 bytes as a number"},
+		{keys: "Alt+V", help: "Paste the copied number (2 Alt+V as a u16, 4 as a u32...)", repeat: "pasted value"},
+		{keys: "Alt+E", help: "Export the selection as a CSV/TSV table of u8-u64, i8-i64, f32 or f64 values"},
+		{keys: "Alt+I", help: "Import a CSV of numbers at the cursor as u8-u64, i8-i64, f32 or f64 values"},
+		{keys: "L", help: "Fill selection with a byte", repeat: "fill"},
+		{keys: "Alt+L", help: "Fill a rectangle from the cursor: width, height, pitch and byte (bitmaps)"},
+		{keys: "=", help: "Enter a value (200, 'Z', u16:1000) at the cursor", repeat: "value"},
+		{keys: "~", help: "Swap the nibbles of the selection or cursor byte", repeat: "nibble swap"},
+		{keys: "|", help: "Pad with a fill byte up to a boundary, at the cursor or after the selection", repeat: "padding"},
+		{keys: "#", help: "Resize the file to an exact size (extend with a fill byte or truncate)"},
+		{keys: `"<a-z>`, help: "Use a named register for the next cut/copy/paste (lists the keys that can follow)"},
+		{keys: "Ctrl+R", help: "List registers"},
+		{keys: "Delete", help: "Delete byte at cursor", repeat: "delete"},
+		{keys: "Backspace", help: "Delete byte before cursor"},
+		{keys: ".", key: "."}, // help lists the repeatable edits
+		{keys: "U", help: "Undo", legend: "Undo", key: "u", action: actionUndo, priority: 1, enabled: canUndo},
+		{keys: "D", help: "Redo", legend: "reDo", hl: 2, key: "d", action: actionRedo, priority: 1, enabled: canRedo},
+	}},
+	{"OTHER", []command{
+		{keys: "F", help: "Find (Tab there reverses the direction, Ctrl+L finds aligned matches only, Ctrl+A finds and replaces in all tabs)", legend: "Find", key: "f", priority: 2},
+		{keys: "*", help: "Find the value at the cursor (u32, or a count of 2/4/8 bytes)"},
+		{keys: "Alt+F", help: "List every offset where a number is stored, e.g. u32 0x1000, in either byte order"},
+		{keys: "]", help: "Select up to the next match of the last search"},
+		{keys: "}", help: "Select through the next match"},
+		{keys: "P", help: "Find runs of a repeated byte (padding, slack space)", legend: "Runs", hl: -1, short: "P", key: "p", priority: 8},
+		{keys: "Y", help: "Find bytes in a value range, e.g. 20-7E 16 or not 00", legend: "Range", hl: -1, short: "Y", key: "y", priority: 8},
+		{keys: "( / )", help: "Previous/next text region (printable bytes)"},
+		{keys: "- / +", help: "Previous/next unsaved change (also marked next to the offsets)"},
+		{keys: "Alt+P / Alt+N", help: "Previous/next difference from the snapshot picked with N in the snapshots view"},
+		{keys: "G", help: "Goto offset", legend: "Goto", key: "g", priority: 2},
+		{keys: "E", help: "Toggle endianness", legend: "Endian", key: "e", priority: 5},
+		{keys: "M", help: "Message log", legend: "Msgs", key: "m", priority: 7},
+		{keys: "Ctrl+O", help: "Allow or stop writing to a device or a file opened read-only"},
+		{keys: "Ctrl+L", help: "Scroll the cursor row to the middle of the screen"},
+		{keys: "Ctrl+G", help: "File properties (path, sizes, owner, hashes, edits; R there reverts)", legend: "Props", hl: -1, short: "^G", key: "ctrl+g", priority: 7},
+		{keys: "'", help: "Marks: label ranges, jump to them, export or import them as JSON"},
+		{keys: "Z", help: "Snapshots: take, restore or diff against one", legend: "Snapshots", hl: -1, short: "Z", key: "z", priority: 7},
+		{keys: "K", help: "Checksum the selection and write or verify it", legend: "Checksum", hl: -1, short: "K", key: "k", priority: 8},
+		{keys: "Ctrl+K", help: "Rewrite the checksums saved for this file"},
+		{keys: "T", help: "Toggle UTF-8 text column (per tab)"},
+		{keys: "W", help: "Hex column as bytes, u16, u32 or u64 values, or nibbles (per tab)"},
+		{keys: "J", help: "Bitfields: name and set the bits of the value at the cursor", legend: "Bitfields", hl: -1, short: "J", key: "j", priority: 8},
+		{keys: "Alt+J", help: "Follow the first pointer field of the applied template (Enter on one in the bitfield view follows it)"},
+		{keys: "Alt+O", help: "Go back to where the last pointer was followed from"},
+		{keys: "X", help: "Highlight bytes equal to the one under the cursor"},
+		{keys: "Alt+B", help: "Show the cursor byte in hex, decimal, text, binary and octal on the status line"},
+		{keys: "Alt+D", help: "Write a diagnostic report of the window, settings and view for a bug report (unhexed --replay reads it)"},
+		{keys: "Ctrl+_", help: "Suspend to the shell (fg resumes)", action: actionSuspend},
+		{keys: "?", help: "List the last commands used (any key closes the list and still runs)"},
+		{keys: "H", help: "Help (this screen)", legend: "Help", key: "h"},
+		{keys: "C", help: "Configuration", legend: "Config", key: "c", priority: 6},
+		{keys: "Q", help: "Quit", legend: "Quit", key: "q"},
+	}},
+}
+
+func (m *Model) renderHelp() string {
+	var b strings.Builder
+	b.WriteString("\nHELP - Unhexed Hex Editor\n")
+	b.WriteString("========================\n")
+	for _, section := range commandSections {
+		b.WriteString("\n" + section.title + "\n")
+		for _, c := range section.commands {
+			keys := c.keys
+			if c.action != "" {
+				keys = keyNames(m.actionKeys(c.action))
+			}
+			help := c.help
+			if c.key == "." {
+				help = repeatHelp()
+			}
+			b.WriteString("  " + padRight(keys, 16) + help + "\n")
+		}
+	}
+	b.WriteString("\nPress ESC or H to close this help screen.\n")
+	return b.String()
+}
+
+func padRight(s string, n int) string {
+	return s + strings.Repeat(" ", max(n-lipgloss.Width(s), 1))
+}
+
+type legendItem struct {
+	text     string
+	priority int
+}
+
+// renderLegendItem renders label with the rune at hl highlighted.
+func (m *Model) renderLegendItem(label string, hl int) string {
+	var b strings.Builder
+	for i, ch := range []rune(label) {
+		if i == hl {
+			b.WriteString(m.styles.LegendHighlight.Render(string(ch)))
+		} else {
+			b.WriteString(m.styles.Legend.Render(string(ch)))
+		}
+	}
+	return b.String()
+}
+
+// mainLegendItems lists the legend entries for the main view in the
+// current mode, most relevant first.
+func (m *Model) mainLegendItems() []legendItem {
+	tab := m.currentTab()
+	hexInput := m.mode == ModeInsert || m.mode == ModeReplace
+	// Typing text leaves no printable key for commands
+	textInput := hexInput && tab != nil && tab.TextFocus
+
+	var items []legendItem
+	if hexInput {
+		exit, keys, hint, pane := " Exit insert", "0-F", " Type hex", " Text"
+		if m.mode == ModeReplace {
+			exit = " Exit replace"
+		}
+		if m.hexNibble == 1 {
+			hint = " Low nibble"
+		}
+		if textInput {
+			keys, hint, pane = "A-z", " Type text", " Hex"
+		}
+		items = append(items,
+			legendItem{m.styles.LegendHighlight.Render("ESC") + m.styles.Legend.Render(exit), 0},
+			legendItem{m.styles.LegendHighlight.Render(keys) + m.styles.Legend.Render(hint), 0},
+			legendItem{m.styles.LegendHighlight.Render("^T") + m.styles.Legend.Render(pane), 0})
+	}
+	for _, section := range commandSections {
+		for _, c := range section.commands {
+			if c.legend == "" || hexInput && isHexChar(c.key) || textInput && utf8.RuneCountInString(c.key) == 1 {
+				continue
+			}
+			var text string
+			switch {
+			case c.enabled != nil && !c.enabled(m, tab):
+				text = m.styles.Disabled.Render(c.legend)
+			case c.hl < 0:
+				text = m.styles.LegendHighlight.Render(c.short) + m.styles.Legend.Render(" "+c.legend)
+			default:
+				text = m.renderLegendItem(c.legend, c.hl)
+			}
+			items = append(items, legendItem{text, c.priority})
+		}
+	}
+	if m.registerPrefix || m.pendingRegister != 0 {
+		items = append(items, legendItem{m.styles.LegendHighlight.Render("Reg " + registerName(m.pendingRegister)), 0})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].priority < items[j].priority })
+	return items
+}
+
+// fitLegend joins as many items as fit in width, in order, ending with a
+// pointer to help when some are left out.
+func (m *Model) fitLegend(items []legendItem, width int) string {
+	sep := m.styles.Legend.Render(" | ")
+	more := m.styles.Legend.Render("… more (") + m.styles.LegendHighlight.Render("H") + m.styles.Legend.Render(")")
+
+	var kept []string
+	used := 0
+	for i, item := range items {
+		w := lipgloss.Width(item.text)
+		if used > 0 {
+			w += lipgloss.Width(sep)
+		}
+		// Room for the tail is needed unless this is the last item
+		reserve := 0
+		if i < len(items)-1 {
+			reserve = lipgloss.Width(sep) + lipgloss.Width(more)
+		}
+		if used+w+reserve > width {
+			kept = append(kept, more)
+			break
+		}
+		kept = append(kept, item.text)
+		used += w
+	}
+	return strings.Join(kept, sep)
+}
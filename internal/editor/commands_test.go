@@ -0,0 +1,63 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLegendFitsNarrowTerminals(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	for _, width := range []int{30, 60, 100, 160} {
+		m.width = width
+		legend := m.renderLegend()
+		if w := lipgloss.Width(legend); w > width {
+			t.Errorf("width %d: legend is %d wide", width, w)
+		}
+		if !strings.HasPrefix(legend, "Help | Quit") {
+			t.Errorf("width %d: help and quit should come first: %q", width, legend)
+		}
+	}
+
+	m.width = 60
+	if legend := m.renderLegend(); !strings.Contains(legend, "… more (H)") || strings.Contains(legend, "Bitfields") {
+		t.Errorf("a narrow legend should drop the least relevant items: %q", legend)
+	}
+	m.width = 400
+	if legend := m.renderLegend(); strings.Contains(legend, "more (H)") || !strings.Contains(legend, "J Bitfields") {
+		t.Errorf("a wide legend should list every command: %q", legend)
+	}
+}
+
+func TestLegendInHexInputModes(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0x11})
+	m.width = 400
+
+	typeKeys(m, "r")
+	legend := m.renderLegend()
+	if !strings.Contains(legend, "ESC Exit replace") || !strings.Contains(legend, "0-F Type hex") {
+		t.Errorf("replace mode should say how to leave it: %q", legend)
+	}
+	for _, hexLetter := range []string{"Endian", "Find", "Config", "reDo"} {
+		if strings.Contains(legend, hexLetter) {
+			t.Errorf("%s is typed as a hex digit in replace mode: %q", hexLetter, legend)
+		}
+	}
+
+	typeKeys(m, "4")
+	if legend := m.renderLegend(); !strings.Contains(legend, "0-F Low nibble") {
+		t.Errorf("expected the pending nibble hint: %q", legend)
+	}
+}
+
+func TestHelpListsEveryCommand(t *testing.T) {
+	help := newTestModel(nil).renderHelp()
+	for _, section := range commandSections {
+		for _, c := range section.commands {
+			if !strings.Contains(help, "  "+c.keys+" ") || !strings.Contains(help, c.help) {
+				t.Errorf("help is missing %s", c.keys)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"os"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The config file is polled for changes made outside the editor, e.g.
+// while tweaking a theme in another terminal. A changed file is reloaded
+// whole: styles are rebuilt and behavior settings take effect on the next
+// frame, since they are read as they are used. Theme values being edited
+// in the Config view win over the file.
+
+const configPollInterval = 2 * time.Second
+
+type configPollMsg struct{}
+
+func configModTime() time.Time {
+	info, err := os.Stat(config.ConfigPath())
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (m *Model) pollConfig() tea.Cmd {
+	return m.clock.Tick(configPollInterval, func(time.Time) tea.Msg {
+		return configPollMsg{}
+	})
+}
+
+func (m *Model) handleConfigPoll() (tea.Model, tea.Cmd) {
+	m.reloadConfigIfChanged()
+	return m, m.pollConfig()
+}
+
+func (m *Model) reloadConfigIfChanged() {
+	if m.configFixed {
+		return
+	}
+	mtime := configModTime()
+	if mtime.Equal(m.configMtime) {
+		return
+	}
+	m.configMtime = mtime
+	if mtime.IsZero() {
+		// Deleted; keep the settings in use
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		m.setStatus(sevError, "Config not reloaded: "+err.Error())
+		return
+	}
+	m.config = cfg
+	// Unsaved edits stay in the inputs and are saved over the new file
+	if m.view == ViewConfig && !m.configChanged {
+		index := m.configIndex
+		m.loadConfigInputs()
+		m.configIndex = index
+	}
+	m.buildStyles()
+	m.setStatus(sevInfo, "Config reloaded")
+}
@@ -0,0 +1,67 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/config"
+)
+
+func writeConfig(t *testing.T, content string, mtime time.Time) {
+	t.Helper()
+	path := config.ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Explicit times, since writes within one test may share an mtime
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigReloadsWhenChanged(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newTestModel(nil)
+	start := time.Now().Add(-time.Hour)
+
+	writeConfig(t, "[behavior]\nscroll_off = 7\n[theme]\nbackground = \"#112233\"\n", start)
+	styles := m.styles
+	m.reloadConfigIfChanged()
+	if m.config.Behavior.ScrollOff != 7 || m.config.Theme.Background != "#112233" {
+		t.Fatalf("config not applied: %+v", m.config.Behavior)
+	}
+	if m.styles == styles || m.status.text != "Config reloaded" {
+		t.Errorf("styles should be rebuilt and the reload reported, status %q", m.status.text)
+	}
+
+	m.setStatus(sevInfo, "")
+	m.reloadConfigIfChanged()
+	if m.status.text != "" {
+		t.Error("an unchanged file should not reload")
+	}
+
+	writeConfig(t, "[behavior\n", start.Add(time.Minute))
+	m.reloadConfigIfChanged()
+	if m.status.sev != sevError || !strings.Contains(m.status.text, "Config not reloaded") {
+		t.Errorf("a parse error should be reported, got %q", m.status.text)
+	}
+	if m.config.Behavior.ScrollOff != 7 {
+		t.Error("a broken file should keep the settings in use")
+	}
+
+	// Edits in progress in the Config view survive a reload
+	typeKeys(m, "c")
+	m.configInputs["background"] = "#ABCDEF"
+	m.configChanged = true
+	writeConfig(t, "[theme]\nbackground = \"#445566\"\n", start.Add(2*time.Minute))
+	m.reloadConfigIfChanged()
+	if m.config.Theme.Background != "#445566" || m.configInputs["background"] != "#ABCDEF" {
+		t.Errorf("got theme %q, input %q", m.config.Theme.Background, m.configInputs["background"])
+	}
+}
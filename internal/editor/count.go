@@ -0,0 +1,34 @@
+package editor
+
+import "fmt"
+
+const maxCount = 1 << 30
+
+// handleCountDigit accumulates a vim-style count prefix in normal mode.
+// It reports whether the key was consumed as part of the count.
+func (m *Model) handleCountDigit(key string) bool {
+	if m.mode != ModeNormal || len(key) != 1 || key[0] < '0' || key[0] > '9' {
+		return false
+	}
+	// A leading zero is not a count
+	if key == "0" && m.pendingCount == 0 {
+		return false
+	}
+
+	m.pendingCount = m.pendingCount*10 + int64(key[0]-'0')
+	if m.pendingCount > maxCount {
+		m.pendingCount = maxCount
+	}
+	m.showStatus(sevInfo, fmt.Sprintf("Count: %d", m.pendingCount))
+	return true
+}
+
+// takeCount returns the pending count (1 if none) and clears it.
+func (m *Model) takeCount() int64 {
+	count := m.pendingCount
+	m.pendingCount = 0
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
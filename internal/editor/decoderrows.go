@@ -0,0 +1,123 @@
+package editor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Optional decoder rows, turned on in the [decoder] section of the config,
+// for encodings the fixed rows don't cover. Both read forward from the
+// cursor whatever the endianness, as the formats using them are
+// big-endian: time2000 is a u32 of seconds since 2000-01-01 UTC, and the
+// Pascal string rows show the text after a 1-byte and a 2-byte length,
+// flagging a length that runs past the end of the file.
+
+var time2000Epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// pascalPreview is how many characters of a Pascal string are shown.
+const pascalPreview = 24
+
+// decoderExtraRows is how many optional rows the decoder panel shows.
+func decoderExtraRows(d config.Decoder) int {
+	n := 0
+	if d.Time2000 {
+		n++
+	}
+	if d.PascalStrings {
+		n++
+	}
+	return n
+}
+
+// formatTime2000 formats the big-endian u32 at the start of b as a time
+// since 2000, or says why it isn't one.
+func formatTime2000(b []byte) string {
+	if len(b) < 4 {
+		return "-"
+	}
+	v := binary.BigEndian.Uint32(b)
+	t := time2000Epoch.Add(time.Duration(v) * time.Second)
+	if !t.Before(plausibleUntil) {
+		return fmt.Sprintf("not a plausible date (%d s)", v)
+	}
+	return t.Format("2006-01-02 15:04:05") + " UTC"
+}
+
+// formatPascalString formats the string at the start of b whose length is
+// the first lenSize bytes, big-endian, avail being the bytes from there to
+// the end of the file. It reports whether the length runs past the end.
+func formatPascalString(b []byte, lenSize int, avail int64) (string, bool) {
+	if len(b) < lenSize {
+		return "-", false
+	}
+	var n int64
+	if lenSize == 1 {
+		n = int64(b[0])
+	} else {
+		n = int64(binary.BigEndian.Uint16(b))
+	}
+	text := b[lenSize:min(int64(len(b)), int64(lenSize)+n)]
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "len %d \"", n)
+	for _, c := range text[:min(len(text), pascalPreview)] {
+		if c < 0x20 || c >= 0x7F {
+			c = '.'
+		}
+		s.WriteByte(c)
+	}
+	s.WriteString(`"`)
+	if n > pascalPreview {
+		s.WriteString("…")
+	}
+	if over := int64(lenSize) + n - avail; over > 0 {
+		fmt.Fprintf(&s, ", %d past EOF", over)
+		return s.String(), true
+	}
+	return s.String(), false
+}
+
+// writeDecoderRows appends the optional rows turned on to b.
+func (m *Model) writeDecoderRows(b *strings.Builder, st *config.Styles) {
+	d := m.config.Decoder
+	if decoderExtraRows(d) == 0 {
+		return
+	}
+	tab := m.currentTab()
+	at, _ := m.decoderAnchor(tab)
+	data := m.typingPatch(tab).apply(at, tab.Buffer.GetBytes(at, 2+pascalPreview))
+
+	if d.Time2000 {
+		b.WriteString("\n")
+		b.WriteString(st.DecoderLabel.Render("time2000: "))
+		b.WriteString(st.DecoderValue.Render(formatTime2000(data)))
+	}
+	if d.PascalStrings {
+		b.WriteString("\n")
+		width := 0
+		avail := tab.Buffer.Size() - at
+		for _, size := range []int{1, 2} {
+			label := fmt.Sprintf("pstr%d: ", size*8)
+			if size > 1 {
+				label = "   " + label
+			}
+			text, past := formatPascalString(data, size, avail)
+			if width+len(label)+lipgloss.Width(text) > m.width {
+				break
+			}
+			value := st.DecoderValue
+			if past {
+				value = st.StatusWarning
+			}
+			b.WriteString(st.DecoderLabel.Render(label))
+			b.WriteString(value.Render(text))
+			width += len(label) + lipgloss.Width(text)
+		}
+	}
+}
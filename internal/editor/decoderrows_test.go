@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTime2000(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte{0, 0, 0, 0}, "2000-01-01 00:00:00 UTC"},
+		{[]byte{0x2D, 0xC3, 0x99, 0xC0, 0xFF}, "2024-04-30 12:00:00 UTC"},
+		{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, "not a plausible date (4294967295 s)"},
+		{[]byte{1, 2, 3}, "-"},
+	}
+	for _, tt := range tests {
+		if got := formatTime2000(tt.data); got != tt.want {
+			t.Errorf("% X: got %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPascalString(t *testing.T) {
+	tests := []struct {
+		data    string
+		lenSize int
+		avail   int64
+		want    string
+		past    bool
+	}{
+		{"\x05hello world", 1, 12, `len 5 "hello"`, false},
+		{"\x00\x03a\x01c", 2, 5, `len 3 "a.c"`, false},
+		{"\x00", 1, 1, `len 0 ""`, false},
+		{"\x1EABCDEFGHIJKLMNOPQRSTUVWXYZ", 1, 27, `len 30 "ABCDEFGHIJKLMNOPQRSTUVWX"…, 4 past EOF`, true},
+		{"\x01", 2, 1, "-", false},
+	}
+	for _, tt := range tests {
+		got, past := formatPascalString([]byte(tt.data), tt.lenSize, tt.avail)
+		if got != tt.want || past != tt.past {
+			t.Errorf("%q: got %q, %v, want %q, %v", tt.data, got, past, tt.want, tt.past)
+		}
+	}
+}
+
+func TestDecoderRowsAreOptional(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "\x00\x00\x00\x3C\x04abcd"))
+	rows := m.visibleRows()
+	if strings.Contains(m.renderDecoder(), "time2000") {
+		t.Error("the rows should be off by default")
+	}
+	m.config.Decoder.Time2000 = true
+	m.config.Decoder.PascalStrings = true
+	view := m.renderDecoder()
+	for _, want := range []string{"time2000: 2000-01-01 00:01:00 UTC", `pstr8: len 0 ""`, `pstr16: len 0 ""`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in:\n%s", want, view)
+		}
+	}
+	if m.visibleRows() != rows-2 {
+		t.Errorf("the rows should come from the hex view, got %d rows, had %d", m.visibleRows(), rows)
+	}
+}
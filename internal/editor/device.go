@@ -0,0 +1,103 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Block devices are opened on demand rather than loaded, start read-only
+// and can't change size. Ctrl+O unlocks them for writing after a warning;
+// saving then writes the changed bytes straight over the device.
+
+func (m *Model) isBlockDevice(path string) bool {
+	info, err := m.fs.Stat(path)
+	if err != nil {
+		return false
+	}
+	mode := info.Mode()
+	return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0
+}
+
+func (m *Model) openDevice(path string, replace bool) error {
+	buf, err := buffer.OpenDevice(path)
+	if err != nil {
+		return err
+	}
+	tab := m.newTab(buf)
+	m.placeTab(tab, replace)
+	m.view = ViewMain
+	m.restoreView()
+	m.setStatus(sevInfo, fmt.Sprintf("Opened device %s read-only (%s); Ctrl+O to allow writing", path, formatSize(buf.Size())))
+	m.lockTab(tab)
+	m.loadRangeLocks(tab)
+	return nil
+}
+
+// editBlocked reports, with a warning, whether tab's buffer is read-only
+// or the edit would land in a collapsed fold.
+func (m *Model) editBlocked(tab *Tab) bool {
+	if !tab.Buffer.ReadOnly() {
+		return m.foldBlocked(tab)
+	}
+	m.setStatus(sevWarning, fmt.Sprintf("%s is read-only; Ctrl+O to allow writing", filepath.Base(tab.Buffer.Filename())))
+	return true
+}
+
+// toggleWriteLock unlocks a device, or a file opened read-only because it
+// is being edited elsewhere, for writing after confirmation, or locks it
+// again.
+func (m *Model) toggleWriteLock() {
+	tab := m.currentTab()
+	if tab == nil || !tab.Buffer.IsDevice() && !tab.Buffer.ReadOnly() {
+		m.setStatus(sevInfo, "Only devices and files being edited elsewhere are opened read-only")
+		return
+	}
+	if !tab.Buffer.ReadOnly() {
+		tab.Buffer.SetReadOnly(true)
+		m.setStatus(sevInfo, tab.Buffer.Filename()+" is read-only again")
+		return
+	}
+	warning := "Saving writes straight to the device."
+	if !tab.Buffer.IsDevice() {
+		warning = "Saving may overwrite edits saved from elsewhere."
+	}
+	m.openDialog(fmt.Sprintf("Allow writing to %s? %s", tab.Buffer.Filename(), warning),
+		m.cancelButton(ViewMain),
+		dialogButton{label: "Allow writing", action: func() (tea.Model, tea.Cmd) {
+			tab.Buffer.SetReadOnly(false)
+			m.view = ViewMain
+			m.setStatus(sevWarning, tab.Buffer.Filename()+" is writable; Ctrl+O to lock it again")
+			return m, nil
+		}})
+}
+
+func (m *Model) sectorSize() int64 {
+	return max(m.config.Behavior.SectorSize, 1)
+}
+
+// parseGotoInput parses a Goto offset, or a sector number written with an
+// s prefix (s2048) in units of the configured sector size.
+func (m *Model) parseGotoInput(input string) (int64, error) {
+	input = strings.TrimSpace(input)
+	if rest, ok := strings.CutPrefix(strings.ToLower(input), "s"); ok {
+		n, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid sector %q", rest)
+		}
+		return n * m.sectorSize(), nil
+	}
+	return parseOffset(input)
+}
+
+// sectorPosition describes pos as a sector and the offset within it.
+func (m *Model) sectorPosition(pos int64) string {
+	size := m.sectorSize()
+	return fmt.Sprintf("Sector %d + 0x%X", pos/size, pos%size)
+}
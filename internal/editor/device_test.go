@@ -0,0 +1,90 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDeviceTab(t *testing.T) {
+	// A regular file stands in for the device
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, make([]byte, 0x400), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	if err := m.openDevice(path, false); err != nil {
+		t.Fatal(err)
+	}
+	tab := m.currentTab()
+
+	typeKeys(m, "gs1")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 512 {
+		t.Fatalf("goto s1 should move to 512, cursor at %d", tab.Cursor)
+	}
+	if got := m.sectorPosition(515); got != "Sector 1 + 0x3" {
+		t.Errorf("sectorPosition(515) = %q", got)
+	}
+
+	typeKeys(m, "rab")
+	if tab.Buffer.IsModified() || !strings.Contains(m.status.text, "read-only") {
+		t.Fatalf("edits should be refused while read-only, status %q", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.view != ViewDialog {
+		t.Fatal("unlocking should ask first")
+	}
+	press(m, tea.KeyRight, tea.KeyEnter)
+	if tab.Buffer.ReadOnly() {
+		t.Fatal("device should be writable after confirming")
+	}
+
+	press(m, tea.KeyEscape, tea.KeyDelete)
+	if tab.Buffer.Size() != 0x400 || !strings.Contains(m.status.text, "device") {
+		t.Fatalf("delete should be refused, status %q", m.status.text)
+	}
+	typeKeys(m, "rab")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	want := make([]byte, 0x400)
+	want[512] = 0xAB
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+		t.Errorf("save should write the device in place (status %q)", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !tab.Buffer.ReadOnly() {
+		t.Error("Ctrl+O should lock the device again without asking")
+	}
+}
+
+func TestDeviceTabReplacesAndClosesWithLastView(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, make([]byte, 0x400), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel([]byte{1, 2})
+	if err := m.openDevice(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.tabs) != 1 || !m.currentTab().Buffer.IsDevice() {
+		t.Fatalf("the device should replace the tab, %d tabs", len(m.tabs))
+	}
+	buf := m.currentTab().Buffer
+
+	m.duplicateView()
+	m.closeCurrentTab()
+	if _, err := buf.HashContext(context.Background()); err != nil {
+		t.Fatalf("device closed while another view is open: %v", err)
+	}
+	m.closeCurrentTab()
+	if _, err := buf.HashContext(context.Background()); err == nil {
+		t.Fatal("device should be closed with its last view")
+	}
+}
@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A dialog is a modal prompt drawn over the main view. Buttons are chosen
+// with Left/Right/Tab and activated with Enter; there are no letter
+// shortcuts, so a stray key can't confirm anything. The first button is
+// the cancel button: it has the focus when the dialog opens and Esc
+// always activates it.
+type dialog struct {
+	message string
+	buttons []dialogButton
+	focus   int
+}
+
+type dialogButton struct {
+	label  string
+	action func() (tea.Model, tea.Cmd)
+}
+
+func (m *Model) openDialog(message string, buttons ...dialogButton) {
+	m.dialog = &dialog{message: message, buttons: buttons}
+	m.view = ViewDialog
+}
+
+// cancelButton closes the dialog and returns to view.
+func (m *Model) cancelButton(view View) dialogButton {
+	return dialogButton{label: "Cancel", action: func() (tea.Model, tea.Cmd) {
+		m.view = view
+		return m, nil
+	}}
+}
+
+func (m *Model) handleDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.dialog
+	if d == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "left", "shift+tab":
+		d.focus = (d.focus - 1 + len(d.buttons)) % len(d.buttons)
+	case "right", "tab":
+		d.focus = (d.focus + 1) % len(d.buttons)
+	case "enter":
+		return m.activateButton(d.focus)
+	case "esc":
+		return m.activateButton(0)
+	}
+	return m, nil
+}
+
+func (m *Model) activateButton(i int) (tea.Model, tea.Cmd) {
+	button := m.dialog.buttons[i]
+	m.dialog = nil
+	m.view = ViewMain
+	return button.action()
+}
+
+func (m *Model) renderDialog() string {
+	d := m.dialog
+	if d == nil {
+		return ""
+	}
+
+	var buttons []string
+	for i, button := range d.buttons {
+		label := "[ " + button.label + " ]"
+		if i == d.focus {
+			label = m.styles.Legend.Render(label)
+		}
+		buttons = append(buttons, label)
+	}
+
+	content := d.message + "\n\n" + strings.Join(buttons, "  ")
+	return m.styles.Border.
+		Border(lipgloss.RoundedBorder()).
+		Padding(1, 2).
+		Render(content)
+}
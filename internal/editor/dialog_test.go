@@ -0,0 +1,83 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCloseDialogDefaultsToCancel(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if m.view != ViewDialog {
+		t.Fatalf("expected the close dialog, got view %v", m.view)
+	}
+
+	// Letters no longer answer the prompt
+	typeKeys(m, "n")
+	if m.view != ViewDialog || len(m.tabs) != 1 {
+		t.Fatal("a stray key closed the dialog")
+	}
+
+	// Enter on the initial focus cancels
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain || len(m.tabs) != 1 {
+		t.Fatalf("expected Enter on the default button to cancel, view %v, %d tabs", m.view, len(m.tabs))
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.view != ViewMain || len(m.tabs) != 1 {
+		t.Fatal("Esc should cancel regardless of focus")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.tabs) != 0 {
+		t.Error("expected Discard to close the tab")
+	}
+}
+
+func TestCloseDialogKeepsTabWhenSaveFails(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	tab := m.currentTab()
+	// A directory can't be opened for writing
+	tab.Buffer.SetFilename(t.TempDir())
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(m.tabs) != 1 || m.currentTab() != tab {
+		t.Fatal("a failed save must not close the tab")
+	}
+	if m.status.sev != sevError {
+		t.Errorf("expected an error in the status bar, got %q", m.status.text)
+	}
+}
+
+func TestLargeEditDialog(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+	m.config.Behavior.LargeEditThreshold = 16
+	m.registers[defaultRegister] = &register{data: make([]byte, 32)}
+	m.mode = ModeInsert
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlV})
+	if m.view != ViewDialog {
+		t.Fatalf("expected a confirmation for the large paste, got view %v", m.view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for cmd != nil {
+		_, cmd = m.Update(cmd())
+		if m.job == nil {
+			break
+		}
+	}
+	if size := m.currentTab().Buffer.Size(); size != 96 {
+		t.Errorf("expected the paste to run after confirming, size %d", size)
+	}
+}
@@ -0,0 +1,190 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/diff"
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The diff view lists the ranges where the active tab differs from another
+// source, computed in the background, and jumps to the one picked. Offsets
+// on the right are in the tab's buffer (the new side).
+
+type diffResult struct {
+	title     string
+	tab       *Tab
+	seq       int
+	cancel    context.CancelFunc
+	computing bool
+	err       error
+	ranges    []diff.Range
+	note      string // shown above the ranges
+	compare   compareFunc
+	sel       int
+	scroll    int
+}
+
+type diffDoneMsg struct {
+	seq     int
+	version uint64 // of the tab's buffer when compared
+	ranges  []diff.Range
+	note    string
+	err     error
+}
+
+// compareFunc compares a snapshot of the tab's buffer with the other side,
+// returning the ranges and a note about them.
+type compareFunc func(ctx context.Context, current *buffer.Buffer) ([]diff.Range, string, error)
+
+// startDiff compares old against a snapshot of tab's buffer and opens the
+// diff view on the result.
+func (m *Model) startDiff(tab *Tab, title string, old diff.Source) tea.Cmd {
+	return m.startCompare(tab, title, func(ctx context.Context, current *buffer.Buffer) ([]diff.Range, string, error) {
+		ranges, err := diff.Compare(ctx, old, current)
+		return ranges, "", err
+	})
+}
+
+func (m *Model) startCompare(tab *Tab, title string, compare compareFunc) tea.Cmd {
+	m.closeDiff()
+	m.diffSeq++
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &diffResult{title: title, tab: tab, seq: m.diffSeq, cancel: cancel, computing: true, compare: compare}
+	m.diff = d
+	m.view = ViewDiff
+
+	current := tab.Buffer.Snapshot()
+	return func() tea.Msg {
+		ranges, note, err := compare(ctx, current)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return diffDoneMsg{seq: d.seq, version: current.Version(), ranges: ranges, note: note, err: err}
+	}
+}
+
+func (m *Model) closeDiff() {
+	if m.diff != nil {
+		m.diff.cancel()
+		m.diff = nil
+	}
+}
+
+func (m *Model) handleDiffDone(msg diffDoneMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	if d == nil || msg.seq != d.seq {
+		return m, nil
+	}
+	if d.tab.Buffer.Version() != msg.version {
+		// The buffer changed while comparing
+		return m, m.startCompare(d.tab, d.title, d.compare)
+	}
+	d.computing = false
+	d.ranges, d.note, d.err = msg.ranges, msg.note, msg.err
+	if d.err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Compare failed: %v", d.err))
+	}
+	return m, nil
+}
+
+func (m *Model) diffRows() int {
+	return max(m.height-10, 1)
+}
+
+func (m *Model) handleDiffKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	if d == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeDiff()
+		m.view = ViewMain
+		return m, nil
+	case "up":
+		d.sel--
+	case "down":
+		d.sel++
+	case "pgup":
+		d.sel -= m.diffRows()
+	case "pgdown":
+		d.sel += m.diffRows()
+	case "home":
+		d.sel = 0
+	case "end":
+		d.sel = len(d.ranges) - 1
+	case "enter":
+		if d.sel < len(d.ranges) && m.currentTab() == d.tab {
+			r := d.ranges[d.sel]
+			m.view = ViewMain
+			m.setCursor(r.BOff)
+			m.setStatus(sevInfo, fmt.Sprintf("Difference %d of %d: %s", d.sel+1, len(d.ranges), describeRange(r)))
+		}
+		return m, nil
+	}
+	d.sel = max(0, min(d.sel, len(d.ranges)-1))
+	rows := m.diffRows()
+	if d.sel < d.scroll {
+		d.scroll = d.sel
+	} else if d.sel >= d.scroll+rows {
+		d.scroll = d.sel - rows + 1
+	}
+	return m, nil
+}
+
+func describeRange(r diff.Range) string {
+	switch r.Kind {
+	case diff.Inserted:
+		return fmt.Sprintf("%d bytes inserted at 0x%X", r.BLen, r.BOff)
+	case diff.Deleted:
+		return fmt.Sprintf("%d bytes deleted at 0x%X", r.ALen, r.BOff)
+	default:
+		return fmt.Sprintf("%d bytes changed at 0x%X", r.BLen, r.BOff)
+	}
+}
+
+func (m *Model) renderDiff() string {
+	d := m.diff
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + strings.ToUpper(d.title) + "\n")
+	b.WriteString(strings.Repeat("=", len(d.title)) + "\n\n")
+
+	if d.note != "" {
+		b.WriteString(d.note + "\n\n")
+	}
+	switch {
+	case d.computing:
+		b.WriteString("Comparing…\n")
+	case d.err != nil:
+		b.WriteString(m.styles.StatusError.Render(d.err.Error()) + "\n")
+	case len(d.ranges) == 0:
+		b.WriteString("No differences\n")
+	default:
+		b.WriteString(fmt.Sprintf("%d ranges, %d bytes differ\n\n", len(d.ranges), diff.Bytes(d.ranges)))
+		b.WriteString(m.styles.DecoderLabel.Render(fmt.Sprintf("  %-9s %-22s %s", "", "old", "new")) + "\n")
+		end := min(d.scroll+m.diffRows(), len(d.ranges))
+		for i := d.scroll; i < end; i++ {
+			r := d.ranges[i]
+			line := fmt.Sprintf("%-9s 0x%08X +%-10d 0x%08X +%d", r.Kind, r.AOff, r.ALen, r.BOff, r.BLen)
+			if i == d.sel {
+				b.WriteString("> " + m.styles.Selection.Render(line) + "\n")
+			} else {
+				b.WriteString("  " + line + "\n")
+			}
+		}
+	}
+
+	b.WriteString("\n↑/↓ to choose, Enter to jump, ESC to close\n")
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Rows holding unsaved changes get a mark in the gutter after the offset,
+// and + and - jump to the next and previous changed run, to go over the
+// edits before saving. The runs come from the buffer's undo history (see
+// Buffer.Modifications), so undo and redo add and remove them.
+
+type dirtyCache struct {
+	buf      *buffer.Buffer
+	version  uint64
+	modified bool
+	mods     []buffer.Modification
+}
+
+// modifications returns the changed runs of tab's buffer, recomputed only
+// after an edit or a save. A new file is all unsaved, so marking it would
+// say nothing.
+func (m *Model) modifications(tab *Tab) []buffer.Modification {
+	buf := tab.Buffer
+	c := &m.dirty
+	if c.buf != buf || c.version != buf.Version() || c.modified != buf.IsModified() {
+		*c = dirtyCache{buf: buf, version: buf.Version(), modified: buf.IsModified()}
+		if buf.IsModified() && !buf.IsNew() {
+			c.mods = buf.Modifications()
+		}
+	}
+	return c.mods
+}
+
+// rowDirty reports whether the row at rowOffset holds changed bytes or
+// the place bytes were deleted from.
+func (m *Model) rowDirty(tab *Tab, rowOffset int64) bool {
+	mods := m.modifications(tab)
+	rowEnd := rowOffset + bytesPerRow
+	// Deletions at the very end show on the last row
+	last := max(tab.Buffer.Size()-1, 0)
+	i := sort.Search(len(mods), func(i int) bool {
+		return mods[i].Offset+mods[i].Len > rowOffset || min(mods[i].Offset, last) >= rowOffset
+	})
+	return i < len(mods) && min(mods[i].Offset, last) < rowEnd
+}
+
+func (m *Model) jumpToChange(forward bool) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	mods := m.modifications(tab)
+	if len(mods) == 0 {
+		msg := "No unsaved changes"
+		if tab.Buffer.IsNew() {
+			msg = "This file has never been saved; all of it is new"
+		}
+		m.setStatus(sevInfo, msg)
+		return nil
+	}
+	i := sort.Search(len(mods), func(i int) bool { return mods[i].Offset > tab.Cursor })
+	if !forward {
+		i = sort.Search(len(mods), func(i int) bool { return mods[i].Offset >= tab.Cursor }) - 1
+	}
+	if i < 0 || i >= len(mods) {
+		where := "after"
+		if !forward {
+			where = "before"
+		}
+		m.setStatus(sevInfo, fmt.Sprintf("No more changes %s the cursor (%d in all)", where, len(mods)))
+		return nil
+	}
+
+	mod := mods[i]
+	m.clearSelection()
+	tab.Cursor = min(mod.Offset, m.maxCursor(tab))
+	tab.resetGoalColumn()
+	m.centerCursor()
+	var what string
+	switch mod.Len {
+	case 0:
+		what = fmt.Sprintf("bytes deleted at 0x%X", mod.Offset)
+	case 1:
+		what = fmt.Sprintf("the byte at 0x%X", mod.Offset)
+	default:
+		what = fmt.Sprintf("0x%X-0x%X, %d bytes", mod.Offset, mod.Offset+mod.Len-1, mod.Len)
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Change %d of %d: %s", i+1, len(mods), what))
+	if mod.Len == 0 {
+		return nil
+	}
+	return m.flashRange(tab, mod.Offset, mod.Offset+mod.Len-1)
+}
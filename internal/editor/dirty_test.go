@@ -0,0 +1,53 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestJumpBetweenUnsavedChanges(t *testing.T) {
+	m := newTestModel(nil)
+	buf := buffer.FromData("data.bin", make([]byte, 0x100))
+	m.tabs = []*Tab{m.newTab(buf)}
+	tab := m.currentTab()
+	buf.Replace(0x05, 0xFF)
+	buf.Insert(0x45, []byte{1, 2})
+
+	if !m.rowDirty(tab, 0) || m.rowDirty(tab, 0x10) || !m.rowDirty(tab, 0x40) {
+		t.Fatal("expected rows 0 and 4 marked")
+	}
+	if lines := strings.Split(m.renderEditor(), "\n"); !strings.Contains(lines[0], "▌") || strings.Contains(lines[1], "▌") {
+		t.Errorf("expected a gutter mark on the first row only, got\n%s\n%s", lines[0], lines[1])
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	if tab.Cursor != 5 || cmd == nil || m.status.text != "Change 1 of 2: the byte at 0x5" {
+		t.Fatalf("expected the first change, flashed, got 0x%X (%q)", tab.Cursor, m.status.text)
+	}
+	typeKeys(m, "+")
+	if tab.Cursor != 0x45 || !m.flashed(tab, 0x46) || !strings.HasSuffix(m.status.text, "0x45-0x46, 2 bytes") {
+		t.Errorf("expected the insert, got 0x%X", tab.Cursor)
+	}
+	typeKeys(m, "+")
+	if tab.Cursor != 0x45 || !strings.Contains(m.status.text, "No more changes after") {
+		t.Errorf("got %q", m.status.text)
+	}
+	typeKeys(m, "-")
+	if tab.Cursor != 5 {
+		t.Errorf("expected to go back to 5, got 0x%X", tab.Cursor)
+	}
+
+	// Undone changes drop out, and saved ones too
+	buf.Undo()
+	if m.rowDirty(tab, 0x40) {
+		t.Error("the undone insert is still marked")
+	}
+	buf.Delete(0x80, 4)
+	if !m.rowDirty(tab, 0x80) || len(m.modifications(tab)) != 2 {
+		t.Errorf("a deletion should mark its row, got %v", m.modifications(tab))
+	}
+}
@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/protohuf/unhexed/internal/diff"
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Ctrl+D lists what saving would change: the differences between the file
+// as it is on disk now, streamed, and the tab's buffer. When the file was
+// changed on disk meanwhile, the note above the list tells whether those
+// changes touch the ranges edited here.
+
+// openDisk opens the part of the file on disk b holds.
+func openDisk(b *buffer.Buffer) (*os.File, diff.Source, error) {
+	f, err := os.Open(b.Filename())
+	if err != nil {
+		return nil, nil, err
+	}
+	// Seeking works for devices too, whose Stat size is 0
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	off, n := int64(0), size
+	if w, ok := b.Window(); ok {
+		off = min(w.Offset, size)
+		n = min(w.Length, size-off)
+	}
+	return f, io.NewSectionReader(f, off, n), nil
+}
+
+func (m *Model) startDiskDiff() tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	if tab.Buffer.IsNew() || tab.Buffer.Filename() == "" {
+		m.setStatus(sevWarning, "Not saved yet; there is nothing on disk to compare with")
+		return nil
+	}
+	f, disk, err := openDisk(tab.Buffer)
+	if err != nil {
+		m.setStatus(sevError, "Error reading the file on disk: "+err.Error())
+		return nil
+	}
+
+	saved := tab.Buffer.Saved()
+	return m.startCompare(tab, "Unsaved changes vs disk", func(ctx context.Context, current *buffer.Buffer) ([]diff.Range, string, error) {
+		defer f.Close()
+		ranges, err := diff.Compare(ctx, disk, current)
+		if err != nil {
+			return nil, "", err
+		}
+
+		theirs, err := diff.Compare(ctx, saved, disk)
+		if err != nil || len(theirs) == 0 {
+			return ranges, "", err
+		}
+		ours, err := diff.Compare(ctx, saved, current)
+		if err != nil {
+			return nil, "", err
+		}
+		return ranges, conflictNote(diff.Conflicts(ours, theirs), len(ours)), nil
+	})
+}
+
+func conflictNote(conflicts, edits int) string {
+	const changed = "The file changed on disk since it was opened or saved"
+	switch {
+	case edits == 0:
+		return changed + "; nothing was edited here"
+	case conflicts == 0:
+		return changed + "; none of the ranges edited here overlap its changes"
+	default:
+		return fmt.Sprintf("%s; %d of %d ranges edited here overlap its changes", changed, conflicts, edits)
+	}
+}
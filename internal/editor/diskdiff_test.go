@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// diskDiff opens a 32-byte file, edits byte 2 and applies external to the
+// file on disk, then compares.
+func diskDiff(t *testing.T, external func(data []byte)) *Model {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.bin")
+	data := make([]byte, 32)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := buffer.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.tabs = []*Tab{m.newTab(buf)}
+	buf.Replace(2, 0xFF)
+
+	external(data)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if cmd == nil {
+		t.Fatalf("expected a compare, status %q", m.status.text)
+	}
+	m.Update(cmd())
+	return m
+}
+
+func TestDiskDiffListsUnsavedChanges(t *testing.T) {
+	m := diskDiff(t, func([]byte) {})
+	if len(m.diff.ranges) != 1 || m.diff.ranges[0].BOff != 2 || m.diff.note != "" {
+		t.Errorf("expected the edit at 2 and no note, got %+v %q", m.diff.ranges, m.diff.note)
+	}
+}
+
+func TestDiskDiffReportsConflicts(t *testing.T) {
+	m := diskDiff(t, func(data []byte) { data[20] = 1 })
+	if len(m.diff.ranges) != 2 || !strings.Contains(m.diff.note, "none of the ranges edited here overlap") {
+		t.Errorf("got %+v %q", m.diff.ranges, m.diff.note)
+	}
+
+	m = diskDiff(t, func(data []byte) { data[2] = 1 })
+	if !strings.Contains(m.diff.note, "1 of 1 ranges edited here overlap") {
+		t.Errorf("got %q", m.diff.note)
+	}
+}
+
+func TestDiskDiffNeedsAFile(t *testing.T) {
+	m := newTestModel([]byte{1})
+	if cmd := m.startDiskDiff(); cmd != nil || m.status.sev != sevWarning {
+		t.Errorf("a new buffer has nothing on disk, status %q", m.status.text)
+	}
+}
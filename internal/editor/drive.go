@@ -0,0 +1,43 @@
+package editor
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Drive feeds msgs to m in order, as a terminal would, and returns m with
+// the view it ends up showing. The command each message returns is run to
+// completion and the messages it produces are fed back in before the next
+// one, so background work such as a search or a save finishes in order.
+// Timers go through m's Clock; one that doesn't fire them on its own keeps
+// the run repeatable. Drive stops early when the editor quits.
+func Drive(m *Model, msgs ...tea.Msg) (*Model, string) {
+	for _, msg := range msgs {
+		_, cmd := m.Update(msg)
+		if !m.run(cmd) {
+			break
+		}
+	}
+	return m, m.View()
+}
+
+// run runs cmd and updates m with what it returns, reporting false once
+// the editor quits.
+func (m *Model) run(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return true
+	}
+	switch msg := cmd().(type) {
+	case nil:
+		return true
+	case tea.QuitMsg:
+		return false
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			if !m.run(cmd) {
+				return false
+			}
+		}
+		return true
+	default:
+		_, next := m.Update(msg)
+		return m.run(next)
+	}
+}
@@ -0,0 +1,190 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testClock stands still; its timers fire only when it is advanced past
+// them.
+type testClock struct {
+	now    time.Time
+	timers []testTimer
+}
+
+type testTimer struct {
+	at time.Time
+	fn func(time.Time) tea.Msg
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func (c *testClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	c.timers = append(c.timers, testTimer{c.now.Add(d), fn})
+	return nil
+}
+
+// advance moves the clock on by d and returns the messages of the timers
+// that came due.
+func (c *testClock) advance(d time.Duration) []tea.Msg {
+	c.now = c.now.Add(d)
+	var due []tea.Msg
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t.fn(t.at))
+		}
+	}
+	c.timers = pending
+	return due
+}
+
+// startEditor opens files in an editor with the default config and a
+// test clock, sized 120x40.
+func startEditor(t *testing.T, files ...string) (*Model, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	m, err := NewModel(files, Options{Config: config.DefaultConfig(), Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Drive(m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	return m, clock
+}
+
+// keys turns s into key presses, one per rune.
+func keys(s string) []tea.Msg {
+	var msgs []tea.Msg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+func key(t tea.KeyType) tea.Msg { return tea.KeyMsg{Type: t} }
+
+func writeTestFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDriveEditAndUndo(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "hello world"))
+	_, view := Drive(m)
+	if !strings.Contains(view, "data.bin") || !strings.Contains(view, "68 65 6C 6C") {
+		t.Fatalf("the file isn't shown:\n%s", view)
+	}
+
+	Drive(m, keys("r4a")...)
+	_, view = Drive(m, key(tea.KeyEscape))
+	tab := m.currentTab()
+	if got := string(tab.Buffer.GetBytes(0, 5)); got != "Jello" || !strings.Contains(view, "4A 65 6C 6C") {
+		t.Fatalf("got %q:\n%s", got, view)
+	}
+
+	_, view = Drive(m, keys("u")...)
+	if got := string(tab.Buffer.GetBytes(0, 5)); got != "hello" || tab.Buffer.IsModified() {
+		t.Errorf("undo left %q, modified %v", got, tab.Buffer.IsModified())
+	}
+	if !strings.Contains(view, "68 65 6C 6C") {
+		t.Errorf("the view wasn't redrawn:\n%s", view)
+	}
+}
+
+func TestDriveFind(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "hello world, hello again"))
+	tab := m.currentTab()
+	Drive(m, keys("fhel")...)
+	if tab.Cursor != 0 {
+		t.Fatalf("typing should stay on the first match, got %d", tab.Cursor)
+	}
+	Drive(m, keys("lo")...)
+	Drive(m, key(tea.KeyEnter))
+	if tab.Cursor != 13 {
+		t.Errorf("expected the second hello at 13, got %d", tab.Cursor)
+	}
+	_, view := Drive(m, key(tea.KeyEscape))
+	if m.view != ViewMain || strings.Contains(view, "Find:") {
+		t.Errorf("Esc should close the prompt:\n%s", view)
+	}
+}
+
+func TestDriveSaveAs(t *testing.T) {
+	path := writeTestFile(t, "abc")
+	m, _ := startEditor(t, path)
+	Drive(m, keys("r41")...)
+	Drive(m, key(tea.KeyEscape))
+
+	Drive(m, keys("a")...)
+	for range m.saveAsInput {
+		Drive(m, key(tea.KeyBackspace))
+	}
+	out := filepath.Join(filepath.Dir(path), "copy.bin")
+	Drive(m, keys(out)...)
+	_, view := Drive(m, key(tea.KeyEnter))
+
+	if data, err := os.ReadFile(out); err != nil || string(data) != "Abc" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "abc" {
+		t.Errorf("the original changed: %q", data)
+	}
+	if !strings.Contains(view, "Saved "+out) || !strings.Contains(view, "copy.bin") {
+		t.Errorf("expected the saved status and tab:\n%s", view)
+	}
+}
+
+func TestDriveCloseAsksToSave(t *testing.T) {
+	path := writeTestFile(t, "abc")
+	m, _ := startEditor(t, path)
+	Drive(m, keys("r41")...)
+	Drive(m, key(tea.KeyEscape))
+
+	_, view := Drive(m, key(tea.KeyCtrlW))
+	if m.view != ViewDialog || !strings.Contains(view, "Save changes before closing?") {
+		t.Fatalf("expected the prompt:\n%s", view)
+	}
+	Drive(m, key(tea.KeyEscape))
+	if m.view != ViewMain || len(m.tabs) != 1 {
+		t.Fatal("Esc should cancel closing")
+	}
+
+	// Save is the third button
+	Drive(m, key(tea.KeyCtrlW), key(tea.KeyRight), key(tea.KeyRight), key(tea.KeyEnter))
+	if len(m.tabs) != 0 || m.view != ViewOpen {
+		t.Errorf("expected the tab closed, %d tabs, view %v", len(m.tabs), m.view)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Abc" {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestDriveStatusExpires(t *testing.T) {
+	m, clock := startEditor(t, writeTestFile(t, "abc"))
+	_, view := Drive(m, keys("x")...)
+	if !strings.Contains(view, "Highlighting bytes equal") {
+		t.Fatalf("expected a status message:\n%s", view)
+	}
+
+	Drive(m, clock.advance(time.Second)...)
+	if m.status.text == "" {
+		t.Error("the message expired early")
+	}
+	Drive(m, clock.advance(10*time.Second)...)
+	if m.status.text != "" {
+		t.Errorf("expected the message gone, got %q", m.status.text)
+	}
+}
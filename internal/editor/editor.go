@@ -1,8 +1,11 @@
 package editor
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"math/big"
 	"os"
@@ -10,9 +13,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	"unhexed/internal/buffer"
-	"unhexed/internal/config"
+	"github.com/protohuf/unhexed/internal/bitfield"
+	"github.com/protohuf/unhexed/internal/config"
+	"github.com/protohuf/unhexed/internal/lockfile"
+	"github.com/protohuf/unhexed/pkg/buffer"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -36,73 +42,302 @@ const (
 	ViewGoto
 	ViewOpen
 	ViewSaveAs
-	ViewConfirmQuit
-	ViewConfirmClose
-	ViewFileSavePrompt
-	ViewFileChangedPrompt
+	ViewDialog
+	ViewFill
+	ViewRegisters
+	ViewLoading
+	ViewMessages
+	ViewRuns
+	ViewValue
+	ViewProperties
+	ViewSnapshots
+	ViewDiff
+	ViewChecksum
+	ViewOpenRange
+	ViewByteRange
+	ViewBitfield
+	ViewFindAll
+	ViewPad
+	ViewResize
+	ViewMarks
+	ViewOpenGlob
+	ViewValueExport
+	ViewValueImport
+	ViewRectFill
+	ViewValueScan
+	ViewStride
 )
 
 type Tab struct {
-	Buffer    *buffer.Buffer
-	Cursor    int64
-	ScrollY   int
-	Selection struct {
+	Buffer     *buffer.Buffer
+	Cursor     int64
+	ScrollY    int
+	UTF8Text   bool               // decode the text column as UTF-8
+	Bitfield   *bitfield.Template // shown in the decoder panel (see bitfield.go)
+	GroupWidth int                // bytes per value in the hex column; 0 or 1 shows bytes
+	TextFocus  bool               // typing goes to the text column (see panes.go)
+	Nibbles    bool               // the hex column addresses nibbles (see nibbles.go)
+	nibble     int                // of the cursor byte in the nibble view, 0 for the high one
+	CopyOf     string             // file a new buffer was duplicated from, for its label
+	rowCache   map[int64]cachedRow
+	unlisten   func() // stops tracking buffer changes (see anchors.go)
+	snapshots  []*snapshot
+	review     *snapReview // snapshot Alt+N/Alt+P step through (see snapnav.go)
+	marks      []*mark
+	goalCol    int           // column vertical motions aim for (see moveVertical)
+	goalAt     int64         // cursor position goalCol was last applied at
+	lastSel    lastSelection // for reselect (see reselect.go)
+	jumps      []jump        // where pointers were followed from (see pointers.go)
+	Fold       foldMode      // what collapses into fold lines (see folds.go)
+	foldsOpen  []openFold
+	zeroRows   zeroRows
+	Selection  struct {
 		Active bool
+		Block  bool // column selection over (row, col) space
 		Start  int64
 		End    int64
 	}
 }
 
 type Model struct {
-	tabs         []*Tab
-	activeTab    int
-	mode         EditMode
-	view         View
-	bigEndian    bool
-	clipboard    []byte
-	hexNibble    int // 0 or 1, for tracking hex input
-	width        int
-	height       int
-	config       *config.Config
-	styles       *config.Styles
-	newFileCount int
+	tabs          []*Tab
+	activeTab     int
+	tabScroll     int // first tab shown in the tab strip
+	mode          EditMode
+	view          View
+	bigEndian     bool
+	highlightSame bool      // highlight bytes equal to the one under the cursor
+	hexNibble     int       // 0 or 1, for tracking hex input
+	typed         *typedRun // bytes typed over in replace mode (see preview.go)
+	stride        int64     // bytes replace mode advances after each typed byte
+	lastEdit      *lastEdit
+	flash         *flash // bytes the last undo or redo changed (see undo.go)
+	flashSeq      int
+	editViews     map[*buffer.Buffer]map[uint64]viewState // see noteView
+	locks         []*lockfile.Lock                        // files opened here (see locks.go)
+	lockConflicts []lockConflict
+	pendingCount  int64
+	width         int
+	height        int
+	config        *config.Config
+	styles        *config.Styles
+	monochrome    bool
+	newFileCount  int
 
 	// Find dialog state
-	findInput   string
-	findMode    string // "ascii", "hex", "bits", "decimal"
-	findWidth   int    // for decimal search
-	findMatches int
+	findInput    string
+	findMode     string // "ascii", "hex", "bits", "decimal"
+	findWidth    int    // for decimal search
+	findMatches  int
+	findBackward bool   // Enter in the find dialog searches backward
+	findAlign    int64  // matches start at multiples of this; 0 allows any (see search.go)
+	lastFind     []byte // pattern of the last search, for select-to-match
+
+	findAll    *findAllState // results across tabs (see findall.go)
+	findAllSeq int
+
+	selStats *selStats // sums over the selection (see selstats.go)
+
+	valueInput  string       // value prompt (see valueentry.go)
+	copiedValue *copiedValue // Alt+C (see valuecopy.go)
+
+	props    *properties // properties view (see properties.go)
+	propsSeq int
+
+	// Marks view (see marks.go)
+	marksSel         int
+	marksPrompt      marksPrompt
+	marksInput       string
+	marksWithMatches bool // export the last search's matches too
+	marksSeq         int
+	marksCancel      context.CancelFunc
+
+	// Snapshots view (see snapshots.go)
+	snapSel    int
+	snapNaming bool
+	snapInput  string
+
+	diff    *diffResult // diff view (see diffview.go)
+	diffSeq int
+
+	split *splitView // two tabs one above the other (see split.go)
+
+	fixedSize bool // keep the size a replayed report was captured at (see session.go)
+
+	checksum    *checksumForm    // checksum view (see checksum.go)
+	valueExport *valueExportForm // value export view (see valueexport.go)
+	valueImport *valueImportForm // value import view (see valueimport.go)
+	rectFill    *rectFillForm    // rectangle fill view (see rectfill.go)
+
+	// Bitfield templates and view state (see bitfield.go)
+	bitfields     []*bitfield.Template
+	bitfieldErr   error
+	bitfieldField int
+	bitfieldInput string
+
+	// Runs dialog state (see runs.go)
+	runInput    string
+	runSeq      int
+	runScanning bool
+	runForward  bool
+	runCancel   context.CancelFunc
+
+	// Byte range dialog state (see byterange.go)
+	byteRangeInput    string
+	byteRangeSeq      int
+	byteRangeScanning bool
+	byteRangeForward  bool
+	byteRangeCancel   context.CancelFunc
+
+	// Text region jumps (see textregion.go)
+	textRegionSeq    int
+	textRegionCancel context.CancelFunc
+
+	// Value scan prompt (see valuescan.go)
+	valueScanInput   string
+	valueScanOrder   int
+	valueScanAligned bool
+
+	// Background match counting (see findcount.go)
+	findSeq      int
+	findCounting bool
+	findCancel   context.CancelFunc
+
+	selCount selCountState // matches within the selection (see selcount.go)
 
 	// Goto dialog state
 	gotoInput string
 
+	// Fill dialog state
+	fillInput string
+
+	// Pad and resize dialog state (see pad.go)
+	padInput    string
+	resizeInput string
+	strideInput string // see stride.go
+
+	// Clipboard registers, keyed by name ('"' is the default register)
+	registers       map[rune]*register
+	registerPrefix  bool       // '"' typed, waiting for the register name
+	recent          []*command // last commands run, for ? (see keyhints.go)
+	showRecent      bool
+	pendingRegister rune
+
 	// File browser state
 	browserPath  string
 	browserItems []os.DirEntry
 	browserIndex int
-	browserFocus int // 0=list, 1=current tab btn, 2=new tab btn
+	browserFocus int // 0=list, 1=current tab btn, 2=new tab btn, 3=range btn, 4=open all btn
+	rangeInput   string
+	globDir      string // Open all prompt (see batchopen.go)
+	globInput    string
+	globCount    int
+	globErr      string
+	// browserErr says why the last directory or file chosen couldn't be
+	// opened, until another one is; browserDenied holds the entries whose
+	// permissions keep us from reading them.
+	browserErr    string
+	browserDenied map[string]bool
+
+	savedViews map[string]savedView // by path, of closed tabs (see positions.go)
+
+	dirty             dirtyCache                   // changed runs for the gutter (see dirty.go)
+	autosaved         map[*buffer.Buffer]time.Time // last autosave (see autosave.go)
+	autosaveConflicts map[*buffer.Buffer]bool      // changed on disk, already reported
+	autosaveChecking  bool                         // comparing files with the disk
 
 	// Save As dialog state
 	saveAsInput string
+	afterSaveAs func() (tea.Model, tea.Cmd) // continues a close or quit
 
 	// Config view state
 	configIndex   int
 	configInputs  map[string]string
+	configMtime   time.Time // of the config file last loaded or saved (see configwatch.go)
 	configChanged bool
+	configFixed   bool // given in Options, so the file is left alone
+
+	// Config view commands (see themeio.go)
+	configPrompt      configPrompt
+	configPromptInput string
+	importPath        string
+
+	// Modal prompt shown in ViewDialog
+	dialog *dialog
+
+	// Large edit running in the background
+	job *editJob
+
+	// File being read in the background
+	loading *fileLoad
 
-	// Confirmation dialog
-	confirmAction string
+	// Large file being saved in steps (see save.go)
+	saving *fileSave
 
-	// Error/status message
-	statusMsg string
+	// Last terminal title sent
+	title string
+
+	clock Clock
+	fs    FS
+
+	// Status bar message and the log of recent ones (see status.go)
+	status      statusEntry
+	statusSeq   int
+	statusTimed int
+	bellSeq     int // the status message ringing the visual bell (see alert.go)
+	bellTimed   int
+	statusLog   []statusEntry
+	logScroll   int
 }
 
 const bytesPerRow = 16
 
-func NewModel(files []string) (*Model, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		cfg = config.DefaultConfig()
+// Options control how the editor presents itself.
+type Options struct {
+	// Monochrome renders without colors, using reverse video and text
+	// attributes for the cursor and selection.
+	Monochrome bool
+
+	// Glob picks the files a directory argument opens; without it the
+	// editor asks.
+	Glob string
+
+	// Config is used instead of the user's config file, which is then
+	// neither read nor watched, and neither are the templates beside it.
+	Config *config.Config
+
+	// Clock tells the time and runs timers; nil uses the system clock.
+	Clock Clock
+
+	// FS opens, lists and writes files (see fs.go); nil uses the real
+	// file system.
+	FS FS
+
+	// Template names a template file whose first template is applied at
+	// Offset in the last file opened (see templatearg.go). Offset alone
+	// just places the cursor.
+	Template string
+	Offset   int64
+}
+
+func NewModel(files []string, opts Options) (*Model, error) {
+	lockfile.Clean()
+	var mtime time.Time
+	cfg := opts.Config
+	if cfg == nil {
+		mtime = configModTime()
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			cfg = config.DefaultConfig()
+		}
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = systemFS{}
 	}
 
 	m := &Model{
@@ -112,44 +347,79 @@ func NewModel(files []string) (*Model, error) {
 		view:         ViewMain,
 		bigEndian:    true,
 		config:       cfg,
-		styles:       config.NewStyles(&cfg.Theme),
+		monochrome:   opts.Monochrome,
 		findMode:     "ascii",
 		findWidth:    1,
 		configInputs: make(map[string]string),
+		registers:    make(map[rune]*register),
+		configMtime:  mtime,
+		configFixed:  opts.Config != nil,
+		clock:        clock,
+		fs:           fsys,
+	}
+	m.buildStyles()
+	if !m.configFixed {
+		m.loadBitfields()
 	}
 
 	// Load files or create new tab
 	if len(files) == 0 {
 		m.view = ViewOpen
-		cwd, _ := os.Getwd()
-		m.browserPath = cwd
-		m.loadBrowserItems()
+		cwd, _ := m.fs.Getwd()
+		m.browse(cwd)
 	} else {
 		for _, f := range files {
-			if err := m.openFile(f); err != nil {
+			if info, err := m.fs.Stat(f); err == nil && info.IsDir() {
+				if err := m.openDirArg(f, opts.Glob); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			path, w, windowed, err := splitWindowArg(f)
+			if err == nil {
+				if windowed {
+					err = m.openWindow(path, w)
+				} else {
+					err = m.openFile(f)
+				}
+			}
+			if err != nil {
 				return nil, fmt.Errorf("failed to open %s: %w", f, err)
 			}
 		}
+		if opts.Template != "" || opts.Offset > 0 {
+			m.openAtTemplate(opts.Template, opts.Offset)
+		}
 	}
 
 	return m, nil
 }
 
 func (m *Model) openFile(filename string) error {
-	buf, err := buffer.Open(filename)
+	if m.jumpToOpenTab(filename) {
+		return nil
+	}
+	if m.isBlockDevice(filename) {
+		return m.openDevice(filename, false)
+	}
+	data, err := m.fs.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	m.tabs = append(m.tabs, &Tab{Buffer: buf})
-	m.activeTab = len(m.tabs) - 1
+	tab := m.newTab(buffer.FromData(filename, data))
+	m.tabs = append(m.tabs, tab)
+	m.selectTab(len(m.tabs) - 1)
+	m.restoreView()
+	m.lockTab(tab)
+	m.loadRangeLocks(tab)
 	return nil
 }
 
 func (m *Model) newFile() {
 	m.newFileCount++
 	buf := buffer.New()
-	m.tabs = append(m.tabs, &Tab{Buffer: buf})
-	m.activeTab = len(m.tabs) - 1
+	m.tabs = append(m.tabs, m.newTab(buf))
+	m.selectTab(len(m.tabs) - 1)
 }
 
 func (m *Model) currentTab() *Tab {
@@ -160,26 +430,135 @@ func (m *Model) currentTab() *Tab {
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.pollConfig(), m.autosaveTimer())
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(m.rescue(r))
+		}
+	}()
+	tab, before := m.currentTab(), m.viewState()
+	var version uint64
+	if tab != nil {
+		version = tab.Buffer.Version()
+	}
+	model, cmd := m.update(msg)
+	m.noteView(tab, version, before)
+	m.syncSplit()
+	return model, tea.Batch(cmd, m.statusTimer(), m.bellTimer(), m.updateSelCount(), m.titleCmd())
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		if m.fixedSize {
+			return m, nil
+		}
 		m.width = msg.Width
 		m.height = msg.Height
+		for _, tab := range m.tabs {
+			m.clampScroll(tab)
+			m.ensureTabCursorVisible(tab)
+		}
+		m.scrollTabs()
 		return m, nil
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+
+	case editJobMsg:
+		return m.handleEditJob(msg)
+
+	case findCountTickMsg:
+		return m.handleFindCountTick(msg)
+
+	case findCountMsg:
+		return m.handleFindCount(msg)
+
+	case selCountTickMsg:
+		return m.handleSelCountTick(msg)
+
+	case selCountMsg:
+		return m.handleSelCount(msg)
+
+	case runFoundMsg:
+		return m.handleRunFound(msg)
+
+	case byteRangeFoundMsg:
+		return m.handleByteRangeFound(msg)
+
+	case textRegionMsg:
+		return m.handleTextRegion(msg)
+
+	case marksMatchesMsg:
+		return m.handleMarksMatches(msg)
+
+	case findAllMsg:
+		return m.handleFindAllResult(msg)
+
+	case propsHashMsg:
+		return m.handlePropsHash(msg)
+
+	case propsTickMsg:
+		return m.handlePropsTick(msg)
+
+	case diffDoneMsg:
+		return m.handleDiffDone(msg)
+
+	case snapDiffMsg:
+		return m.handleSnapDiff(msg)
+
+	case loadProgressMsg:
+		return m.handleLoadProgress(msg)
+
+	case loadDoneMsg:
+		return m.handleLoadDone(msg)
+
+	case saveStepMsg:
+		return m.handleSaveStep(msg)
+
+	case statusExpireMsg:
+		return m.handleStatusExpire(msg)
+
+	case bellExpireMsg:
+		return m.handleBellExpire(msg)
+
+	case flashExpireMsg:
+		return m.handleFlashExpire(msg)
+
+	case autosaveMsg:
+		return m.handleAutosave()
+	case autosaveCheckMsg:
+		return m.handleAutosaveCheck(msg)
+
+	case configPollMsg:
+		return m.handleConfigPoll()
+
+	case tea.ResumeMsg:
+		return m.handleResume()
 	}
 
 	return m, nil
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Clear status message on any key
-	m.statusMsg = ""
+	// Input is blocked while a large edit runs; ESC cancels it
+	if m.job != nil {
+		if msg.Type == tea.KeyEscape {
+			m.cancelJob()
+		}
+		return m, nil
+	}
+	if m.saving != nil {
+		if msg.Type == tea.KeyEscape {
+			m.cancelSave()
+		}
+		return m, nil
+	}
+	// The recent commands hint closes on any key, which still counts
+	m.showRecent = false
 
 	switch m.view {
 	case ViewHelp:
@@ -190,18 +569,58 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFindKey(msg)
 	case ViewGoto:
 		return m.handleGotoKey(msg)
+	case ViewRuns:
+		return m.handleRunsKey(msg)
+	case ViewByteRange:
+		return m.handleByteRangeKey(msg)
+	case ViewBitfield:
+		return m.handleBitfieldKey(msg)
+	case ViewFindAll:
+		return m.handleFindAllKey(msg)
+	case ViewPad:
+		return m.handlePadKey(msg)
+	case ViewResize:
+		return m.handleResizeKey(msg)
+	case ViewStride:
+		return m.handleStrideKey(msg)
+	case ViewValue:
+		return m.handleValueKey(msg)
+	case ViewProperties:
+		return m.handlePropertiesKey(msg)
+	case ViewSnapshots:
+		return m.handleSnapshotsKey(msg)
+	case ViewMarks:
+		return m.handleMarksKey(msg)
+	case ViewDiff:
+		return m.handleDiffKey(msg)
+	case ViewChecksum:
+		return m.handleChecksumKey(msg)
+	case ViewValueExport:
+		return m.handleValueExportKey(msg)
+	case ViewValueImport:
+		return m.handleValueImportKey(msg)
+	case ViewRectFill:
+		return m.handleRectFillKey(msg)
+	case ViewValueScan:
+		return m.handleValueScanKey(msg)
 	case ViewOpen:
 		return m.handleOpenKey(msg)
+	case ViewOpenRange:
+		return m.handleOpenRangeKey(msg)
+	case ViewOpenGlob:
+		return m.handleOpenGlobKey(msg)
 	case ViewSaveAs:
 		return m.handleSaveAsKey(msg)
-	case ViewConfirmQuit:
-		return m.handleConfirmQuitKey(msg)
-	case ViewConfirmClose:
-		return m.handleConfirmCloseKey(msg)
-	case ViewFileSavePrompt:
-		return m.handleFileSavePromptKey(msg)
-	case ViewFileChangedPrompt:
-		return m.handleFileChangedPromptKey(msg)
+	case ViewDialog:
+		return m.handleDialogKey(msg)
+	case ViewFill:
+		return m.handleFillKey(msg)
+	case ViewRegisters:
+		return m.handleRegistersKey(msg)
+	case ViewLoading:
+		return m.handleLoadingKey(msg)
+	case ViewMessages:
+		return m.handleMessagesKey(msg)
 	default:
 		return m.handleMainKey(msg)
 	}
@@ -213,54 +632,114 @@ func (m *Model) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle mode-specific input first
 	if m.mode == ModeInsert || m.mode == ModeReplace {
 		if msg.Type == tea.KeyEscape {
-			m.mode = ModeNormal
-			m.hexNibble = 0
+			m.setMode(ModeNormal)
 			return m, nil
 		}
 
+		if msg.String() == "ctrl+t" {
+			m.togglePane()
+			return m, nil
+		}
+		if tab != nil && tab.TextFocus {
+			if text, ok := typedText(msg); ok {
+				return m, m.typeText(text)
+			}
+		}
 		// Handle hex input
 		if isHexChar(msg.String()) {
 			return m.handleHexInput(msg.String())
 		}
 	}
 
+	if m.handleRegisterKey(msg.String()) {
+		return m, nil
+	}
+	// Esc in normal mode drops whatever is pending: a count, a selected
+	// register and the selection. Leaving insert/replace takes precedence,
+	// so clearing a selection from those modes needs a second Esc.
+	if msg.Type == tea.KeyEscape {
+		m.pendingCount = 0
+		m.pendingRegister = 0
+		m.clearSelection()
+		return m, nil
+	}
+	if m.handleCountDigit(msg.String()) {
+		return m, nil
+	}
+	count := m.takeCount()
+	reg := m.takeRegister()
+
+	m.noteCommand(msg.String())
+	if action := m.keyAction(msg.String()); action != "" {
+		return m.runAction(action)
+	}
 	switch msg.String() {
 	// Navigation
 	case "up":
-		m.moveCursor(-bytesPerRow, msg.Alt)
+		m.moveVertical(-count, msg.Alt, false)
 	case "down":
-		m.moveCursor(bytesPerRow, msg.Alt)
+		m.moveVertical(count, msg.Alt, false)
 	case "left":
-		m.moveCursor(-1, msg.Alt)
+		if tab != nil && tab.Nibbles {
+			m.moveNibbles(-count, msg.Alt)
+		} else {
+			m.moveCursor(m.horizontalDelta(tab, -count), msg.Alt)
+		}
 	case "right":
-		m.moveCursor(1, msg.Alt)
+		if tab != nil && tab.Nibbles {
+			m.moveNibbles(count, msg.Alt)
+		} else {
+			m.moveCursor(m.horizontalDelta(tab, count), msg.Alt)
+		}
 	case "shift+up":
-		m.selectMove(-bytesPerRow)
+		m.moveVertical(-count, false, true)
 	case "shift+down":
-		m.selectMove(bytesPerRow)
+		m.moveVertical(count, false, true)
 	case "shift+left":
-		m.selectMove(-1)
+		m.selectMove(m.horizontalDelta(tab, -count))
 	case "shift+right":
-		m.selectMove(1)
+		m.selectMove(m.horizontalDelta(tab, count))
+	case "ctrl+right", "ctrl+shift+right":
+		m.jumpBoundary(classChange, true, msg.String() != "ctrl+right")
+	case "ctrl+left", "ctrl+shift+left":
+		m.jumpBoundary(classChange, false, msg.String() != "ctrl+left")
+	case "ctrl+down", "ctrl+shift+down":
+		m.jumpBoundary(stringStart, true, msg.String() != "ctrl+down")
+	case "ctrl+up", "ctrl+shift+up":
+		m.jumpBoundary(stringStart, false, msg.String() != "ctrl+up")
 	case "pgup":
-		m.moveCursor(-int64(m.visibleRows())*bytesPerRow, false)
+		m.page(-count)
 	case "pgdown":
-		m.moveCursor(int64(m.visibleRows())*bytesPerRow, false)
+		m.page(count)
+	case "ctrl+l":
+		m.centerCursor()
 	case "home":
 		if tab != nil {
-			row := tab.Cursor / bytesPerRow
-			m.setCursor(row * bytesPerRow)
+			m.setCursor(rowStart(tab.Cursor))
 		}
 	case "end":
 		if tab != nil {
-			row := tab.Cursor / bytesPerRow
-			m.setCursor(row*bytesPerRow + bytesPerRow - 1)
+			m.setCursor(rowEnd(tab, tab.Cursor))
+		}
+	case "shift+home":
+		if tab != nil {
+			m.selectTo(rowStart(tab.Cursor))
+		}
+	case "shift+end":
+		if tab != nil {
+			m.selectTo(rowEnd(tab, tab.Cursor))
 		}
 	case "ctrl+home":
 		m.setCursor(0)
 	case "ctrl+end":
-		if tab != nil && tab.Buffer.Size() > 0 {
-			m.setCursor(tab.Buffer.Size() - 1)
+		if tab != nil {
+			m.setCursor(m.maxCursor(tab))
+		}
+	case "ctrl+shift+home":
+		m.selectTo(0)
+	case "ctrl+shift+end":
+		if tab != nil {
+			m.selectTo(tab.Buffer.Size() - 1)
 		}
 
 	// Commands
@@ -273,9 +752,8 @@ func (m *Model) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.loadConfigInputs()
 	case "o", "O":
 		m.view = ViewOpen
-		cwd, _ := os.Getwd()
-		m.browserPath = cwd
-		m.loadBrowserItems()
+		cwd, _ := m.fs.Getwd()
+		m.browse(cwd)
 	case "s", "S", "ctrl+s":
 		return m.trySave()
 	case "a", "A":
@@ -287,43 +765,183 @@ func (m *Model) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "n", "N":
 		m.newFile()
 	case "i", "I":
-		m.mode = ModeInsert
-		m.hexNibble = 0
+		if tab == nil || !m.resizeBlocked(tab) {
+			m.setMode(ModeInsert)
+		}
 	case "r", "R":
-		m.mode = ModeReplace
-		m.hexNibble = 0
+		// A count walks a column of records: 8R changes every 8th byte
+		m.replaceWithStride(count)
 	case "f", "F":
+		// The last search is kept, so Enter finds its next match; the
+		// buffer may have changed since, so the count is refreshed
 		m.view = ViewFind
-		m.findInput = ""
+		return m, m.updateFindMatches()
+	case "p", "P":
+		if tab != nil {
+			m.view = ViewRuns
+		}
+	case "y", "Y":
+		if tab != nil {
+			m.view = ViewByteRange
+		}
+	case "j", "J":
+		m.openBitfields()
+	case "ctrl+g":
+		return m, m.openProperties()
+	case "z", "Z":
+		m.openSnapshots()
+	case "k", "K":
+		m.openChecksum()
+	case "ctrl+k":
+		m.rerunChecksums()
+	case "~":
+		swap := func() tea.Cmd { return m.transformSelection("Nibble swap", swapNibbles) }
+		m.recordEdit("nibble swap", true, swap)
+		return m, swap()
+	case ".":
+		return m, m.repeatEdit()
+	case "=":
+		if tab != nil {
+			m.view = ViewValue
+			m.valueInput = ""
+		}
 	case "g", "G":
 		m.view = ViewGoto
 		m.gotoInput = ""
 	case "e", "E":
 		m.bigEndian = !m.bigEndian
+	case "b", "B":
+		m.toggleBlockSelection()
+	case "*":
+		return m, m.searchValueAtCursor(count)
+	case "'":
+		m.openMarks()
+	case "]":
+		m.selectToMatch(false)
+	case "}":
+		m.selectToMatch(true)
+	case ")":
+		return m, m.jumpTextRegion(true)
+	case "(":
+		return m, m.jumpTextRegion(false)
+	case "l", "L":
+		if tab != nil {
+			m.view = ViewFill
+			m.fillInput = ""
+		}
+	case "|":
+		if tab != nil {
+			m.view = ViewPad
+		}
+	case "#":
+		if tab != nil {
+			m.view = ViewResize
+			m.resizeInput = ""
+		}
+	case "t", "T":
+		if tab != nil {
+			tab.UTF8Text = !tab.UTF8Text
+			if tab.UTF8Text {
+				m.setStatus(sevInfo, "Text column: UTF-8")
+			} else {
+				m.setStatus(sevInfo, "Text column: ASCII")
+			}
+		}
+	case "w", "W":
+		m.cycleGroupWidth()
+	case "x", "X":
+		m.highlightSame = !m.highlightSame
+		if m.highlightSame {
+			m.setStatus(sevInfo, "Highlighting bytes equal to the cursor byte")
+		} else {
+			m.setStatus(sevInfo, "Same-byte highlighting off")
+		}
 	case "tab":
 		m.nextTab()
 	case "shift+tab":
 		m.prevTab()
 	case "ctrl+w":
 		return m.tryCloseTab()
-	case "u", "U":
-		if tab != nil && tab.Buffer.CanUndo() {
-			tab.Buffer.Undo()
-		}
-	case "d", "D":
-		if tab != nil && tab.Buffer.CanRedo() {
-			tab.Buffer.Redo()
-		}
+	case "v", "V":
+		m.duplicateView()
+	case "ctrl+n":
+		m.duplicateBuffer()
+	case "ctrl+o":
+		m.toggleWriteLock()
 	case "ctrl+x":
-		m.cut()
+		m.cut(reg)
 	case "ctrl+c":
-		m.copy()
+		m.copy(reg)
+	case "ctrl+d":
+		return m, m.startDiskDiff()
 	case "ctrl+v":
-		m.paste()
-	case "delete":
-		m.delete(false)
-	case "backspace":
-		m.delete(true)
+		paste := func() tea.Cmd { return m.paste(reg) }
+		m.recordEdit("paste", false, paste)
+		return m, paste()
+	case "alt+c":
+		m.copyValue()
+	case "alt+v":
+		m.pasteValue(count)
+	case "alt+e":
+		m.openValueExport(reg)
+	case "alt+i":
+		m.openValueImport()
+	case "alt+r":
+		m.reselect()
+	case "alt+b":
+		m.toggleByteReadout()
+	case "alt+l":
+		m.openRectFill()
+	case "alt+n":
+		return m, m.jumpToSnapshotDiff(true)
+	case "alt+p":
+		return m, m.jumpToSnapshotDiff(false)
+	case "alt+z":
+		m.cycleFolds()
+	case "alt+s":
+		m.toggleSplit()
+	case "alt+w":
+		m.switchPane()
+	case "alt+y":
+		m.toggleSplitLink()
+	case "alt+d":
+		m.exportSession()
+	case "alt+k":
+		m.toggleRangeLock()
+	case "alt+a":
+		if tab != nil {
+			m.openStridePrompt()
+		}
+	case "alt+j":
+		m.followFirstPointer()
+	case "alt+o":
+		m.jumpBack()
+	case "?":
+		m.showRecent = true
+	case "alt+f":
+		m.openValueScan()
+	case "enter":
+		if tab != nil && m.expandFold(tab, tab.Cursor) {
+			m.ensureCursorVisible()
+		}
+	case "+":
+		return m, m.jumpToChange(true)
+	case "-":
+		return m, m.jumpToChange(false)
+	case "ctrl+t":
+		m.togglePane()
+	case "ctrl+r":
+		m.view = ViewRegisters
+	case "m", "M":
+		m.openMessageLog()
+	case "delete", "backspace":
+		backspace := msg.String() == "backspace"
+		del := func() tea.Cmd {
+			m.delete(backspace, count)
+			return nil
+		}
+		m.recordEdit("delete", !backspace, del)
+		return m, del()
 	}
 
 	return m, nil
@@ -335,19 +953,34 @@ func (m *Model) handleHexInput(char string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.editBlocked(tab) {
+		return m, nil
+	}
 	nibble := hexCharToNibble(char)
+	if tab.Nibbles && m.mode == ModeReplace && tab.Cursor < tab.Buffer.Size() {
+		m.typeNibble(tab, nibble)
+		m.clearSelection()
+		return m, nil
+	}
+	if (m.mode == ModeInsert || tab.Cursor >= tab.Buffer.Size()) && m.resizeBlocked(tab) {
+		return m, nil
+	}
 
 	if m.mode == ModeInsert {
 		if m.hexNibble == 0 {
 			// First nibble - insert a new byte
-			tab.Buffer.Insert(tab.Cursor, []byte{nibble << 4})
+			if m.refused("insert", tab.Buffer.Insert(tab.Cursor, []byte{nibble << 4})) {
+				return m, nil
+			}
 			m.hexNibble = 1
 		} else {
 			// Second nibble - complete the byte
-			if b, ok := tab.Buffer.GetByte(tab.Cursor); ok {
-				tab.Buffer.Replace(tab.Cursor, (b&0xF0)|nibble)
-			}
+			b, _ := tab.Buffer.GetByte(tab.Cursor)
 			m.hexNibble = 0
+			if m.refused("type", tab.Buffer.Amend(tab.Cursor, (b&0xF0)|nibble)) {
+				return m, nil
+			}
+			m.recordPut("typed byte", []byte{(b & 0xF0) | nibble}, true)
 			tab.Cursor++
 			if tab.Cursor > tab.Buffer.Size() {
 				tab.Cursor = tab.Buffer.Size()
@@ -356,26 +989,38 @@ func (m *Model) handleHexInput(char string) (tea.Model, tea.Cmd) {
 	} else if m.mode == ModeReplace {
 		if tab.Cursor >= tab.Buffer.Size() {
 			// At EOF, extend file
-			tab.Buffer.Insert(tab.Buffer.Size(), []byte{nibble << 4})
+			if m.refused("extend the file", tab.Buffer.Insert(tab.Buffer.Size(), []byte{nibble << 4})) {
+				return m, nil
+			}
 			m.hexNibble = 1
+		} else if m.hexNibble == 1 && m.typingPatch(tab) == nil {
+			// Second nibble of a byte added at EOF - complete it, and go
+			// on adding
+			b, _ := tab.Buffer.GetByte(tab.Cursor)
+			m.hexNibble = 0
+			if m.refused("type", tab.Buffer.Amend(tab.Cursor, (b&0xF0)|nibble)) {
+				return m, nil
+			}
+			m.recordPut("typed byte", []byte{(b & 0xF0) | nibble}, false)
+			tab.Cursor = tab.Buffer.Size()
+			m.ensureCursorVisible()
 		} else {
-			if m.hexNibble == 0 {
-				if b, ok := tab.Buffer.GetByte(tab.Cursor); ok {
-					tab.Buffer.Replace(tab.Cursor, (nibble<<4)|(b&0x0F))
-				}
-				m.hexNibble = 1
-			} else {
-				if b, ok := tab.Buffer.GetByte(tab.Cursor); ok {
-					tab.Buffer.Replace(tab.Cursor, (b&0xF0)|nibble)
-				}
-				m.hexNibble = 0
-				tab.Cursor++
+			// The first nibble is held until the second (see preview.go)
+			if m.typingPatch(tab) == nil {
+				m.typeHigh(tab, nibble)
+			} else if m.typeLow(tab, nibble) {
+				tab.Cursor += max(m.stride, 1)
 				if tab.Cursor >= tab.Buffer.Size() {
+					if m.stride > 1 {
+						m.setStatus(sevWarning, "The next byte is past the end of the file")
+					}
 					tab.Cursor = tab.Buffer.Size() - 1
 					if tab.Cursor < 0 {
 						tab.Cursor = 0
 					}
 				}
+				m.followTyping(tab)
+				m.ensureCursorVisible()
 			}
 		}
 	}
@@ -384,6 +1029,26 @@ func (m *Model) handleHexInput(char string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m *Model) setMode(mode EditMode) {
+	m.mode = mode
+	m.hexNibble = 0
+	m.stride = 1
+	if tab := m.currentTab(); tab != nil && tab.Cursor > m.maxCursor(tab) {
+		tab.Cursor = m.maxCursor(tab)
+		m.ensureCursorVisible()
+	}
+}
+
+// maxCursor is the last valid cursor position. Insert mode may sit one past
+// the last byte to append; other modes stay on existing bytes.
+func (m *Model) maxCursor(tab *Tab) int64 {
+	size := tab.Buffer.Size()
+	if m.mode == ModeInsert {
+		return size
+	}
+	return max(size-1, 0)
+}
+
 func (m *Model) moveCursor(delta int64, clearSel bool) {
 	tab := m.currentTab()
 	if tab == nil {
@@ -394,14 +1059,12 @@ func (m *Model) moveCursor(delta int64, clearSel bool) {
 		m.clearSelection()
 	}
 
+	tab.resetGoalColumn()
 	newPos := tab.Cursor + delta
 	if newPos < 0 {
 		newPos = 0
 	}
-	maxPos := tab.Buffer.Size() - 1
-	if maxPos < 0 {
-		maxPos = 0
-	}
+	maxPos := m.maxCursor(tab)
 	if newPos > maxPos {
 		newPos = maxPos
 	}
@@ -409,6 +1072,38 @@ func (m *Model) moveCursor(delta int64, clearSel bool) {
 	m.ensureCursorVisible()
 }
 
+// moveVertical moves the cursor by rows, aiming for the goal column: the
+// column the cursor was in when a run of vertical motions began. Rows too
+// short for it clamp the cursor without forgetting it, so the column comes
+// back on the next long row. Any other motion, Home and End included,
+// starts a new goal.
+func (m *Model) moveVertical(rows int64, clearSel, selecting bool) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if tab.Cursor != tab.goalAt {
+		tab.goalCol = int(tab.Cursor % bytesPerRow)
+	}
+	row := max(tab.Cursor/bytesPerRow+rows, 0)
+	if tab.Fold != foldOff {
+		row = m.moveLines(tab, tab.Cursor/bytesPerRow, rows)
+	}
+	delta := row*bytesPerRow + int64(tab.goalCol) - tab.Cursor
+	if selecting {
+		m.selectMove(delta)
+	} else {
+		m.moveCursor(delta, clearSel)
+	}
+	tab.goalAt = tab.Cursor
+}
+
+// resetGoalColumn makes the next vertical motion start from the cursor's
+// own column.
+func (tab *Tab) resetGoalColumn() {
+	tab.goalAt = -1
+}
+
 func (m *Model) setCursor(pos int64) {
 	tab := m.currentTab()
 	if tab == nil {
@@ -416,17 +1111,16 @@ func (m *Model) setCursor(pos int64) {
 	}
 
 	m.clearSelection()
+	tab.resetGoalColumn()
 	if pos < 0 {
 		pos = 0
 	}
-	maxPos := tab.Buffer.Size() - 1
-	if maxPos < 0 {
-		maxPos = 0
-	}
+	maxPos := m.maxCursor(tab)
 	if pos > maxPos {
 		pos = maxPos
 	}
 	tab.Cursor = pos
+	m.expandFold(tab, pos)
 	m.ensureCursorVisible()
 }
 
@@ -442,6 +1136,7 @@ func (m *Model) selectMove(delta int64) {
 		tab.Selection.End = tab.Cursor
 	}
 
+	tab.resetGoalColumn()
 	newPos := tab.Cursor + delta
 	if newPos < 0 {
 		newPos = 0
@@ -459,16 +1154,44 @@ func (m *Model) selectMove(delta int64) {
 	m.ensureCursorVisible()
 }
 
+// selectTo extends the selection (or starts one at the cursor) up to pos.
+func (m *Model) selectTo(pos int64) {
+	if tab := m.currentTab(); tab != nil {
+		m.selectMove(pos - tab.Cursor)
+	}
+}
+
+func rowStart(pos int64) int64 {
+	return pos / bytesPerRow * bytesPerRow
+}
+
+// rowEnd is the last byte of pos's row, which on the final, partial row is
+// the last byte of the file.
+func rowEnd(tab *Tab, pos int64) int64 {
+	end := rowStart(pos) + bytesPerRow - 1
+	return max(min(end, tab.Buffer.Size()-1), 0)
+}
+
 func (m *Model) clearSelection() {
 	tab := m.currentTab()
 	if tab != nil {
+		rememberSelection(tab)
 		tab.Selection.Active = false
+		tab.Selection.Block = false
 	}
 }
 
 func (m *Model) getSelectedRange() (int64, int64) {
 	tab := m.currentTab()
-	if tab == nil || !tab.Selection.Active {
+	if tab == nil {
+		return -1, -1
+	}
+	return tab.selectedRange()
+}
+
+// selectedRange returns the ends of tab's selection in order, or -1, -1.
+func (tab *Tab) selectedRange() (int64, int64) {
+	if !tab.Selection.Active {
 		return -1, -1
 	}
 	start, end := tab.Selection.Start, tab.Selection.End
@@ -483,20 +1206,35 @@ func (m *Model) ensureCursorVisible() {
 	if tab == nil {
 		return
 	}
+	m.ensureTabCursorVisible(tab)
+}
 
+// ensureTabCursorVisible scrolls just enough to show the cursor row with
+// scrollOff rows of context on either side, fewer at the ends of the file.
+func (m *Model) ensureTabCursorVisible(tab *Tab) {
+	if tab.Fold != foldOff {
+		m.ensureFoldedCursorVisible(tab)
+		return
+	}
 	visRows := m.visibleRows()
 	cursorRow := int(tab.Cursor / bytesPerRow)
+	off := m.scrollOff()
 
-	if cursorRow < tab.ScrollY {
-		tab.ScrollY = cursorRow
-	} else if cursorRow >= tab.ScrollY+visRows {
-		tab.ScrollY = cursorRow - visRows + 1
+	if cursorRow-off < tab.ScrollY {
+		tab.ScrollY = cursorRow - off
+	} else if cursorRow+off >= tab.ScrollY+visRows {
+		tab.ScrollY = cursorRow + off - visRows + 1
 	}
+	m.clampScroll(tab)
 }
 
 func (m *Model) visibleRows() int {
-	// Account for legend, tabs, column header, decoder panel
-	rows := m.height - 10
+	// Account for legend, tabs, column header, decoder panel, status bar
+	rows := m.height - 11 - decoderExtraRows(m.config.Decoder)
+	if m.split != nil {
+		// Two panes and the bar between them (see split.go)
+		rows = (rows - 1) / 2
+	}
 	if rows < 1 {
 		rows = 1
 	}
@@ -505,74 +1243,110 @@ func (m *Model) visibleRows() int {
 
 func (m *Model) nextTab() {
 	if len(m.tabs) > 1 {
-		m.activeTab = (m.activeTab + 1) % len(m.tabs)
+		m.selectTab((m.activeTab + 1) % len(m.tabs))
 	}
 }
 
 func (m *Model) prevTab() {
 	if len(m.tabs) > 1 {
-		m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
+		m.selectTab((m.activeTab - 1 + len(m.tabs)) % len(m.tabs))
 	}
 }
 
-func (m *Model) copy() {
+func (m *Model) copy(reg rune) {
 	tab := m.currentTab()
 	if tab == nil {
 		return
 	}
 
+	if tab.Selection.Block {
+		m.storeRegister(reg, m.copyBlock(tab))
+		return
+	}
+
 	if tab.Selection.Active {
 		start, end := m.getSelectedRange()
-		m.clipboard = tab.Buffer.GetBytes(start, int(end-start+1))
+		m.storeRegister(reg, &register{data: tab.Buffer.GetBytes(start, int(end-start+1))})
 	} else {
 		if b, ok := tab.Buffer.GetByte(tab.Cursor); ok {
-			m.clipboard = []byte{b}
+			m.storeRegister(reg, &register{data: []byte{b}})
 		}
 	}
 }
 
-func (m *Model) cut() {
-	m.copy()
-	m.delete(false)
+func (m *Model) cut(reg rune) {
+	m.copy(reg)
+	if tab := m.currentTab(); tab != nil && tab.Selection.Block {
+		m.setStatus(sevInfo, "Column selection copied; use fiLl to clear it")
+		return
+	}
+	m.delete(false, 1)
 }
 
-func (m *Model) paste() {
+func (m *Model) paste(reg rune) tea.Cmd {
 	tab := m.currentTab()
-	if tab == nil || len(m.clipboard) == 0 {
-		return
+	if tab == nil {
+		return nil
 	}
 
-	if m.mode == ModeInsert {
-		tab.Buffer.Insert(tab.Cursor, m.clipboard)
-		tab.Cursor += int64(len(m.clipboard))
-	} else {
-		tab.Buffer.ReplaceBytes(tab.Cursor, m.clipboard)
+	r := m.registers[reg]
+	if r == nil || len(r.data) == 0 {
+		m.alert(fmt.Sprintf("Nothing to paste: register %s is empty", registerName(reg)))
+		return nil
 	}
+	if m.editBlocked(tab) {
+		return nil
+	}
+
 	m.clearSelection()
+	if r.width > 0 {
+		m.pasteBlock(tab, r)
+		return nil
+	}
+	if m.mode == ModeInsert || tab.Cursor+int64(len(r.data)) > tab.Buffer.Size() {
+		if m.resizeBlocked(tab) {
+			return nil
+		}
+	}
+	return m.runEdit(m.pasteJob(tab, r.data))
 }
 
-func (m *Model) delete(backspace bool) {
+func (m *Model) delete(backspace bool, count int64) {
 	tab := m.currentTab()
 	if tab == nil || m.mode != ModeNormal {
 		return
 	}
 
+	if tab.Selection.Block {
+		m.setStatus(sevWarning, "Cannot delete a column selection; use fiLl to clear it")
+		return
+	}
+	if m.editBlocked(tab) || m.resizeBlocked(tab) {
+		return
+	}
+
 	if tab.Selection.Active {
 		start, end := m.getSelectedRange()
-		tab.Buffer.Delete(start, int(end-start+1))
+		if m.refused("delete", tab.Buffer.Delete(start, int(end-start+1))) {
+			return
+		}
 		tab.Cursor = start
 		m.clearSelection()
-	} else {
-		if backspace {
-			if tab.Cursor > 0 {
-				tab.Buffer.Delete(tab.Cursor-1, 1)
-				tab.Cursor--
-			}
-		} else {
-			if tab.Cursor < tab.Buffer.Size() {
-				tab.Buffer.Delete(tab.Cursor, 1)
-			}
-		}
+	} else if backspace {
+		count = min(count, tab.Cursor)
+		if count == 0 {
+			m.alert("Nothing to delete before the start of the file")
+			return
+		}
+		if m.refused("delete", tab.Buffer.Delete(tab.Cursor-count, int(count))) {
+			return
+		}
+		tab.Cursor -= count
+	} else if tab.Cursor >= tab.Buffer.Size() {
+		m.alert("Nothing to delete at the end of the file")
+		return
+	} else if m.refused("delete", tab.Buffer.Delete(tab.Cursor, int(count))) {
+		return
 	}
 
 	// Adjust cursor if past end
@@ -587,7 +1361,12 @@ func (m *Model) delete(backspace bool) {
 func (m *Model) tryQuit() (tea.Model, tea.Cmd) {
 	for _, tab := range m.tabs {
 		if tab.Buffer.IsModified() {
-			m.view = ViewConfirmQuit
+			m.openDialog("Unsaved changes. Quit anyway?",
+				m.cancelButton(ViewMain),
+				dialogButton{label: "Discard and quit", action: func() (tea.Model, tea.Cmd) {
+					return m, tea.Quit
+				}},
+				dialogButton{label: "Save all and quit", action: m.saveAllAndQuit})
 			return m, nil
 		}
 	}
@@ -605,20 +1384,44 @@ func (m *Model) trySave() (tea.Model, tea.Cmd) {
 		m.saveAsInput = ""
 		return m, nil
 	}
+	if m.sizeChangeGuarded(tab.Buffer) {
+		m.openDialog("Save "+filepath.Base(tab.Buffer.Filename())+"? "+sizeChangeText(tab.Buffer),
+			m.cancelButton(ViewMain),
+			dialogButton{label: "Save", action: func() (tea.Model, tea.Cmd) {
+				return m.saveUnlessChanged(tab)
+			}})
+		return m, nil
+	}
+	return m.saveUnlessChanged(tab)
+}
 
-	// Check if file changed on disk
+// saveUnlessChanged saves tab, asking first when its file changed on disk.
+func (m *Model) saveUnlessChanged(tab *Tab) (tea.Model, tea.Cmd) {
 	changed, err := tab.Buffer.HasChangedOnDisk()
 	if err == nil && changed {
-		m.view = ViewFileChangedPrompt
+		m.openDialog("File changed on disk. Overwrite?",
+			m.cancelButton(ViewMain),
+			dialogButton{label: "Compare", action: func() (tea.Model, tea.Cmd) {
+				return m, m.startDiskDiff()
+			}},
+			dialogButton{label: "Overwrite", action: func() (tea.Model, tea.Cmd) {
+				return m, m.startSave(tab)
+			}})
 		return m, nil
 	}
 
+	return m, m.startSave(tab)
+}
+
+// saveTab saves tab's buffer and reports the outcome in the status bar.
+func (m *Model) saveTab(tab *Tab) error {
 	if err := tab.Buffer.Save(); err != nil {
-		m.statusMsg = fmt.Sprintf("Error saving: %v", err)
-	} else {
-		m.statusMsg = "File saved"
+		m.setStatus(sevError, fmt.Sprintf("Error saving %s: %v", tab.Buffer.Filename(), err))
+		return err
 	}
-	return m, nil
+	m.setStatus(sevInfo, "Saved "+tab.Buffer.Filename())
+	m.savedRangeLocks(tab)
+	return nil
 }
 
 func (m *Model) tryCloseTab() (tea.Model, tea.Cmd) {
@@ -627,8 +1430,23 @@ func (m *Model) tryCloseTab() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if tab.Buffer.IsModified() {
-		m.view = ViewConfirmClose
+	// Other views keep the changes, so only the last one asks to save
+	if tab.Buffer.IsModified() && m.viewCount(tab) == 1 {
+		m.openDialog("Save changes before closing?",
+			m.cancelButton(ViewMain),
+			dialogButton{label: "Discard", action: m.closeCurrentTab},
+			dialogButton{label: "Save", action: func() (tea.Model, tea.Cmd) {
+				if tab.Buffer.IsNew() {
+					m.askSaveAs("", m.closeCurrentTab)
+					return m, nil
+				}
+				// A failed save keeps the tab open
+				if err := m.saveTab(tab); err != nil {
+					m.saveFailed(tab, err, m.closeCurrentTab)
+					return m, nil
+				}
+				return m.closeCurrentTab()
+			}})
 		return m, nil
 	}
 
@@ -640,17 +1458,17 @@ func (m *Model) closeCurrentTab() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	m.rememberView(m.tabs[m.activeTab])
+	m.dropTab(m.tabs[m.activeTab])
 	m.tabs = append(m.tabs[:m.activeTab], m.tabs[m.activeTab+1:]...)
-	if m.activeTab >= len(m.tabs) {
-		m.activeTab = len(m.tabs) - 1
-	}
+	m.selectTab(min(m.activeTab, len(m.tabs)-1))
+	m.releaseLocks()
 
 	if len(m.tabs) == 0 {
 		// Show file browser instead of quitting
 		m.view = ViewOpen
-		cwd, _ := os.Getwd()
-		m.browserPath = cwd
-		m.loadBrowserItems()
+		cwd, _ := m.fs.Getwd()
+		m.browse(cwd)
 	}
 
 	return m, nil
@@ -664,11 +1482,24 @@ func (m *Model) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleConfigKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.configPrompt != promptNone {
+		return m.handleConfigPromptKey(msg)
+	}
+	if m.startConfigPrompt(msg.String()) {
+		return m, nil
+	}
 	switch msg.Type {
 	case tea.KeyEscape:
 		if m.configChanged {
-			m.view = ViewFileSavePrompt
-			m.confirmAction = "config"
+			m.openDialog("Save configuration changes?",
+				m.cancelButton(ViewConfig),
+				dialogButton{label: "Discard", action: func() (tea.Model, tea.Cmd) {
+					return m, nil
+				}},
+				dialogButton{label: "Save", action: func() (tea.Model, tea.Cmd) {
+					m.saveConfig()
+					return m, nil
+				}})
 		} else {
 			m.view = ViewMain
 		}
@@ -725,22 +1556,39 @@ func (m *Model) loadConfigInputs() {
 	}
 	m.configChanged = false
 	m.configIndex = 0
+	m.configPrompt = promptNone
+}
+
+// themeFromInputs returns the current theme with the Config view's edits.
+func (m *Model) themeFromInputs() config.Theme {
+	theme := m.config.Theme
+	theme.Background = m.configInputs["background"]
+	theme.MarkerBackground = m.configInputs["marker_background"]
+	theme.MarkerInsertBackground = m.configInputs["marker_insert_background"]
+	theme.MarkerReplaceBackground = m.configInputs["marker_replace_background"]
+	theme.IndexMarkerBackground = m.configInputs["index_marker_background"]
+	theme.LegendBackground = m.configInputs["legend_background"]
+	theme.LegendHighlight = m.configInputs["legend_highlight"]
+	theme.BorderColor = m.configInputs["border_color"]
+	theme.EndianColor = m.configInputs["endian_color"]
+	theme.ActiveTab = m.configInputs["active_tab"]
+	theme.SelectionBackground = m.configInputs["selection_background"]
+	return theme
 }
 
 func (m *Model) saveConfig() {
-	m.config.Theme.Background = m.configInputs["background"]
-	m.config.Theme.MarkerBackground = m.configInputs["marker_background"]
-	m.config.Theme.MarkerInsertBackground = m.configInputs["marker_insert_background"]
-	m.config.Theme.MarkerReplaceBackground = m.configInputs["marker_replace_background"]
-	m.config.Theme.IndexMarkerBackground = m.configInputs["index_marker_background"]
-	m.config.Theme.LegendBackground = m.configInputs["legend_background"]
-	m.config.Theme.LegendHighlight = m.configInputs["legend_highlight"]
-	m.config.Theme.BorderColor = m.configInputs["border_color"]
-	m.config.Theme.EndianColor = m.configInputs["endian_color"]
-	m.config.Theme.ActiveTab = m.configInputs["active_tab"]
-	m.config.Theme.SelectionBackground = m.configInputs["selection_background"]
+	m.config.Theme = m.themeFromInputs()
 	m.config.Save()
-	m.styles = config.NewStyles(&m.config.Theme)
+	m.configMtime = configModTime()
+	m.buildStyles()
+}
+
+func (m *Model) buildStyles() {
+	if m.monochrome {
+		m.styles = config.NewMonochromeStyles()
+	} else {
+		m.styles = config.NewStyles(&m.config.Theme)
+	}
 }
 
 func (m *Model) handleFindKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -753,7 +1601,7 @@ func (m *Model) handleFindKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if mode == m.findMode && i > 0 {
 				m.findMode = modes[i-1]
 				m.findInput = ""
-				break
+				return m, m.updateFindMatches()
 			}
 		}
 	case tea.KeyDown:
@@ -762,22 +1610,43 @@ func (m *Model) handleFindKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if mode == m.findMode && i < len(modes)-1 {
 				m.findMode = modes[i+1]
 				m.findInput = ""
-				break
+				return m, m.updateFindMatches()
 			}
 		}
 	case tea.KeyEnter:
-		m.doFind(true)
+		m.doFind(!m.findBackward)
+	case tea.KeyTab:
+		m.findBackward = !m.findBackward
+	case tea.KeyCtrlL:
+		m.cycleFindAlign()
+		return m, m.updateFindMatches()
+	case tea.KeyCtrlU:
+		m.findInput = ""
+		m.lastFind = nil
+		return m, m.updateFindMatches()
+	case tea.KeyCtrlA:
+		return m, m.openFindAll()
 	case tea.KeyBackspace:
 		if len(m.findInput) > 0 {
-			m.findInput = m.findInput[:len(m.findInput)-1]
-			m.updateFindMatches()
+			if m.findMode == "hex" {
+				m.findInput = dropHexToken(m.findInput)
+			} else {
+				m.findInput = m.findInput[:len(m.findInput)-1]
+			}
+			return m, m.updateFindMatches()
 		}
 	default:
 		char := msg.String()
 		if m.isValidFindChar(char) {
 			m.findInput += char
-			m.updateFindMatches()
-			m.doFind(true)
+			// A longer pattern refines the match at the cursor rather
+			// than moving past it
+			if tab := m.currentTab(); tab != nil && m.findBackward {
+				m.findFrom(tab.Cursor+1, false)
+			} else if tab != nil {
+				m.findFrom(tab.Cursor, true)
+			}
+			return m, m.updateFindMatches()
 		}
 	}
 	return m, nil
@@ -789,7 +1658,7 @@ func (m *Model) isValidFindChar(char string) bool {
 	}
 	switch m.findMode {
 	case "hex":
-		return isHexChar(char)
+		return isHexInputChar(char)
 	case "bits":
 		return char == "0" || char == "1"
 	case "decimal":
@@ -802,17 +1671,12 @@ func (m *Model) isValidFindChar(char string) bool {
 func (m *Model) getFindPattern() []byte {
 	switch m.findMode {
 	case "hex":
-		// Convert hex string to bytes
-		s := strings.ReplaceAll(m.findInput, " ", "")
-		if len(s)%2 != 0 {
-			s = "0" + s
-		}
-		result := make([]byte, len(s)/2)
-		for i := 0; i < len(s); i += 2 {
-			b, _ := strconv.ParseUint(s[i:i+2], 16, 8)
-			result[i/2] = byte(b)
+		// A pending half byte isn't searched for until it's completed
+		data, _, err := parseHexBytes(m.findInput)
+		if err != nil {
+			return nil
 		}
-		return result
+		return data
 	case "bits":
 		// Convert bit string to bytes
 		s := strings.ReplaceAll(m.findInput, " ", "")
@@ -847,32 +1711,76 @@ func (m *Model) getFindPattern() []byte {
 	}
 }
 
-func (m *Model) updateFindMatches() {
+// doFind moves to the next or previous match of the find pattern, going
+// around the end of the file unless wrap_search is off, and says why when
+// there is none. The notes aren't logged, since typing a pattern searches
+// at every key.
+func (m *Model) doFind(forward bool) {
 	tab := m.currentTab()
 	if tab == nil {
-		m.findMatches = 0
 		return
 	}
-	pattern := m.getFindPattern()
-	m.findMatches = tab.Buffer.CountMatches(pattern)
+	start := tab.Cursor
+	if forward {
+		start++
+	}
+	m.findFrom(start, forward)
 }
 
-func (m *Model) doFind(forward bool) {
+// findFrom moves to the first match from start on, or searching backwards
+// to the last one before start.
+func (m *Model) findFrom(start int64, forward bool) {
 	tab := m.currentTab()
-	if tab == nil || m.findInput == "" {
+	if tab == nil {
 		return
 	}
 
 	pattern := m.getFindPattern()
-	start := tab.Cursor
-	if forward {
-		start++
+	size := tab.Buffer.Size()
+	switch {
+	case len(pattern) == 0:
+		m.showStatus(sevWarning, "Enter a pattern")
+		return
+	case int64(len(pattern)) > size:
+		m.lastFind = pattern
+		m.showStatus(sevWarning, fmt.Sprintf("The pattern (%d bytes) is longer than the file (%d bytes)", len(pattern), size))
+		return
 	}
-	pos := tab.Buffer.Find(pattern, start, forward)
-	if pos >= 0 {
-		tab.Cursor = pos
-		m.ensureCursorVisible()
+	m.lastFind = pattern
+	align := m.findAlignment(tab)
+	notFound := "Pattern not found"
+	if m.findAlign > 1 {
+		notFound += fmt.Sprintf(" at a multiple of %d", m.findAlign)
+	}
+	pos := tab.Buffer.FindAligned(pattern, start, forward, align)
+	from, where, past := int64(0), "start", "after"
+	if !forward {
+		from, where, past = size, "end", "before"
+	}
+	switch {
+	case pos >= 0:
+	case !m.config.Behavior.WrapSearch:
+		if tab.Buffer.FindAligned(pattern, from, forward, align) < 0 {
+			m.showStatus(sevWarning, notFound)
+		} else {
+			m.showStatus(sevWarning, "No more matches "+past+" the cursor (wrap_search is off)")
+		}
+		return
+	default:
+		// Around the end, which also finds a match at the cursor
+		pos = tab.Buffer.FindAligned(pattern, from, forward, align)
+		switch {
+		case pos < 0:
+			m.showStatus(sevWarning, notFound)
+			return
+		case pos == tab.Cursor:
+			m.showStatus(sevInfo, "No more matches: this is the only one")
+		default:
+			m.showStatus(sevInfo, "Search continued from the "+where+" of the file")
+		}
 	}
+	tab.Cursor = pos
+	m.ensureCursorVisible()
 }
 
 func (m *Model) handleGotoKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -880,36 +1788,63 @@ func (m *Model) handleGotoKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyEscape:
 		m.view = ViewMain
 	case tea.KeyEnter:
-		m.doGoto()
-		m.view = ViewMain
+		// Keep the dialog open to correct an invalid offset
+		if m.doGoto() {
+			m.view = ViewMain
+		}
 	case tea.KeyBackspace:
 		if len(m.gotoInput) > 0 {
 			m.gotoInput = m.gotoInput[:len(m.gotoInput)-1]
 		}
 	default:
 		char := msg.String()
-		if len(char) == 1 && (isHexChar(char) || char == "x" || char == "X") {
+		if len(char) == 1 && (isHexChar(char) || char == "x" || char == "X" || char == "s" || char == "S") {
 			m.gotoInput += char
 		}
 	}
 	return m, nil
 }
 
-func (m *Model) doGoto() {
+// parseOffset parses a decimal offset or a hex one prefixed with 0x.
+func parseOffset(input string) (int64, error) {
+	input = strings.ToLower(input)
+	if strings.HasPrefix(input, "0x") {
+		return strconv.ParseInt(input[2:], 16, 64)
+	}
+	return strconv.ParseInt(input, 10, 64)
+}
+
+// doGoto moves to the offset typed in the Goto dialog. It reports false
+// when the input isn't a valid offset.
+func (m *Model) doGoto() bool {
 	tab := m.currentTab()
 	if tab == nil || m.gotoInput == "" {
-		return
+		return true
 	}
 
-	var offset int64
-	input := strings.ToLower(m.gotoInput)
-	if strings.HasPrefix(input, "0x") {
-		offset, _ = strconv.ParseInt(input[2:], 16, 64)
-	} else {
-		offset, _ = strconv.ParseInt(input, 10, 64)
+	offset, err := m.parseGotoInput(m.gotoInput)
+	if err != nil {
+		m.setStatus(sevWarning, fmt.Sprintf("Invalid offset %q", m.gotoInput))
+		return false
+	}
+	if base := m.displayBase(tab); base > 0 {
+		if offset < base {
+			m.setStatus(sevWarning, fmt.Sprintf("Offset 0x%X is before the window at 0x%X", offset, base))
+			return false
+		}
+		offset -= base
 	}
 
+	size := tab.Buffer.Size()
 	m.setCursor(offset)
+	m.clampScroll(tab)
+	switch {
+	case size == 0 && offset > 0:
+		m.setStatus(sevWarning, "Buffer is empty, moved to start")
+	case offset >= size && size > 0:
+		m.setStatus(sevWarning, fmt.Sprintf("Offset 0x%X beyond EOF (size 0x%X), moved to end", offset, size))
+	}
+	return true
 }
 
 func (m *Model) handleOpenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -931,11 +1866,11 @@ func (m *Model) handleOpenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.browserFocus--
 		}
 	case tea.KeyRight:
-		if m.browserFocus < 2 {
+		if m.browserFocus < 4 {
 			m.browserFocus++
 		}
 	case tea.KeyTab:
-		m.browserFocus = (m.browserFocus + 1) % 3
+		m.browserFocus = (m.browserFocus + 1) % 5
 	case tea.KeyEnter:
 		return m.handleBrowserEnter()
 	}
@@ -943,6 +1878,20 @@ func (m *Model) handleOpenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleBrowserEnter() (tea.Model, tea.Cmd) {
+	if m.browserFocus < 3 && m.browserIndex < len(m.browserItems) {
+		if item := m.browserItems[m.browserIndex]; m.browserDenied[item.Name()] {
+			info, err := item.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				m.browserErr = fmt.Sprintf("Can't open %s: it no longer exists", item.Name())
+				return m, nil
+			} else if err != nil {
+				m.browserErr = fmt.Sprintf("Can't open %s: %s", item.Name(), errReason(err))
+				return m, nil
+			}
+			m.browserErr = fmt.Sprintf("Can't open %s: no read permission (%s, owned by %s)", item.Name(), info.Mode(), fileOwner(info))
+			return m, nil
+		}
+	}
 	if m.browserFocus == 0 {
 		// File/directory selected
 		if m.browserIndex < len(m.browserItems) {
@@ -950,16 +1899,10 @@ func (m *Model) handleBrowserEnter() (tea.Model, tea.Cmd) {
 			path := filepath.Join(m.browserPath, item.Name())
 
 			if item.IsDir() {
-				m.browserPath = path
-				m.loadBrowserItems()
-				m.browserIndex = 0
+				m.browse(path)
 			} else {
 				// Open file in new tab
-				if err := m.openFile(path); err != nil {
-					m.statusMsg = fmt.Sprintf("Error: %v", err)
-				} else {
-					m.view = ViewMain
-				}
+				return m, m.startLoad(path, false)
 			}
 		}
 	} else if m.browserFocus == 1 {
@@ -968,46 +1911,49 @@ func (m *Model) handleBrowserEnter() (tea.Model, tea.Cmd) {
 			item := m.browserItems[m.browserIndex]
 			if !item.IsDir() {
 				path := filepath.Join(m.browserPath, item.Name())
-				buf, err := buffer.Open(path)
-				if err != nil {
-					m.statusMsg = fmt.Sprintf("Error: %v", err)
-				} else {
-					if len(m.tabs) == 0 {
-						m.tabs = append(m.tabs, &Tab{Buffer: buf})
-						m.activeTab = 0
-					} else {
-						m.tabs[m.activeTab] = &Tab{Buffer: buf}
-					}
-					m.view = ViewMain
-				}
+				return m, m.startLoad(path, true)
 			}
 		}
+	} else if m.browserFocus == 3 {
+		m.openRangePrompt()
+	} else if m.browserFocus == 4 {
+		// The selected directory, or the one listed
+		dir := m.browserPath
+		if m.browserIndex < len(m.browserItems) && m.browserItems[m.browserIndex].IsDir() && m.browserItems[m.browserIndex].Name() != ".." {
+			dir = filepath.Join(dir, m.browserItems[m.browserIndex].Name())
+		}
+		m.openGlobPrompt(dir)
 	} else {
 		// Open in new tab
 		if m.browserIndex < len(m.browserItems) {
 			item := m.browserItems[m.browserIndex]
 			if !item.IsDir() {
 				path := filepath.Join(m.browserPath, item.Name())
-				if err := m.openFile(path); err != nil {
-					m.statusMsg = fmt.Sprintf("Error: %v", err)
-				} else {
-					m.view = ViewMain
-				}
+				return m, m.startLoad(path, false)
 			}
 		}
 	}
 	return m, nil
 }
 
-func (m *Model) loadBrowserItems() {
-	entries, err := os.ReadDir(m.browserPath)
+// browse lists dir in the file browser. When it can't be read, the
+// previous listing stays and the browser says why.
+func (m *Model) browse(dir string) {
+	dir = filepath.Clean(dir)
+	entries, err := m.fs.ReadDir(dir)
 	if err != nil {
-		m.browserItems = nil
+		m.browserErr = fmt.Sprintf("Can't open %s: %s", dir, errReason(err))
+		if m.browserPath == "" {
+			m.browserPath = dir
+		}
 		return
 	}
+	m.browserPath = dir
+	m.browserIndex = 0
+	m.browserErr = ""
 
-	// Add parent directory
 	m.browserItems = make([]os.DirEntry, 0, len(entries)+1)
+	m.browserDenied = make(map[string]bool)
 
 	// Sort: directories first, then files
 	var dirs, files []os.DirEntry
@@ -1017,6 +1963,9 @@ func (m *Model) loadBrowserItems() {
 		} else {
 			files = append(files, e)
 		}
+		if info, err := e.Info(); err == nil && !canRead(info) {
+			m.browserDenied[e.Name()] = true
+		}
 	}
 	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() })
 	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
@@ -1029,6 +1978,16 @@ func (m *Model) loadBrowserItems() {
 	m.browserItems = append(m.browserItems, files...)
 }
 
+// errReason returns the system's reason for a failed file operation, e.g.
+// "permission denied", without the operation and path.
+func errReason(err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
+
 type parentDirEntry struct{}
 
 func (p *parentDirEntry) Name() string               { return ".." }
@@ -1040,15 +1999,21 @@ func (m *Model) handleSaveAsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEscape:
 		m.view = ViewMain
+		m.afterSaveAs = nil
 	case tea.KeyEnter:
 		if m.saveAsInput != "" {
 			tab := m.currentTab()
 			if tab != nil {
 				if err := tab.Buffer.SaveAs(m.saveAsInput); err != nil {
-					m.statusMsg = fmt.Sprintf("Error: %v", err)
+					m.setStatus(sevError, fmt.Sprintf("Error saving %s: %v", m.saveAsInput, err))
 				} else {
-					m.statusMsg = "File saved"
+					m.setStatus(sevInfo, "Saved "+tab.Buffer.Filename())
+					m.savedRangeLocks(tab)
 					m.view = ViewMain
+					if then := m.afterSaveAs; then != nil {
+						m.afterSaveAs = nil
+						return then()
+					}
 				}
 			}
 		}
@@ -1064,77 +2029,16 @@ func (m *Model) handleSaveAsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) handleConfirmQuitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		return m, tea.Quit
-	case "n", "N", "escape":
-		m.view = ViewMain
-	}
-	return m, nil
-}
-
-func (m *Model) handleConfirmCloseKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		tab := m.currentTab()
-		if tab != nil {
-			if tab.Buffer.IsNew() {
-				m.view = ViewSaveAs
-				m.saveAsInput = ""
-			} else {
-				tab.Buffer.Save()
-				return m.closeCurrentTab()
-			}
-		}
-	case "n", "N":
-		return m.closeCurrentTab()
-	case "escape":
-		m.view = ViewMain
-	}
-	return m, nil
-}
-
-func (m *Model) handleFileSavePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		if m.confirmAction == "config" {
-			m.saveConfig()
-		}
-		m.view = ViewMain
-		m.confirmAction = ""
-	case "n", "N":
-		m.view = ViewMain
-		m.confirmAction = ""
-	case "escape":
-		m.view = ViewConfig
-		m.confirmAction = ""
-	}
-	return m, nil
-}
-
-func (m *Model) handleFileChangedPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		tab := m.currentTab()
-		if tab != nil {
-			if err := tab.Buffer.Save(); err != nil {
-				m.statusMsg = fmt.Sprintf("Error: %v", err)
-			} else {
-				m.statusMsg = "File saved"
-			}
-		}
-		m.view = ViewMain
-	case "n", "N", "escape":
-		m.view = ViewMain
-	}
-	return m, nil
-}
-
 func (m *Model) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
+	if need := minEditorWidth(); m.width < need {
+		return fmt.Sprintf("Terminal too narrow (need %d cols, have %d)", need, m.width)
+	}
+	if need := minEditorHeight + decoderExtraRows(m.config.Decoder); m.height < need {
+		return fmt.Sprintf("Terminal too short (need %d rows, have %d)", need, m.height)
+	}
 
 	var b strings.Builder
 
@@ -1151,88 +2055,93 @@ func (m *Model) View() string {
 		b.WriteString(m.renderFind())
 	case ViewGoto:
 		b.WriteString(m.renderGoto())
+	case ViewRuns:
+		b.WriteString(m.renderRuns())
+	case ViewByteRange:
+		b.WriteString(m.renderByteRange())
+	case ViewBitfield:
+		b.WriteString(m.renderBitfield())
+	case ViewFindAll:
+		b.WriteString(m.renderFindAll())
+	case ViewPad:
+		b.WriteString(m.renderPad())
+	case ViewResize:
+		b.WriteString(m.renderResize())
+	case ViewStride:
+		b.WriteString(m.renderStride())
+	case ViewValue:
+		b.WriteString(m.renderValue())
+	case ViewProperties:
+		b.WriteString(m.renderProperties())
+	case ViewSnapshots:
+		b.WriteString(m.renderSnapshots())
+	case ViewMarks:
+		b.WriteString(m.renderMarks())
+	case ViewDiff:
+		b.WriteString(m.renderDiff())
+	case ViewChecksum:
+		b.WriteString(m.renderChecksum())
+	case ViewValueExport:
+		b.WriteString(m.renderValueExport())
+	case ViewValueImport:
+		b.WriteString(m.renderValueImport())
+	case ViewRectFill:
+		b.WriteString(m.renderRectFill())
+	case ViewValueScan:
+		b.WriteString(m.renderValueScan())
 	case ViewOpen:
 		b.WriteString(m.renderOpen())
+	case ViewOpenRange:
+		b.WriteString(m.renderOpenRange())
+	case ViewOpenGlob:
+		b.WriteString(m.renderOpenGlob())
 	case ViewSaveAs:
 		b.WriteString(m.renderSaveAs())
-	case ViewConfirmQuit:
-		b.WriteString(m.renderMainView())
-		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog("Unsaved changes. Quit anyway? (Y/N)"))
-	case ViewConfirmClose:
-		b.WriteString(m.renderMainView())
-		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog("Save before closing? (Y)es/(N)o/E(sc)ape"))
-	case ViewFileSavePrompt:
+	case ViewFill:
+		b.WriteString(m.renderFill())
+	case ViewLoading:
+		b.WriteString(m.renderLoading())
+	case ViewMessages:
+		b.WriteString(m.renderMessages())
+	case ViewRegisters:
+		b.WriteString(m.renderRegisters())
+	case ViewDialog:
 		b.WriteString(m.renderMainView())
 		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog("Save changes? (Y/N)"))
-	case ViewFileChangedPrompt:
-		b.WriteString(m.renderMainView())
-		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog("File changed on disk. Overwrite? (Y/N)"))
+		b.WriteString(m.renderDialog())
 	default:
-		b.WriteString(m.renderMainView())
+		main := m.renderMainView()
+		if hint := m.keyHint(); hint != "" {
+			main = overlayBottom(main, hint)
+		}
+		b.WriteString(main)
 	}
 
-	// Status message
-	if m.statusMsg != "" {
-		b.WriteString("\n")
-		b.WriteString(m.statusMsg)
-	}
+	// Status bar, always present so messages don't shift the layout
+	b.WriteString("\n")
+	b.WriteString(m.renderStatus())
 
 	return b.String()
 }
 
 func (m *Model) renderLegend() string {
-	var items []string
-
-	hl := func(text string, highlightIdx int) string {
-		var result strings.Builder
-		for i, ch := range text {
-			if i == highlightIdx {
-				result.WriteString(m.styles.LegendHighlight.Render(string(ch)))
-			} else {
-				result.WriteString(m.styles.Legend.Render(string(ch)))
-			}
-		}
-		return result.String()
+	if m.view == ViewMain {
+		// Commands come from the registry (see commands.go)
+		return m.styles.Legend.Width(m.width).Render(m.fitLegend(m.mainLegendItems(), m.width))
 	}
 
+	var items []string
 	// Always visible
-	items = append(items, hl("Quit", 0))
-	items = append(items, hl("Help", 0))
-	items = append(items, hl("Config", 0))
-
-	if m.view == ViewMain {
-		items = append(items, hl("Open", 0))
-		items = append(items, hl("Save", 0))
-		items = append(items, hl("sAve As", 1))
-		items = append(items, hl("New", 0))
-		items = append(items, hl("Insert", 0))
-		items = append(items, hl("Replace", 0))
-		items = append(items, hl("Find", 0))
-		items = append(items, hl("Goto", 0))
-		items = append(items, hl("Endian", 0))
-		items = append(items, m.styles.LegendHighlight.Render("TAB"))
-
-		tab := m.currentTab()
-		if tab != nil {
-			if tab.Buffer.CanUndo() {
-				items = append(items, hl("Undo", 0))
-			} else {
-				items = append(items, m.styles.Disabled.Render("Undo"))
-			}
-			if tab.Buffer.CanRedo() {
-				items = append(items, hl("reDo", 2))
-			} else {
-				items = append(items, m.styles.Disabled.Render("reDo"))
-			}
-		}
+	items = append(items, m.renderLegendItem("Quit", 0))
+	items = append(items, m.renderLegendItem("Help", 0))
+	items = append(items, m.renderLegendItem("Config", 0))
 
-		items = append(items, m.styles.LegendHighlight.Render("^X")+" "+m.styles.LegendHighlight.Render("^C")+" "+m.styles.LegendHighlight.Render("^V"))
-	} else if m.view == ViewFind || m.view == ViewGoto || m.view == ViewOpen || m.view == ViewSaveAs {
+	if m.view == ViewFind || m.view == ViewGoto || m.view == ViewOpen || m.view == ViewSaveAs || m.view == ViewFill || m.view == ViewRegisters || m.view == ViewMessages || m.view == ViewRuns || m.view == ViewValue || m.view == ViewProperties || m.view == ViewSnapshots || m.view == ViewDiff || m.view == ViewChecksum || m.view == ViewValueExport || m.view == ViewValueImport || m.view == ViewRectFill || m.view == ViewOpenRange || m.view == ViewOpenGlob || m.view == ViewByteRange || m.view == ViewBitfield || m.view == ViewFindAll || m.view == ViewPad || m.view == ViewResize || m.view == ViewStride || m.view == ViewMarks || m.view == ViewValueScan {
 		items = append(items, m.styles.LegendHighlight.Render("ESC")+" Back")
+	} else if m.view == ViewDialog {
+		items = append(items, m.styles.LegendHighlight.Render("←/→")+" Choose")
+		items = append(items, m.styles.LegendHighlight.Render("Enter")+" Confirm")
+		items = append(items, m.styles.LegendHighlight.Render("ESC")+" Cancel")
 	}
 
 	legend := strings.Join(items, m.styles.Legend.Render(" | "))
@@ -1275,14 +2184,18 @@ func (m *Model) renderTabs() string {
 		return ""
 	}
 
+	labels := m.tabLabels()
+	widths := m.tabWidths(labels)
+	first := m.tabStripStart(widths)
+	last := lastVisibleTab(widths, first, m.width)
+
 	var tabs []string
-	for i, tab := range m.tabs {
-		name := tab.Buffer.Filename()
-		if name == "" {
-			name = "[New File]"
-		} else {
-			name = filepath.Base(name)
-		}
+	if first > 0 {
+		tabs = append(tabs, m.styles.Disabled.Render(moreLeft(first)))
+	}
+	for i := first; i <= last; i++ {
+		tab := m.tabs[i]
+		name := labels[i]
 
 		style := m.styles.InactiveTab
 		if i == m.activeTab {
@@ -1297,8 +2210,11 @@ func (m *Model) renderTabs() string {
 
 		tabs = append(tabs, style.Render(name))
 	}
+	if rest := len(m.tabs) - 1 - last; rest > 0 {
+		tabs = append(tabs, m.styles.Disabled.Render(moreRight(rest)))
+	}
 
-	return strings.Join(tabs, " | ")
+	return strings.Join(tabs, tabSeparator)
 }
 
 func (m *Model) renderColumnHeader() string {
@@ -1309,6 +2225,9 @@ func (m *Model) renderColumnHeader() string {
 
 	// Offset column width (8 hex chars)
 	header := strings.Repeat(" ", 10)
+	if w := groupWidth(tab); w > 1 {
+		return header + m.groupedColumnHeader(tab, w)
+	}
 
 	// Hex column headers
 	cursorCol := int(tab.Cursor % bytesPerRow)
@@ -1331,95 +2250,6 @@ func (m *Model) renderColumnHeader() string {
 	return header
 }
 
-func (m *Model) renderEditor() string {
-	tab := m.currentTab()
-	if tab == nil {
-		return ""
-	}
-
-	var lines []string
-	visRows := m.visibleRows()
-	startOffset := int64(tab.ScrollY) * bytesPerRow
-
-	selStart, selEnd := m.getSelectedRange()
-
-	for row := 0; row < visRows; row++ {
-		rowOffset := startOffset + int64(row)*bytesPerRow
-		if rowOffset >= tab.Buffer.Size() && rowOffset > 0 {
-			break
-		}
-
-		// Offset column
-		offsetStr := fmt.Sprintf("%08X  ", rowOffset)
-		cursorRow := tab.Cursor / bytesPerRow
-		if int64(tab.ScrollY+row) == cursorRow {
-			offsetStr = m.styles.IndexMarker.Render(offsetStr)
-		}
-
-		// Hex and ASCII - build strings directly to match header alignment
-		var hexLine strings.Builder
-		var asciiLine strings.Builder
-
-		for col := 0; col < bytesPerRow; col++ {
-			offset := rowOffset + int64(col)
-			b, ok := tab.Buffer.GetByte(offset)
-
-			hexStr := "  "
-			asciiStr := " "
-
-			if ok {
-				hexStr = fmt.Sprintf("%02X", b)
-				if b >= 32 && b < 127 {
-					asciiStr = string(b)
-				} else {
-					asciiStr = "."
-				}
-			}
-
-			// Apply styling
-			style := m.styles.Normal
-
-			// Check if in selection
-			if tab.Selection.Active && offset >= selStart && offset <= selEnd {
-				style = m.styles.Selection
-			} else if offset == tab.Cursor {
-				// Cursor styling
-				switch m.mode {
-				case ModeInsert:
-					style = m.styles.MarkerInsert
-				case ModeReplace:
-					style = m.styles.MarkerReplace
-				default:
-					style = m.styles.MarkerNormal
-				}
-			} else if ok {
-				// Bit-width color coding for decoder panel correspondence
-				if bitStyle := m.getBitWidthStyle(offset, tab.Cursor); bitStyle != nil {
-					style = *bitStyle
-				}
-			}
-
-			hexLine.WriteString(style.Render(hexStr))
-			asciiLine.WriteString(style.Render(asciiStr))
-
-			// Spacing - must match renderColumnHeader exactly
-			if col < bytesPerRow-1 {
-				if (col+1)%8 == 0 {
-					hexLine.WriteString("  ") // 2 extra spaces after byte 7
-				} else if (col+1)%4 == 0 {
-					hexLine.WriteString(" ") // 1 extra space after byte 3, 11
-				}
-				hexLine.WriteString(" ") // normal space between bytes
-			}
-		}
-
-		line := offsetStr + hexLine.String() + "  " + asciiLine.String()
-		lines = append(lines, line)
-	}
-
-	return strings.Join(lines, "\n")
-}
-
 func (m *Model) getEndianRange(cursor int64) (int64, int64) {
 	if m.bigEndian {
 		return cursor, cursor + 15
@@ -1468,14 +2298,35 @@ func (m *Model) renderDecoder() string {
 		return ""
 	}
 
+	st := m.styles
+	if _, preview := m.decoderAnchor(tab); preview {
+		st = previewStyles(st)
+	}
+
 	var b strings.Builder
 
 	endianStr := "Big"
 	if !m.bigEndian {
 		endianStr = "Little"
 	}
-	b.WriteString(m.styles.DecoderLabel.Render("Endianness: "))
-	b.WriteString(m.styles.DecoderValue.Render(endianStr))
+	b.WriteString(st.DecoderLabel.Render("Endianness: "))
+	b.WriteString(st.DecoderValue.Render(endianStr))
+	if tab.Nibbles {
+		b.WriteString(st.DecoderLabel.Render("   Nibble: "))
+		b.WriteString(st.DecoderValue.Render(m.nibblePosition(tab)))
+	}
+	if typing := m.typingReadout(tab); typing != "" {
+		b.WriteString(st.DecoderLabel.Render("   Typing: "))
+		b.WriteString(st.DecoderValue.Render(typing))
+	}
+	if sel := m.selectionReadout(); sel != "" {
+		b.WriteString(st.DecoderLabel.Render("   Selection: "))
+		b.WriteString(st.DecoderValue.Render(sel))
+	}
+	if fields := m.bitfieldReadout(tab, m.width-lipgloss.Width(b.String())-3); fields != "" {
+		b.WriteString(st.DecoderLabel.Render("   "))
+		b.WriteString(st.DecoderValue.Render(fields))
+	}
 	b.WriteString("\n")
 
 	// Get bytes for decoding
@@ -1484,7 +2335,7 @@ func (m *Model) renderDecoder() string {
 	// Bit string (128 bits) - split into two rows of 64 bits each
 	// Color coded by bit-width: byte 0 = marker, byte 1 = 16-bit, bytes 2-3 = 32-bit, etc.
 	// First row: Bits (0-63) - bytes 0-7
-	b.WriteString(m.styles.DecoderLabel.Render("Bits (0-63):   "))
+	b.WriteString(st.DecoderLabel.Render("Bits (0-63):   "))
 	if len(bytes) > 0 {
 		for i := 0; i < 8 && i < len(bytes); i++ {
 			if i > 0 {
@@ -1494,13 +2345,13 @@ func (m *Model) renderDecoder() string {
 			// Apply color based on byte index
 			switch {
 			case i == 0:
-				b.WriteString(m.styles.MarkerNormal.Render(bitStr))
+				b.WriteString(st.MarkerNormal.Render(bitStr))
 			case i == 1:
-				b.WriteString(m.styles.Bit16.Render(bitStr))
+				b.WriteString(st.Bit16.Render(bitStr))
 			case i >= 2 && i <= 3:
-				b.WriteString(m.styles.Bit32.Render(bitStr))
+				b.WriteString(st.Bit32.Render(bitStr))
 			case i >= 4 && i <= 7:
-				b.WriteString(m.styles.Bit64.Render(bitStr))
+				b.WriteString(st.Bit64.Render(bitStr))
 			}
 		}
 	} else {
@@ -1508,137 +2359,166 @@ func (m *Model) renderDecoder() string {
 	}
 	b.WriteString("\n")
 
-	// Second row: Bits (64-127) - bytes 8-15 (all 128-bit color)
-	b.WriteString(m.styles.DecoderLabel.Render("Bits (64-127): "))
-	if len(bytes) > 8 {
+	// Second row: Bits (64-127) - bytes 8-15 (all 128-bit color), or the
+	// selection's stats while there is one
+	if stats := m.selectionStats(); stats != nil {
+		m.writeSelectionStats(&b, stats)
+	} else if len(bytes) > 8 {
+		b.WriteString(st.DecoderLabel.Render("Bits (64-127): "))
 		for i := 8; i < 16 && i < len(bytes); i++ {
 			if i > 8 {
 				b.WriteString(" ")
 			}
 			bitStr := fmt.Sprintf("%08b", bytes[i])
-			b.WriteString(m.styles.Bit128.Render(bitStr))
+			b.WriteString(st.Bit128.Render(bitStr))
 		}
 	} else {
-		b.WriteString("-")
+		b.WriteString(st.DecoderLabel.Render("Bits (64-127): ") + "-")
 	}
 	b.WriteString("\n")
 
 	// Integer values (8-32 bit) with bit-width color coding
 	// u8/i8 - uses MarkerNormal style (matches cursor byte in hex panel)
-	b.WriteString(m.styles.MarkerNormal.Render("u8: "))
+	b.WriteString(st.MarkerNormal.Render("u8: "))
 	if len(bytes) >= 1 {
-		b.WriteString(m.styles.MarkerNormal.Render(m.formatInt(bytes[:1], false)))
+		b.WriteString(st.MarkerNormal.Render(m.formatInt(bytes[:1], false)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
-	b.WriteString(m.styles.MarkerNormal.Render("i8: "))
+	b.WriteString(st.MarkerNormal.Render("i8: "))
 	if len(bytes) >= 1 {
-		b.WriteString(m.styles.MarkerNormal.Render(m.formatInt(bytes[:1], true)))
+		b.WriteString(st.MarkerNormal.Render(m.formatInt(bytes[:1], true)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
 
 	// u16/i16 - uses Bit16 style
-	b.WriteString(m.styles.Bit16.Render("u16: "))
+	b.WriteString(st.Bit16.Render("u16: "))
 	if len(bytes) >= 2 {
-		b.WriteString(m.styles.Bit16.Render(m.formatInt(bytes[:2], false)))
+		b.WriteString(st.Bit16.Render(m.formatInt(bytes[:2], false)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
-	b.WriteString(m.styles.Bit16.Render("i16: "))
+	b.WriteString(st.Bit16.Render("i16: "))
 	if len(bytes) >= 2 {
-		b.WriteString(m.styles.Bit16.Render(m.formatInt(bytes[:2], true)))
+		b.WriteString(st.Bit16.Render(m.formatInt(bytes[:2], true)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
 
 	// u32/i32 - uses Bit32 style
-	b.WriteString(m.styles.Bit32.Render("u32: "))
+	b.WriteString(st.Bit32.Render("u32: "))
 	if len(bytes) >= 4 {
-		b.WriteString(m.styles.Bit32.Render(m.formatInt(bytes[:4], false)))
+		b.WriteString(st.Bit32.Render(m.formatInt(bytes[:4], false)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
-	b.WriteString(m.styles.Bit32.Render("i32: "))
+	b.WriteString(st.Bit32.Render("i32: "))
 	if len(bytes) >= 4 {
-		b.WriteString(m.styles.Bit32.Render(m.formatInt(bytes[:4], true)))
+		b.WriteString(st.Bit32.Render(m.formatInt(bytes[:4], true)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("\n")
 
 	// 64-bit integers (separate row) - uses Bit64 style
-	b.WriteString(m.styles.Bit64.Render("u64: "))
+	b.WriteString(st.Bit64.Render("u64: "))
 	if len(bytes) >= 8 {
-		b.WriteString(m.styles.Bit64.Render(m.formatInt(bytes[:8], false)))
+		b.WriteString(st.Bit64.Render(m.formatInt(bytes[:8], false)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
-	b.WriteString(m.styles.Bit64.Render("i64: "))
+	b.WriteString(st.Bit64.Render("i64: "))
 	if len(bytes) >= 8 {
-		b.WriteString(m.styles.Bit64.Render(m.formatInt(bytes[:8], true)))
+		b.WriteString(st.Bit64.Render(m.formatInt(bytes[:8], true)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("\n")
 
 	// 128-bit integers (separate row) - uses Bit128 style
-	b.WriteString(m.styles.Bit128.Render("u128: "))
+	b.WriteString(st.Bit128.Render("u128: "))
 	if len(bytes) >= 16 {
-		b.WriteString(m.styles.Bit128.Render(m.formatInt(bytes[:16], false)))
+		b.WriteString(st.Bit128.Render(m.formatInt(bytes[:16], false)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
-	b.WriteString(m.styles.Bit128.Render("i128: "))
+	b.WriteString(st.Bit128.Render("i128: "))
 	if len(bytes) >= 16 {
-		b.WriteString(m.styles.Bit128.Render(m.formatInt(bytes[:16], true)))
+		b.WriteString(st.Bit128.Render(m.formatInt(bytes[:16], true)))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("\n")
 
 	// Float values - use corresponding bit-width styles
-	b.WriteString(m.styles.Bit32.Render("f32: "))
+	b.WriteString(st.Bit32.Render("f32: "))
 	if len(bytes) >= 4 {
-		b.WriteString(m.styles.Bit32.Render(m.formatFloat32(bytes[:4])))
+		b.WriteString(st.Bit32.Render(m.formatFloat32(bytes[:4])))
 	} else {
 		b.WriteString("-")
 	}
 	b.WriteString("  ")
 
-	b.WriteString(m.styles.Bit64.Render("f64: "))
+	b.WriteString(st.Bit64.Render("f64: "))
 	if len(bytes) >= 8 {
-		b.WriteString(m.styles.Bit64.Render(m.formatFloat64(bytes[:8])))
+		b.WriteString(st.Bit64.Render(m.formatFloat64(bytes[:8])))
 	} else {
 		b.WriteString("-")
 	}
+	m.writeTimeGuesses(&b, bytes)
+	m.writeDecoderRows(&b, st)
 
 	return b.String()
 }
 
+// writeTimeGuesses appends the plausible timestamps at the cursor to the
+// last line of b, as many as fit; nothing is shown when none are found.
+func (m *Model) writeTimeGuesses(b *strings.Builder, bytes []byte) {
+	var order binary.ByteOrder = binary.BigEndian
+	if !m.bigEndian {
+		order = binary.LittleEndian
+	}
+	s := b.String()
+	width := lipgloss.Width(s[strings.LastIndex(s, "\n")+1:])
+	label := "  time: "
+	for _, g := range guessTimes(bytes, order) {
+		text := g.String()
+		if width+len(label)+len(text) > m.width {
+			break
+		}
+		b.WriteString(m.styles.DecoderLabel.Render(label))
+		b.WriteString(m.styles.DecoderValue.Render(text))
+		width += len(label) + len(text)
+		label = "  "
+	}
+}
+
 func (m *Model) getDecoderBytes(count int) []byte {
 	tab := m.currentTab()
 	if tab == nil {
 		return nil
 	}
 
+	at, _ := m.decoderAnchor(tab)
+	patch := m.typingPatch(tab)
 	if m.bigEndian {
-		return tab.Buffer.GetBytes(tab.Cursor, count)
+		return patch.apply(at, tab.Buffer.GetBytes(at, count))
 	}
 
 	// Little endian - get bytes before cursor
-	start := tab.Cursor - int64(count) + 1
+	start := at - int64(count) + 1
 	if start < 0 {
 		start = 0
 	}
-	bytes := tab.Buffer.GetBytes(start, int(tab.Cursor-start+1))
+	bytes := patch.apply(start, tab.Buffer.GetBytes(start, int(at-start+1)))
 
 	// Reverse for little endian interpretation
 	result := make([]byte, len(bytes))
@@ -1733,52 +2613,6 @@ func (m *Model) formatFloat64(bytes []byte) string {
 	return fmt.Sprintf("%g", f)
 }
 
-func (m *Model) renderHelp() string {
-	help := `
-HELP - Unhexed Hex Editor
-========================
-
-NAVIGATION
-  Arrow keys      Move cursor
-  Shift+Arrows    Select bytes
-  PgUp/PgDown     Page up/down
-  Home/End        Start/end of line
-  Ctrl+Home/End   Start/end of file
-
-FILE OPERATIONS
-  O               Open file
-  S / Ctrl+S      Save file
-  A               Save As
-  N               New file
-  Ctrl+W          Close tab
-  TAB             Next tab
-  Shift+TAB       Previous tab
-
-EDITING
-  I               Enter Insert mode
-  R               Enter Replace mode
-  ESC             Exit Insert/Replace mode
-  Ctrl+X          Cut
-  Ctrl+C          Copy
-  Ctrl+V          Paste
-  Delete          Delete byte at cursor
-  Backspace       Delete byte before cursor
-  U               Undo
-  D               Redo
-
-OTHER
-  F               Find
-  G               Goto offset
-  E               Toggle endianness
-  H               Help (this screen)
-  C               Configuration
-  Q               Quit
-
-Press ESC or H to close this help screen.
-`
-	return help
-}
-
 func (m *Model) renderConfig() string {
 	var b strings.Builder
 	b.WriteString("\nCONFIGURATION\n")
@@ -1809,6 +2643,7 @@ func (m *Model) renderConfig() string {
 	}
 
 	b.WriteString("\nUse Up/Down to navigate, type to edit, ESC to exit\n")
+	b.WriteString(m.renderConfigPrompt())
 
 	return b.String()
 }
@@ -1833,16 +2668,34 @@ func (m *Model) renderFind() string {
 		if mode.key == m.findMode {
 			prefix = "> "
 		}
-		b.WriteString(fmt.Sprintf("%s%s: ", prefix, mode.label))
+		label := mode.label
+		if mode.key == "decimal" {
+			label += " (" + m.findWidthLabel() + ")"
+		}
+		b.WriteString(fmt.Sprintf("%s%s: ", prefix, label))
 		if mode.key == m.findMode {
-			b.WriteString(m.findInput)
+			if mode.key == "hex" {
+				b.WriteString(m.renderHexFindInput())
+			} else {
+				b.WriteString(m.findInput)
+			}
 			b.WriteString("_")
 		}
 		b.WriteString("\n")
 	}
 
-	b.WriteString(fmt.Sprintf("\nMatches: %d\n", m.findMatches))
-	b.WriteString("\nPress Enter to find next, ESC to close\n")
+	if m.findCounting {
+		b.WriteString("\nMatches: counting…\n")
+	} else {
+		b.WriteString(fmt.Sprintf("\nMatches: %d\n", m.findMatches))
+	}
+	direction := "forward ↓"
+	if m.findBackward {
+		direction = "backward ↑"
+	}
+	b.WriteString("Direction: " + direction + "\n")
+	b.WriteString("Aligned:   " + m.findAlignLabel() + "\n")
+	b.WriteString("\nPress Enter to find the next match, Tab to reverse, Ctrl+L to change the alignment, Ctrl+A to find in all tabs, Ctrl+U to clear, ESC to close\n")
 
 	return b.String()
 }
@@ -1854,7 +2707,10 @@ func (m *Model) renderGoto() string {
 	b.WriteString("Offset: ")
 	b.WriteString(m.gotoInput)
 	b.WriteString("_\n\n")
-	b.WriteString("(Prefix with 0x for hex offset)\n")
+	b.WriteString(fmt.Sprintf("(Prefix with 0x for hex offset, s for a %d-byte sector)\n", m.sectorSize()))
+	if tab := m.currentTab(); tab != nil && m.displayBase(tab) > 0 {
+		b.WriteString(fmt.Sprintf("(Offsets are in the file; this window starts at 0x%X)\n", m.displayBase(tab)))
+	}
 	b.WriteString("\nPress Enter to go, ESC to close\n")
 
 	return b.String()
@@ -1866,7 +2722,11 @@ func (m *Model) renderOpen() string {
 	b.WriteString("=========\n\n")
 	b.WriteString("Path: ")
 	b.WriteString(m.browserPath)
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if m.browserErr != "" {
+		b.WriteString(m.styles.StatusWarning.Render(m.browserErr) + "\n")
+	}
+	b.WriteString("\n")
 
 	// File list
 	visibleItems := 15
@@ -1885,6 +2745,9 @@ func (m *Model) renderOpen() string {
 		if item.IsDir() {
 			name += "/"
 		}
+		if m.browserDenied[item.Name()] {
+			name = m.styles.Disabled.Render(name)
+		}
 		b.WriteString(fmt.Sprintf("%s%s\n", prefix, name))
 	}
 
@@ -1893,13 +2756,21 @@ func (m *Model) renderOpen() string {
 	// Buttons
 	btn1 := "[Open in current tab]"
 	btn2 := "[Open in new tab]"
+	btn3 := "[Open range…]"
+	btn4 := "[Open all…]"
 	if m.browserFocus == 1 {
 		btn1 = ">" + btn1 + "<"
 	}
 	if m.browserFocus == 2 {
 		btn2 = ">" + btn2 + "<"
 	}
-	b.WriteString(fmt.Sprintf("%s  %s\n", btn1, btn2))
+	if m.browserFocus == 3 {
+		btn3 = ">" + btn3 + "<"
+	}
+	if m.browserFocus == 4 {
+		btn4 = ">" + btn4 + "<"
+	}
+	b.WriteString(fmt.Sprintf("%s  %s  %s  %s\n", btn1, btn2, btn3, btn4))
 
 	return b.String()
 }
@@ -1916,15 +2787,6 @@ func (m *Model) renderSaveAs() string {
 	return b.String()
 }
 
-func (m *Model) renderConfirmDialog(message string) string {
-	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(m.config.Theme.BorderColor)).
-		Padding(1, 2).
-		Render(message)
-	return box
-}
-
 func isHexChar(s string) bool {
 	if len(s) != 1 {
 		return false
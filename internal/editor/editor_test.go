@@ -0,0 +1,405 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/config"
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestModel(data []byte) *Model {
+	cfg := config.DefaultConfig()
+	buf := buffer.New()
+	buf.Insert(0, data)
+
+	m := &Model{
+		view:      ViewMain,
+		bigEndian: true,
+		width:     200,
+		height:    70,
+		config:    cfg,
+		styles:    config.NewStyles(&cfg.Theme),
+		findMode:  "ascii",
+		findWidth: 1,
+		registers: make(map[rune]*register),
+		clock:     systemClock{},
+		fs:        systemFS{},
+	}
+	m.tabs = []*Tab{m.newTab(buf)}
+	return m
+}
+
+func typeKeys(m *Model, s string) {
+	for _, r := range s {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestEscapeExitsModeBeforeClearingSelection(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0x11, 0x22, 0x33})
+	tab := m.currentTab()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	typeKeys(m, "i")
+	if !tab.Selection.Active || m.mode != ModeInsert {
+		t.Fatalf("setup: selection %v, mode %v", tab.Selection.Active, m.mode)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.mode != ModeNormal {
+		t.Fatalf("first Esc should leave insert mode, mode is %v", m.mode)
+	}
+	if !tab.Selection.Active {
+		t.Fatal("first Esc should keep the selection")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if tab.Selection.Active || tab.Selection.Block {
+		t.Error("second Esc should clear the selection")
+	}
+}
+
+func TestEscapeClearsPendingCountAndRegister(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+	tab := m.currentTab()
+
+	typeKeys(m, `5"a`)
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.pendingCount != 0 || m.pendingRegister != 0 || m.registerPrefix {
+		t.Fatalf("pending state survived Esc: count %d, register %q", m.pendingCount, m.pendingRegister)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != 1 {
+		t.Errorf("count should not apply after Esc, cursor at %d", tab.Cursor)
+	}
+
+	// Nothing pending: Esc is a silent no-op
+	seq := m.statusSeq
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.statusSeq != seq || tab.Cursor != 1 {
+		t.Errorf("unexpected effect of idle Esc: status %q, cursor %d", m.status.text, tab.Cursor)
+	}
+}
+
+func TestEscapeDiscardsHalfEnteredNibble(t *testing.T) {
+	m := newTestModel([]byte{0xAB})
+	tab := m.currentTab()
+
+	typeKeys(m, "r")
+	typeKeys(m, "c")
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.hexNibble != 0 {
+		t.Errorf("Esc should reset the nibble position, got %d", m.hexNibble)
+	}
+	if b, _ := tab.Buffer.GetByte(0); b != 0xAB {
+		t.Errorf("half-entered byte should be dropped, got %02X", b)
+	}
+
+	// The next edit starts a fresh byte
+	typeKeys(m, "r")
+	typeKeys(m, "de")
+	if b, _ := tab.Buffer.GetByte(0); b != 0xDE {
+		t.Errorf("expected DE after re-entering replace mode, got %02X", b)
+	}
+}
+
+func TestReplaceStride(t *testing.T) {
+	m := newTestModel(make([]byte, 20))
+	tab := m.currentTab()
+
+	typeKeys(m, "8r")
+	typeKeys(m, "aabbcc")
+	want := make([]byte, 20)
+	want[0], want[8], want[16] = 0xAA, 0xBB, 0xCC
+	if got := tab.Buffer.GetBytes(0, 20); string(got) != string(want) {
+		t.Errorf("expected every 8th byte replaced, got % X", got)
+	}
+	if tab.Cursor != 19 || m.status.sev != sevWarning {
+		t.Errorf("expected the cursor clamped at the end with a warning, got %d %q", tab.Cursor, m.status.text)
+	}
+
+	// Undo takes back one typed byte at a time
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	typeKeys(m, "u")
+	if got := tab.Buffer.GetBytes(0, 20); got[16] != 0 || got[8] != 0xBB {
+		t.Errorf("expected only the last byte undone, got % X", got)
+	}
+
+	// Leaving replace mode resets the stride
+	typeKeys(m, "r")
+	if m.stride != 1 {
+		t.Errorf("expected stride 1 after Esc, got %d", m.stride)
+	}
+}
+
+func TestReplaceStridePrompt(t *testing.T) {
+	m := newTestModel(make([]byte, 20))
+	tab := m.currentTab()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}, Alt: true})
+	typeKeys(m, "0")
+	press(m, tea.KeyEnter)
+	if m.view != ViewStride || m.mode == ModeReplace {
+		t.Fatalf("a stride of 0 should be refused, view %d mode %d", m.view, m.mode)
+	}
+	press(m, tea.KeyBackspace)
+	typeKeys(m, "0x10")
+	press(m, tea.KeyEnter)
+	if m.view != ViewMain || m.mode != ModeReplace || m.stride != 16 {
+		t.Fatalf("expected Replace mode with stride 16, got view %d mode %d stride %d", m.view, m.mode, m.stride)
+	}
+
+	m.status.text = ""
+	if !strings.Contains(m.renderStatus(), "stride 16") {
+		t.Errorf("the status bar should show the stride, got %q", m.renderStatus())
+	}
+	typeKeys(m, "aabb")
+	if got := tab.Buffer.GetBytes(0, 20); got[0] != 0xAA || got[16] != 0xBB {
+		t.Errorf("expected bytes 0 and 16 replaced, got % X", got)
+	}
+
+	typeKeys(m, "r")
+	if strings.Contains(m.renderStatus(), "stride") {
+		t.Errorf("a stride of 1 isn't shown, got %q", m.renderStatus())
+	}
+}
+
+func TestGotoReportsOffsetsBeyondEOF(t *testing.T) {
+	m := newTestModel(make([]byte, 0x1000))
+	tab := m.currentTab()
+
+	typeKeys(m, "g0x5000")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain || tab.Cursor != 0xFFF {
+		t.Fatalf("expected to land on the last byte, view %v, cursor %X", m.view, tab.Cursor)
+	}
+	if want := "Offset 0x5000 beyond EOF (size 0x1000), moved to end"; m.status.text != want {
+		t.Errorf("expected %q, got %q", want, m.status.text)
+	}
+}
+
+func TestGotoKeepsDialogOpenOnInvalidInput(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+	tab := m.currentTab()
+	tab.Cursor = 10
+
+	typeKeys(m, "g1f")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewGoto || m.gotoInput != "1f" {
+		t.Fatalf("expected the dialog to stay open with its input, view %v, input %q", m.view, m.gotoInput)
+	}
+	if tab.Cursor != 10 || m.status.sev != sevWarning {
+		t.Errorf("invalid input moved the cursor to %d, status %q", tab.Cursor, m.status.text)
+	}
+}
+
+func TestGotoOnEmptyBuffer(t *testing.T) {
+	m := newTestModel(nil)
+	tab := m.currentTab()
+	tab.ScrollY = 3
+
+	typeKeys(m, "g0x10")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 0 || tab.ScrollY != 0 {
+		t.Errorf("expected cursor and scroll at 0, got %d and %d", tab.Cursor, tab.ScrollY)
+	}
+	if m.view != ViewMain {
+		t.Errorf("expected the dialog to close, view %v", m.view)
+	}
+}
+
+func TestInsertIntoEmptyBuffer(t *testing.T) {
+	m := newTestModel(nil)
+	tab := m.currentTab()
+
+	typeKeys(m, "iabcd")
+	if got := tab.Buffer.Data(); len(got) != 2 || got[0] != 0xAB || got[1] != 0xCD {
+		t.Fatalf("expected AB CD, got % X", got)
+	}
+	if tab.Cursor != 2 {
+		t.Errorf("expected the cursor after the last byte, got %d", tab.Cursor)
+	}
+}
+
+func TestInsertAppendsAtEOF(t *testing.T) {
+	m := newTestModel(make([]byte, bytesPerRow))
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	tab := m.currentTab()
+
+	// Normal mode stops on the last byte
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlEnd})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != bytesPerRow-1 {
+		t.Fatalf("normal mode cursor went past the last byte to %d", tab.Cursor)
+	}
+
+	// Insert mode may step onto the phantom cell after it
+	typeKeys(m, "i")
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != bytesPerRow {
+		t.Fatalf("expected the insert cursor at EOF, got %d", tab.Cursor)
+	}
+	if rows := strings.Count(m.renderEditor(), "\n") + 1; rows != 2 {
+		t.Errorf("expected a phantom row for the append cursor, got %d rows", rows)
+	}
+
+	typeKeys(m, "ff")
+	if size := tab.Buffer.Size(); size != bytesPerRow+1 {
+		t.Fatalf("expected an appended byte, size %d", size)
+	}
+	if b, _ := tab.Buffer.GetByte(bytesPerRow); b != 0xFF {
+		t.Errorf("expected FF appended, got %02X", b)
+	}
+
+	// Leaving insert mode puts the cursor back on real data
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if tab.Cursor != bytesPerRow {
+		t.Errorf("expected the cursor on the last byte, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != bytesPerRow {
+		t.Errorf("normal mode cursor moved past EOF to %d", tab.Cursor)
+	}
+}
+
+func TestHomeEndOnPartialRow(t *testing.T) {
+	m := newTestModel(make([]byte, bytesPerRow+5))
+	tab := m.currentTab()
+
+	m.setCursor(bytesPerRow + 2)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if tab.Cursor != bytesPerRow+4 {
+		t.Errorf("End on a partial row should stop at the last byte, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if tab.Cursor != bytesPerRow {
+		t.Errorf("expected Home at the row start, got %d", tab.Cursor)
+	}
+}
+
+func TestShiftHomeEndSelect(t *testing.T) {
+	m := newTestModel(make([]byte, 4*bytesPerRow))
+	tab := m.currentTab()
+	m.setCursor(bytesPerRow + 5)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftEnd})
+	if start, end := m.getSelectedRange(); start != bytesPerRow+5 || end != 2*bytesPerRow-1 {
+		t.Fatalf("expected selection to the row end, got [%d,%d]", start, end)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftHome})
+	if start, end := m.getSelectedRange(); start != bytesPerRow || end != bytesPerRow+5 {
+		t.Errorf("expected selection back to the row start, got [%d,%d]", start, end)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlShiftEnd})
+	if start, end := m.getSelectedRange(); start != bytesPerRow+5 || end != 4*bytesPerRow-1 {
+		t.Errorf("expected selection to EOF, got [%d,%d]", start, end)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlShiftHome})
+	if start, end := m.getSelectedRange(); start != 0 || end != bytesPerRow+5 {
+		t.Errorf("expected selection to the start of file, got [%d,%d]", start, end)
+	}
+
+	// Plain Home drops the selection
+	m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if tab.Selection.Active {
+		t.Error("Home should clear the selection")
+	}
+}
+
+func TestVerticalMotionKeepsGoalColumn(t *testing.T) {
+	m := newTestModel(make([]byte, 3*bytesPerRow+4))
+	tab := m.currentTab()
+	tab.Cursor = 12
+
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyDown)
+	if want := int64(3*bytesPerRow + 3); tab.Cursor != want {
+		t.Fatalf("short last row should clamp to %d, got %d", want, tab.Cursor)
+	}
+	press(m, tea.KeyUp)
+	if want := int64(2*bytesPerRow + 12); tab.Cursor != want {
+		t.Errorf("moving up should restore column 12, got offset %d", tab.Cursor)
+	}
+
+	press(m, tea.KeyPgDown, tea.KeyPgUp)
+	if tab.Cursor != 12 {
+		t.Errorf("paging should keep column 12, got offset %d", tab.Cursor)
+	}
+
+	// End starts a new goal on the short row
+	tab.Cursor = 3 * bytesPerRow
+	press(m, tea.KeyEnd, tea.KeyUp)
+	if want := int64(2*bytesPerRow + 3); tab.Cursor != want {
+		t.Errorf("after End the goal should be column 3, got offset %d", tab.Cursor)
+	}
+}
+
+func TestBrowserKeepsTheListingWhenADirectoryFails(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "gone")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data.bin"), []byte{1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.view = ViewOpen
+	m.browse(dir)
+	if len(m.browserItems) != 3 || m.browserItems[1].Name() != "gone" {
+		t.Fatalf("unexpected listing %v", m.browserItems)
+	}
+
+	// Removed after it was listed
+	os.Remove(sub)
+	m.browserIndex = 1
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.browserPath != dir || len(m.browserItems) != 3 {
+		t.Errorf("the listing of %s should stay, got %s with %d items", dir, m.browserPath, len(m.browserItems))
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if out := m.renderOpen(); !strings.Contains(out, "Can't open "+sub+": no such file or directory") {
+		t.Errorf("the reason should stay in the browser:\n%s", out)
+	}
+
+	m.browse(dir)
+	if m.browserErr != "" {
+		t.Errorf("a listing that loads should clear the reason, got %q", m.browserErr)
+	}
+}
+
+func TestBrowserRefusesUnreadableEntries(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "secret"), []byte{1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.view = ViewOpen
+	m.browse(dir)
+	// As if the permissions denied us, which they don't for root
+	m.browserDenied["secret"] = true
+	m.browserIndex = 1
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewOpen || m.loading != nil {
+		t.Fatal("an unreadable file should not be opened")
+	}
+	if !strings.Contains(m.browserErr, "Can't open secret: no read permission (-rw-r--r--") {
+		t.Errorf("got %q", m.browserErr)
+	}
+
+	// Deleted since it was listed
+	if err := os.Remove(filepath.Join(dir, "secret")); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.browserErr != "Can't open secret: it no longer exists" {
+		t.Errorf("got %q", m.browserErr)
+	}
+}
@@ -0,0 +1,376 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Ctrl+A in the Find dialog searches every open tab for the pattern and
+// lists the hits grouped by tab. Tabs showing the same buffer are searched
+// once. From the list, R replaces every match in every tab, each buffer's
+// replacements being one undo step; read-only tabs are skipped. The hits
+// listed are the ones replaced, so if a buffer changed since the search
+// it is searched again first. Value
+// scans (see valuescan.go) list their hits here too.
+
+const findAllListed = 256 // hits listed per tab; all of them are counted
+
+type findAllGroup struct {
+	tab       *Tab
+	hits      []int64
+	notes     []string // for each hit of a value scan, the byte order that matched
+	truncated bool     // a value scan stopped at valueScanLimit
+}
+
+type findAllMsg struct {
+	seq      int
+	groups   []*findAllGroup
+	versions map[*buffer.Buffer]uint64 // of the buffers searched
+}
+
+type findAllState struct {
+	pattern   []byte
+	groups    []*findAllGroup
+	versions  map[*buffer.Buffer]uint64 // of the buffers groups are from
+	sel       int                       // row in rows()
+	scanning  bool
+	replacing bool   // typing the replacement
+	input     string // replacement, as hex
+	summary   []string
+	seq       int
+	cancel    context.CancelFunc
+	scan      *valueScan // what a value scan looks for, instead of pattern
+	tab       *Tab       // the tab a value scan looks in
+}
+
+// findAllRow is a line of the results: a tab's header when hit < 0.
+type findAllRow struct {
+	group int
+	hit   int
+}
+
+func (s *findAllState) rows() []findAllRow {
+	var rows []findAllRow
+	for g, group := range s.groups {
+		rows = append(rows, findAllRow{g, -1})
+		for h := range min(len(group.hits), findAllListed) {
+			rows = append(rows, findAllRow{g, h})
+		}
+	}
+	return rows
+}
+
+// openFindAll searches all tabs for the Find dialog's pattern.
+func (m *Model) openFindAll() tea.Cmd {
+	pattern := m.getFindPattern()
+	if len(pattern) == 0 {
+		m.setStatus(sevWarning, "Nothing to find")
+		return nil
+	}
+	m.lastFind = pattern
+	if m.findAll != nil && m.findAll.cancel != nil {
+		m.findAll.cancel()
+	}
+	m.findAll = &findAllState{pattern: pattern}
+	m.view = ViewFindAll
+	return m.scanAllTabs()
+}
+
+// scanAllTabs searches snapshots of the tabs' buffers in the background.
+func (m *Model) scanAllTabs() tea.Cmd {
+	s := m.findAll
+	m.findAllSeq++
+	s.seq = m.findAllSeq
+	s.scanning = true
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	var tabs []*Tab
+	var snapshots []*buffer.Buffer
+	versions := make(map[*buffer.Buffer]uint64)
+	for _, tab := range m.tabs {
+		if _, ok := versions[tab.Buffer]; !ok {
+			tabs = append(tabs, tab)
+			snapshots = append(snapshots, tab.Buffer.Snapshot())
+			versions[tab.Buffer] = tab.Buffer.Version()
+		}
+	}
+	pattern, seq := s.pattern, s.seq
+	return func() tea.Msg {
+		var groups []*findAllGroup
+		for i, snap := range snapshots {
+			hits, err := snap.FindAllContext(ctx, pattern)
+			if err != nil {
+				return nil
+			}
+			if len(hits) > 0 {
+				groups = append(groups, &findAllGroup{tab: tabs[i], hits: hits})
+			}
+		}
+		return findAllMsg{seq: seq, groups: groups, versions: versions}
+	}
+}
+
+func (m *Model) handleFindAllResult(msg findAllMsg) (tea.Model, tea.Cmd) {
+	s := m.findAll
+	if s == nil || msg.seq != s.seq {
+		return m, nil
+	}
+	for buf, version := range msg.versions {
+		if buf.Version() != version {
+			// A buffer changed during the scan; its offsets may be wrong
+			return m, m.rescanFindAll()
+		}
+	}
+	s.groups, s.versions = msg.groups, msg.versions
+	s.scanning = false
+	s.cancel = nil
+	s.sel = max(min(s.sel, len(s.rows())-1), 0)
+	return m, nil
+}
+
+// rescanFindAll runs the search or value scan the results are from again.
+func (m *Model) rescanFindAll() tea.Cmd {
+	if m.findAll.scan != nil {
+		return m.scanValue()
+	}
+	return m.scanAllTabs()
+}
+
+func (m *Model) handleFindAllKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.findAll
+	if s == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	if s.replacing {
+		switch msg.Type {
+		case tea.KeyEscape:
+			s.replacing = false
+		case tea.KeyEnter:
+			m.confirmReplaceAll()
+		case tea.KeyBackspace:
+			s.input = dropHexToken(s.input)
+		case tea.KeyCtrlU:
+			s.input = ""
+		default:
+			if char := msg.String(); len(char) == 1 && isHexInputChar(char) {
+				s.input += char
+			}
+		}
+		return m, nil
+	}
+
+	rows := s.rows()
+	switch msg.String() {
+	case "esc":
+		if s.cancel != nil {
+			s.cancel()
+		}
+		m.view = ViewFind
+		if s.scan != nil {
+			m.view = ViewValueScan
+		}
+	case "up":
+		s.sel = max(s.sel-1, 0)
+	case "down":
+		s.sel = max(min(s.sel+1, len(rows)-1), 0)
+	case "pgup":
+		s.sel = max(s.sel-m.findAllPage(), 0)
+	case "pgdown":
+		s.sel = max(min(s.sel+m.findAllPage(), len(rows)-1), 0)
+	case "enter":
+		if s.sel < len(rows) {
+			m.jumpToFindAllRow(rows[s.sel])
+		}
+	case "r", "R":
+		if !s.scanning && len(s.groups) > 0 && s.scan == nil {
+			s.replacing = true
+			s.input = ""
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) findAllPage() int {
+	return max(m.height-12, 5)
+}
+
+func (m *Model) jumpToFindAllRow(row findAllRow) {
+	group := m.findAll.groups[row.group]
+	i := slices.Index(m.tabs, group.tab)
+	if i < 0 {
+		m.setStatus(sevWarning, "That tab has been closed")
+		return
+	}
+	m.selectTab(i)
+	m.view = ViewMain
+	m.setCursor(group.hits[max(row.hit, 0)])
+}
+
+func (m *Model) confirmReplaceAll() {
+	s := m.findAll
+	with, pending, err := parseHexBytes(s.input)
+	if err != nil || pending >= 0 {
+		m.setStatus(sevWarning, "Replacement must be whole hex bytes")
+		return
+	}
+	s.replacing = false
+	total := 0
+	for _, group := range s.groups {
+		total += len(group.hits)
+	}
+	m.openDialog(fmt.Sprintf("Replace %d matches in %d tabs with % X?", total, len(s.groups), with),
+		m.cancelButton(ViewFindAll),
+		dialogButton{label: "Replace all", action: func() (tea.Model, tea.Cmd) {
+			m.view = ViewFindAll
+			return m, m.replaceAll(with)
+		}})
+}
+
+// replaceAll replaces the pattern in every buffer searched, summarizes
+// what happened to each and searches again.
+func (m *Model) replaceAll(with []byte) tea.Cmd {
+	s := m.findAll
+	for _, group := range s.groups {
+		if buf := group.tab.Buffer; buf.Version() != s.versions[buf] {
+			m.setStatus(sevWarning, "Tabs changed since the search; searching again (R to replace)")
+			return m.scanAllTabs()
+		}
+	}
+	s.summary = nil
+	labels := m.tabLabels()
+	replaced, files := 0, 0
+	for _, group := range s.groups {
+		tab := group.tab
+		i := slices.Index(m.tabs, tab)
+		if i < 0 {
+			continue
+		}
+		name := labels[i]
+		switch {
+		case tab.Buffer.ReadOnly():
+			s.summary = append(s.summary, name+": read-only, skipped")
+			continue
+		case tab.Buffer.FixedSize() && len(with) != len(s.pattern):
+			s.summary = append(s.summary, name+": fixed size, skipped (replacement length differs)")
+			continue
+		}
+
+		n, locked := 0, 0
+		tab.Buffer.BeginGroup()
+		for _, pos := range slices.Backward(group.hits) {
+			var err error
+			if len(with) == len(s.pattern) {
+				err = tab.Buffer.ReplaceBytes(pos, with)
+			} else if err = tab.Buffer.Delete(pos, len(s.pattern)); err == nil {
+				// Inserting where the match was can't split a locked range
+				err = tab.Buffer.Insert(pos, with)
+			}
+			if err != nil {
+				locked++
+				continue
+			}
+			n++
+		}
+		tab.Buffer.EndGroup()
+		tab.Cursor = min(tab.Cursor, m.maxCursor(tab))
+		summary := fmt.Sprintf("%s: replaced %d", name, n)
+		if locked > 0 {
+			summary += fmt.Sprintf(", %d in locked ranges skipped", locked)
+		}
+		s.summary = append(s.summary, summary)
+		replaced += n
+		files++
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Replaced %d matches in %d files", replaced, files))
+	s.sel = 0
+	return m.scanAllTabs()
+}
+
+func (m *Model) renderFindAll() string {
+	s := m.findAll
+	var b strings.Builder
+	if s != nil && s.scan != nil {
+		b.WriteString("\nFIND VALUE AT EVERY OFFSET\n")
+		b.WriteString("==========================\n\n")
+		b.WriteString("Value: " + s.scan.String() + "\n\n")
+	} else {
+		b.WriteString("\nFIND IN ALL TABS\n")
+		b.WriteString("================\n\n")
+		if s == nil {
+			return b.String()
+		}
+		b.WriteString(fmt.Sprintf("Pattern: % X\n\n", s.pattern))
+	}
+
+	for _, line := range s.summary {
+		b.WriteString(line + "\n")
+	}
+	if len(s.summary) > 0 {
+		b.WriteString("\n")
+	}
+
+	rows := s.rows()
+	switch {
+	case s.scanning:
+		b.WriteString("Searching…\n")
+	case len(rows) == 0 && s.scan != nil:
+		b.WriteString("No matches\n")
+	case len(rows) == 0:
+		b.WriteString("No matches in any tab\n")
+	}
+
+	labels := m.tabLabels()
+	page := m.findAllPage()
+	top := max(min(s.sel-page/2, len(rows)-page), 0)
+	for i := top; i < min(top+page, len(rows)); i++ {
+		row := rows[i]
+		group := s.groups[row.group]
+		var line string
+		if row.hit < 0 {
+			label := "(closed)"
+			if t := slices.Index(m.tabs, group.tab); t >= 0 {
+				label = labels[t]
+			}
+			line = fmt.Sprintf("%s  %d matches", label, len(group.hits))
+			if group.truncated {
+				line += " (the scan stopped there)"
+			}
+			if len(group.hits) > findAllListed {
+				line += fmt.Sprintf(" (first %d listed)", findAllListed)
+			}
+			if group.tab.Buffer.ReadOnly() {
+				line += "  read-only"
+			}
+		} else {
+			pos := group.hits[row.hit]
+			line = fmt.Sprintf("    0x%08X  % X", pos+m.displayBase(group.tab), group.tab.Buffer.GetBytes(pos, 16))
+			if group.notes != nil {
+				line += "  " + group.notes[row.hit]
+			}
+		}
+		if i == s.sel {
+			b.WriteString("> " + m.styles.Selection.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if s.replacing {
+		b.WriteString("\nReplace with (hex): " + s.input + "_\n")
+		b.WriteString("\nPress Enter to replace in all tabs, ESC to cancel\n")
+	} else if s.scan != nil {
+		b.WriteString("\nEnter go to match, ESC back to the value\n")
+	} else {
+		b.WriteString("\nEnter go to match, R replace in all tabs, ESC back to Find\n")
+	}
+	return b.String()
+}
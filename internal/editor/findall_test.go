@@ -0,0 +1,98 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// findAllTabs opens three tabs holding "MAGIC", the last one read-only,
+// and searches them all.
+func findAllTabs(t *testing.T) *Model {
+	t.Helper()
+	m := newTestModel([]byte("..MAGIC..MAGIC"))
+	second := m.newTab(buffer.FromData("b.bin", []byte("MAGIC")))
+	locked := m.newTab(buffer.FromData("c.bin", []byte("xxMAGIC")))
+	locked.Buffer.SetReadOnly(true)
+	m.tabs = append(m.tabs, second, locked)
+
+	typeKeys(m, "fMAGIC")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	if cmd == nil {
+		t.Fatalf("expected a search, status %q", m.status.text)
+	}
+	m.Update(cmd())
+	return m
+}
+
+func TestFindAllGroupsHitsByTab(t *testing.T) {
+	m := findAllTabs(t)
+
+	view := m.renderFindAll()
+	for _, want := range []string{"2 matches", "b.bin  1 matches", "c.bin  1 matches  read-only"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("results should contain %q:\n%s", want, view)
+		}
+	}
+
+	// Header of the third tab, then its hit
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	if m.view != ViewMain || m.activeTab != 2 || m.currentTab().Cursor != 2 {
+		t.Errorf("expected the hit in tab 3, got view %d tab %d cursor %d", m.view, m.activeTab, m.currentTab().Cursor)
+	}
+}
+
+func TestReplaceAllSkipsReadOnlyTabs(t *testing.T) {
+	m := findAllTabs(t)
+
+	typeKeys(m, "r4d41 47")
+	press(m, tea.KeyEnter)
+	if m.view != ViewDialog {
+		t.Fatalf("expected a confirmation, status %q", m.status.text)
+	}
+	_, cmd := m.activateButton(1)
+	m.Update(cmd())
+
+	got := func(i int) []byte {
+		buf := m.tabs[i].Buffer
+		return buf.GetBytes(0, int(buf.Size()))
+	}
+	if !bytes.Equal(got(0), []byte("..MAG..MAG")) || !bytes.Equal(got(1), []byte("MAG")) {
+		t.Errorf("got %q and %q", got(0), got(1))
+	}
+	if !bytes.Equal(got(2), []byte("xxMAGIC")) {
+		t.Errorf("read-only tab changed: %q", got(2))
+	}
+	if m.status.text != "Replaced 3 matches in 2 files" {
+		t.Errorf("status %q", m.status.text)
+	}
+	if view := m.renderFindAll(); !strings.Contains(view, "c.bin: read-only, skipped") {
+		t.Errorf("summary should note the skipped tab:\n%s", view)
+	}
+
+	// One undo step per buffer
+	m.tabs[0].Buffer.Undo()
+	if !bytes.Equal(got(0), []byte("..MAGIC..MAGIC")) {
+		t.Errorf("undo should restore every match, got %q", got(0))
+	}
+}
+
+func TestReplaceAllSearchesChangedTabsAgain(t *testing.T) {
+	m := findAllTabs(t)
+	m.tabs[1].Buffer.Insert(0, []byte("MAGIC"))
+
+	typeKeys(m, "r4d41 47")
+	press(m, tea.KeyEnter)
+	_, cmd := m.activateButton(1)
+	if string(m.tabs[1].Buffer.Data()) != "MAGICMAGIC" || !strings.Contains(m.status.text, "searching again") {
+		t.Fatalf("expected nothing replaced in a changed tab, got %q, status %q", m.tabs[1].Buffer.Data(), m.status.text)
+	}
+	m.Update(cmd())
+	if view := m.renderFindAll(); !strings.Contains(view, "b.bin  2 matches") {
+		t.Errorf("expected the new hits listed:\n%s", view)
+	}
+}
@@ -0,0 +1,83 @@
+package editor
+
+import (
+	"context"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const findCountDelay = 150 * time.Millisecond
+
+type findCountTickMsg struct {
+	seq int
+}
+
+type findCountMsg struct {
+	seq     int
+	buf     *buffer.Buffer
+	version uint64 // of buf when counted
+	count   int
+}
+
+// updateFindMatches schedules a recount of the find pattern once typing
+// pauses. Each call supersedes the previous one: its pending tick is
+// ignored and a count already running is cancelled.
+func (m *Model) updateFindMatches() tea.Cmd {
+	m.findSeq++
+	if m.findCancel != nil {
+		m.findCancel()
+		m.findCancel = nil
+	}
+
+	if m.currentTab() == nil || m.findInput == "" {
+		m.findMatches = 0
+		m.findCounting = false
+		return nil
+	}
+
+	m.findCounting = true
+	seq := m.findSeq
+	return m.clock.Tick(findCountDelay, func(time.Time) tea.Msg {
+		return findCountTickMsg{seq: seq}
+	})
+}
+
+func (m *Model) handleFindCountTick(msg findCountTickMsg) (tea.Model, tea.Cmd) {
+	tab := m.currentTab()
+	if msg.seq != m.findSeq || tab == nil {
+		return m, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.findCancel = cancel
+
+	// Count over a snapshot so edits made meanwhile can't race the scan
+	snapshot := tab.Buffer.Snapshot()
+	pattern := m.getFindPattern()
+	align := m.findAlignment(tab)
+	return m, func() tea.Msg {
+		count, err := snapshot.CountMatchesAlignedContext(ctx, pattern, align)
+		if err != nil {
+			return nil
+		}
+		return findCountMsg{seq: msg.seq, buf: tab.Buffer, version: snapshot.Version(), count: count}
+	}
+}
+
+func (m *Model) handleFindCount(msg findCountMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.findSeq {
+		// Result for a pattern that has since changed
+		return m, nil
+	}
+	if tab := m.currentTab(); tab == nil || tab.Buffer != msg.buf || tab.Buffer.Version() != msg.version {
+		// The buffer changed while counting
+		return m.handleFindCountTick(findCountTickMsg{seq: msg.seq})
+	}
+	m.findMatches = msg.count
+	m.findCounting = false
+	m.findCancel = nil
+	return m, nil
+}
@@ -0,0 +1,70 @@
+package editor
+
+import "testing"
+
+func TestFindCountDiscardsStaleResults(t *testing.T) {
+	m := newTestModel([]byte("aaaa"))
+	m.view = ViewFind
+
+	typeKeys(m, "a")
+	staleSeq := m.findSeq
+	typeKeys(m, "a")
+
+	if !m.findCounting {
+		t.Fatal("expected counting to be pending after typing")
+	}
+
+	// A late result for the superseded pattern "a" must be ignored
+	m.Update(findCountMsg{seq: staleSeq, count: 4})
+	if !m.findCounting || m.findMatches != 0 {
+		t.Errorf("stale result applied: counting=%v matches=%d", m.findCounting, m.findMatches)
+	}
+
+	// The debounce tick for the current pattern runs the count
+	_, cmd := m.Update(findCountTickMsg{seq: m.findSeq})
+	if cmd == nil {
+		t.Fatal("expected a count command for the current pattern")
+	}
+	m.Update(cmd())
+
+	if m.findCounting {
+		t.Error("expected counting to finish")
+	}
+	// Overlapping matches are counted: "aa" occurs 3 times in "aaaa"
+	if m.findMatches != 3 {
+		t.Errorf("expected 3 matches, got %d", m.findMatches)
+	}
+}
+
+func TestFindCountIgnoresSupersededTick(t *testing.T) {
+	m := newTestModel([]byte("abc"))
+	m.view = ViewFind
+
+	typeKeys(m, "a")
+	oldSeq := m.findSeq
+	typeKeys(m, "b")
+
+	if _, cmd := m.Update(findCountTickMsg{seq: oldSeq}); cmd != nil {
+		t.Error("expected the superseded tick to be ignored")
+	}
+}
+
+func TestFindCountRestartsAfterAnEdit(t *testing.T) {
+	m := newTestModel([]byte("aaaa"))
+	m.view = ViewFind
+	typeKeys(m, "a")
+
+	_, cmd := m.Update(findCountTickMsg{seq: m.findSeq})
+	msg := cmd()
+	m.currentTab().Buffer.Insert(0, []byte("aa"))
+
+	// Counted before the insert: counted again rather than shown
+	_, cmd = m.Update(msg)
+	if cmd == nil || !m.findCounting || m.findMatches != 0 {
+		t.Fatalf("a count of the old contents was applied: %d", m.findMatches)
+	}
+	m.Update(cmd())
+	if m.findCounting || m.findMatches != 6 {
+		t.Errorf("expected 6 matches after the insert, got %d", m.findMatches)
+	}
+}
@@ -0,0 +1,308 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// Alt+Z folds long runs of uninteresting rows into a single line: rows
+// that are all zeros, or rows without unsaved changes. The arrow keys move
+// over a fold as one line, Enter on it expands it, and jumps into one
+// (Goto, Find, marks) expand it. Nothing under a collapsed fold can be
+// edited. Zero rows are found a block at a time and cached until an edit
+// touches the block or moves its bytes.
+
+type foldMode int
+
+const (
+	foldOff foldMode = iota
+	foldZeros
+	foldUnmodified
+)
+
+const (
+	// foldMinRows is the shortest run that folds.
+	foldMinRows = 4
+	// foldBlockRows rows are scanned for zeros at a time.
+	foldBlockRows = 4096
+	// foldMaxRows bounds how far a fold of zeros is scanned: runs are cut
+	// at multiples of it, so a huge empty region folds into a few lines.
+	foldMaxRows = 1024 * foldBlockRows
+)
+
+// foldSpan is a run of rows, first and last included.
+type foldSpan struct {
+	first int64
+	last  int64
+}
+
+// openFold is the bytes of a fold expanded with Enter.
+type openFold struct {
+	start, end int64
+}
+
+// zeroRows caches the runs of all-zero rows in each scanned block.
+type zeroRows struct {
+	blocks map[int64][]foldSpan
+}
+
+// forget drops the blocks c changed: those it overwrote, or every one from
+// where it moved bytes.
+func (z *zeroRows) forget(c buffer.Change) {
+	first, last := c.Offset/bytesPerRow/foldBlockRows, int64(math.MaxInt64)
+	if c.Removed == 0 && c.Inserted == 0 {
+		last = (c.Offset + c.Overwritten - 1) / bytesPerRow / foldBlockRows
+	}
+	for block := range z.blocks {
+		if block >= first && block <= last {
+			delete(z.blocks, block)
+		}
+	}
+}
+
+// zeroRuns returns the runs of all-zero rows in block.
+func (tab *Tab) zeroRuns(block int64) []foldSpan {
+	c := &tab.zeroRows
+	if c.blocks == nil {
+		c.blocks = make(map[int64][]foldSpan)
+	}
+	if runs, ok := c.blocks[block]; ok {
+		return runs
+	}
+
+	// The rows a run of zero bytes covers, a short last row included
+	var runs []foldSpan
+	start := block * foldBlockRows * bytesPerRow
+	size := tab.Buffer.Size()
+	zeros, _ := tab.Buffer.RunsInContext(context.Background(), start, start+foldBlockRows*bytesPerRow, 1, 0)
+	for _, r := range zeros {
+		first, last := (r.Start+bytesPerRow-1)/bytesPerRow, (r.End+1)/bytesPerRow-1
+		if r.End == size-1 {
+			last = r.End / bytesPerRow
+		}
+		if first <= last {
+			runs = append(runs, foldSpan{first, last})
+		}
+	}
+	c.blocks[block] = runs
+	return runs
+}
+
+// zeroSpan returns the run of zero rows holding row, within row's stretch
+// of foldMaxRows.
+func (tab *Tab) zeroSpan(row int64) (foldSpan, bool) {
+	runIn := func(block, row int64) (foldSpan, bool) {
+		runs := tab.zeroRuns(block)
+		i := sort.Search(len(runs), func(i int) bool { return runs[i].last >= row })
+		if i == len(runs) || runs[i].first > row {
+			return foldSpan{}, false
+		}
+		return runs[i], true
+	}
+	span, ok := runIn(row/foldBlockRows, row)
+	if !ok {
+		return foldSpan{}, false
+	}
+	lo := row / foldMaxRows * foldMaxRows
+	hi := min(lo+foldMaxRows, int64(totalRows(tab.Buffer.Size()))) - 1
+	for span.first > lo && span.first%foldBlockRows == 0 {
+		prev, ok := runIn(span.first/foldBlockRows-1, span.first-1)
+		if !ok {
+			break
+		}
+		span.first = prev.first
+	}
+	for span.last < hi && (span.last+1)%foldBlockRows == 0 {
+		next, ok := runIn(span.last/foldBlockRows+1, span.last+1)
+		if !ok {
+			break
+		}
+		span.last = next.last
+	}
+	span.first, span.last = max(span.first, lo), min(span.last, hi)
+	return span, true
+}
+
+// unmodifiedSpan returns the run of rows without unsaved changes holding
+// row.
+func (m *Model) unmodifiedSpan(tab *Tab, row int64) (foldSpan, bool) {
+	if tab.Buffer.IsNew() || m.rowDirty(tab, row*bytesPerRow) {
+		return foldSpan{}, false
+	}
+	mods := m.modifications(tab)
+	span := foldSpan{0, int64(totalRows(tab.Buffer.Size())) - 1}
+	i := sort.Search(len(mods), func(i int) bool { return mods[i].Offset >= row*bytesPerRow })
+	if i < len(mods) {
+		span.last = min(span.last, mods[i].Offset/bytesPerRow-1)
+	}
+	if i > 0 {
+		prev := mods[i-1]
+		span.first = (prev.Offset+max(prev.Len, 1)-1)/bytesPerRow + 1
+	}
+	return span, true
+}
+
+// foldAt returns the collapsed fold holding row, if any.
+func (m *Model) foldAt(tab *Tab, row int64) (foldSpan, bool) {
+	var span foldSpan
+	var ok bool
+	switch tab.Fold {
+	case foldZeros:
+		span, ok = tab.zeroSpan(row)
+	case foldUnmodified:
+		span, ok = m.unmodifiedSpan(tab, row)
+	}
+	if !ok || span.last-span.first+1 < foldMinRows {
+		return foldSpan{}, false
+	}
+	start, end := span.first*bytesPerRow, (span.last+1)*bytesPerRow-1
+	for _, r := range tab.foldsOpen {
+		if r.start <= end && r.end >= start {
+			return foldSpan{}, false
+		}
+	}
+	return span, true
+}
+
+// lineStart returns the first row of the screen line showing row.
+func (m *Model) lineStart(tab *Tab, row int64) int64 {
+	if f, ok := m.foldAt(tab, row); ok {
+		return f.first
+	}
+	return row
+}
+
+// nextLine returns the first row of the line after row's.
+func (m *Model) nextLine(tab *Tab, row int64) int64 {
+	if f, ok := m.foldAt(tab, row); ok {
+		return f.last + 1
+	}
+	return row + 1
+}
+
+// prevLine returns the first row of the line before row's, or -1.
+func (m *Model) prevLine(tab *Tab, row int64) int64 {
+	row = m.lineStart(tab, row) - 1
+	if row < 0 {
+		return -1
+	}
+	return m.lineStart(tab, row)
+}
+
+// moveLines returns the row n lines after row, or before it for negative
+// n, stopping at the ends of the file.
+func (m *Model) moveLines(tab *Tab, row, n int64) int64 {
+	last := int64(totalRows(max(tab.Buffer.Size(), tab.Cursor+1))) - 1
+	for ; n > 0 && m.nextLine(tab, row) <= last; n-- {
+		row = m.nextLine(tab, row)
+	}
+	for ; n < 0 && m.prevLine(tab, row) >= 0; n++ {
+		row = m.prevLine(tab, row)
+	}
+	return m.lineStart(tab, row)
+}
+
+// ensureFoldedCursorVisible is ensureTabCursorVisible counting screen
+// lines instead of rows.
+func (m *Model) ensureFoldedCursorVisible(tab *Tab) {
+	visRows := int64(m.visibleRows())
+	off := int64(m.scrollOff())
+	cursor := m.lineStart(tab, tab.Cursor/bytesPerRow)
+	top := m.lineStart(tab, int64(tab.ScrollY))
+
+	if above := m.moveLines(tab, cursor, -off); above < top {
+		top = above
+	} else {
+		lines := int64(0)
+		for row := top; row < cursor && lines < visRows; row = m.nextLine(tab, row) {
+			lines++
+		}
+		if lines+off >= visRows {
+			top = m.moveLines(tab, cursor, off-visRows+1)
+		}
+	}
+	tab.ScrollY = int(top)
+	m.clampScroll(tab)
+}
+
+// cursorFold returns the collapsed fold the cursor is on, if any.
+func (m *Model) cursorFold(tab *Tab) (foldSpan, bool) {
+	return m.foldAt(tab, tab.Cursor/bytesPerRow)
+}
+
+// expandFold opens the fold holding offset, if there is one.
+func (m *Model) expandFold(tab *Tab, offset int64) bool {
+	f, ok := m.foldAt(tab, offset/bytesPerRow)
+	if !ok {
+		return false
+	}
+	tab.foldsOpen = append(tab.foldsOpen, openFold{start: f.first * bytesPerRow, end: (f.last+1)*bytesPerRow - 1})
+	return true
+}
+
+// foldBlocked refuses edits at a collapsed fold: at the cursor or at an
+// end of the selection.
+func (m *Model) foldBlocked(tab *Tab) bool {
+	if tab.Fold == foldOff || tab != m.currentTab() {
+		return false
+	}
+	at := []int64{tab.Cursor}
+	if tab.Selection.Active {
+		at = append(at, tab.Selection.Start, tab.Selection.End)
+	}
+	for _, off := range at {
+		if _, ok := m.foldAt(tab, off/bytesPerRow); ok {
+			m.setStatus(sevWarning, "Expand the fold first (Enter)")
+			return true
+		}
+	}
+	return false
+}
+
+// trackFolds maps the expanded folds through an edit.
+func trackFolds(tab *Tab, c buffer.Change) {
+	tab.zeroRows.forget(c)
+	kept := tab.foldsOpen[:0]
+	for _, r := range tab.foldsOpen {
+		if start, end, ok := c.ShiftRange(r.start, r.end); ok {
+			kept = append(kept, openFold{start, end})
+		}
+	}
+	tab.foldsOpen = kept
+}
+
+func (m *Model) cycleFolds() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	tab.Fold = (tab.Fold + 1) % (foldUnmodified + 1)
+	tab.foldsOpen = nil
+	switch tab.Fold {
+	case foldZeros:
+		m.setStatus(sevInfo, "Folding runs of zero rows (Enter expands a fold)")
+	case foldUnmodified:
+		m.setStatus(sevInfo, "Folding rows without unsaved changes (Enter expands a fold)")
+	default:
+		m.setStatus(sevInfo, "Folding off")
+	}
+	m.ensureCursorVisible()
+}
+
+func (m *Model) renderFold(tab *Tab, f foldSpan) string {
+	start := f.first * bytesPerRow
+	n := min((f.last+1)*bytesPerRow, tab.Buffer.Size()) - start
+	offsetStr := fmt.Sprintf("%08X  ", start+m.displayBase(tab))
+	label := fmt.Sprintf("⋯ 0x%X bytes of 0x00 skipped ⋯", n)
+	if tab.Fold == foldUnmodified {
+		label = fmt.Sprintf("⋯ 0x%X unmodified bytes skipped ⋯", n)
+	}
+	if row := tab.Cursor / bytesPerRow; row >= f.first && row <= f.last {
+		return m.styles.IndexMarker.Render(offsetStr) + m.styles.MarkerNormal.Render(label)
+	}
+	return offsetStr + m.styles.Disabled.Render(label)
+}
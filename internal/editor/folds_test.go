@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFoldZeroRows(t *testing.T) {
+	data := make([]byte, 0x100)
+	data[0], data[0xF0] = 1, 2
+	m := newTestModel(data)
+	tab := m.currentTab()
+
+	m.Update(altKey('z'))
+	if !strings.Contains(m.renderEditor(), "⋯ 0xE0 bytes of 0x00 skipped ⋯") {
+		t.Fatalf("no fold line in\n%s", m.renderEditor())
+	}
+	if lines := strings.Count(m.renderEditor(), "\n") + 1; lines != 3 {
+		t.Errorf("expected 3 lines, got %d", lines)
+	}
+
+	// Down crosses the fold as one line
+	press(m, tea.KeyDown)
+	if tab.Cursor != 0x10 {
+		t.Fatalf("expected the cursor on the fold, got 0x%X", tab.Cursor)
+	}
+	typeKeys(m, "i")
+	typeKeys(m, "ff")
+	if tab.Buffer.GetBytes(0x10, 1)[0] != 0 || m.status.text != "Expand the fold first (Enter)" {
+		t.Errorf("edited inside a fold: %q", m.status.text)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	press(m, tea.KeyDown)
+	if tab.Cursor != 0xF0 {
+		t.Errorf("expected the row after the fold, got 0x%X", tab.Cursor)
+	}
+	press(m, tea.KeyLeft, tea.KeyLeft)
+	if tab.Cursor != 0x0F {
+		t.Errorf("expected the byte before the fold, got 0x%X", tab.Cursor)
+	}
+
+	// Jumping into the fold expands it, and it stays open across edits
+	m.setCursor(0x80)
+	if strings.Contains(m.renderEditor(), "skipped") {
+		t.Error("goto should expand the fold")
+	}
+	tab.Buffer.Insert(0, []byte{9})
+	if strings.Contains(m.renderEditor(), "skipped") {
+		t.Error("the fold closed after an edit")
+	}
+
+	m.Update(altKey('z'))
+	m.Update(altKey('z'))
+	if tab.Fold != foldOff || strings.Contains(m.renderEditor(), "skipped") {
+		t.Error("expected folding off")
+	}
+}
+
+func TestFoldExpandsWithEnter(t *testing.T) {
+	m := newTestModel(make([]byte, 0x80))
+	tab := m.currentTab()
+	m.Update(altKey('z'))
+	if f, ok := m.cursorFold(tab); !ok || f.first != 0 || f.last != 7 {
+		t.Fatalf("expected one fold over the file, got %v %v", f, ok)
+	}
+	press(m, tea.KeyEnter)
+	if _, ok := m.cursorFold(tab); ok {
+		t.Error("Enter should expand the fold")
+	}
+}
+
+func TestZeroFoldsSpanBlocks(t *testing.T) {
+	m := newTestModel(make([]byte, 3*foldBlockRows*bytesPerRow))
+	tab := m.currentTab()
+	tab.Fold = foldZeros
+	if f, ok := m.foldAt(tab, foldBlockRows+5); !ok || f.first != 0 || f.last != 3*foldBlockRows-1 {
+		t.Errorf("got %v %v", f, ok)
+	}
+
+	tab.Buffer.Replace(foldBlockRows*bytesPerRow, 1)
+	if f, ok := m.foldAt(tab, 0); !ok || f.last != foldBlockRows-1 {
+		t.Errorf("the cache wasn't refreshed: %v %v", f, ok)
+	}
+}
+
+func TestZeroFoldsForgetOnlyTouchedBlocks(t *testing.T) {
+	m := newTestModel(make([]byte, 3*foldBlockRows*bytesPerRow+5))
+	tab := m.currentTab()
+	tab.Fold = foldZeros
+	m.foldAt(tab, 0)
+	if len(tab.zeroRows.blocks) != 4 {
+		t.Fatalf("expected every block scanned, got %d", len(tab.zeroRows.blocks))
+	}
+
+	// Overwriting drops the block written to alone
+	tab.Buffer.Replace(foldBlockRows*bytesPerRow+3, 1)
+	if _, ok := tab.zeroRows.blocks[1]; ok || len(tab.zeroRows.blocks) != 3 {
+		t.Errorf("expected block 1 alone dropped, have %d", len(tab.zeroRows.blocks))
+	}
+	if f, ok := m.foldAt(tab, foldBlockRows+1); !ok || f.first != foldBlockRows+1 || f.last != 3*foldBlockRows {
+		t.Errorf("got %v %v", f, ok)
+	}
+
+	// Inserting drops the blocks from there on
+	tab.Buffer.Insert(2*foldBlockRows*bytesPerRow, []byte{1})
+	if len(tab.zeroRows.blocks) != 2 {
+		t.Errorf("expected blocks 0 and 1 kept, have %d", len(tab.zeroRows.blocks))
+	}
+	if _, ok := m.foldAt(tab, 2*foldBlockRows); ok {
+		t.Error("the row inserted into isn't zeros")
+	}
+}
@@ -0,0 +1,37 @@
+package editor
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// FS is the file system the editor opens, lists and stats files through,
+// and writes the files it makes itself to: exported marks and values, and
+// diagnostic reports. Saving, devices, windows and the disk diff work on
+// the real file through pkg/buffer, and the config, templates, lock files
+// and rescue files live in the user's directories, so those don't use it.
+// A fake FS lets a run (see Drive) open and list files off the disk.
+type FS interface {
+	Open(name string) (fs.File, error)
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Create(name string) (io.WriteCloser, error)
+	// Getwd returns the directory the file browser starts in.
+	Getwd() (string, error)
+}
+
+type systemFS struct{}
+
+func (systemFS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (systemFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (systemFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (systemFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (systemFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (systemFS) Getwd() (string, error)                     { return os.Getwd() }
+
+func (systemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
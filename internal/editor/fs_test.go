@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// memFS is an FS in memory, rooted at ".".
+type memFS struct {
+	fstest.MapFS
+}
+
+func (f memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f.MapFS[name] = &fstest.MapFile{Data: data, Mode: perm}
+	return nil
+}
+
+func (f memFS) Create(name string) (io.WriteCloser, error) {
+	return &memFile{fs: f, name: name}, nil
+}
+
+func (f memFS) Getwd() (string, error) { return ".", nil }
+
+type memFile struct {
+	bytes.Buffer
+	fs   memFS
+	name string
+}
+
+func (f *memFile) Close() error { return f.fs.WriteFile(f.name, f.Bytes(), 0644) }
+
+func TestEditorUsesItsFS(t *testing.T) {
+	fsys := memFS{fstest.MapFS{"memfs-test.bin": {Data: []byte("abcd")}}}
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	m, err := NewModel(nil, Options{Config: config.DefaultConfig(), Clock: clock, FS: fsys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Drive(m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	if m.view != ViewOpen || len(m.browserItems) == 0 {
+		t.Fatalf("expected the browser listing the FS, view %v", m.view)
+	}
+	for i, item := range m.browserItems {
+		if item.Name() == "memfs-test.bin" {
+			m.browserIndex = i
+		}
+	}
+	Drive(m, key(tea.KeyEnter))
+	tab := m.currentTab()
+	if tab == nil || string(tab.Buffer.Data()) != "abcd" {
+		t.Fatalf("expected the file opened from the FS, status %q", m.status.text)
+	}
+
+	// Edits are timed by the editor's clock
+	clock.advance(time.Hour)
+	Drive(m, keys("rff")...)
+	if first := tab.Buffer.Stats().First; !first.Equal(clock.now) {
+		t.Errorf("edit timed at %v, clock at %v", first, clock.now)
+	}
+
+	m.exportMarks(tab, "marks.json")
+	if _, ok := fsys.MapFS["marks.json"]; !ok {
+		t.Errorf("marks weren't exported to the FS, status %q", m.status.text)
+	}
+}
@@ -0,0 +1,44 @@
+package editor
+
+import (
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Bytes with no glyph of their own show in the text column as the
+// text_substitute character, or with control_pictures as a glyph of their
+// own: the Unicode control pictures for C0 and DEL, and a shade for 0xFF,
+// the value of erased flash. Either way they take the Control style and
+// one cell, so the columns stay aligned.
+
+// controlPicture returns the glyph of a control character, DEL or 0xFF.
+func controlPicture(b byte) (string, bool) {
+	switch {
+	case b < 0x20:
+		return string(rune(0x2400 + int(b))), true
+	case b == 0x7F:
+		return "␡", true
+	case b == 0xFF:
+		return "░", true
+	}
+	return "", false
+}
+
+// textSubstitute returns s if it is one character one cell wide, or ".".
+func textSubstitute(s string) string {
+	if utf8.RuneCountInString(s) != 1 || runewidth.StringWidth(s) != 1 {
+		return "."
+	}
+	return s
+}
+
+// substituteGlyph returns the text-column cell for b when it has no glyph.
+func (m *Model) substituteGlyph(b byte) string {
+	if m.config.Behavior.ControlPictures {
+		if g, ok := controlPicture(b); ok {
+			return g
+		}
+	}
+	return textSubstitute(m.config.Behavior.TextSubstitute)
+}
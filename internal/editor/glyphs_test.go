@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestTextSubstitute(t *testing.T) {
+	m := newTestModel([]byte("A\x00\n\r\t\xFF\x7F\x80"))
+	if !strings.Contains(m.renderEditor(), "A.......") {
+		t.Fatalf("expected the default substitute:\n%s", m.renderEditor())
+	}
+
+	m.config.Behavior.TextSubstitute = "·"
+	if !strings.Contains(m.renderEditor(), "A·······") {
+		t.Errorf("expected the configured substitute:\n%s", m.renderEditor())
+	}
+
+	m.config.Behavior.ControlPictures = true
+	if !strings.Contains(m.renderEditor(), "A␀␊␍␉░␡·") {
+		t.Errorf("expected control pictures:\n%s", m.renderEditor())
+	}
+}
+
+func TestTextSubstituteMustBeOneCell(t *testing.T) {
+	for _, s := range []string{"", "ab", "漢"} {
+		if got := textSubstitute(s); got != "." {
+			t.Errorf("%q: got %q", s, got)
+		}
+	}
+	for b := 0; b < 256; b++ {
+		if g, ok := controlPicture(byte(b)); ok && runewidth.StringWidth(g) != 1 {
+			t.Errorf("the glyph of 0x%02X is %d cells wide", b, runewidth.StringWidth(g))
+		}
+	}
+}
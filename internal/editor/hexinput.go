@@ -0,0 +1,127 @@
+package editor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Hex byte input as typed or pasted by hand: tokens separated by spaces,
+// commas or colons, each an optional 0x prefix followed by hex digits that
+// pair up from the left, so "0xDE 0xAD, be:ef" and "DEADBEEF" are the same
+// four bytes. A lone digit standing as its own token is a whole byte
+// ("0xA," is 0A). A trailing unpaired digit at the very end of the input is
+// returned as pending rather than padded, since it's usually half typed.
+
+func isHexSeparator(r rune) bool {
+	return r == ' ' || r == ',' || r == ':' || r == '\t'
+}
+
+func isHexInputChar(s string) bool {
+	return isHexChar(s) || s == "x" || s == "X" || (len(s) == 1 && isHexSeparator(rune(s[0])))
+}
+
+// parseHexBytes returns the bytes in s and a pending trailing nibble, or -1
+// when there is none.
+func parseHexBytes(s string) (data []byte, pending int, err error) {
+	pending = -1
+	tokens := strings.FieldsFunc(s, isHexSeparator)
+	open := s != "" && !isHexSeparator(rune(s[len(s)-1]))
+
+	for i, tok := range tokens {
+		digits := strings.TrimPrefix(strings.ToLower(tok), "0x")
+		if digits == "" {
+			if i == len(tokens)-1 && open {
+				break // "0x" still being typed
+			}
+			return nil, -1, fmt.Errorf("missing digits in %q", tok)
+		}
+		if len(digits)%2 != 0 {
+			switch {
+			case i == len(tokens)-1 && open:
+				if !isHexChar(digits[len(digits)-1:]) {
+					return nil, -1, fmt.Errorf("invalid hex %q", tok)
+				}
+				pending = int(hexNibble(digits[len(digits)-1]))
+				digits = digits[:len(digits)-1]
+			case len(digits) == 1:
+				digits = "0" + digits
+			default:
+				return nil, -1, fmt.Errorf("odd number of digits in %q", tok)
+			}
+		}
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return nil, -1, fmt.Errorf("invalid hex %q", tok)
+		}
+		data = append(data, b...)
+	}
+	return data, pending, nil
+}
+
+func hexNibble(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
+
+// formatHexBytes writes data as space-separated byte tokens, followed by
+// the pending nibble if any.
+func formatHexBytes(data []byte, pending int) string {
+	var b strings.Builder
+	for i, d := range data {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%02X", d)
+	}
+	if pending >= 0 {
+		if len(data) > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%X", pending)
+	}
+	return b.String()
+}
+
+// renderHexFindInput shows the hex find input grouped into bytes, with a
+// pending nibble or a parse error flagged.
+func (m *Model) renderHexFindInput() string {
+	data, pending, err := parseHexBytes(m.findInput)
+	if err != nil {
+		return m.findInput + " " + m.styles.StatusWarning.Render("("+err.Error()+")")
+	}
+	var tail string
+	switch {
+	case pending >= 0:
+		tail = m.styles.StatusWarning.Render(fmt.Sprintf("%X?", pending))
+	case strings.HasSuffix(strings.ToLower(m.findInput), "0x"):
+		tail = "0x" // prefix typed, digits to follow
+	}
+	s := formatHexBytes(data, -1)
+	if s != "" && tail != "" {
+		s += " "
+	}
+	return s + tail
+}
+
+// dropHexToken removes the last displayed token, a pending nibble or a
+// whole byte, and returns the input in canonical form. Input that doesn't
+// parse loses its last character instead.
+func dropHexToken(s string) string {
+	data, pending, err := parseHexBytes(s)
+	if err != nil {
+		return s[:len(s)-1]
+	}
+	if pending >= 0 {
+		pending = -1
+	} else if len(data) > 0 {
+		data = data[:len(data)-1]
+	}
+	return formatHexBytes(data, pending)
+}
@@ -0,0 +1,65 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseHexBytes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []byte
+		pending int
+		wantErr bool
+	}{
+		{"DEADBEEF", []byte{0xDE, 0xAD, 0xBE, 0xEF}, -1, false},
+		{"0xDE 0xAD, be:ef", []byte{0xDE, 0xAD, 0xBE, 0xEF}, -1, false},
+		{"0xA, 0xB", []byte{0x0A}, 0xB, false},
+		{"0xA, 0xB ", []byte{0x0A, 0x0B}, -1, false},
+		{"DE AD B", []byte{0xDE, 0xAD}, 0xB, false},
+		{"DEA", []byte{0xDE}, 0xA, false},
+		{"DE 0x", []byte{0xDE}, -1, false},
+		{"", nil, -1, false},
+		{"ABC DE", nil, -1, true},
+		{"0x, DE", nil, -1, true},
+		{"x5", nil, -1, true},
+	}
+	for _, tt := range tests {
+		got, pending, err := parseHexBytes(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHexBytes(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) || pending != tt.pending {
+			t.Errorf("parseHexBytes(%q) = %X, %d; want %X, %d", tt.in, got, pending, tt.want, tt.pending)
+		}
+	}
+}
+
+func TestHexFindBackspaceDropsTokens(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0xDE, 0xAD, 0xBE, 0xEF})
+	m.findMode = "hex"
+	typeKeys(m, "f0xDE 0xAD,BE")
+
+	if got := m.getFindPattern(); !bytes.Equal(got, []byte{0xDE, 0xAD, 0xBE}) {
+		t.Fatalf("unexpected pattern %X", got)
+	}
+	if m.currentTab().Cursor != 1 {
+		t.Errorf("expected the match at 1, got %d", m.currentTab().Cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.findInput != "DE AD" {
+		t.Errorf("backspace should drop the whole byte, got %q", m.findInput)
+	}
+	typeKeys(m, "B")
+	if _, pending, _ := parseHexBytes(m.findInput); pending != 0xB {
+		t.Errorf("expected a pending nibble after typing one digit, got %q", m.findInput)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.findInput != "DE AD" {
+		t.Errorf("backspace should drop the pending nibble, got %q", m.findInput)
+	}
+}
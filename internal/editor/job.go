@@ -0,0 +1,230 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const jobChunk = 256 * 1024
+
+// editJob is a large edit applied in chunks through the Update loop, so the
+// UI keeps rendering progress while it runs. All of its edits form a single
+// undo group; cancelling rolls the group back, as does an edit the buffer
+// refuses.
+type editJob struct {
+	tab    *Tab
+	label  string
+	offset int64
+	total  int64
+	done   int64
+	// step applies the next chunk starting done bytes into the job and
+	// returns how many bytes it processed.
+	step    func(done int64) int64
+	finish  func()
+	err     error  // the first edit refused
+	version uint64 // of the buffer before the job
+}
+
+// refused records err, when an edit was refused, and reports whether it
+// was. A step that gets true returns 0 and the job stops.
+func (j *editJob) refused(err error) bool {
+	if err != nil && j.err == nil {
+		j.err = err
+	}
+	return err != nil
+}
+
+type editJobMsg struct {
+	job *editJob
+}
+
+func (j *editJob) next() tea.Cmd {
+	return func() tea.Msg { return editJobMsg{job: j} }
+}
+
+// runEdit applies job directly when it is small, and otherwise asks for
+// confirmation before running it in the background.
+func (m *Model) runEdit(job *editJob) tea.Cmd {
+	threshold := m.config.Behavior.LargeEditThreshold
+	if threshold > 0 && job.total > threshold {
+		m.openDialog(
+			fmt.Sprintf("%s will modify %d bytes at offset 0x%08X. Continue?", job.label, job.total, job.offset),
+			m.cancelButton(ViewMain),
+			dialogButton{label: "Continue", action: func() (tea.Model, tea.Cmd) {
+				return m, m.startJob(job)
+			}})
+		return nil
+	}
+
+	job.version = job.tab.Buffer.Version()
+	job.tab.Buffer.BeginGroup()
+	for job.done < job.total && job.err == nil {
+		job.done += job.step(job.done)
+	}
+	job.tab.Buffer.EndGroup()
+	if m.rollBack(job) {
+		return nil
+	}
+	if job.finish != nil {
+		job.finish()
+	}
+	return nil
+}
+
+// rollBack undoes what job did when one of its edits was refused, and
+// says why.
+func (m *Model) rollBack(job *editJob) bool {
+	if job.err == nil {
+		return false
+	}
+	if job.tab.Buffer.Version() != job.version {
+		if _, err := job.tab.Buffer.Undo(); err != nil {
+			m.setStatus(sevError, job.label+" failed, and its changes can't be undone: "+err.Error())
+			return true
+		}
+	}
+	m.refused(strings.ToLower(job.label), job.err)
+	return true
+}
+
+func (m *Model) startJob(job *editJob) tea.Cmd {
+	m.job = job
+	job.version = job.tab.Buffer.Version()
+	job.tab.Buffer.BeginGroup()
+	return job.next()
+}
+
+func (m *Model) handleEditJob(msg editJobMsg) (tea.Model, tea.Cmd) {
+	job := msg.job
+	if job != m.job {
+		// Cancelled
+		return m, nil
+	}
+
+	job.done += job.step(job.done)
+	if job.done < job.total && job.err == nil {
+		return m, job.next()
+	}
+
+	job.tab.Buffer.EndGroup()
+	m.job = nil
+	if m.rollBack(job) {
+		return m, nil
+	}
+	if job.finish != nil {
+		job.finish()
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("%s: %d bytes at 0x%08X", job.label, job.total, job.offset))
+	return m, nil
+}
+
+func (m *Model) cancelJob() {
+	job := m.job
+	if job == nil {
+		return
+	}
+	job.tab.Buffer.EndGroup()
+	if job.done > 0 {
+		if _, err := job.tab.Buffer.Undo(); err != nil {
+			m.job = nil
+			m.setStatus(sevError, job.label+" cancelled, but its changes can't be undone: "+err.Error())
+			return
+		}
+	}
+	m.job = nil
+	m.setStatus(sevInfo, job.label+" cancelled")
+}
+
+func (m *Model) renderJobProgress() string {
+	job := m.job
+	percent := int64(100)
+	if job.total > 0 {
+		percent = job.done * 100 / job.total
+	}
+	return fmt.Sprintf("%s... %d%% (ESC to cancel)", job.label, percent)
+}
+
+func chunkLen(done, total int64) int64 {
+	n := total - done
+	if n > jobChunk {
+		n = jobChunk
+	}
+	return n
+}
+
+func (m *Model) pasteJob(tab *Tab, data []byte) *editJob {
+	start := tab.Cursor
+	job := &editJob{
+		tab:    tab,
+		offset: start,
+		total:  int64(len(data)),
+	}
+
+	if m.mode == ModeInsert {
+		job.label = "Paste (insert)"
+		job.step = func(done int64) int64 {
+			n := chunkLen(done, job.total)
+			if job.refused(tab.Buffer.Insert(start+done, data[done:done+n])) {
+				return 0
+			}
+			return n
+		}
+		job.finish = func() {
+			tab.Cursor = start + job.total
+			m.ensureCursorVisible()
+		}
+	} else {
+		job.label = "Paste (replace)"
+		job.step = func(done int64) int64 {
+			n := chunkLen(done, job.total)
+			if job.refused(tab.Buffer.ReplaceBytes(start+done, data[done:done+n])) {
+				return 0
+			}
+			return n
+		}
+	}
+	return job
+}
+
+// insertFillJob inserts count bytes of value at start, a chunk at a time.
+func (m *Model) insertFillJob(tab *Tab, label string, start, count int64, value byte) *editJob {
+	fill := bytes.Repeat([]byte{value}, int(min(count, jobChunk)))
+	job := &editJob{
+		tab:    tab,
+		label:  label,
+		offset: start,
+		total:  count,
+	}
+	job.step = func(done int64) int64 {
+		n := chunkLen(done, job.total)
+		if job.refused(tab.Buffer.Insert(start+done, fill[:n])) {
+			return 0
+		}
+		return n
+	}
+	return job
+}
+
+func (m *Model) fillJob(tab *Tab, start, count int64, value byte) *editJob {
+	var fill []byte
+	job := &editJob{
+		tab:    tab,
+		label:  "Fill",
+		offset: start,
+		total:  count,
+	}
+	job.step = func(done int64) int64 {
+		n := chunkLen(done, job.total)
+		if int64(len(fill)) < n {
+			fill = bytes.Repeat([]byte{value}, int(n))
+		}
+		if job.refused(tab.Buffer.ReplaceBytes(start+done, fill[:n])) {
+			return 0
+		}
+		return n
+	}
+	return job
+}
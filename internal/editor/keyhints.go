@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Two hints drawn over the bottom of the main view help find a key
+// mid-task. ? lists the last commands used, most recent first, and after
+// the " prefix the keys that can follow it are listed, which-key style.
+// Both come from the command registry (see commands.go) and close on the
+// next key, which then does what it always does.
+
+// maxRecent is how many commands ? lists.
+const maxRecent = 8
+
+// commandForKey returns the registry entry for the command key runs in
+// the main view, or nil.
+func (m *Model) commandForKey(key string) *command {
+	for i := range commandSections {
+		for j := range commandSections[i].commands {
+			c := &commandSections[i].commands[j]
+			if c.action != "" {
+				if slices.Contains(m.actionKeys(c.action), key) {
+					return c
+				}
+				continue
+			}
+			for _, k := range strings.Split(c.keys, " / ") {
+				if strings.EqualFold(k, key) {
+					return c
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// noteCommand puts the command key runs at the top of the recent ones.
+func (m *Model) noteCommand(key string) {
+	c := m.commandForKey(key)
+	if c == nil || c.keys == "?" {
+		return
+	}
+	m.recent = slices.DeleteFunc(m.recent, func(r *command) bool { return r == c })
+	m.recent = slices.Insert(m.recent, 0, c)
+	if len(m.recent) > maxRecent {
+		m.recent = m.recent[:maxRecent]
+	}
+}
+
+// commandLine lists c in a hint, keys then help, in at most width runes.
+func (m *Model) commandLine(c *command, width int) string {
+	keys := c.keys
+	if c.action != "" {
+		keys = keyNames(m.actionKeys(c.action))
+	}
+	help := c.help
+	if c.key == "." {
+		help = "Repeat the last edit"
+	}
+	line := []rune(padRight(keys, 10) + help)
+	if len(line) > width {
+		return string(line[:width-1]) + "…"
+	}
+	return string(line)
+}
+
+// keyHint returns the hint to draw over the main view, or "".
+func (m *Model) keyHint() string {
+	width := min(m.width-6, 72)
+	var lines []string
+	switch {
+	case m.registerPrefix:
+		var set []string
+		for r := 'a'; r <= 'z'; r++ {
+			if m.registers[r] != nil {
+				set = append(set, string(r))
+			}
+		}
+		lines = append(lines, `" then a register, a-z`)
+		if len(set) > 0 {
+			lines = append(lines, "  holding data: "+strings.Join(set, " "))
+		}
+		lines = append(lines, "", "and then:")
+		for _, section := range commandSections {
+			for i := range section.commands {
+				if c := &section.commands[i]; c.register {
+					lines = append(lines, m.commandLine(c, width))
+				}
+			}
+		}
+	case m.showRecent:
+		lines = append(lines, "Recently used")
+		for _, c := range m.recent {
+			lines = append(lines, m.commandLine(c, width))
+		}
+		if len(m.recent) == 0 {
+			lines = append(lines, "(nothing yet; H lists every command)")
+		}
+	default:
+		return ""
+	}
+	return m.styles.Border.
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// overlayBottom draws box over the last lines of view, keeping its height.
+func overlayBottom(view, box string) string {
+	lines := strings.Split(view, "\n")
+	boxLines := strings.Split(box, "\n")
+	at := max(len(lines)-len(boxLines), 0)
+	return strings.Join(append(lines[:at], boxLines...), "\n")
+}
@@ -0,0 +1,51 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecentCommandsHint(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, strings.Repeat("\x00", 64)))
+	Drive(m, keys("x")...)
+	Drive(m, altKey('b'))
+	Drive(m, keys("x?")...)
+	hint := m.keyHint()
+	if !strings.Contains(hint, "Recently used") {
+		t.Fatalf("got %q", hint)
+	}
+	x, altB := strings.Index(hint, "Highlight bytes equal"), strings.Index(hint, "Show the cursor byte")
+	if x < 0 || altB < 0 || x > altB || strings.Count(hint, "Highlight bytes equal") != 1 {
+		t.Errorf("expected X listed once, above Alt+B:\n%s", hint)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Recently used") {
+		t.Errorf("the hint should be drawn over the main view:\n%s", view)
+	}
+
+	// The next key closes it and still runs
+	Drive(m, key(tea.KeyRight))
+	if m.keyHint() != "" || m.currentTab().Cursor != 1 {
+		t.Errorf("hint %q, cursor %d", m.keyHint(), m.currentTab().Cursor)
+	}
+}
+
+func TestRegisterPrefixHint(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "abcd"))
+	Drive(m, key(tea.KeyShiftRight))
+	Drive(m, keys(`"a`)...)
+	Drive(m, key(tea.KeyCtrlC))
+	Drive(m, keys(`"`)...)
+	hint := m.keyHint()
+	for _, want := range []string{"holding data: a", "Ctrl+X", "Ctrl+C", "Ctrl+V"} {
+		if !strings.Contains(hint, want) {
+			t.Errorf("expected %q in:\n%s", want, hint)
+		}
+	}
+	Drive(m, keys("b")...)
+	if m.keyHint() != "" || m.pendingRegister != 'b' {
+		t.Errorf("hint %q, register %q", m.keyHint(), m.pendingRegister)
+	}
+}
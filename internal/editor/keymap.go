@@ -0,0 +1,94 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Commands that can be rebound in the [keys] config table. Ctrl+Z undoes
+// by default rather than suspending, which in a terminal leaves the screen
+// in a confusing state; suspending has a key of its own.
+
+const (
+	actionUndo    = "undo"
+	actionRedo    = "redo"
+	actionSuspend = "suspend"
+)
+
+// actionKeys returns the keys bound to action.
+func (m *Model) actionKeys(action string) []string {
+	switch action {
+	case actionUndo:
+		return m.config.Keys.Undo
+	case actionRedo:
+		return m.config.Keys.Redo
+	case actionSuspend:
+		return m.config.Keys.Suspend
+	}
+	return nil
+}
+
+// keyAction returns the action key is bound to, or "".
+func (m *Model) keyAction(key string) string {
+	for _, action := range []string{actionUndo, actionRedo, actionSuspend} {
+		for _, k := range m.actionKeys(action) {
+			if k == key || len([]rune(k)) == 1 && strings.EqualFold(k, key) {
+				return action
+			}
+		}
+	}
+	return ""
+}
+
+func (m *Model) runAction(action string) (tea.Model, tea.Cmd) {
+	tab := m.currentTab()
+	switch action {
+	case actionUndo:
+		if tab != nil && !tab.Buffer.CanUndo() {
+			m.alert("Nothing to undo")
+		} else if tab != nil && !m.editBlocked(tab) {
+			m.dropTyping()
+			if _, err := tab.Buffer.Undo(); err != nil {
+				m.setStatus(sevError, "Can't undo: "+err.Error()+"; the undo history was cleared")
+				return m, nil
+			}
+			return m, m.showStep(tab, true)
+		}
+	case actionRedo:
+		if tab != nil && !tab.Buffer.CanRedo() {
+			m.alert("Nothing to redo")
+		} else if tab != nil && !m.editBlocked(tab) {
+			m.dropTyping()
+			if _, err := tab.Buffer.Redo(); err != nil {
+				m.setStatus(sevError, "Can't redo: "+err.Error()+"; the undo history was cleared")
+				return m, nil
+			}
+			return m, m.showStep(tab, false)
+		}
+	case actionSuspend:
+		return m, tea.Suspend
+	}
+	return m, nil
+}
+
+func (m *Model) handleResume() (tea.Model, tea.Cmd) {
+	// The shell may have changed the window title meanwhile
+	m.title = ""
+	return m, nil
+}
+
+// keyNames formats keys for help, e.g. "U / Ctrl+Z".
+func keyNames(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		parts := strings.Split(k, "+")
+		for j, p := range parts {
+			if len(p) > 0 {
+				parts[j] = strings.ToUpper(p[:1]) + p[1:]
+			}
+		}
+		names[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(names, " / ")
+}
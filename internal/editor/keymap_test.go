@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoRedoAliasesAndSuspend(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	tab := m.currentTab()
+	tab.Buffer.Replace(0, 0xFF)
+
+	press(m, tea.KeyCtrlZ)
+	if b, _ := tab.Buffer.GetByte(0); b != 0x00 {
+		t.Errorf("Ctrl+Z should undo, got 0x%02X", b)
+	}
+	press(m, tea.KeyCtrlY)
+	if b, _ := tab.Buffer.GetByte(0); b != 0xFF {
+		t.Errorf("Ctrl+Y should redo, got 0x%02X", b)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlUnderscore})
+	if cmd == nil || !containsMsg(cmd(), tea.SuspendMsg{}) {
+		t.Error("Ctrl+_ should suspend")
+	}
+}
+
+// containsMsg reports whether msg is want or a batch including it.
+func containsMsg(msg tea.Msg, want tea.Msg) bool {
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, cmd := range batch {
+			if cmd != nil && containsMsg(cmd(), want) {
+				return true
+			}
+		}
+		return false
+	}
+	return msg == want
+}
+
+func TestUndoDropsHalfTypedByte(t *testing.T) {
+	m := newTestModel([]byte{0xAA})
+	tab := m.currentTab()
+	m.setMode(ModeInsert)
+
+	typeKeys(m, "4")
+	press(m, tea.KeyCtrlZ)
+	typeKeys(m, "1")
+	if got := tab.Buffer.GetBytes(0, 2); string(got) != "\x10\xAA" {
+		t.Errorf("the nibble typed after undo should start a new byte, got % X", got)
+	}
+}
+
+func TestKeymapRebinds(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	m.config.Keys.Undo = []string{"ctrl+z"}
+	tab := m.currentTab()
+	tab.Buffer.Replace(0, 0xFF)
+
+	typeKeys(m, "u")
+	if b, _ := tab.Buffer.GetByte(0); b != 0xFF {
+		t.Error("u should no longer undo")
+	}
+	typeKeys(m, "U")
+	press(m, tea.KeyCtrlZ)
+	if b, _ := tab.Buffer.GetByte(0); b != 0x00 {
+		t.Error("Ctrl+Z should still undo")
+	}
+	if help := m.renderHelp(); !strings.Contains(help, "  Ctrl+Z          Undo") || !strings.Contains(help, "  D / Ctrl+Y      Redo") {
+		t.Errorf("help should list the bound keys:\n%s", help)
+	}
+}
@@ -0,0 +1,73 @@
+package editor
+
+// minEditorHeight fits the legend, tabs, column header, one hex row, the
+// decoder panel and the status bar (see visibleRows).
+const minEditorHeight = 12
+
+// minEditorWidth is the number of columns a hex row needs: the offset
+// column, the grouped hex cells and the text column.
+func minEditorWidth() int {
+	offset := 10
+	return offset + hexColumnWidth() + 2 + bytesPerRow
+}
+
+func totalRows(size int64) int {
+	rows := int((size + bytesPerRow - 1) / bytesPerRow)
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// scrollOff returns the configured rows of context around the cursor,
+// limited so the cursor can still move within a short window.
+func (m *Model) scrollOff() int {
+	return max(min(m.config.Behavior.ScrollOff, (m.visibleRows()-1)/2), 0)
+}
+
+// page moves the cursor and the view by n screens together, so the cursor
+// keeps its place on screen until the view reaches an end of the file.
+func (m *Model) page(n int64) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	screenRow := int(tab.Cursor/bytesPerRow) - tab.ScrollY
+	m.moveVertical(n*int64(m.visibleRows()), false, false)
+	tab.ScrollY = int(tab.Cursor/bytesPerRow) - screenRow
+	m.ensureTabCursorVisible(tab)
+}
+
+// centerCursor scrolls the cursor row to the middle of the view.
+func (m *Model) centerCursor() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	tab.ScrollY = int(tab.Cursor/bytesPerRow) - m.visibleRows()/2
+	if tab.Fold != foldOff {
+		tab.ScrollY = int(m.moveLines(tab, tab.Cursor/bytesPerRow, -int64(m.visibleRows()/2)))
+	}
+	m.clampScroll(tab)
+}
+
+// clampScroll keeps the viewport from scrolling past the last row, e.g.
+// after the terminal grows or the buffer shrinks.
+func (m *Model) clampScroll(tab *Tab) {
+	// The insert cursor may sit on a row of its own past the last byte
+	maxScroll := totalRows(max(tab.Buffer.Size(), tab.Cursor+1)) - m.visibleRows()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if tab.ScrollY > maxScroll {
+		tab.ScrollY = maxScroll
+	}
+	if tab.ScrollY < 0 {
+		tab.ScrollY = 0
+	}
+	if tab.Fold != foldOff {
+		last := int64(totalRows(max(tab.Buffer.Size(), tab.Cursor+1))) - 1
+		top := min(m.lineStart(tab, int64(tab.ScrollY)), m.moveLines(tab, last, 1-int64(m.visibleRows())))
+		tab.ScrollY = int(top)
+	}
+}
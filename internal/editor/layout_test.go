@@ -0,0 +1,84 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResizeClampsScrollAndKeepsCursorVisible(t *testing.T) {
+	m := newTestModel(make([]byte, 100*bytesPerRow))
+	tab := m.currentTab()
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m.setCursor(99 * bytesPerRow)
+	if tab.ScrollY != 99-m.visibleRows()+1 {
+		t.Fatalf("unexpected scroll %d before resize", tab.ScrollY)
+	}
+
+	// Shrinking must bring the cursor row back on screen
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 14})
+	cursorRow := int(tab.Cursor / bytesPerRow)
+	if cursorRow < tab.ScrollY || cursorRow >= tab.ScrollY+m.visibleRows() {
+		t.Errorf("cursor row %d not visible at scroll %d with %d rows", cursorRow, tab.ScrollY, m.visibleRows())
+	}
+
+	// Growing past the file must not leave blank rows below the data
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 200})
+	if tab.ScrollY != 0 {
+		t.Errorf("expected scroll clamped to 0, got %d", tab.ScrollY)
+	}
+}
+
+func TestNarrowTerminalPlaceholder(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	m.Update(tea.WindowSizeMsg{Width: minEditorWidth() - 1, Height: 40})
+
+	if view := m.View(); !strings.HasPrefix(view, "Terminal too narrow") {
+		t.Errorf("expected narrow placeholder, got %q", view)
+	}
+}
+
+func TestScrollOffKeepsContextRows(t *testing.T) {
+	m := newTestModel(make([]byte, 200*bytesPerRow))
+	tab := m.currentTab()
+	rows := m.visibleRows()
+
+	m.setCursor(int64(rows-4) * bytesPerRow)
+	if tab.ScrollY != 0 {
+		t.Fatalf("row %d still leaves 3 rows of context, got scroll %d", rows-4, tab.ScrollY)
+	}
+	press(m, tea.KeyDown)
+	if tab.ScrollY != 1 {
+		t.Errorf("moving into the margin should scroll by one, got %d", tab.ScrollY)
+	}
+
+	// Paging moves the view with the cursor
+	tab.Cursor, tab.ScrollY = 10*bytesPerRow, 0
+	press(m, tea.KeyPgDown)
+	if tab.ScrollY != rows || tab.Cursor != int64(10+rows)*bytesPerRow {
+		t.Errorf("page down gave cursor row %d at scroll %d", tab.Cursor/bytesPerRow, tab.ScrollY)
+	}
+	press(m, tea.KeyPgUp)
+	if tab.ScrollY != 0 || tab.Cursor != 10*bytesPerRow {
+		t.Errorf("page up gave cursor row %d at scroll %d", tab.Cursor/bytesPerRow, tab.ScrollY)
+	}
+
+	// At the top of the file the margin gives way
+	press(m, tea.KeyPgUp)
+	if tab.ScrollY != 0 || tab.Cursor != 0 {
+		t.Errorf("page up at the top gave cursor %d at scroll %d", tab.Cursor, tab.ScrollY)
+	}
+
+	m.setCursor(100 * bytesPerRow)
+	press(m, tea.KeyCtrlL)
+	if tab.ScrollY != 100-rows/2 {
+		t.Errorf("Ctrl+L should center row 100, got scroll %d", tab.ScrollY)
+	}
+	m.setCursor(199 * bytesPerRow)
+	press(m, tea.KeyCtrlL)
+	if tab.ScrollY != 200-rows {
+		t.Errorf("centering near the end should stop at the last row, got scroll %d", tab.ScrollY)
+	}
+}
@@ -0,0 +1,174 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const loadChunk = 4 << 20
+
+// fileLoad reads a file in chunks off the Update loop. Each chunk is a
+// separate command, so cancelling simply stops issuing the next one.
+type fileLoad struct {
+	fs       FS
+	path     string
+	replace  bool // open into the current tab instead of a new one
+	prevView View
+	size     int64
+	read     int64
+
+	// Owned by the reading command
+	file fs.File
+	data []byte
+}
+
+type loadProgressMsg struct {
+	load *fileLoad
+	read int64
+}
+
+type loadDoneMsg struct {
+	load *fileLoad
+	buf  *buffer.Buffer
+	err  error
+}
+
+func (m *Model) startLoad(path string, replace bool) tea.Cmd {
+	if m.jumpToOpenTab(path) {
+		return nil
+	}
+	if m.isBlockDevice(path) {
+		// Read on demand, so there's nothing to load
+		if err := m.openDevice(path, replace); err != nil {
+			m.setStatus(sevError, fmt.Sprintf("Error opening %s: %v", path, err))
+		}
+		return nil
+	}
+	ld := &fileLoad{fs: m.fs, path: path, replace: replace, prevView: m.view}
+	m.loading = ld
+	m.view = ViewLoading
+	return ld.open
+}
+
+func (ld *fileLoad) open() tea.Msg {
+	f, err := ld.fs.Open(ld.path)
+	if err != nil {
+		return loadDoneMsg{load: ld, err: err}
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return loadDoneMsg{load: ld, err: err}
+	}
+
+	ld.file = f
+	ld.size = info.Size()
+	ld.data = make([]byte, 0, ld.size)
+	return loadProgressMsg{load: ld}
+}
+
+func (ld *fileLoad) readChunk() tea.Msg {
+	if cap(ld.data)-len(ld.data) < loadChunk {
+		grown := make([]byte, len(ld.data), len(ld.data)+loadChunk)
+		copy(grown, ld.data)
+		ld.data = grown
+	}
+
+	n, err := ld.file.Read(ld.data[len(ld.data) : len(ld.data)+loadChunk])
+	ld.data = ld.data[:len(ld.data)+n]
+
+	if err == io.EOF {
+		ld.file.Close()
+		return loadDoneMsg{load: ld, buf: buffer.FromData(ld.path, ld.data)}
+	}
+	if err != nil {
+		ld.file.Close()
+		return loadDoneMsg{load: ld, err: err}
+	}
+	return loadProgressMsg{load: ld, read: int64(len(ld.data))}
+}
+
+func (m *Model) handleLoadProgress(msg loadProgressMsg) (tea.Model, tea.Cmd) {
+	if msg.load != m.loading {
+		// Cancelled while the chunk was being read
+		msg.load.file.Close()
+		return m, nil
+	}
+	m.loading.read = msg.read
+	return m, msg.load.readChunk
+}
+
+func (m *Model) handleLoadDone(msg loadDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.load != m.loading {
+		return m, nil
+	}
+	ld := m.loading
+	m.loading = nil
+
+	if msg.err != nil {
+		m.view = ld.prevView
+		m.setStatus(sevError, fmt.Sprintf("Error opening %s: %v", ld.path, msg.err))
+		if m.view == ViewOpen {
+			// Kept in the browser: the status is gone after the next key
+			m.browserErr = fmt.Sprintf("Can't open %s: %s", filepath.Base(ld.path), errReason(msg.err))
+		}
+		return m, nil
+	}
+
+	tab := m.newTab(msg.buf)
+	m.placeTab(tab, ld.replace)
+	m.view = ViewMain
+	m.restoreView()
+	m.lockTab(tab)
+	m.loadRangeLocks(tab)
+	return m, nil
+}
+
+// placeTab puts a newly opened tab in place of the current one if replace
+// is set, or after the last one.
+func (m *Model) placeTab(tab *Tab, replace bool) {
+	if replace && len(m.tabs) > 0 {
+		m.rememberView(m.tabs[m.activeTab])
+		m.dropTab(m.tabs[m.activeTab])
+		m.tabs[m.activeTab] = tab
+		m.releaseLocks()
+	} else {
+		m.tabs = append(m.tabs, tab)
+		m.selectTab(len(m.tabs) - 1)
+	}
+}
+
+func (m *Model) handleLoadingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEscape && m.loading != nil {
+		m.view = m.loading.prevView
+		m.setStatus(sevInfo, "Loading cancelled")
+		m.loading = nil
+	}
+	return m, nil
+}
+
+func (m *Model) renderLoading() string {
+	ld := m.loading
+	if ld == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nLoading ")
+	b.WriteString(filepath.Base(ld.path))
+	b.WriteString("…")
+	if ld.size > 0 {
+		b.WriteString(fmt.Sprintf(" %d%%", ld.read*100/ld.size))
+	} else {
+		b.WriteString(fmt.Sprintf(" %d bytes", ld.read))
+	}
+	b.WriteString("\n\nPress ESC to cancel\n")
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/protohuf/unhexed/internal/lockfile"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Files opened from disk are locked (see package lockfile) until the last
+// tab showing them closes. Opening a file another instance holds asks
+// whether to open it read-only, edit it anyway or close it again; when
+// several files are held, the prompts follow each other.
+
+type lockConflict struct {
+	tab    *Tab
+	holder *lockfile.Info
+}
+
+func (m *Model) lockTab(tab *Tab) {
+	path := tab.Buffer.Filename()
+	if path == "" || slices.ContainsFunc(m.locks, func(l *lockfile.Lock) bool { return samePath(m.fs, l.Path(), path) }) {
+		return
+	}
+	l, holder, err := lockfile.Acquire(path)
+	switch {
+	case err != nil:
+		m.setStatus(sevWarning, fmt.Sprintf("Can't lock %s: %v", filepath.Base(path), err))
+	case holder != nil:
+		m.lockConflicts = append(m.lockConflicts, lockConflict{tab: tab, holder: holder})
+		m.promptLockConflict()
+	default:
+		m.locks = append(m.locks, l)
+	}
+}
+
+// promptLockConflict asks about the next file held elsewhere, unless a
+// dialog is already showing.
+func (m *Model) promptLockConflict() {
+	for m.view != ViewDialog && len(m.lockConflicts) > 0 {
+		c := m.lockConflicts[0]
+		m.lockConflicts = m.lockConflicts[1:]
+		i := slices.Index(m.tabs, c.tab)
+		if i < 0 {
+			continue
+		}
+		m.selectTab(i)
+		name := filepath.Base(c.tab.Buffer.Filename())
+		next := func() (tea.Model, tea.Cmd) {
+			m.promptLockConflict()
+			return m, nil
+		}
+		m.openDialog(fmt.Sprintf("%s is open in another unhexed (pid %d, started %s). Edits saved from both overwrite each other.",
+			name, c.holder.PID, c.holder.Started.Format("Jan 2 15:04")),
+			dialogButton{label: "Open read-only", action: func() (tea.Model, tea.Cmd) {
+				c.tab.Buffer.SetReadOnly(true)
+				m.setStatus(sevInfo, name+" is read-only; Ctrl+O to allow writing")
+				return next()
+			}},
+			dialogButton{label: "Edit anyway", action: next},
+			dialogButton{label: "Close", action: func() (tea.Model, tea.Cmd) {
+				if i := slices.Index(m.tabs, c.tab); i >= 0 {
+					m.selectTab(i)
+					m.closeCurrentTab()
+				}
+				return next()
+			}})
+	}
+}
+
+// releaseLocks releases the locks of files no tab shows any more.
+func (m *Model) releaseLocks() {
+	m.locks = slices.DeleteFunc(m.locks, func(l *lockfile.Lock) bool {
+		if slices.ContainsFunc(m.tabs, func(tab *Tab) bool { return samePath(m.fs, tab.Buffer.Filename(), l.Path()) }) {
+			return false
+		}
+		l.Release()
+		return true
+	})
+}
+
+// ReleaseLocks releases every lock, once the editor is done.
+func (m *Model) ReleaseLocks() {
+	for _, l := range m.locks {
+		l.Release()
+	}
+	m.locks = nil
+}
@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/protohuf/unhexed/internal/lockfile"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestOpenLocksUntilLastTabCloses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte{1, 2}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	m.duplicateView()
+	if len(m.locks) != 1 {
+		t.Fatalf("expected one lock, got %d", len(m.locks))
+	}
+
+	m.closeCurrentTab()
+	if len(m.locks) != 1 {
+		t.Error("the lock must be kept while a tab shows the file")
+	}
+	m.closeCurrentTab()
+	if len(m.locks) != 0 {
+		t.Error("closing the last tab should release the lock")
+	}
+}
+
+// TestOpenHeldFileOffersReadOnly locks the file as the test's parent
+// process would have, then opens it.
+func TestOpenHeldFileOffersReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte{1, 2}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	holdLock(t, path, os.Getppid())
+
+	m := newTestModel(nil)
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if m.view != ViewDialog || !strings.Contains(m.dialog.message, "open in another unhexed") {
+		t.Fatalf("expected the lock prompt, view %d", m.view)
+	}
+	m.activateButton(0)
+	tab := m.currentTab()
+	if !tab.Buffer.ReadOnly() || len(m.locks) != 0 {
+		t.Errorf("expected a read-only tab without a lock, read-only %v, locks %d", tab.Buffer.ReadOnly(), len(m.locks))
+	}
+}
+
+// holdLock writes path's lock as held by pid.
+func holdLock(t *testing.T, path string, pid int) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(lockfile.Info{PID: pid, Started: time.Now(), Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(lockfile.Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockfile.File(path), buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
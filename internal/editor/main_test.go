@@ -0,0 +1,18 @@
+package editor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the locks of files the tests open out of the real runtime dir
+	dir, err := os.MkdirTemp("", "unhexed-locks")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_RUNTIME_DIR", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,355 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Marks are labelled ranges of a tab's buffer, listed by "'" to jump back
+// to. They follow the data through edits like the selection does and are
+// dropped with the tab. The list can be exported as JSON for scripts, with
+// the last search's matches if asked, and marks written by a script can be
+// imported; imported marks that don't fit the buffer or reuse a label are
+// skipped and reported, never moved or renamed. The matches are found in
+// the background over a snapshot before the list is written.
+
+type mark struct {
+	label      string
+	start, end int64 // inclusive
+}
+
+// jumpList is the exported form. Offsets are as displayed, so they include
+// the display base of a window (see displayBase).
+type jumpList struct {
+	File    string      `json:"file,omitempty"`
+	Base    int64       `json:"base"`
+	Size    int64       `json:"size"`
+	Marks   []jumpEntry `json:"marks"`
+	Matches []jumpEntry `json:"matches,omitempty"`
+}
+
+type marksMatchesMsg struct {
+	seq     int
+	tab     *Tab
+	version uint64 // of the tab's buffer when scanned
+	path    string
+	find    []byte
+	hits    []int64
+	err     error
+}
+
+type jumpEntry struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	Label  string `json:"label,omitempty"`
+}
+
+type marksPrompt int
+
+const (
+	marksPromptNone marksPrompt = iota
+	marksPromptLabel
+	marksPromptExport
+	marksPromptImport
+)
+
+var marksPromptLabels = map[marksPrompt]string{
+	marksPromptLabel:  "Label: ",
+	marksPromptExport: "Export to: ",
+	marksPromptImport: "Import from: ",
+}
+
+func (m *Model) openMarks() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	m.view = ViewMarks
+	m.marksPrompt = marksPromptNone
+	m.marksSel = max(0, min(m.marksSel, len(tab.marks)-1))
+}
+
+// addMark marks the selection, or the byte under the cursor.
+func (m *Model) addMark(tab *Tab, label string) {
+	start, end := tab.Cursor, tab.Cursor
+	if tab.Selection.Active && !tab.Selection.Block {
+		start, end = m.getSelectedRange()
+	}
+	if start >= tab.Buffer.Size() {
+		m.setStatus(sevWarning, "Nothing to mark at the end of the file")
+		return
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = fmt.Sprintf("mark %d", len(tab.marks)+1)
+	}
+	if hasMark(tab, label) {
+		m.setStatus(sevWarning, fmt.Sprintf("There is already a mark %q", label))
+		return
+	}
+	tab.marks = append(tab.marks, &mark{label: label, start: start, end: end})
+	m.marksSel = len(tab.marks) - 1
+	m.setStatus(sevInfo, fmt.Sprintf("Marked 0x%X as %q", start+m.displayBase(tab), label))
+}
+
+func hasMark(tab *Tab, label string) bool {
+	return slices.ContainsFunc(tab.marks, func(k *mark) bool { return k.label == label })
+}
+
+// trackMarks maps tab's marks through an edit, dropping those deleted.
+func trackMarks(tab *Tab, c buffer.Change) {
+	tab.marks = slices.DeleteFunc(tab.marks, func(k *mark) bool {
+		start, end, ok := c.ShiftRange(k.start, k.end)
+		k.start, k.end = start, end
+		return !ok
+	})
+}
+
+func (m *Model) handleMarksKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tab := m.currentTab()
+	if tab == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	if m.marksPrompt != marksPromptNone {
+		switch msg.Type {
+		case tea.KeyEscape:
+			m.marksPrompt = marksPromptNone
+		case tea.KeyEnter:
+			prompt := m.marksPrompt
+			m.marksPrompt = marksPromptNone
+			return m, m.runMarksPrompt(tab, prompt, m.marksInput)
+		case tea.KeyBackspace:
+			if r := []rune(m.marksInput); len(r) > 0 {
+				m.marksInput = string(r[:len(r)-1])
+			}
+		case tea.KeyCtrlU:
+			m.marksInput = ""
+		case tea.KeyRunes, tea.KeySpace:
+			m.marksInput += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	var sel *mark
+	if m.marksSel < len(tab.marks) {
+		sel = tab.marks[m.marksSel]
+	}
+
+	switch msg.String() {
+	case "esc", "'":
+		m.view = ViewMain
+	case "up":
+		m.marksSel = max(m.marksSel-1, 0)
+	case "down":
+		m.marksSel = max(min(m.marksSel+1, len(tab.marks)-1), 0)
+	case "enter":
+		if sel != nil {
+			m.view = ViewMain
+			m.setCursor(sel.start)
+			if sel.end > sel.start {
+				m.selectTo(sel.end)
+			}
+		}
+	case "a", "A":
+		m.marksPrompt, m.marksInput = marksPromptLabel, ""
+	case "delete", "x", "X":
+		if sel != nil {
+			tab.marks = slices.Delete(tab.marks, m.marksSel, m.marksSel+1)
+			m.marksSel = max(min(m.marksSel, len(tab.marks)-1), 0)
+			m.setStatus(sevInfo, fmt.Sprintf("Dropped mark %q", sel.label))
+		}
+	case "s", "S":
+		m.marksWithMatches = !m.marksWithMatches
+	case "e", "E":
+		m.marksPrompt, m.marksInput = marksPromptExport, m.marksPath(tab)
+	case "i", "I":
+		m.marksPrompt, m.marksInput = marksPromptImport, m.marksPath(tab)
+	}
+	return m, nil
+}
+
+// marksPath suggests a file next to the tab's file for the jump list.
+func (m *Model) marksPath(tab *Tab) string {
+	if name := tab.Buffer.Filename(); name != "" {
+		return name + ".marks.json"
+	}
+	return "marks.json"
+}
+
+func (m *Model) runMarksPrompt(tab *Tab, prompt marksPrompt, input string) tea.Cmd {
+	if prompt == marksPromptLabel {
+		m.addMark(tab, input)
+		return nil
+	}
+	path := strings.TrimSpace(input)
+	if path == "" {
+		return nil
+	}
+	if prompt == marksPromptExport {
+		return m.exportMarks(tab, path)
+	}
+	m.importMarks(tab, path)
+	return nil
+}
+
+// exportMarks writes tab's marks to path, with the last search's matches
+// when asked for once they are found.
+func (m *Model) exportMarks(tab *Tab, path string) tea.Cmd {
+	if m.marksCancel != nil {
+		m.marksCancel()
+		m.marksCancel = nil
+	}
+	m.marksSeq++
+	if !m.marksWithMatches || len(m.lastFind) == 0 {
+		m.writeMarks(tab, path, nil, nil)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.marksCancel = cancel
+	m.setStatus(sevInfo, "Finding the matches to export…")
+
+	snapshot := tab.Buffer.Snapshot()
+	seq, find := m.marksSeq, m.lastFind
+	return func() tea.Msg {
+		hits, err := snapshot.FindAllContext(ctx, find)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return marksMatchesMsg{seq: seq, tab: tab, version: snapshot.Version(), path: path, find: find, hits: hits, err: err}
+	}
+}
+
+func (m *Model) handleMarksMatches(msg marksMatchesMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.marksSeq || !slices.Contains(m.tabs, msg.tab) {
+		return m, nil
+	}
+	if msg.err == nil && msg.tab.Buffer.Version() != msg.version {
+		// The buffer changed during the scan; the offsets may be wrong
+		return m, m.exportMarks(msg.tab, msg.path)
+	}
+	m.marksCancel = nil
+	if msg.err != nil {
+		m.setStatus(sevError, "Error finding matches: "+msg.err.Error())
+		return m, nil
+	}
+	m.writeMarks(msg.tab, msg.path, msg.find, msg.hits)
+	return m, nil
+}
+
+// writeMarks writes tab's marks to path, with hits, the offsets of find.
+func (m *Model) writeMarks(tab *Tab, path string, find []byte, hits []int64) {
+	base := m.displayBase(tab)
+	list := jumpList{File: tab.Buffer.Filename(), Base: base, Size: tab.Buffer.Size(), Marks: []jumpEntry{}}
+	for _, k := range tab.marks {
+		list.Marks = append(list.Marks, jumpEntry{Offset: k.start + base, Length: k.end - k.start + 1, Label: k.label})
+	}
+	for _, hit := range hits {
+		list.Matches = append(list.Matches, jumpEntry{Offset: hit + base, Length: int64(len(find))})
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err == nil {
+		err = m.fs.WriteFile(path, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		m.setStatus(sevError, "Error exporting marks: "+err.Error())
+		return
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Exported %d marks and %d matches to %s", len(list.Marks), len(list.Matches), filepath.Base(path)))
+}
+
+// importMarks adds the marks of the jump list at path to tab. Search
+// matches in it are not imported.
+func (m *Model) importMarks(tab *Tab, path string) {
+	data, err := m.fs.ReadFile(path)
+	var list jumpList
+	if err == nil {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		m.setStatus(sevError, "Error importing marks: "+err.Error())
+		return
+	}
+
+	base, size := m.displayBase(tab), tab.Buffer.Size()
+	added, skipped := 0, 0
+	for i, e := range list.Marks {
+		label := strings.TrimSpace(e.Label)
+		if label == "" {
+			label = fmt.Sprintf("imported %d", i+1)
+		}
+		start := e.Offset - base
+		var problem string
+		switch {
+		case e.Length < 1:
+			problem = fmt.Sprintf("length %d", e.Length)
+		case start < 0 || start+e.Length > size:
+			problem = fmt.Sprintf("0x%X+%d is outside 0x%X-0x%X", e.Offset, e.Length, base, base+size)
+		case hasMark(tab, label):
+			problem = "the label is taken"
+		}
+		if problem != "" {
+			m.setStatus(sevWarning, fmt.Sprintf("Skipped mark %q: %s", label, problem))
+			skipped++
+			continue
+		}
+		tab.marks = append(tab.marks, &mark{label: label, start: start, end: start + e.Length - 1})
+		added++
+	}
+
+	msg := fmt.Sprintf("Imported %d marks from %s", added, filepath.Base(path))
+	if skipped > 0 {
+		m.setStatus(sevWarning, fmt.Sprintf("%s, skipped %d (M shows why)", msg, skipped))
+		return
+	}
+	m.setStatus(sevInfo, msg)
+}
+
+func (m *Model) renderMarks() string {
+	tab := m.currentTab()
+	if tab == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nMARKS\n")
+	b.WriteString("=====\n\n")
+
+	if len(tab.marks) == 0 {
+		b.WriteString("No marks in this tab yet\n")
+	}
+	base := m.displayBase(tab)
+	for i, k := range tab.marks {
+		line := fmt.Sprintf("%-24s 0x%08X  %d bytes", truncateMiddle(k.label, 24), k.start+base, k.end-k.start+1)
+		if i == m.marksSel {
+			b.WriteString("> " + m.styles.Selection.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if m.marksPrompt != marksPromptNone {
+		b.WriteString("\n" + marksPromptLabels[m.marksPrompt] + m.marksInput + "_\n")
+		b.WriteString("\nPress Enter to confirm, ESC to cancel\n")
+		return b.String()
+	}
+	matches := "off"
+	if m.marksWithMatches {
+		matches = "on"
+	}
+	b.WriteString(fmt.Sprintf("\nExporting the last search's matches: %s\n", matches))
+	b.WriteString("\nEnter jump, A mark the cursor or selection, X drop, E export, I import, S toggle matches, ESC close\n")
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package editor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMarksFollowEditsAndJump(t *testing.T) {
+	m := newTestModel(make([]byte, 32))
+	tab := m.currentTab()
+	m.setCursor(8)
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight)
+	typeKeys(m, "'a")
+	typeKeys(m, "header")
+	press(m, tea.KeyEnter)
+	if len(tab.marks) != 1 || *tab.marks[0] != (mark{"header", 8, 11}) {
+		t.Fatalf("expected the selection marked, got %+v", tab.marks)
+	}
+
+	tab.Buffer.Insert(0, []byte{1, 2})
+	press(m, tea.KeyEnter)
+	if m.view != ViewMain || !tab.Selection.Active || tab.Selection.Start != 10 || tab.Selection.End != 13 {
+		t.Errorf("expected the mark moved to 10-13 and selected, got %+v", tab.Selection)
+	}
+
+	tab.Buffer.Delete(8, 10)
+	if len(tab.marks) != 0 {
+		t.Errorf("deleting the marked bytes should drop the mark, got %+v", tab.marks)
+	}
+}
+
+func TestMarksKeptThroughRevertAndRestore(t *testing.T) {
+	m := newTestModel(nil)
+	tab := m.newTab(buffer.FromData("data.bin", make([]byte, 32)))
+	m.tabs = []*Tab{tab}
+	tab.marks = []*mark{{"a", 9, 9}, {"tail", 30, 31}}
+	tab.Buffer.Insert(32, make([]byte, 8))
+	tab.marks = append(tab.marks, &mark{"added", 36, 39})
+
+	tab.Buffer.Revert()
+	if len(tab.marks) != 2 || *tab.marks[0] != (mark{"a", 9, 9}) || *tab.marks[1] != (mark{"tail", 30, 31}) {
+		t.Fatalf("expected the marks within the file kept, got %+v", tab.marks)
+	}
+
+	tab.Buffer.Restore(buffer.FromData("", make([]byte, 31)))
+	tab.Buffer.Undo()
+	if len(tab.marks) != 2 || *tab.marks[0] != (mark{"a", 9, 9}) || *tab.marks[1] != (mark{"tail", 30, 30}) {
+		t.Errorf("expected the marks kept, cut at the restored end, got %+v", tab.marks)
+	}
+}
+
+func TestMarksExportAndImport(t *testing.T) {
+	data := []byte("..ab....ab......")
+	m := newTestModel(data)
+	tab := m.currentTab()
+	tab.marks = []*mark{{"one", 2, 3}}
+	m.lastFind = []byte("ab")
+	m.marksWithMatches = true
+
+	path := filepath.Join(t.TempDir(), "marks.json")
+	scan := m.exportMarks(tab, path)
+	if scan == nil {
+		t.Fatal("expected the matches found in the background")
+	}
+	// An edit during the scan finds them again
+	tab.Buffer.Insert(0, []byte("."))
+	_, rescan := m.handleMarksMatches(scan().(marksMatchesMsg))
+	if _, err := os.Stat(path); err == nil || rescan == nil {
+		t.Fatal("expected a stale scan to start another, not write the list")
+	}
+	m.Update(rescan())
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list jumpList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Marks) != 1 || list.Marks[0] != (jumpEntry{3, 2, "one"}) || len(list.Matches) != 2 || list.Matches[1].Offset != 9 {
+		t.Errorf("unexpected export %s", raw)
+	}
+
+	// One fits, one reuses a label, one is past the end
+	list.Marks = append(list.Marks, jumpEntry{10, 4, "two"}, jumpEntry{14, 4, "three"})
+	raw, _ = json.Marshal(list)
+	os.WriteFile(path, raw, 0644)
+	m.importMarks(tab, path)
+	if len(tab.marks) != 2 || tab.marks[1].label != "two" {
+		t.Errorf("expected only \"two\" added, got %+v", tab.marks)
+	}
+	if m.status.sev != sevWarning || !strings.Contains(m.status.text, "skipped 2") {
+		t.Errorf("expected the skipped marks reported, got %q", m.status.text)
+	}
+}
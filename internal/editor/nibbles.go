@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The nibble view (the last step of W) addresses the hex column a digit at
+// a time, for packed formats and codecs that work in 4-bit units. Left and
+// Right walk nibbles, the cursor marks one digit of its byte, and Replace
+// mode writes one nibble per key instead of a byte per two. Everything
+// else, from the selection to the decoder, still works on the byte the
+// cursor is in, and leaving the view lands on it.
+
+// moveNibbles moves the cursor n nibbles left or right.
+func (m *Model) moveNibbles(n int64, clearSel bool) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	pos := max(tab.Cursor*2+int64(tab.nibble)+n, 0)
+	if last := m.maxCursor(tab); pos/2 > last {
+		pos = last * 2
+		if last < tab.Buffer.Size() {
+			pos++
+		}
+	}
+	tab.nibble = int(pos % 2)
+	m.moveCursor(pos/2-tab.Cursor, clearSel)
+}
+
+// typeNibble writes v to the cursor's nibble and moves to the next one.
+func (m *Model) typeNibble(tab *Tab, v byte) {
+	b, _ := tab.Buffer.GetByte(tab.Cursor)
+	if tab.nibble == 0 {
+		b = v<<4 | b&0x0F
+	} else {
+		b = b&0xF0 | v
+	}
+	if m.refused("replace", tab.Buffer.Replace(tab.Cursor, b)) {
+		return
+	}
+	m.recordPut("typed byte", []byte{b}, false)
+	m.moveNibbles(1, true)
+}
+
+// nibblePosition formats the cursor as byte.nibble, e.g. 0x1F3.1.
+func (m *Model) nibblePosition(tab *Tab) string {
+	return fmt.Sprintf("0x%X.%d", tab.Cursor+m.displayBase(tab), tab.nibble)
+}
+
+// writeNibbleCell writes the cursor byte's two digits, giving the cursor's
+// style to its nibble only.
+func (m *Model) writeNibbleCell(line *styleRun, tab *Tab, offset int64, hexStr string, style *lipgloss.Style) {
+	rest := m.crosshairStyle(tab, offset)
+	if tab.nibble == 0 {
+		line.write(style, hexStr[:1])
+		line.write(rest, hexStr[1:])
+	} else {
+		line.write(rest, hexStr[:1])
+		line.write(style, hexStr[1:])
+	}
+}
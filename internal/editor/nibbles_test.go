@@ -0,0 +1,49 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNibbleView(t *testing.T) {
+	m := newTestModel([]byte{0x12, 0x34, 0x56})
+	tab := m.currentTab()
+	for range 4 {
+		typeKeys(m, "w")
+	}
+	if !tab.Nibbles || !strings.Contains(m.status.text, "nibbles") {
+		t.Fatalf("the nibble view should follow u64 values, got %q", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != 1 || tab.nibble != 1 || !strings.Contains(m.renderDecoder(), "Nibble: 0x1.1") {
+		t.Fatalf("expected the low nibble of byte 1, got %s", m.nibblePosition(tab))
+	}
+
+	// One nibble per key, carrying on into the next byte
+	typeKeys(m, "r")
+	typeKeys(m, "ab")
+	if got := tab.Buffer.GetBytes(0, 3); !bytes.Equal(got, []byte{0x12, 0x3A, 0xB6}) {
+		t.Errorf("expected 12 3A B6, got % X", got)
+	}
+	if tab.Cursor != 2 || tab.nibble != 1 {
+		t.Errorf("expected the cursor on 0x2.1, got %s", m.nibblePosition(tab))
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+
+	// The last nibble is as far as the cursor goes
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if tab.Cursor != 2 || tab.nibble != 1 {
+		t.Errorf("expected the cursor to stay on 0x2.1, got %s", m.nibblePosition(tab))
+	}
+
+	typeKeys(m, "w")
+	if tab.Nibbles || tab.Cursor != 2 || tab.nibble != 0 {
+		t.Errorf("expected bytes again on byte 2, got %s", m.nibblePosition(tab))
+	}
+}
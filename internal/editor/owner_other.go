@@ -0,0 +1,13 @@
+//go:build !unix
+
+package editor
+
+import "os"
+
+func fileOwner(os.FileInfo) string {
+	return "unknown"
+}
+
+func canRead(os.FileInfo) bool {
+	return true
+}
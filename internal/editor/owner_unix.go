@@ -0,0 +1,60 @@
+//go:build unix
+
+package editor
+
+import (
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// fileOwner returns the user and group owning the file.
+func fileOwner(info os.FileInfo) string {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "unknown"
+	}
+	uid := strconv.FormatUint(uint64(st.Uid), 10)
+	gid := strconv.FormatUint(uint64(st.Gid), 10)
+	owner, group := uid, gid
+	if u, err := user.LookupId(uid); err == nil {
+		owner = u.Username
+	}
+	if g, err := user.LookupGroupId(gid); err == nil {
+		group = g.Name
+	}
+	return owner + ":" + group
+}
+
+// canRead reports whether the permission bits of info let this process
+// read it, and list it when it is a directory.
+func canRead(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+	groups, _ := os.Getgroups()
+	return permits(info.Mode(), st.Uid, st.Gid, uint32(os.Geteuid()), uint32(os.Getegid()), groups)
+}
+
+// permits applies the owner, group or other bits of mode, as the kernel
+// does, for a process running as uid with gid and groups. Root reads
+// anything.
+func permits(mode os.FileMode, owner, group, uid, gid uint32, groups []int) bool {
+	need := os.FileMode(04)
+	if mode.IsDir() {
+		need = 05
+	}
+	perm := mode.Perm()
+	switch {
+	case uid == 0:
+		return true
+	case uid == owner:
+		perm >>= 6
+	case gid == group || slices.Contains(groups, int(group)):
+		perm >>= 3
+	}
+	return perm&need == need
+}
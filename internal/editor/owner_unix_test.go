@@ -0,0 +1,32 @@
+//go:build unix
+
+package editor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPermits(t *testing.T) {
+	tests := []struct {
+		mode       os.FileMode
+		owner, uid uint32
+		group, gid uint32
+		groups     []int
+		want       bool
+	}{
+		{0600, 1000, 1000, 100, 100, nil, true},
+		{0600, 1000, 1001, 100, 100, nil, false},
+		{0640, 1000, 1001, 100, 100, nil, true},
+		{0640, 1000, 1001, 100, 200, []int{100}, true},
+		{0604, 1000, 1001, 100, 200, nil, true},
+		{0000, 1000, 0, 100, 0, nil, true},
+		{os.ModeDir | 0744, 1000, 1001, 100, 100, nil, false},
+		{os.ModeDir | 0755, 1000, 1001, 100, 100, nil, true},
+	}
+	for _, tt := range tests {
+		if got := permits(tt.mode, tt.owner, tt.group, tt.uid, tt.gid, tt.groups); got != tt.want {
+			t.Errorf("permits(%v, owner %d:%d, as %d:%d %v) = %v", tt.mode, tt.owner, tt.group, tt.uid, tt.gid, tt.groups, got)
+		}
+	}
+}
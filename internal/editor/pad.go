@@ -0,0 +1,187 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// "|" pads to an alignment: it inserts fill bytes at the cursor, or after
+// the selection, up to the next multiple of a boundary. "#" resizes the
+// file to an exact size, extending it with a fill byte or truncating it.
+
+// parseSizeFill parses "N [BYTE]": a size in decimal or 0x hex and an
+// optional hex fill byte, 00 by default.
+func parseSizeFill(input string) (int64, byte, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, 0, fmt.Errorf("enter a size, optionally followed by a hex fill byte")
+	}
+	n, err := parseOffset(fields[0])
+	if err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("invalid size %q", fields[0])
+	}
+	var fill byte
+	if len(fields) == 2 {
+		if fill, err = parseHexByte(fields[1]); err != nil {
+			return 0, 0, err
+		}
+	}
+	return n, fill, nil
+}
+
+// padBytes returns how many bytes to add at pos to reach a multiple of
+// boundary.
+func padBytes(pos, boundary int64) int64 {
+	return (boundary - pos%boundary) % boundary
+}
+
+// handleSizeInputKey edits input, calling enter on Enter; enter reports
+// whether the input was accepted, and returns the command running it.
+func (m *Model) handleSizeInputKey(msg tea.KeyMsg, input *string, enter func() (bool, tea.Cmd)) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewMain
+	case tea.KeyEnter:
+		// Keep the dialog open to correct the input
+		ok, cmd := enter()
+		if ok && m.view != ViewDialog {
+			m.view = ViewMain
+		}
+		return m, cmd
+	case tea.KeyCtrlU:
+		*input = ""
+	case tea.KeyBackspace:
+		if len(*input) > 0 {
+			*input = (*input)[:len(*input)-1]
+		}
+	default:
+		char := msg.String()
+		if isHexChar(char) || char == " " || char == "x" || char == "X" {
+			*input += char
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handlePadKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleSizeInputKey(msg, &m.padInput, func() (bool, tea.Cmd) {
+		boundary, fill, err := parseSizeFill(m.padInput)
+		if err == nil && boundary < 2 {
+			err = fmt.Errorf("the boundary must be 2 or more")
+		}
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return false, nil
+		}
+		pad := func() tea.Cmd {
+			return m.padTo(boundary, fill)
+		}
+		m.recordEdit("padding", false, pad)
+		return true, pad()
+	})
+}
+
+// padTo inserts fill bytes at the cursor, or after the selection, so the
+// offset after them is a multiple of boundary.
+func (m *Model) padTo(boundary int64, fill byte) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	pos := tab.Cursor
+	if tab.Selection.Active && !tab.Selection.Block {
+		_, end := m.getSelectedRange()
+		pos = end + 1
+	}
+	n := padBytes(pos+m.displayBase(tab), boundary)
+	if n == 0 {
+		m.setStatus(sevInfo, fmt.Sprintf("0x%X is already aligned to %d", pos+m.displayBase(tab), boundary))
+		return nil
+	}
+	if m.editBlocked(tab) || m.resizeBlocked(tab) {
+		return nil
+	}
+	job := m.insertFillJob(tab, "Pad", pos, n, fill)
+	job.finish = func() {
+		m.setCursor(pos + n)
+		m.setStatus(sevInfo, fmt.Sprintf("Added %d bytes of 0x%02X; 0x%X is aligned to %d", n, fill, pos+n+m.displayBase(tab), boundary))
+	}
+	return m.runEdit(job)
+}
+
+func (m *Model) handleResizeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleSizeInputKey(msg, &m.resizeInput, func() (bool, tea.Cmd) {
+		size, fill, err := parseSizeFill(m.resizeInput)
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return false, nil
+		}
+		m.confirmResize(size, fill)
+		return true, nil
+	})
+}
+
+func (m *Model) confirmResize(size int64, fill byte) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	cur := tab.Buffer.Size()
+	if size == cur {
+		m.setStatus(sevInfo, fmt.Sprintf("The file is already %d bytes", size))
+		return
+	}
+	if m.editBlocked(tab) || m.resizeBlocked(tab) {
+		return
+	}
+
+	message := fmt.Sprintf("Extend to %d bytes, adding %d bytes of 0x%02X?", size, size-cur, fill)
+	if size < cur {
+		message = fmt.Sprintf("Truncate to %d bytes, dropping the last %d?", size, cur-size)
+	}
+	m.openDialog(message,
+		m.cancelButton(ViewResize),
+		dialogButton{label: "Resize", action: func() (tea.Model, tea.Cmd) {
+			done := func() {
+				m.setCursor(tab.Cursor)
+				m.setStatus(sevInfo, fmt.Sprintf("Resized from %d to %d bytes", cur, size))
+			}
+			if size < cur {
+				if !m.refused("truncate", tab.Buffer.Delete(size, int(cur-size))) {
+					done()
+				}
+				return m, nil
+			}
+			job := m.insertFillJob(tab, "Extend", cur, size-cur, fill)
+			job.finish = done
+			return m, m.runEdit(job)
+		}})
+}
+
+func (m *Model) renderPad() string {
+	var b strings.Builder
+	b.WriteString("\nPAD TO ALIGNMENT\n")
+	b.WriteString("================\n\n")
+	b.WriteString("Boundary: " + m.padInput + "_\n\n")
+	b.WriteString("A boundary (2, 4, 8, 16, 512, 0x1000…), optionally followed by a hex fill byte:\n")
+	b.WriteString("\"16\" pads with 00 up to the next multiple of 16, \"512 FF\" with FF\n")
+	b.WriteString("Bytes are inserted at the cursor, or after the selection\n")
+	b.WriteString("\nPress Enter to pad, ESC to cancel\n")
+	return b.String()
+}
+
+func (m *Model) renderResize() string {
+	var b strings.Builder
+	b.WriteString("\nRESIZE FILE\n")
+	b.WriteString("===========\n\n")
+	if tab := m.currentTab(); tab != nil {
+		b.WriteString(fmt.Sprintf("Current size: %d (0x%X) bytes\n\n", tab.Buffer.Size(), tab.Buffer.Size()))
+	}
+	b.WriteString("New size: " + m.resizeInput + "_\n\n")
+	b.WriteString("A size in decimal or 0x hex, optionally followed by a hex fill byte\n")
+	b.WriteString("for the bytes added when extending, e.g. \"0x10000 FF\"\n")
+	b.WriteString("\nPress Enter to resize, ESC to cancel\n")
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPadToAlignment(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 3, 4, 5})
+	tab := m.currentTab()
+	tab.Cursor = 3
+
+	typeKeys(m, "|8 ff")
+	press(m, tea.KeyEnter)
+	want := []byte{1, 2, 3, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 4, 5}
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, want) {
+		t.Errorf("got % X", got)
+	}
+	if tab.Cursor != 8 || m.view != ViewMain {
+		t.Errorf("expected the cursor on the aligned offset, got %d", tab.Cursor)
+	}
+
+	// One undo step; padding again at an aligned offset does nothing
+	typeKeys(m, ".")
+	if tab.Buffer.Size() != 10 || m.status.text != "0x8 is already aligned to 8" {
+		t.Errorf("size %d, status %q", tab.Buffer.Size(), m.status.text)
+	}
+	typeKeys(m, "u")
+	if tab.Buffer.Size() != 5 {
+		t.Errorf("undo should remove the padding, size %d", tab.Buffer.Size())
+	}
+}
+
+func TestPadAfterSelection(t *testing.T) {
+	m := newTestModel(make([]byte, 8))
+	tab := m.currentTab()
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight)
+
+	typeKeys(m, "|4")
+	press(m, tea.KeyEnter)
+	if tab.Buffer.Size() != 9 || tab.Cursor != 4 {
+		t.Errorf("expected 1 byte after the selection, size %d cursor %d", tab.Buffer.Size(), tab.Cursor)
+	}
+}
+
+func TestResizeConfirms(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 3, 4})
+	tab := m.currentTab()
+
+	typeKeys(m, "#6 aa")
+	press(m, tea.KeyEnter)
+	if m.view != ViewDialog {
+		t.Fatalf("expected a confirmation, status %q", m.status.text)
+	}
+	m.activateButton(1)
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, []byte{1, 2, 3, 4, 0xAA, 0xAA}) {
+		t.Errorf("got % X", got)
+	}
+
+	typeKeys(m, "#0x2")
+	press(m, tea.KeyEnter)
+	m.activateButton(1)
+	if tab.Buffer.Size() != 2 || tab.Cursor > 1 {
+		t.Errorf("expected 2 bytes, got %d, cursor %d", tab.Buffer.Size(), tab.Cursor)
+	}
+}
+
+func TestHugePadAndExtendRunAsJobs(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 3})
+	tab := m.currentTab()
+	tab.Cursor = 1
+
+	typeKeys(m, "|0x10000000000")
+	press(m, tea.KeyEnter)
+	if m.view != ViewDialog || tab.Buffer.Size() != 3 {
+		t.Fatalf("expected the large edit confirmation, view %v size %d", m.view, tab.Buffer.Size())
+	}
+	m.activateButton(0)
+
+	typeKeys(m, "#0x20000000000")
+	press(m, tea.KeyEnter)
+	if m.view != ViewDialog {
+		t.Fatalf("expected the resize confirmation, status %q", m.status.text)
+	}
+	_, cmd := m.activateButton(1)
+	if cmd != nil || m.view != ViewDialog || tab.Buffer.Size() != 3 {
+		t.Fatalf("expected the large edit confirmation, view %v size %d", m.view, tab.Buffer.Size())
+	}
+}
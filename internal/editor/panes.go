@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Both columns show the cursor, but only one has focus: the hex column by
+// default, or the text column after Ctrl+T. The focused column draws the
+// full marker and the other a hollow one, and in insert or replace mode
+// typing goes where the focus is: hex digits in the hex column, printable
+// characters in the text column.
+
+func (m *Model) togglePane() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	tab.TextFocus = !tab.TextFocus
+	m.hexNibble = 0
+	if tab.TextFocus {
+		m.showStatus(sevInfo, "Text column focused")
+	} else {
+		m.showStatus(sevInfo, "Hex column focused")
+	}
+}
+
+// paneStyles splits a cell's style into the hex and text columns' styles,
+// making the cursor hollow in the column without focus.
+func (m *Model) paneStyles(tab *Tab, offset int64, style *lipgloss.Style) (hex, text *lipgloss.Style) {
+	if offset != tab.Cursor || style == &m.styles.Selection {
+		return style, style
+	}
+	if tab.TextFocus {
+		return &m.styles.MarkerHollow, style
+	}
+	return style, &m.styles.MarkerHollow
+}
+
+// typedText returns the printable text a key types, if any.
+func typedText(msg tea.KeyMsg) (string, bool) {
+	switch {
+	case msg.Alt:
+		return "", false
+	case msg.Type == tea.KeySpace:
+		return " ", true
+	case msg.Type == tea.KeyRunes:
+		return string(msg.Runes), true
+	}
+	return "", false
+}
+
+// typeText writes text at the cursor as the text column shows it: ASCII,
+// or UTF-8 when the tab decodes it so.
+func (m *Model) typeText(text string) tea.Cmd {
+	tab := m.currentTab()
+	if !tab.UTF8Text {
+		for _, r := range text {
+			if r >= utf8.RuneSelf {
+				m.setStatus(sevWarning, fmt.Sprintf("%q isn't ASCII; T shows the text column as UTF-8 so it can be typed", r))
+				return nil
+			}
+		}
+	}
+	data := []byte(text)
+	insert := m.mode == ModeInsert
+	if !m.putBytes(tab, data, insert) {
+		return nil
+	}
+	m.recordPut("typed text", data, insert)
+	m.clearSelection()
+	return nil
+}
@@ -0,0 +1,49 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTypingFollowsThePaneFocus(t *testing.T) {
+	m := newTestModel([]byte("abcdef"))
+	tab := m.currentTab()
+
+	hex, text := m.paneStyles(tab, 0, m.cellStyle(tab, 0, 'a', true, -1))
+	if hex != &m.styles.MarkerNormal || text != &m.styles.MarkerHollow {
+		t.Error("the hex column should have the focus at first")
+	}
+
+	typeKeys(m, "r")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if !tab.TextFocus {
+		t.Fatal("Ctrl+T should focus the text column")
+	}
+	hex, text = m.paneStyles(tab, 0, m.cellStyle(tab, 0, 'a', true, -1))
+	if hex != &m.styles.MarkerHollow || text != &m.styles.MarkerReplace {
+		t.Error("the text column should draw the full marker")
+	}
+
+	// Letters and hex digits are text now, not commands or nibbles
+	typeKeys(m, "Qa")
+	m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	if got := tab.Buffer.GetBytes(0, 6); !bytes.Equal(got, []byte("Qa def")) {
+		t.Fatalf("expected the text typed over the bytes, got %q", got)
+	}
+	if m.view != ViewMain || tab.Cursor != 3 {
+		t.Errorf("expected the cursor after the text, got %d", tab.Cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'é'}})
+	if m.status.sev != sevWarning || tab.Buffer.GetBytes(3, 1)[0] != 'd' {
+		t.Errorf("non-ASCII text needs the UTF-8 column, got %q", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	typeKeys(m, "41")
+	if got := tab.Buffer.GetBytes(3, 1); got[0] != 0x41 {
+		t.Errorf("the hex column should take hex digits again, got % X", got)
+	}
+}
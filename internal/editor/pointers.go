@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/internal/bitfield"
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// Bitfield fields listed as pointers hold a file offset. Enter on one in
+// the bitfield view, or Alt+J on the first one of the active template,
+// moves the cursor to the offset it holds and, if the template says what
+// it points at (points_to), switches to that template there, so a chain of
+// headers can be walked field by field. Each jump is remembered and Alt+O
+// goes back to where it was made, template included. A pointer past the
+// end of the file is flagged in the panel rather than followed.
+
+// maxJumps is how many jumps back Alt+O can go.
+const maxJumps = 100
+
+type jump struct {
+	cursor   int64
+	template *bitfield.Template
+}
+
+// pointerTarget returns the offset in tab that field of word points at,
+// and whether that is within the file. Pointers hold offsets in the file,
+// which for a window of one start before the window does.
+func (m *Model) pointerTarget(tab *Tab, field bitfield.Field, word uint64) (int64, bool) {
+	base := m.displayBase(tab)
+	v := field.Get(word)
+	if v < uint64(base) || v-uint64(base) >= uint64(tab.Buffer.Size()) {
+		return 0, false
+	}
+	return int64(v - uint64(base)), true
+}
+
+// pointerValue describes where field of word points, for the panels.
+func (m *Model) pointerValue(tab *Tab, field bitfield.Field, word uint64) string {
+	if _, ok := m.pointerTarget(tab, field, word); !ok {
+		return m.styles.StatusWarning.Render(fmt.Sprintf("→ 0x%X (past the end)", field.Get(word)))
+	}
+	return fmt.Sprintf("→ 0x%X", field.Get(word))
+}
+
+// followPointer jumps to where field of tab's template points at the
+// cursor, reporting whether it did.
+func (m *Model) followPointer(tab *Tab, field bitfield.Field) bool {
+	word, ok := m.bitfieldWord(tab)
+	if !ok {
+		m.setStatus(sevWarning, fmt.Sprintf("%s needs %d bytes at the cursor", tab.Bitfield.Name, tab.Bitfield.Size))
+		return false
+	}
+	target, ok := m.pointerTarget(tab, field, word)
+	if !ok {
+		m.alert(fmt.Sprintf("%s points at 0x%X, past the end of the file", field.Name, field.Get(word)))
+		return false
+	}
+
+	tab.jumps = append(tab.jumps, jump{tab.Cursor, tab.Bitfield})
+	if len(tab.jumps) > maxJumps {
+		tab.jumps = tab.jumps[len(tab.jumps)-maxJumps:]
+	}
+	from := tab.Bitfield
+	m.setCursor(target)
+	m.ensureCursorVisible()
+
+	msg := fmt.Sprintf("Followed %s to 0x%X (Alt+O goes back)", field.Name, target+m.displayBase(tab))
+	if name := from.PointsTo; name != "" {
+		next := m.findBitfield(name)
+		if next == nil {
+			m.setStatus(sevWarning, fmt.Sprintf("%s; no template %s to apply there", msg, name))
+			return true
+		}
+		tab.Bitfield = next
+		msg += ", as " + name
+	}
+	m.setStatus(sevInfo, msg)
+	return true
+}
+
+// findBitfield returns the loaded template called name, or nil.
+func (m *Model) findBitfield(name string) *bitfield.Template {
+	for _, t := range m.bitfields {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// followFirstPointer follows the first pointer field of the current tab's
+// template, from the main view.
+func (m *Model) followFirstPointer() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if tab.Bitfield == nil {
+		m.setStatus(sevWarning, "No template applied (J chooses one)")
+		return
+	}
+	for _, f := range tab.Bitfield.Fields {
+		if f.Pointer {
+			m.followPointer(tab, f)
+			return
+		}
+	}
+	m.setStatus(sevWarning, fmt.Sprintf("Template %s has no pointer fields", tab.Bitfield.Name))
+}
+
+// jumpBack returns to where the last pointer was followed from.
+func (m *Model) jumpBack() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if len(tab.jumps) == 0 {
+		m.setStatus(sevInfo, "No jumps to go back from")
+		return
+	}
+	j := tab.jumps[len(tab.jumps)-1]
+	tab.jumps = tab.jumps[:len(tab.jumps)-1]
+	m.setCursor(j.cursor)
+	m.ensureCursorVisible()
+	tab.Bitfield = j.template
+	m.bitfieldField = 0
+	m.setStatus(sevInfo, fmt.Sprintf("Back at 0x%X (%d more)", tab.Cursor+m.displayBase(tab), len(tab.jumps)))
+}
+
+// trackJumps moves tab's jump history through an edit.
+func trackJumps(tab *Tab, c buffer.Change) {
+	for i := range tab.jumps {
+		tab.jumps[i].cursor = shiftOffset(tab.jumps[i].cursor, c)
+	}
+}
@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/bitfield"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFollowPointers(t *testing.T) {
+	templates, err := bitfield.LoadFile(writeTemplate(t, `
+[[bitfield]]
+name = "head"
+size = 1
+fields = "bits 0-7: first"
+pointers = "first"
+points_to = "chunk"
+
+[[bitfield]]
+name = "chunk"
+size = 1
+fields = "bit 7: last, bits 0-6: next"
+pointers = "next"
+points_to = "chunk"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := startEditor(t, writeTestFile(t, "\x04...\x08...\x7F..."))
+	m.addBitfields(templates)
+	tab := m.currentTab()
+	tab.Bitfield = templates[0]
+
+	Drive(m, altKey('j'))
+	if tab.Cursor != 4 || tab.Bitfield.Name != "chunk" {
+		t.Fatalf("cursor 0x%X, template %s", tab.Cursor, tab.Bitfield.Name)
+	}
+
+	// Enter on the pointer in the bitfield view follows it too
+	Drive(m, keys("j")...)
+	Drive(m, key(tea.KeyDown), key(tea.KeyEnter))
+	if tab.Cursor != 8 || m.view != ViewBitfield {
+		t.Fatalf("cursor 0x%X, view %v", tab.Cursor, m.view)
+	}
+	if view := m.renderBitfield(); !strings.Contains(view, "→ 0x7F (past the end)") {
+		t.Errorf("expected the broken pointer flagged:\n%s", view)
+	}
+	Drive(m, key(tea.KeyDown), key(tea.KeyEnter))
+	if tab.Cursor != 8 || !strings.Contains(m.status.text, "past the end of the file") {
+		t.Errorf("cursor 0x%X, status %q", tab.Cursor, m.status.text)
+	}
+
+	// Back through the jumps, which follow edits before them
+	Drive(m, key(tea.KeyEscape))
+	tab.Buffer.Insert(0, []byte("ab"))
+	Drive(m, altKey('o'))
+	if tab.Cursor != 6 || tab.Bitfield.Name != "chunk" {
+		t.Errorf("cursor 0x%X, template %s", tab.Cursor, tab.Bitfield.Name)
+	}
+	Drive(m, altKey('o'), altKey('o'))
+	if tab.Cursor != 2 || tab.Bitfield.Name != "head" || m.status.text != "No jumps to go back from" {
+		t.Errorf("cursor 0x%X, template %s, status %q", tab.Cursor, tab.Bitfield.Name, m.status.text)
+	}
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Closing a tab remembers where it was in its file, and opening the file
+// again later in the session goes back there: the cursor, the scroll
+// position, the value grouping, the text column's encoding and the byte
+// order. A file that shrank meanwhile keeps as much of the position as
+// still exists.
+
+type savedView struct {
+	cursor     int64
+	scrollY    int
+	groupWidth int
+	utf8Text   bool
+	bigEndian  bool
+}
+
+// viewKey names the file tab shows, or returns "" for buffers whose
+// position isn't kept: new files and windows of a file.
+func viewKey(tab *Tab) string {
+	buf := tab.Buffer
+	if buf.IsNew() || buf.Filename() == "" {
+		return ""
+	}
+	if _, ok := buf.Window(); ok {
+		return ""
+	}
+	path, err := filepath.Abs(buf.Filename())
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+func (m *Model) rememberView(tab *Tab) {
+	key := viewKey(tab)
+	if key == "" {
+		return
+	}
+	if m.savedViews == nil {
+		m.savedViews = make(map[string]savedView)
+	}
+	m.savedViews[key] = savedView{
+		cursor:     tab.Cursor,
+		scrollY:    tab.ScrollY,
+		groupWidth: tab.GroupWidth,
+		utf8Text:   tab.UTF8Text,
+		bigEndian:  m.bigEndian,
+	}
+}
+
+// restoreView returns the current tab to where its file was left when it
+// was last closed, if it was.
+func (m *Model) restoreView() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	key := viewKey(tab)
+	v, ok := m.savedViews[key]
+	if key == "" || !ok {
+		return
+	}
+	tab.GroupWidth, tab.UTF8Text = v.groupWidth, v.utf8Text
+	m.bigEndian = v.bigEndian
+	tab.Cursor = min(v.cursor, m.maxCursor(tab))
+	tab.ScrollY = v.scrollY
+	m.ensureCursorVisible()
+
+	msg := fmt.Sprintf("Restored position 0x%X", tab.Cursor)
+	if tab.Cursor < v.cursor {
+		msg += fmt.Sprintf(" (0x%X is past the end of the file now)", v.cursor)
+	}
+	m.setStatus(sevInfo, msg)
+}
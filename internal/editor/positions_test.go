@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReopeningAFileRestoresItsPosition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, make([]byte, 0x4000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.tabs = nil
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	tab := m.currentTab()
+	m.setCursor(0x3F00)
+	tab.GroupWidth, tab.UTF8Text = 4, true
+	m.bigEndian = false
+	m.closeCurrentTab()
+
+	m.bigEndian = true
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	tab = m.currentTab()
+	if tab.Cursor != 0x3F00 || tab.GroupWidth != 4 || !tab.UTF8Text || m.bigEndian {
+		t.Errorf("expected the view restored, got cursor 0x%X, width %d, UTF-8 %v, big-endian %v", tab.Cursor, tab.GroupWidth, tab.UTF8Text, m.bigEndian)
+	}
+	if m.status.text != "Restored position 0x3F00" {
+		t.Errorf("got %q", m.status.text)
+	}
+	if row := int(tab.Cursor / bytesPerRow); row < tab.ScrollY || row >= tab.ScrollY+m.visibleRows() {
+		t.Errorf("cursor row %d not visible from %d", row, tab.ScrollY)
+	}
+
+	// Truncated elsewhere while closed
+	m.closeCurrentTab()
+	if err := os.WriteFile(path, make([]byte, 0x100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if tab = m.currentTab(); tab.Cursor != 0xFF || tab.ScrollY != 0 {
+		t.Errorf("expected the cursor clamped to 0xFF, got 0x%X (scroll %d)", tab.Cursor, tab.ScrollY)
+	}
+	if !strings.Contains(m.status.text, "0x3F00 is past the end") {
+		t.Errorf("got %q", m.status.text)
+	}
+}
+
+func TestNewFilesDontRememberPositions(t *testing.T) {
+	m := newTestModel([]byte("abcdef"))
+	m.setCursor(4)
+	m.closeCurrentTab()
+	if len(m.savedViews) != 0 {
+		t.Errorf("a new buffer has no file to remember, got %v", m.savedViews)
+	}
+}
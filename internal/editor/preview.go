@@ -0,0 +1,145 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Typing in replace mode holds the first nibble of each byte instead of
+// writing it, and the hex column and decoder show it laid over the buffer.
+// While the cursor follows the typing, the decoder stays on the first byte
+// typed, so a multi-byte value reads as it will once the rest is typed,
+// rather than as a mixture starting at the cursor. Esc drops a held
+// nibble.
+
+// typedRun is the bytes typed over in replace mode since the cursor last
+// moved some other way.
+type typedRun struct {
+	tab     *Tab
+	start   int64  // the first byte typed
+	last    int64  // the byte typed most recently, or being typed
+	cursor  int64  // where typing left the cursor
+	version uint64 // of the buffer when typing left it
+	high    byte   // the held byte: the typed nibble over the old low one
+	pending bool   // whether high is waiting for its low nibble
+}
+
+// bytePatch is bytes shown in place of the buffer's without writing them.
+type bytePatch struct {
+	offset int64
+	data   []byte
+}
+
+// apply returns data, read from offset, with the patch laid over it. The
+// caller's slice is left alone.
+func (p *bytePatch) apply(offset int64, data []byte) []byte {
+	if p == nil || p.offset >= offset+int64(len(data)) || p.offset+int64(len(p.data)) <= offset {
+		return data
+	}
+	out := append([]byte(nil), data...)
+	for i, b := range p.data {
+		if j := p.offset + int64(i) - offset; j >= 0 && j < int64(len(out)) {
+			out[j] = b
+		}
+	}
+	return out
+}
+
+// typingRun returns the run of typing the cursor is still following, or
+// nil once the cursor, the buffer or the mode has moved on.
+func (m *Model) typingRun(tab *Tab) *typedRun {
+	r := m.typed
+	if r == nil || r.tab != tab || m.mode != ModeReplace ||
+		tab.Cursor != r.cursor || tab.Buffer.Version() != r.version {
+		return nil
+	}
+	return r
+}
+
+// typingPatch returns the held byte as a patch, or nil when none is held.
+func (m *Model) typingPatch(tab *Tab) *bytePatch {
+	if r := m.typingRun(tab); r != nil && r.pending && m.hexNibble == 1 {
+		return &bytePatch{offset: r.last, data: []byte{r.high}}
+	}
+	return nil
+}
+
+// typeHigh holds nibble as the high half of the byte under the cursor.
+func (m *Model) typeHigh(tab *Tab, nibble byte) {
+	b, _ := tab.Buffer.GetByte(tab.Cursor)
+	r := m.typingRun(tab)
+	if r == nil {
+		r = &typedRun{tab: tab, start: tab.Cursor}
+		m.typed = r
+	}
+	r.last, r.cursor, r.version = tab.Cursor, tab.Cursor, tab.Buffer.Version()
+	r.high, r.pending = nibble<<4|b&0x0F, true
+	m.hexNibble = 1
+}
+
+// typeLow writes the held byte with nibble as its low half, and reports
+// whether it was written.
+func (m *Model) typeLow(tab *Tab, nibble byte) bool {
+	r := m.typingRun(tab)
+	b := r.high&0xF0 | nibble
+	r.pending = false
+	m.hexNibble = 0
+	if m.refused("replace", tab.Buffer.Replace(tab.Cursor, b)) {
+		return false
+	}
+	m.recordPut("typed byte", []byte{b}, false)
+	return true
+}
+
+// dropTyping forgets a half-typed byte, for undo and redo: the byte its
+// first nibble went into may be gone or moved.
+func (m *Model) dropTyping() {
+	m.hexNibble = 0
+	m.typed = nil
+}
+
+// followTyping keeps the run going after the cursor moved past a typed
+// byte.
+func (m *Model) followTyping(tab *Tab) {
+	if r := m.typed; r != nil && r.tab == tab {
+		r.cursor, r.version = tab.Cursor, tab.Buffer.Version()
+	}
+}
+
+// decoderAnchor returns the offset the decoder reads from, and whether it
+// is previewing a held byte.
+func (m *Model) decoderAnchor(tab *Tab) (int64, bool) {
+	r := m.typingRun(tab)
+	if r == nil {
+		return tab.Cursor, false
+	}
+	preview := m.typingPatch(tab) != nil
+	if m.bigEndian {
+		return r.start, preview
+	}
+	return r.last, preview
+}
+
+// previewStyles returns s with the decoder's values dimmed and italic.
+func previewStyles(s *config.Styles) *config.Styles {
+	p := *s
+	for _, st := range []*lipgloss.Style{&p.MarkerNormal, &p.Bit16, &p.Bit32, &p.Bit64, &p.Bit128, &p.DecoderValue} {
+		*st = st.Italic(true).Faint(true)
+	}
+	return &p
+}
+
+func (m *Model) typingReadout(tab *Tab) string {
+	r := m.typingRun(tab)
+	if r == nil || r.start == tab.Cursor && !r.pending {
+		return ""
+	}
+	text := fmt.Sprintf("from 0x%X", r.start+m.displayBase(tab))
+	if r.pending {
+		text += ", preview (Esc discards)"
+	}
+	return text
+}
@@ -0,0 +1,70 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTypingPreviewsInDecoder(t *testing.T) {
+	m := newTestModel([]byte{0x11, 0x22, 0x33, 0x44, 0x55})
+	tab := m.currentTab()
+
+	typeKeys(m, "r")
+	typeKeys(m, "aabbc")
+	if b, _ := tab.Buffer.GetByte(2); b != 0x33 {
+		t.Fatalf("the held nibble was written: %02X", b)
+	}
+	// The decoder reads from the first typed byte, with the held one laid over
+	want := []byte{0xAA, 0xBB, 0xC3, 0x44}
+	if got := m.getDecoderBytes(4); string(got) != string(want) {
+		t.Errorf("got % X, want % X", got, want)
+	}
+	if !strings.Contains(m.renderDecoder(), "Typing: from 0x0, preview") {
+		t.Errorf("no preview readout:\n%s", m.renderDecoder())
+	}
+	if !strings.Contains(m.renderEditor(), "AA BB C3 44") {
+		t.Errorf("the hex column should show the held nibble:\n%s", m.renderEditor())
+	}
+
+	typeKeys(m, "c")
+	if b, _ := tab.Buffer.GetByte(2); b != 0xCC || strings.Contains(m.renderDecoder(), "preview") {
+		t.Errorf("expected CC written and the preview over, got %02X", b)
+	}
+
+	// Moving the cursor ends the run
+	press(m, tea.KeyLeft)
+	if got := m.getDecoderBytes(1); got[0] != 0xCC {
+		t.Errorf("the decoder should follow the cursor again, got % X", got)
+	}
+}
+
+func TestReplaceTypingAtEOF(t *testing.T) {
+	m := newTestModel(nil)
+	tab := m.currentTab()
+
+	typeKeys(m, "r")
+	typeKeys(m, "4142")
+	if got := tab.Buffer.Data(); string(got) != "AB" {
+		t.Errorf("expected 41 42 added, got % X", got)
+	}
+}
+
+func TestBytePatchApply(t *testing.T) {
+	p := &bytePatch{offset: 3, data: []byte{9, 9}}
+	data := []byte{0, 1, 2, 3}
+	if got := p.apply(2, data); string(got) != "\x00\x09\x09\x03" {
+		t.Errorf("got %v", got)
+	}
+	if data[1] != 1 {
+		t.Error("apply changed the caller's slice")
+	}
+	if got := p.apply(10, data); &got[0] != &data[0] {
+		t.Error("a patch elsewhere should leave the data as it is")
+	}
+	var none *bytePatch
+	if got := none.apply(0, data); &got[0] != &data[0] {
+		t.Error("a nil patch should leave the data as it is")
+	}
+}
@@ -0,0 +1,269 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The properties view (Ctrl+G) shows what is known about the active tab's
+// file. The two hashes that need a full pass over the data, the current
+// buffer's and the file on disk's, are computed in the background over a
+// snapshot while a spinner runs; the file's details on disk are read once,
+// when the view opens. It also counts the edits made to the
+// buffer this session, and R reverts them all.
+
+type properties struct {
+	tab      *Tab
+	seq      int
+	ctx      context.Context
+	cancel   context.CancelFunc
+	info     fs.FileInfo // of the file on disk, when it has one
+	infoErr  error
+	owner    string
+	bufHash  string
+	bufErr   error
+	diskHash string
+	diskErr  error
+	pending  int
+	frame    int
+}
+
+type propsHashMsg struct {
+	seq     int
+	disk    bool
+	version uint64 // of the buffer hashed
+	hash    string
+	err     error
+}
+
+type propsTickMsg struct {
+	seq int
+}
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+func (m *Model) openProperties() tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	m.closeProperties()
+	m.propsSeq++
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &properties{tab: tab, seq: m.propsSeq, ctx: ctx, cancel: cancel, pending: 1}
+	m.props = p
+	m.view = ViewProperties
+	if path := tab.Buffer.Filename(); path != "" && !tab.Buffer.IsNew() && !tab.Buffer.IsDevice() {
+		p.info, p.infoErr = m.fs.Stat(path)
+		if p.infoErr == nil {
+			p.owner = fileOwner(p.info)
+		}
+	}
+
+	snapshot := tab.Buffer.Snapshot()
+	cmds := []tea.Cmd{p.hashBuffer(ctx, snapshot), m.propsTick()}
+	if path := tab.Buffer.Filename(); path != "" && !tab.Buffer.IsNew() && !tab.Buffer.IsDevice() {
+		p.pending++
+		cmds = append(cmds, func() tea.Msg {
+			hash, err := snapshot.DiskHashContext(ctx)
+			return propsHashMsg{seq: p.seq, disk: true, hash: hash, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// hashBuffer hashes snapshot, a Snapshot of the properties' tab.
+func (p *properties) hashBuffer(ctx context.Context, snapshot *buffer.Buffer) tea.Cmd {
+	seq := p.seq
+	return func() tea.Msg {
+		hash, err := snapshot.HashContext(ctx)
+		return propsHashMsg{seq: seq, version: snapshot.Version(), hash: hash, err: err}
+	}
+}
+
+func (m *Model) closeProperties() {
+	if m.props != nil {
+		m.props.cancel()
+		m.props = nil
+	}
+}
+
+func (m *Model) propsTick() tea.Cmd {
+	seq := m.propsSeq
+	return m.clock.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return propsTickMsg{seq: seq}
+	})
+}
+
+func (m *Model) handlePropsTick(msg propsTickMsg) (tea.Model, tea.Cmd) {
+	p := m.props
+	if p == nil || msg.seq != p.seq || p.pending == 0 {
+		return m, nil
+	}
+	p.frame++
+	return m, m.propsTick()
+}
+
+func (m *Model) handlePropsHash(msg propsHashMsg) (tea.Model, tea.Cmd) {
+	p := m.props
+	if p == nil || msg.seq != p.seq {
+		return m, nil
+	}
+	if !msg.disk && msg.err == nil && p.tab.Buffer.Version() != msg.version {
+		// The buffer changed while hashing
+		return m, p.hashBuffer(p.ctx, p.tab.Buffer.Snapshot())
+	}
+	p.pending--
+	if msg.disk {
+		p.diskHash, p.diskErr = msg.hash, msg.err
+	} else {
+		p.bufHash, p.bufErr = msg.hash, msg.err
+	}
+	return m, nil
+}
+
+func (m *Model) handlePropertiesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g", "q":
+		m.closeProperties()
+		m.view = ViewMain
+	case "r", "R":
+		m.askRevert()
+	}
+	return m, nil
+}
+
+// askRevert offers to discard every change to the properties' tab.
+func (m *Model) askRevert() {
+	tab := m.props.tab
+	if !tab.Buffer.IsModified() || m.editBlocked(tab) {
+		return
+	}
+	name := "the new file"
+	if tab.Buffer.Filename() != "" {
+		name = filepath.Base(tab.Buffer.Filename())
+	}
+	m.openDialog(fmt.Sprintf("Discard all changes to %s? This can't be undone.", name),
+		m.cancelButton(ViewProperties),
+		dialogButton{label: "Revert", action: func() (tea.Model, tea.Cmd) {
+			m.closeProperties()
+			m.view = ViewMain
+			tab.Buffer.Revert()
+			m.setCursor(tab.Cursor)
+			m.setStatus(sevInfo, "Reverted "+name)
+			return m, nil
+		}})
+}
+
+// writeEditStats adds the edit counts of buf to the properties.
+func (m *Model) writeEditStats(row func(label, value string), buf *buffer.Buffer) {
+	s := buf.Stats()
+	if s.First.IsZero() {
+		row("Edits:", "none this session")
+		return
+	}
+	row("Edits:", fmt.Sprintf("%d bytes replaced, %d inserted, %d deleted", s.Replaced, s.Inserted, s.Deleted))
+	row("Size change:", fmt.Sprintf("%+d bytes since opened", buf.Size()-s.OpenedSize))
+	row("Undo/redo:", fmt.Sprintf("%d undone, %d redone", s.Undos, s.Redos))
+	row("Edited:", fmt.Sprintf("first at %s, last at %s", s.First.Format("15:04:05"), s.Last.Format("15:04:05")))
+}
+
+func (m *Model) renderProperties() string {
+	p := m.props
+	if p == nil {
+		return ""
+	}
+	buf := p.tab.Buffer
+	spinner := spinnerFrames[p.frame%len(spinnerFrames)] + " computing"
+
+	var b strings.Builder
+	b.WriteString("\nFILE PROPERTIES\n")
+	b.WriteString("===============\n\n")
+	row := func(label, value string) {
+		b.WriteString(m.styles.DecoderLabel.Render(fmt.Sprintf("%-18s", label)))
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	path := buf.Filename()
+	if path == "" {
+		row("Path:", "(new file, never saved)")
+	} else {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		row("Path:", path)
+	}
+	if w, ok := buf.Window(); ok {
+		row("Window:", fmt.Sprintf("0x%X–0x%X of the file", w.Offset, w.Offset+w.Length-1))
+	}
+	row("Size in buffer:", formatSize(buf.Size()))
+
+	if buf.IsDevice() {
+		access := "read-only (Ctrl+O to allow writing)"
+		if !buf.ReadOnly() {
+			access = m.styles.StatusWarning.Render("writable, saved in place")
+		}
+		row("Device:", access)
+		row("Sectors:", fmt.Sprintf("%d of %d bytes", buf.Size()/m.sectorSize(), m.sectorSize()))
+	} else if path != "" && !buf.IsNew() {
+		if p.infoErr != nil {
+			row("On disk:", m.styles.StatusWarning.Render(p.infoErr.Error()))
+		} else if info := p.info; info != nil {
+			row("Size on disk:", formatSize(info.Size()))
+			row("Permissions:", info.Mode().String())
+			row("Owner:", p.owner)
+			row("Modified:", info.ModTime().Format("2006-01-02 15:04:05"))
+		}
+		row("SHA-256 at open:", buf.OriginalHash())
+	}
+
+	switch {
+	case p.bufErr != nil:
+		row("SHA-256 of buffer:", m.styles.StatusWarning.Render(p.bufErr.Error()))
+	case p.bufHash != "":
+		hash := p.bufHash
+		if hash == buf.OriginalHash() {
+			hash += m.styles.Disabled.Render(" (unchanged)")
+		}
+		row("SHA-256 of buffer:", hash)
+	default:
+		row("SHA-256 of buffer:", spinner)
+	}
+
+	if path != "" && !buf.IsNew() && !buf.IsDevice() {
+		switch {
+		case p.diskErr != nil:
+			row("Changed on disk:", m.styles.StatusWarning.Render(p.diskErr.Error()))
+		case p.diskHash == "":
+			row("Changed on disk:", spinner)
+		case p.diskHash != buf.OriginalHash():
+			row("Changed on disk:", m.styles.StatusWarning.Render("yes, since it was opened or saved"))
+		default:
+			row("Changed on disk:", "no")
+		}
+	}
+
+	m.writeEditStats(row, buf)
+
+	if buf.IsModified() {
+		b.WriteString("\nPress R to revert all changes, ESC to close\n")
+	} else {
+		b.WriteString("\nPress ESC to close\n")
+	}
+	return b.String()
+}
+
+func formatSize(n int64) string {
+	return fmt.Sprintf("%d bytes (0x%X)", n, n)
+}
@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runPropsHashes delivers the background hash results of the properties
+// view, skipping the spinner ticks.
+func runPropsHashes(m *Model, cmd tea.Cmd) {
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		return
+	}
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(propsHashMsg); ok {
+			m.Update(msg)
+		}
+	}
+}
+
+func TestPropertiesShowsHashesAndDiskChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := buffer.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.tabs = []*Tab{m.newTab(buf)}
+
+	cmd := m.openProperties()
+	if m.view != ViewProperties || !strings.Contains(m.renderProperties(), "computing") {
+		t.Fatal("expected the view open with hashes pending")
+	}
+	runPropsHashes(m, cmd)
+	out := m.renderProperties()
+	for _, want := range []string{path, "5 bytes (0x5)", "-rw-r--r--", buf.OriginalHash(), "(unchanged)", "Changed on disk:  no"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("properties missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("HELLO"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Insert(0, []byte{0})
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	runPropsHashes(m, m.openProperties())
+	out = m.renderProperties()
+	if strings.Contains(out, "(unchanged)") {
+		t.Error("an edited buffer should not report an unchanged hash")
+	}
+	if !strings.Contains(out, "Changed on disk:  yes") {
+		t.Errorf("expected the disk change to be reported:\n%s", out)
+	}
+}
+
+func TestPropertiesCountEditsAndRevert(t *testing.T) {
+	m := newTestModel(nil)
+	buf := buffer.FromData("data.bin", []byte("abcdef"))
+	m.tabs = []*Tab{m.newTab(buf)}
+	tab := m.currentTab()
+
+	m.openProperties()
+	if !strings.Contains(m.renderProperties(), "none this session") {
+		t.Error("expected no edits yet")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+
+	buf.ReplaceBytes(0, []byte("XY"))
+	buf.Insert(6, []byte("gh"))
+	buf.Delete(2, 1)
+	m.openProperties()
+	out := m.renderProperties()
+	for _, want := range []string{"2 bytes replaced, 2 inserted, 1 deleted", "+1 bytes since opened", "0 undone, 0 redone", "R to revert"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("properties missing %q:\n%s", want, out)
+		}
+	}
+
+	typeKeys(m, "r")
+	if m.view != ViewDialog {
+		t.Fatal("expected a confirmation")
+	}
+	m.activateButton(1)
+	if m.view != ViewMain || string(buf.Data()) != "abcdef" || buf.IsModified() || buf.CanUndo() {
+		t.Fatalf("expected the file as opened, got %q", buf.Data())
+	}
+	if tab.Cursor > buf.Size() {
+		t.Errorf("cursor past the end: %d", tab.Cursor)
+	}
+	m.openProperties()
+	if !strings.Contains(m.renderProperties(), "none this session") {
+		t.Error("revert should reset the counts")
+	}
+}
+
+func TestPropertiesStatOnceAndShowHashErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := buffer.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	m.tabs = []*Tab{m.newTab(buf)}
+
+	m.openProperties()
+	// Redrawing doesn't read the file's details again
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if out := m.renderProperties(); !strings.Contains(out, "5 bytes (0x5)") || !strings.Contains(out, "-rw-r--r--") {
+		t.Errorf("expected the details read at open:\n%s", out)
+	}
+
+	m.Update(propsHashMsg{seq: m.props.seq, err: errors.New("read failed")})
+	if out := m.renderProperties(); !strings.Contains(out, "SHA-256 of buffer:read failed") {
+		t.Errorf("expected the hash error instead of the spinner:\n%s", out)
+	}
+}
@@ -0,0 +1,124 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/internal/sidecar"
+)
+
+// Alt+K locks the selected bytes, e.g. a header already checked, so that
+// edits that would change them are refused, naming the range, until Alt+K
+// inside the range unlocks it. Undo and redo still go through (see
+// buffer.Buffer.Lock). Locked bytes are tinted and follow the data through
+// edits like marks do. The ranges are kept in the file's sidecar: written
+// straight away while the file has no unsaved edits, and otherwise with
+// the next save, so their offsets always match the file on disk. Windows
+// of a file keep theirs for the session only.
+
+func (m *Model) toggleRangeLock() {
+	tab := m.currentTab()
+	if tab == nil || tab.Buffer.Size() == 0 {
+		return
+	}
+	if tab.Selection.Active && tab.Selection.Block {
+		m.setStatus(sevWarning, "Locks are ranges of bytes; select them without a column selection")
+		return
+	}
+	start, end := tab.Cursor, tab.Cursor
+	if tab.Selection.Active {
+		start, end = m.getSelectedRange()
+	}
+	end = min(end, tab.Buffer.Size()-1)
+	base := m.displayBase(tab)
+
+	var done string
+	if r, ok := tab.Buffer.LockedAt(start, end); ok {
+		if !tab.Selection.Active {
+			start, end = r.Start, r.End
+		}
+		tab.Buffer.Unlock(start, end)
+		done = fmt.Sprintf("Unlocked 0x%X-0x%X", start+base, end+base)
+	} else {
+		if !tab.Selection.Active {
+			m.setStatus(sevWarning, "Select the bytes to lock first")
+			return
+		}
+		tab.Buffer.Lock(start, end)
+		done = fmt.Sprintf("Locked 0x%X-0x%X against edits (Alt+K in it unlocks)", start+base, end+base)
+	}
+	if err := m.storeRangeLocks(tab); err != nil {
+		m.setStatus(sevError, fmt.Sprintf("%s, but writing %s failed: %v", done, sidecar.Path(tab.Buffer.Filename()), err))
+		return
+	}
+	if sidecarLocks(tab) && tab.Buffer.IsModified() {
+		done += "; kept in the sidecar once saved"
+	}
+	m.setStatus(sevInfo, done)
+}
+
+// sidecarLocks reports whether tab's locked ranges are kept in a sidecar.
+func sidecarLocks(tab *Tab) bool {
+	_, window := tab.Buffer.Window()
+	return tab.Buffer.Filename() != "" && !tab.Buffer.IsNew() && !window
+}
+
+// loadRangeLocks locks the ranges the sidecar of tab's file lists.
+func (m *Model) loadRangeLocks(tab *Tab) {
+	if !sidecarLocks(tab) {
+		return
+	}
+	path := tab.Buffer.Filename()
+	sc, err := sidecar.Load(path)
+	if err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Reading %s: %v", sidecar.Path(path), err))
+		return
+	}
+	for _, l := range sc.Locks {
+		tab.Buffer.Lock(l.Start, l.End)
+	}
+}
+
+// storeRangeLocks writes tab's locked ranges to its file's sidecar, unless
+// there are unsaved edits the offsets might not match the file with.
+func (m *Model) storeRangeLocks(tab *Tab) error {
+	if !sidecarLocks(tab) || tab.Buffer.IsModified() {
+		return nil
+	}
+	path := tab.Buffer.Filename()
+	sc, err := sidecar.Load(path)
+	if err != nil {
+		return err
+	}
+	ranges := tab.Buffer.LockedRanges()
+	if len(ranges) == 0 && len(sc.Locks) == 0 {
+		return nil
+	}
+	sc.Locks = nil
+	for _, r := range ranges {
+		sc.Locks = append(sc.Locks, sidecar.Lock{Start: r.Start, End: r.End})
+	}
+	return sc.Save(path)
+}
+
+// savedRangeLocks stores tab's locked ranges once it has been saved,
+// reporting a failure.
+func (m *Model) savedRangeLocks(tab *Tab) {
+	if err := m.storeRangeLocks(tab); err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Saved %s, but writing %s failed: %v", tab.Buffer.Filename(), sidecar.Path(tab.Buffer.Filename()), err))
+	}
+}
+
+// lockedMask returns which of the row's bytes are locked, bit i for column
+// i.
+func lockedMask(tab *Tab, rowOffset int64) uint16 {
+	var mask uint16
+	for _, r := range tab.Buffer.LockedRanges() {
+		if r.End < rowOffset || r.Start >= rowOffset+bytesPerRow {
+			continue
+		}
+		for col := max(r.Start-rowOffset, 0); col <= min(r.End-rowOffset, bytesPerRow-1); col++ {
+			mask |= 1 << col
+		}
+	}
+	return mask
+}
@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/sidecar"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRangeLockRefusesAndPersists(t *testing.T) {
+	path := writeTestFile(t, strings.Repeat("\x00", 64))
+	m, _ := startEditor(t, path)
+	tab := m.currentTab()
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight)
+	Drive(m, altKey('k'))
+	if !strings.HasPrefix(m.status.text, "Locked 0x0-0x3") {
+		t.Fatalf("got %q", m.status.text)
+	}
+	sc, err := sidecar.Load(path)
+	if err != nil || len(sc.Locks) != 1 || sc.Locks[0] != (sidecar.Lock{Start: 0, End: 3}) {
+		t.Fatalf("expected the lock in the sidecar, got %+v, %v", sc, err)
+	}
+
+	Drive(m, key(tea.KeyEscape), key(tea.KeyLeft))
+	Drive(m, keys("rff")...)
+	if m.status.sev != sevWarning || m.status.text != "Cannot replace: 0x0-0x3 is locked (Alt+K unlocks)" {
+		t.Errorf("got %q", m.status.text)
+	}
+	Drive(m, key(tea.KeyEscape))
+	m.fillSelection(0xFF)
+	if b, _ := tab.Buffer.GetByte(2); b != 0 {
+		t.Error("a locked byte was changed")
+	}
+
+	// Inserting before the range moves it; saving stores where it went
+	Drive(m, key(tea.KeyHome))
+	Drive(m, keys("iab")...)
+	Drive(m, key(tea.KeyEscape))
+	Drive(m, keys("s")...)
+	if sc, _ := sidecar.Load(path); len(sc.Locks) != 1 || sc.Locks[0] != (sidecar.Lock{Start: 1, End: 4}) {
+		t.Errorf("expected the saved lock moved by the insert, got %+v", sc.Locks)
+	}
+
+	// Reopened, the file is locked again
+	m2, _ := startEditor(t, path)
+	if got := m2.currentTab().Buffer.LockedRanges(); len(got) != 1 || got[0].Start != 1 || got[0].End != 4 {
+		t.Errorf("got %v", got)
+	}
+	m2.currentTab().Cursor = 2
+	Drive(m2, altKey('k'))
+	if len(m2.currentTab().Buffer.LockedRanges()) != 0 {
+		t.Error("Alt+K in the range should unlock it")
+	}
+	data, _ := os.ReadFile(path + ".unhexed.toml")
+	if strings.Contains(string(data), "[[lock]]") {
+		t.Errorf("the unlock wasn't stored:\n%s", data)
+	}
+}
+
+func TestEditJobRollsBackAtALockedRange(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, strings.Repeat("\x00", 16)))
+	tab := m.currentTab()
+	tab.Buffer.Lock(12, 13)
+	for range 15 {
+		press(m, tea.KeyShiftRight)
+	}
+	m.fillSelection(0xFF)
+	if tab.Buffer.IsModified() || tab.Buffer.CanUndo() {
+		t.Errorf("the fill should be undone whole, got % X", tab.Buffer.Data())
+	}
+	if m.status.text != "Cannot fill: 0xC-0xD is locked (Alt+K unlocks)" {
+		t.Errorf("got %q", m.status.text)
+	}
+}
@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// With byte_readout on, the idle status line starts with the byte under
+// the cursor in every base at once, e.g. 0x4A 74 'J' 0b01001010 oct 112:
+// the decoder panel at a glance. Hex digits are uppercase, as in the hex
+// column. Alt+B turns it on or off for the session.
+
+// byteReadout describes the byte under the cursor, or returns "" past the
+// end of the file.
+func (m *Model) byteReadout(tab *Tab) string {
+	data := tab.Buffer.GetBytes(tab.Cursor, 1)
+	if len(data) == 0 {
+		return ""
+	}
+	b := data[0]
+	s := fmt.Sprintf("0x%02X %d", b, b)
+	if int8(b) < 0 {
+		s += " (" + strconv.Itoa(int(int8(b))) + ")"
+	}
+	if b >= 0x20 && b < 0x7F {
+		s += " '" + string(rune(b)) + "'"
+	}
+	return s + fmt.Sprintf(" 0b%08b oct %03o", b, b)
+}
+
+func (m *Model) toggleByteReadout() {
+	m.config.Behavior.ByteReadout = !m.config.Behavior.ByteReadout
+	if m.config.Behavior.ByteReadout {
+		m.setStatus(sevInfo, "Byte readout on the status line")
+	} else {
+		m.setStatus(sevInfo, "Byte readout off")
+	}
+}
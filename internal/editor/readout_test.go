@@ -0,0 +1,34 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestByteReadout(t *testing.T) {
+	m := newTestModel([]byte{0x4A, 0xCA, 0x0A})
+	tab := m.currentTab()
+	for _, tc := range []struct {
+		at   int64
+		want string
+	}{
+		{0, "0x4A 74 'J' 0b01001010 oct 112"},
+		{1, "0xCA 202 (-54) 0b11001010 oct 312"},
+		{2, "0x0A 10 0b00001010 oct 012"},
+	} {
+		tab.Cursor = tc.at
+		if got := m.byteReadout(tab); got != tc.want {
+			t.Errorf("at %d: got %q, want %q", tc.at, got, tc.want)
+		}
+	}
+
+	tab.Cursor = 0
+	if strings.Contains(m.renderStatus(), "0x4A") {
+		t.Error("the readout is off by default")
+	}
+	m.Update(altKey('b'))
+	m.status.text = ""
+	if got := m.renderStatus(); !strings.HasPrefix(got, "0x4A 74 'J'") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+L fills a rectangle of bitmap-like data without selecting it first:
+// width by height bytes from the cursor, where each row is pitch bytes
+// after the one above. The pitch defaults to the bytes per row on screen;
+// rows are counted from the start of the file, as for column selections.
+// A rectangle reaching past the end of a row or of the file is clipped
+// with a warning, and the fill is one undo step.
+
+type rectFillForm struct {
+	fields [rectFillFields]string
+	focus  int
+}
+
+const (
+	rectFillWidth = iota
+	rectFillHeight
+	rectFillPitch
+	rectFillByte
+	rectFillFields
+)
+
+var rectFillLabels = [rectFillFields]string{"Width:", "Height:", "Pitch:", "Fill byte:"}
+
+// openRectFill shows the form, keeping the values last used.
+func (m *Model) openRectFill() {
+	if m.currentTab() == nil {
+		return
+	}
+	if m.rectFill == nil {
+		m.rectFill = &rectFillForm{fields: [rectFillFields]string{"", "", fmt.Sprint(bytesPerRow), "00"}}
+	}
+	m.rectFill.focus = rectFillWidth
+	m.view = ViewRectFill
+}
+
+// parse returns the rectangle the form describes.
+func (f *rectFillForm) parse() (width, height, pitch int64, value byte, err error) {
+	sizes := []*int64{&width, &height, &pitch}
+	for i, n := range sizes {
+		label := strings.ToLower(strings.TrimSuffix(rectFillLabels[i], ":"))
+		v, err := parseOffset(f.fields[i])
+		if err != nil || v < 1 {
+			return 0, 0, 0, 0, fmt.Errorf("the %s must be 1 or more", label)
+		}
+		*n = v
+	}
+	value, err = parseHexByte(f.fields[rectFillByte])
+	return width, height, pitch, value, err
+}
+
+func (m *Model) handleRectFillKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.rectFill
+	if f == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	field := &f.fields[f.focus]
+	switch msg.String() {
+	case "esc":
+		m.view = ViewMain
+	case "up", "shift+tab":
+		f.focus = (f.focus + rectFillFields - 1) % rectFillFields
+	case "down", "tab":
+		f.focus = (f.focus + 1) % rectFillFields
+	case "backspace":
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case "ctrl+u":
+		*field = ""
+	case "enter":
+		width, height, pitch, value, err := f.parse()
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return m, nil
+		}
+		m.view = ViewMain
+		fill := func() tea.Cmd { return m.fillRect(width, height, pitch, value) }
+		m.recordEdit("rectangle fill", true, fill)
+		return m, fill()
+	default:
+		if char := msg.String(); isHexChar(char) || char == "x" || char == "X" {
+			*field += char
+		}
+	}
+	return m, nil
+}
+
+// fillRect fills width by height bytes from the cursor, rows pitch bytes
+// apart, clipped to the cursor's row and to the end of the file.
+func (m *Model) fillRect(width, height, pitch int64, value byte) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil || m.editBlocked(tab) {
+		return nil
+	}
+	origin, size := tab.Cursor, tab.Buffer.Size()
+	if origin >= size {
+		m.setStatus(sevWarning, "No byte under the cursor to start the rectangle at")
+		return nil
+	}
+
+	var clipped []string
+	if room := pitch - origin%pitch; width > room {
+		clipped = append(clipped, fmt.Sprintf("%d columns past the end of the row", width-room))
+		width = room
+	}
+	if width > size-origin {
+		clipped = append(clipped, fmt.Sprintf("%d columns past the end of the file", width-(size-origin)))
+		width = size - origin
+	}
+	if rows := (size-1-origin)/pitch + 1; height > rows {
+		clipped = append(clipped, fmt.Sprintf("%d rows past the end of the file", height-rows))
+		height = rows
+	}
+	if last := origin + (height-1)*pitch + width; last > size {
+		clipped = append(clipped, fmt.Sprintf("%d bytes of the last row past the end of the file", last-size))
+	}
+
+	job := m.rectFillJob(tab, "Rectangle fill", origin, width, height, pitch, value)
+	job.finish = func() {
+		text := fmt.Sprintf("Filled %dx%d bytes with 0x%02X", width, height, value)
+		if len(clipped) > 0 {
+			m.setStatus(sevWarning, text+"; clipped "+strings.Join(clipped, ", "))
+		} else {
+			m.setStatus(sevInfo, text)
+		}
+	}
+	return m.runEdit(job)
+}
+
+// rectFillJob fills width bytes at origin and at every pitch bytes after
+// it, height times, stopping at the end of the buffer. Each step fills up
+// to jobChunk bytes, however wide the rows.
+func (m *Model) rectFillJob(tab *Tab, label string, origin, width, height, pitch int64, value byte) *editJob {
+	fill := bytes.Repeat([]byte{value}, int(min(width, jobChunk)))
+	job := &editJob{
+		tab:    tab,
+		label:  label,
+		offset: origin,
+		total:  height * width,
+	}
+	job.step = func(done int64) int64 {
+		var n int64
+		for n < jobChunk && done+n < job.total {
+			row, col := (done+n)/width, (done+n)%width
+			k := min(width-col, jobChunk-n)
+			pos := origin + row*pitch + col
+			if remaining := tab.Buffer.Size() - pos; remaining > 0 && job.refused(tab.Buffer.ReplaceBytes(pos, fill[:min(k, remaining)])) {
+				return 0
+			}
+			n += k
+		}
+		return n
+	}
+	return job
+}
+
+func (m *Model) renderRectFill() string {
+	f := m.rectFill
+	if f == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nFILL RECTANGLE\n")
+	b.WriteString("==============\n\n")
+	for i, label := range rectFillLabels {
+		prefix, value := "  ", f.fields[i]
+		if f.focus == i {
+			prefix, value = "> ", value+"_"
+		}
+		b.WriteString(fmt.Sprintf("%s%-11s %s\n", prefix, label, value))
+	}
+	b.WriteString("\nFills from the cursor; each row starts pitch bytes after the one above\n")
+	b.WriteString("(sizes in decimal or 0x hex, the fill byte in hex)\n")
+	b.WriteString("\n↑/↓ to choose a field, Enter to fill, ESC to cancel\n")
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFillRectangle(t *testing.T) {
+	m := newTestModel(make([]byte, 40))
+	tab := m.currentTab()
+	m.setCursor(1)
+
+	m.Update(altKey('l'))
+	typeKeys(m, "2")
+	press(m, tea.KeyDown)
+	typeKeys(m, "3")
+	press(m, tea.KeyDown, tea.KeyCtrlU)
+	typeKeys(m, "8")
+	press(m, tea.KeyDown, tea.KeyCtrlU)
+	typeKeys(m, "ff")
+	press(m, tea.KeyEnter)
+
+	want := make([]byte, 40)
+	for _, off := range []int{1, 2, 9, 10, 17, 18} {
+		want[off] = 0xFF
+	}
+	if got := tab.Buffer.GetBytes(0, 40); !bytes.Equal(got, want) {
+		t.Fatalf("got % X", got)
+	}
+	if m.status.text != "Filled 2x3 bytes with 0xFF" {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	typeKeys(m, "u")
+	if got := tab.Buffer.GetBytes(0, 40); !bytes.Equal(got, make([]byte, 40)) {
+		t.Errorf("one undo should revert the whole rectangle, got % X", got)
+	}
+}
+
+func TestFillRectangleClips(t *testing.T) {
+	m := newTestModel(make([]byte, 20))
+	tab := m.currentTab()
+	m.setCursor(6)
+	m.fillRect(4, 5, 8, 0xAA)
+
+	// Columns 6-7 of rows at 6 and 14; the row at 22 is past the end
+	want := make([]byte, 20)
+	for _, off := range []int{6, 7, 14, 15} {
+		want[off] = 0xAA
+	}
+	if got := tab.Buffer.GetBytes(0, 20); !bytes.Equal(got, want) {
+		t.Fatalf("got % X", got)
+	}
+	if m.status.sev != sevWarning || !strings.Contains(m.status.text, "2 columns past the end of the row") ||
+		!strings.Contains(m.status.text, "3 rows past the end of the file") {
+		t.Errorf("got %q", m.status.text)
+	}
+}
+
+func TestRectFillFormErrors(t *testing.T) {
+	m := newTestModel(make([]byte, 4))
+	m.Update(altKey('l'))
+	press(m, tea.KeyEnter)
+	if m.view != ViewRectFill || m.status.text != "the width must be 1 or more" {
+		t.Errorf("view %v, status %q", m.view, m.status.text)
+	}
+}
+
+func TestFillRectangleClipsHugeWidths(t *testing.T) {
+	m := newTestModel(make([]byte, 20))
+	tab := m.currentTab()
+	m.setCursor(6)
+	m.fillRect(0x20000000000, 2, 0x20000000000, 0xAA)
+
+	want := append(make([]byte, 6), bytes.Repeat([]byte{0xAA}, 14)...)
+	if got := tab.Buffer.GetBytes(0, 20); !bytes.Equal(got, want) {
+		t.Errorf("got % X", got)
+	}
+	if !strings.Contains(m.status.text, "columns past the end of the file") {
+		t.Errorf("got %q", m.status.text)
+	}
+}
@@ -0,0 +1,115 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultRegister = '"'
+
+type register struct {
+	data  []byte
+	width int // row width of a column-selection copy, 0 for linear
+}
+
+func registerName(reg rune) string {
+	if reg == 0 {
+		reg = defaultRegister
+	}
+	return `"` + string(reg)
+}
+
+// handleRegisterKey implements the '"' prefix that selects the register used
+// by the next cut, copy or paste. It reports whether the key was consumed.
+func (m *Model) handleRegisterKey(key string) bool {
+	if m.registerPrefix {
+		m.registerPrefix = false
+		if key == "esc" {
+			return true
+		}
+		if len(key) == 1 {
+			c := rune(strings.ToLower(key)[0])
+			if (c >= 'a' && c <= 'z') || c == defaultRegister {
+				m.pendingRegister = c
+				m.showStatus(sevInfo, "Register "+registerName(c))
+				return true
+			}
+		}
+		m.setStatus(sevWarning, "Registers are named a-z")
+		return true
+	}
+
+	if key == `"` {
+		m.registerPrefix = true
+		m.showStatus(sevInfo, `"`)
+		return true
+	}
+	return false
+}
+
+// takeRegister returns the pending register (the default if none) and
+// clears it.
+func (m *Model) takeRegister() rune {
+	reg := m.pendingRegister
+	m.pendingRegister = 0
+	if reg == 0 {
+		reg = defaultRegister
+	}
+	return reg
+}
+
+// storeRegister saves a copy into reg. Copies into a named register also
+// update the default register, as in vim.
+func (m *Model) storeRegister(reg rune, r *register) {
+	m.registers[reg] = r
+	m.registers[defaultRegister] = r
+}
+
+func (m *Model) handleRegistersKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEscape || msg.String() == "ctrl+r" {
+		m.view = ViewMain
+	}
+	return m, nil
+}
+
+func (m *Model) renderRegisters() string {
+	var b strings.Builder
+	b.WriteString("\nREGISTERS\n")
+	b.WriteString("=========\n\n")
+
+	names := make([]rune, 0, len(m.registers))
+	for name := range m.registers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	if len(names) == 0 {
+		b.WriteString("  (all registers are empty)\n")
+	}
+	for _, name := range names {
+		r := m.registers[name]
+		preview := r.data
+		if len(preview) > 16 {
+			preview = preview[:16]
+		}
+		hex := make([]string, len(preview))
+		for i, d := range preview {
+			hex[i] = fmt.Sprintf("%02X", d)
+		}
+		line := strings.Join(hex, " ")
+		if len(r.data) > len(preview) {
+			line += " ..."
+		}
+		shape := ""
+		if r.width > 0 {
+			shape = fmt.Sprintf(" (%d-wide column)", r.width)
+		}
+		b.WriteString(fmt.Sprintf("  %s  %8d bytes%s  %s\n", registerName(name), len(r.data), shape, line))
+	}
+
+	b.WriteString("\nPress ESC to close\n")
+	return b.String()
+}
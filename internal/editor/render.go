@@ -0,0 +1,338 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rowKey captures everything a rendered row depends on, so rows that the
+// cursor or selection did not touch can be reused between frames.
+type rowKey struct {
+	version   uint64
+	cursor    int64 // -1 when the cursor doesn't style this row
+	selStart  int64 // -1 when no selection touches this row
+	selEnd    int64
+	selBlock  bool
+	mode      EditMode
+	textFocus bool
+	nibble    int // the cursor's nibble in the nibble view, -1 otherwise
+	dirty     bool
+	bigEndian bool
+	utf8      bool
+	group     int
+	base      int64
+	same      int // highlighted byte value, -1 when off
+	crosshair bool
+	subst     string
+	pictures  bool
+	locked    uint16 // the row's locked bytes, bit i for column i
+	flash     int    // flash sequence while undo or redo highlights this row
+	patchAt   int64  // where a held typed byte is laid over this row, -1 if none
+	patch     string // the held bytes
+	styles    *config.Styles
+}
+
+type cachedRow struct {
+	key  rowKey
+	line string
+}
+
+func (m *Model) renderEditor() string {
+	tab := m.currentTab()
+	if tab == nil {
+		return ""
+	}
+	if m.split != nil {
+		return m.renderSplit()
+	}
+	return m.renderPane(tab)
+}
+
+// renderPane renders tab's rows, as many as fit.
+func (m *Model) renderPane(tab *Tab) string {
+	var lines []string
+	visRows := m.visibleRows()
+
+	if tab.rowCache == nil || len(tab.rowCache) > 4*visRows {
+		tab.rowCache = make(map[int64]cachedRow, visRows)
+	}
+	same := m.sameByteValue(tab)
+
+	for row := int64(tab.ScrollY); len(lines) < visRows; row++ {
+		rowOffset := row * bytesPerRow
+		// Past EOF only the row holding an append cursor is drawn
+		if rowOffset >= tab.Buffer.Size() && rowOffset > 0 && rowOffset > tab.Cursor {
+			break
+		}
+		if f, ok := m.foldAt(tab, row); ok {
+			lines = append(lines, m.renderFold(tab, f))
+			row = f.last
+			continue
+		}
+
+		key := m.rowCacheKey(tab, rowOffset, same)
+		if cached, ok := tab.rowCache[rowOffset]; ok && cached.key == key {
+			lines = append(lines, cached.line)
+			continue
+		}
+
+		line := m.renderRow(tab, rowOffset, same)
+		tab.rowCache[rowOffset] = cachedRow{key: key, line: line}
+		lines = append(lines, line)
+	}
+
+	// The indicator is added after caching, since it changes on every scroll
+	if marks := m.scrollbarMarks(tab, visRows); marks != nil {
+		for i := range lines {
+			lines[i] += " " + marks[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sameByteValue returns the value under the cursor when same-byte
+// highlighting is on, or -1.
+func (m *Model) sameByteValue(tab *Tab) int {
+	if !m.highlightSame {
+		return -1
+	}
+	data := tab.Buffer.GetBytes(tab.Cursor, 1)
+	if len(data) == 0 {
+		return -1
+	}
+	return int(data[0])
+}
+
+func (m *Model) rowCacheKey(tab *Tab, rowOffset int64, same int) rowKey {
+	key := rowKey{
+		version:   tab.Buffer.Version(),
+		cursor:    -1,
+		selStart:  -1,
+		selEnd:    -1,
+		mode:      m.mode,
+		textFocus: tab.TextFocus,
+		nibble:    -1,
+		patchAt:   -1,
+		dirty:     m.rowDirty(tab, rowOffset),
+		bigEndian: m.bigEndian,
+		utf8:      tab.UTF8Text,
+		group:     groupWidth(tab),
+		base:      m.displayBase(tab),
+		same:      same,
+		crosshair: m.config.Behavior.Crosshair,
+		subst:     m.config.Behavior.TextSubstitute,
+		pictures:  m.config.Behavior.ControlPictures,
+		locked:    lockedMask(tab, rowOffset),
+		styles:    m.styles,
+	}
+
+	rowEnd := rowOffset + bytesPerRow - 1
+	if f := m.flash; f != nil && f.tab == tab && f.start <= rowEnd && f.end >= rowOffset {
+		key.flash = m.flashSeq
+	}
+	// The cursor styles its own row's offset column and up to 15 bytes of
+	// bit-width highlighting on either side.
+	if tab.Cursor/bytesPerRow == rowOffset/bytesPerRow ||
+		(tab.Cursor-15 <= rowEnd && tab.Cursor+15 >= rowOffset) {
+		key.cursor = tab.Cursor
+	}
+
+	if tab.Nibbles {
+		key.nibble = tab.nibble
+	}
+	if p := m.typingPatch(tab); p != nil && p.offset <= rowEnd && p.offset+int64(len(p.data)) > rowOffset {
+		key.patchAt, key.patch = p.offset, string(p.data)
+	}
+
+	if tab.Selection.Active {
+		touches := false
+		if tab.Selection.Block {
+			rowStart, rowLast, _, _ := m.blockRect(tab)
+			touches = rowOffset/bytesPerRow >= rowStart && rowOffset/bytesPerRow <= rowLast
+		} else {
+			start, end := tab.selectedRange()
+			touches = start <= rowEnd && end >= rowOffset
+		}
+		if touches {
+			key.selStart = tab.Selection.Start
+			key.selEnd = tab.Selection.End
+			key.selBlock = tab.Selection.Block
+		}
+	}
+	return key
+}
+
+// cellStyle returns the style for the byte b at offset, or nil for unstyled
+// cells which are written without a Render call. Selection wins over the
+// cursor, which wins over the flash of an undo or redo and the same-byte
+// and bit-width highlights; the crosshair row sits under all of them.
+func (m *Model) cellStyle(tab *Tab, offset int64, b byte, ok bool, same int) *lipgloss.Style {
+	if m.inSelection(tab, offset) {
+		return &m.styles.Selection
+	}
+	if offset == tab.Cursor {
+		switch m.mode {
+		case ModeInsert:
+			return &m.styles.MarkerInsert
+		case ModeReplace:
+			return &m.styles.MarkerReplace
+		default:
+			return &m.styles.MarkerNormal
+		}
+	}
+	if m.flashed(tab, offset) {
+		return &m.styles.Flash
+	}
+	if ok && int(b) == same {
+		return &m.styles.SameByte
+	}
+	if ok {
+		// Bit-width color coding for decoder panel correspondence
+		if style := m.getBitWidthStyle(offset, tab.Cursor); style != nil {
+			return style
+		}
+		if _, locked := tab.Buffer.LockedAt(offset, offset); locked {
+			return &m.styles.Locked
+		}
+	}
+	return m.crosshairStyle(tab, offset)
+}
+
+// crosshairStyle returns the crosshair style for cells on the cursor's row.
+func (m *Model) crosshairStyle(tab *Tab, offset int64) *lipgloss.Style {
+	if m.config.Behavior.Crosshair && offset/bytesPerRow == tab.Cursor/bytesPerRow {
+		return &m.styles.Crosshair
+	}
+	return nil
+}
+
+// styleRun accumulates consecutive cells sharing a style so each run is
+// rendered with a single Render call.
+type styleRun struct {
+	out   strings.Builder
+	run   strings.Builder
+	style *lipgloss.Style
+}
+
+func (r *styleRun) write(style *lipgloss.Style, s string) {
+	if style != r.style {
+		r.flush()
+		r.style = style
+	}
+	r.run.WriteString(s)
+}
+
+func (r *styleRun) flush() {
+	if r.run.Len() == 0 {
+		return
+	}
+	if r.style == nil {
+		r.out.WriteString(r.run.String())
+	} else {
+		r.out.WriteString(r.style.Render(r.run.String()))
+	}
+	r.run.Reset()
+}
+
+func (r *styleRun) String() string {
+	r.flush()
+	return r.out.String()
+}
+
+func (m *Model) renderRow(tab *Tab, rowOffset int64, same int) string {
+	// Offset column
+	offsetStr := fmt.Sprintf("%08X  ", rowOffset+m.displayBase(tab))
+	gutter := ""
+	if m.rowDirty(tab, rowOffset) {
+		// Unsaved changes on this row (see dirty.go)
+		offsetStr, gutter = offsetStr[:9], m.styles.UnsavedFile.Render("▌")
+	}
+	if rowOffset/bytesPerRow == tab.Cursor/bytesPerRow {
+		offsetStr = m.styles.IndexMarker.Render(offsetStr)
+	}
+	offsetStr += gutter
+
+	data := m.typingPatch(tab).apply(rowOffset, tab.Buffer.GetBytes(rowOffset, bytesPerRow))
+
+	// Hex and ASCII - build strings directly to match header alignment
+	var hexLine, asciiLine styleRun
+	var prev *lipgloss.Style
+	w := groupWidth(tab)
+	var cells []hexCell
+	if w > 1 {
+		cells = make([]hexCell, bytesPerRow)
+	}
+
+	for col := 0; col < bytesPerRow; col++ {
+		offset := rowOffset + int64(col)
+		ok := col < len(data)
+
+		hexStr := "  "
+		asciiStr := " "
+		asciiDim, asciiControl := false, false
+
+		var b byte
+		if ok {
+			b = data[col]
+			hexStr = fmt.Sprintf("%02X", b)
+			if tab.UTF8Text {
+				var kind textCellKind
+				asciiStr, kind = utf8Cell(tab.Buffer, offset)
+				asciiDim = kind == textCellContinuation
+				if kind == textCellInvalid {
+					asciiStr, asciiControl = m.substituteGlyph(b), true
+				}
+			} else if b >= 32 && b < 127 {
+				asciiStr = string(b)
+			} else {
+				asciiStr, asciiControl = m.substituteGlyph(b), true
+			}
+		}
+
+		style, textStyle := m.paneStyles(tab, offset, m.cellStyle(tab, offset, b, ok, same))
+
+		if cells != nil {
+			// Laid out per value once the row is complete
+			cells[col] = hexCell{text: hexStr, style: style}
+		} else {
+			// Spacing - must match renderColumnHeader exactly. The gap
+			// joins the run when both neighbours share a style.
+			if gap := hexGap(col); gap != "" {
+				if style == prev {
+					hexLine.write(style, gap)
+				} else {
+					hexLine.write(m.crosshairStyle(tab, offset), gap)
+				}
+			}
+			if tab.Nibbles && offset == tab.Cursor && ok && style != &m.styles.Selection {
+				m.writeNibbleCell(&hexLine, tab, offset, hexStr, style)
+			} else {
+				hexLine.write(style, hexStr)
+			}
+			prev = style
+		}
+
+		if asciiDim && textStyle == nil {
+			asciiLine.write(&m.styles.Disabled, asciiStr)
+		} else if asciiControl && textStyle == nil {
+			asciiLine.write(&m.styles.Control, asciiStr)
+		} else {
+			asciiLine.write(textStyle, asciiStr)
+		}
+	}
+
+	if cells != nil {
+		m.writeGroupedHex(&hexLine, tab, rowOffset, cells, w)
+	}
+
+	sep := "  "
+	if style := m.crosshairStyle(tab, rowOffset); style != nil {
+		sep = style.Render(sep)
+	}
+	return offsetStr + hexLine.String() + sep + asciiLine.String()
+}
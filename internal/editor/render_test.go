@@ -0,0 +1,99 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func newBenchModel(size int) *Model {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	return newTestModel(data)
+}
+
+func BenchmarkRenderEditor(b *testing.B) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	m := newBenchModel(1 << 20)
+	tab := m.currentTab()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Move the cursor around so every frame differs from the last
+		tab.Cursor = int64(i%int(m.visibleRows())) * bytesPerRow
+		m.renderEditor()
+	}
+}
+
+func BenchmarkRenderEditorUncached(b *testing.B) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	m := newBenchModel(1 << 20)
+	tab := m.currentTab()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tab.Cursor = int64(i%int(m.visibleRows())) * bytesPerRow
+		tab.rowCache = nil
+		m.renderEditor()
+	}
+}
+
+func TestSameByteHighlight(t *testing.T) {
+	m := newTestModel([]byte{0xAA, 0x00, 0xAA, 0x01, 0xAA})
+	tab := m.currentTab()
+
+	if same := m.sameByteValue(tab); same != -1 {
+		t.Fatalf("highlighting should start off, got %d", same)
+	}
+	typeKeys(m, "x")
+	same := m.sameByteValue(tab)
+	if same != 0xAA {
+		t.Fatalf("expected the cursor byte 0xAA, got %d", same)
+	}
+
+	if style := m.cellStyle(tab, 2, 0xAA, true, same); style != &m.styles.SameByte {
+		t.Error("equal bytes should get the same-byte style")
+	}
+	if style := m.cellStyle(tab, 0, 0xAA, true, same); style != &m.styles.MarkerNormal {
+		t.Error("the cursor should win over the same-byte highlight")
+	}
+	tab.Selection.Active, tab.Selection.Start, tab.Selection.End = true, 3, 4
+	if style := m.cellStyle(tab, 4, 0xAA, true, same); style != &m.styles.Selection {
+		t.Error("the selection should win over the same-byte highlight")
+	}
+
+	// Moving onto a different value must invalidate cached rows
+	before := m.rowCacheKey(tab, 0, same)
+	tab.Cursor = 1
+	if after := m.rowCacheKey(tab, 0, m.sameByteValue(tab)); after == before {
+		t.Error("row cache key should change with the highlighted value")
+	}
+}
+
+func TestCrosshairLayersUnderOtherStyles(t *testing.T) {
+	m := newTestModel(make([]byte, 3*bytesPerRow))
+	tab := m.currentTab()
+	tab.Cursor = bytesPerRow + 4
+	plain := m.renderRow(tab, bytesPerRow, -1)
+
+	m.config.Behavior.Crosshair = true
+	if style := m.cellStyle(tab, bytesPerRow, 0, true, -1); style != &m.styles.Crosshair {
+		t.Error("cells on the cursor row should get the crosshair style")
+	}
+	if style := m.cellStyle(tab, 2*bytesPerRow+12, 0, true, -1); style != nil {
+		t.Error("other rows should stay unstyled")
+	}
+	if style := m.cellStyle(tab, tab.Cursor, 0, true, -1); style != &m.styles.MarkerNormal {
+		t.Error("the cursor should win over the crosshair")
+	}
+	if style := m.cellStyle(tab, tab.Cursor+1, 0, true, -1); style != &m.styles.Bit16 {
+		t.Error("bit-width highlighting should win over the crosshair")
+	}
+
+	if got := m.renderRow(tab, bytesPerRow, -1); lipgloss.Width(got) != lipgloss.Width(plain) {
+		t.Errorf("crosshair changed the row width: %d vs %d", lipgloss.Width(got), lipgloss.Width(plain))
+	}
+}
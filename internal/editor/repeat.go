@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// "." repeats the last edit at the cursor or selection, like vi. An edit
+// is recorded with its parameters as it runs (the fill byte, the value
+// written, the register pasted), so repeating never prompts again. The
+// commands that record one are marked in the registry.
+
+type lastEdit struct {
+	label  string
+	atByte bool // needs a byte under the cursor when nothing is selected
+	run    func() tea.Cmd
+}
+
+func (m *Model) recordEdit(label string, atByte bool, run func() tea.Cmd) {
+	m.lastEdit = &lastEdit{label: label, atByte: atByte, run: run}
+}
+
+func (m *Model) repeatEdit() tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	edit := m.lastEdit
+	if edit == nil {
+		m.setStatus(sevWarning, "Nothing to repeat")
+		return nil
+	}
+	if m.hexNibble == 1 {
+		m.setStatus(sevWarning, "Finish typing the byte before repeating")
+		return nil
+	}
+	if edit.atByte && !tab.Selection.Active && tab.Cursor >= tab.Buffer.Size() {
+		m.setStatus(sevWarning, "Can't repeat "+edit.label+": no byte under the cursor")
+		return nil
+	}
+	return edit.run()
+}
+
+// putBytes writes data at the cursor, inserting or overwriting, and moves
+// past it. It reports false when the write was refused.
+func (m *Model) putBytes(tab *Tab, data []byte, insert bool) bool {
+	if m.editBlocked(tab) {
+		return false
+	}
+	if insert || tab.Cursor+int64(len(data)) > tab.Buffer.Size() {
+		if m.resizeBlocked(tab) {
+			return false
+		}
+	}
+	var err error
+	if insert {
+		err = tab.Buffer.Insert(tab.Cursor, data)
+	} else {
+		err = tab.Buffer.ReplaceBytes(tab.Cursor, data)
+	}
+	if m.refused("write", err) {
+		return false
+	}
+	m.setCursor(tab.Cursor + int64(len(data)))
+	return true
+}
+
+// recordPut records writing data at the cursor, as a typed byte or an
+// entered value does.
+func (m *Model) recordPut(label string, data []byte, insert bool) {
+	data = append([]byte(nil), data...)
+	m.recordEdit(label, false, func() tea.Cmd {
+		if tab := m.currentTab(); tab != nil && m.putBytes(tab, data, insert) {
+			m.setStatus(sevInfo, fmt.Sprintf("Wrote % X", data))
+		}
+		return nil
+	})
+}
+
+// repeatHelp lists the edits "." repeats, from the registry.
+func repeatHelp() string {
+	var names []string
+	for _, section := range commandSections {
+		for _, c := range section.commands {
+			if c.repeat != "" {
+				names = append(names, c.repeat)
+			}
+		}
+	}
+	if len(names) > 1 {
+		names[len(names)-2] += " or " + names[len(names)-1]
+		names = names[:len(names)-1]
+	}
+	return "Repeat the last " + strings.Join(names, ", ")
+}
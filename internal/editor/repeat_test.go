@@ -0,0 +1,82 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRepeatFillAtNewCursor(t *testing.T) {
+	m := newTestModel(make([]byte, 4))
+	tab := m.currentTab()
+
+	typeKeys(m, "lab")
+	press(m, tea.KeyEnter)
+	press(m, tea.KeyRight, tea.KeyRight)
+	typeKeys(m, ".")
+
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, []byte{0xAB, 0x00, 0xAB, 0x00}) {
+		t.Errorf("got % X", got)
+	}
+	if m.view != ViewMain {
+		t.Error("repeat should not prompt again")
+	}
+}
+
+func TestRepeatTypedByteAndValue(t *testing.T) {
+	m := newTestModel(make([]byte, 4))
+	tab := m.currentTab()
+
+	typeKeys(m, "r7f")
+	press(m, tea.KeyEscape)
+	typeKeys(m, ".")
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, []byte{0x7F, 0x7F, 0x00, 0x00}) {
+		t.Errorf("typed byte: got % X", got)
+	}
+
+	typeKeys(m, "=u16:258")
+	press(m, tea.KeyEnter)
+	tab.Cursor = 0
+	typeKeys(m, ".")
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, []byte{0x01, 0x02, 0x01, 0x02}) {
+		t.Errorf("value: got % X", got)
+	}
+}
+
+func TestRepeatDelete(t *testing.T) {
+	m := newTestModel([]byte{0, 1, 2, 3, 4, 5})
+	tab := m.currentTab()
+
+	typeKeys(m, "2")
+	press(m, tea.KeyDelete)
+	typeKeys(m, ".")
+	if got := tab.Buffer.GetBytes(0, int(tab.Buffer.Size())); !bytes.Equal(got, []byte{4, 5}) {
+		t.Errorf("got % X", got)
+	}
+}
+
+func TestRepeatThatNoLongerApplies(t *testing.T) {
+	m := newTestModel(nil)
+	typeKeys(m, ".")
+	if m.status.sev != sevWarning || m.status.text != "Nothing to repeat" {
+		t.Errorf("status %q", m.status.text)
+	}
+
+	m = newTestModel([]byte{0x12})
+	typeKeys(m, "~")
+	m.currentTab().Buffer.Delete(0, 1)
+	typeKeys(m, ".")
+	if m.status.sev != sevWarning || !strings.Contains(m.status.text, "no byte under the cursor") {
+		t.Errorf("status %q", m.status.text)
+	}
+}
+
+func TestRepeatHelpListsRepeatableCommands(t *testing.T) {
+	help := newTestModel(nil).renderHelp()
+	want := "Repeat the last typed byte, typed text, paste, pasted value, fill, value, nibble swap, padding or delete"
+	if !strings.Contains(help, want) {
+		t.Errorf("help should contain %q:\n%s", want, help)
+	}
+}
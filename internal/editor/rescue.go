@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// A panic while handling a message would lose every unsaved edit. Update
+// catches it, writes the modified buffers to the recovery directory and
+// panics again with their paths, which Bubble Tea prints once it has
+// restored the terminal.
+
+// RecoveryDir is where modified buffers are written after a crash.
+func RecoveryDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "unhexed", "recovery")
+}
+
+// rescue writes the modified buffers to RecoveryDir and returns the panic
+// value r with where they went.
+func (m *Model) rescue(r any) string {
+	var saved, failed []string
+	seen := make(map[*buffer.Buffer]bool)
+	stamp := m.clock.Now().Format("20060102-150405")
+	for i, tab := range m.tabs {
+		buf := tab.Buffer
+		if seen[buf] || !buf.IsModified() {
+			continue
+		}
+		seen[buf] = true
+		name := filepath.Base(buf.Filename())
+		if buf.Filename() == "" {
+			name = fmt.Sprintf("new-%d", i+1)
+		}
+		path, err := rescueBuffer(buf, fmt.Sprintf("%s.%s", name, stamp))
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+			continue
+		}
+		saved = append(saved, path)
+	}
+
+	msg := fmt.Sprint(r)
+	if len(saved) > 0 {
+		msg += "\n\nUnsaved changes were written to:\n  " + strings.Join(saved, "\n  ")
+	}
+	if len(failed) > 0 {
+		msg += "\n\nCould not save:\n  " + strings.Join(failed, "\n  ")
+	}
+	return msg
+}
+
+func rescueBuffer(buf *buffer.Buffer, name string) (string, error) {
+	dir := RecoveryDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, io.NewSectionReader(buf, 0, buf.Size()))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,46 @@
+package editor
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRescueWritesModifiedBuffers(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	m := newTestModel([]byte("new data"))
+	clean := m.newTab(buffer.FromData("clean.bin", []byte("x")))
+	m.tabs = append(m.tabs, clean, m.newTab(m.tabs[0].Buffer))
+
+	msg := m.rescue("boom")
+	lines := strings.Split(msg, "\n")
+	if lines[0] != "boom" || len(lines) != 4 {
+		t.Fatalf("expected the panic and one saved file, got %q", msg)
+	}
+	path := strings.TrimSpace(lines[3])
+	if !strings.HasPrefix(path, RecoveryDir()) {
+		t.Errorf("expected a file in %s, got %s", RecoveryDir(), path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "new data" {
+		t.Errorf("got %q %v", data, err)
+	}
+}
+
+func TestUpdateRescuesOnPanic(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	m := newTestModel([]byte{1})
+	m.currentTab().Buffer.Replace(0, 2)
+
+	m.openDialog("Crash?", dialogButton{label: "Boom", action: func() (tea.Model, tea.Cmd) { panic("boom") }})
+	defer func() {
+		r := recover()
+		if s, _ := r.(string); !strings.Contains(s, "Unsaved changes were written to") {
+			t.Errorf("expected the panic to name the recovery file, got %v", r)
+		}
+	}()
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
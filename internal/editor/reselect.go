@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// Clearing the selection remembers it, and Alt+R selects it again, as
+// vim's gv does: the range follows edits made since, like marks, and a
+// range deleted entirely is reported rather than reselected. Reselecting
+// while something is selected swaps the two.
+
+type lastSelection struct {
+	set     bool
+	deleted bool
+	block   bool
+	start   int64 // the selection's anchor
+	end     int64 // where the cursor was
+}
+
+// rememberSelection keeps tab's selection for reselect before it is
+// cleared.
+func rememberSelection(tab *Tab) {
+	if tab.Selection.Active {
+		tab.lastSel = lastSelection{set: true, block: tab.Selection.Block, start: tab.Selection.Start, end: tab.Selection.End}
+	}
+}
+
+// trackLastSelection maps the remembered selection through an edit.
+func trackLastSelection(tab *Tab, c buffer.Change) {
+	s := &tab.lastSel
+	if !s.set || s.deleted {
+		return
+	}
+	start, end, ok := c.ShiftRange(s.start, s.end)
+	s.start, s.end, s.deleted = start, end, !ok
+}
+
+func (m *Model) reselect() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	prev := tab.lastSel
+	switch {
+	case !prev.set:
+		m.setStatus(sevWarning, "Nothing was selected before")
+		return
+	case prev.deleted:
+		m.setStatus(sevWarning, "The last selection was deleted")
+		return
+	}
+
+	rememberSelection(tab)
+	tab.Selection.Active, tab.Selection.Block = true, prev.block
+	tab.Selection.Start, tab.Selection.End = prev.start, prev.end
+	tab.Cursor = prev.end
+	m.ensureCursorVisible()
+
+	start, end := m.getSelectedRange()
+	base := m.displayBase(tab)
+	m.setStatus(sevInfo, fmt.Sprintf("Reselected 0x%X-0x%X (%d bytes)", start+base, end+base, end-start+1))
+}
@@ -0,0 +1,41 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReselectFollowsEdits(t *testing.T) {
+	m := newTestModel([]byte("0123456789abcdef"))
+	tab := m.currentTab()
+
+	m.Update(altKey('r'))
+	if !strings.Contains(m.status.text, "Nothing was selected") {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	m.setCursor(4)
+	for range 3 {
+		m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m.clearSelection()
+	tab.Buffer.Insert(0, []byte("xx"))
+
+	m.Update(altKey('r'))
+	if start, end := m.getSelectedRange(); start != 6 || end != 9 || tab.Cursor != 9 {
+		t.Fatalf("expected 6-9 reselected, got %d-%d, cursor %d", start, end, tab.Cursor)
+	}
+	if m.status.text != "Reselected 0x6-0x9 (4 bytes)" {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	m.clearSelection()
+	tab.Buffer.Delete(4, 8)
+	m.Update(altKey('r'))
+	if tab.Selection.Active || m.status.text != "The last selection was deleted" {
+		t.Errorf("got %q, selection %v", m.status.text, tab.Selection.Active)
+	}
+}
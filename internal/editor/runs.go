@@ -0,0 +1,157 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The runs dialog finds runs of one repeated byte, such as padding or slack
+// space. Input is a minimum length with an optional hex byte: "64 00" is a
+// run of 64 or more 0x00 bytes, "256" any byte repeated 256 times. Scans
+// run in the background over a snapshot, like the find match count.
+
+type runFoundMsg struct {
+	seq     int
+	tab     *Tab
+	version uint64 // of the tab's buffer when scanned
+	start   int64
+	length  int64
+}
+
+// parseRunInput returns the minimum run length and byte value, -1 for any.
+func parseRunInput(input string) (int64, int, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, 0, fmt.Errorf("enter a length, optionally followed by a hex byte")
+	}
+	minLen, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || minLen < 1 {
+		return 0, 0, fmt.Errorf("invalid run length %q", fields[0])
+	}
+	value := -1
+	if len(fields) == 2 {
+		v, err := parseHexByte(fields[1])
+		if err != nil {
+			return 0, 0, err
+		}
+		value = int(v)
+	}
+	return minLen, value, nil
+}
+
+func describeRun(minLen int64, value int) string {
+	if value < 0 {
+		return fmt.Sprintf("run of ≥%d identical bytes", minLen)
+	}
+	return fmt.Sprintf("run of ≥%d 0x%02X bytes", minLen, value)
+}
+
+func (m *Model) handleRunsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.cancelRunScan()
+		m.view = ViewMain
+	case tea.KeyEnter, tea.KeyDown:
+		return m, m.startRunScan(true)
+	case tea.KeyUp:
+		return m, m.startRunScan(false)
+	case tea.KeyCtrlU:
+		m.runInput = ""
+	case tea.KeyBackspace:
+		if len(m.runInput) > 0 {
+			m.runInput = m.runInput[:len(m.runInput)-1]
+		}
+	default:
+		char := msg.String()
+		if isHexChar(char) || char == " " || char == "x" || char == "X" {
+			m.runInput += char
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) cancelRunScan() {
+	m.runSeq++
+	m.runScanning = false
+	if m.runCancel != nil {
+		m.runCancel()
+		m.runCancel = nil
+	}
+}
+
+func (m *Model) startRunScan(forward bool) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	minLen, value, err := parseRunInput(m.runInput)
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return nil
+	}
+
+	m.cancelRunScan()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.runCancel = cancel
+	m.runScanning = true
+	m.runForward = forward
+
+	snapshot := tab.Buffer.Snapshot()
+	seq, from := m.runSeq, tab.Cursor
+	return func() tea.Msg {
+		start, length, err := snapshot.FindRunContext(ctx, from, minLen, value, forward)
+		if err != nil {
+			return nil
+		}
+		return runFoundMsg{seq: seq, tab: tab, version: snapshot.Version(), start: start, length: length}
+	}
+}
+
+func (m *Model) handleRunFound(msg runFoundMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.runSeq || msg.tab != m.currentTab() {
+		return m, nil
+	}
+	if msg.tab.Buffer.Version() != msg.version {
+		// The buffer changed during the scan; the offsets may be wrong
+		return m, m.startRunScan(m.runForward)
+	}
+	m.runScanning = false
+	m.runCancel = nil
+
+	minLen, value, _ := parseRunInput(m.runInput)
+	if msg.start < 0 {
+		where := "after"
+		if !m.runForward {
+			where = "before"
+		}
+		m.setStatus(sevInfo, fmt.Sprintf("No %s %s the cursor", describeRun(minLen, value), where))
+		return m, nil
+	}
+
+	m.view = ViewMain
+	m.setCursor(msg.start)
+	b, _ := msg.tab.Buffer.GetByte(msg.start)
+	m.setStatus(sevInfo, fmt.Sprintf("Run of %d × 0x%02X at 0x%X", msg.length, b, msg.start))
+	return m, nil
+}
+
+func (m *Model) renderRuns() string {
+	var b strings.Builder
+	b.WriteString("\nFIND RUNS\n")
+	b.WriteString("=========\n\n")
+	b.WriteString("Run: ")
+	b.WriteString(m.runInput)
+	b.WriteString("_\n\n")
+	b.WriteString("Minimum length, optionally followed by a hex byte:\n")
+	b.WriteString("\"64 00\" finds 64 or more 0x00 bytes, \"256\" any byte repeated 256 times\n")
+	if m.runScanning {
+		b.WriteString("\nScanning…\n")
+	}
+	b.WriteString("\nPress Enter or ↓ for the next run, ↑ for the previous, ESC to close\n")
+
+	return b.String()
+}
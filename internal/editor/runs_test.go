@@ -0,0 +1,94 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runScan(t *testing.T, m *Model, key tea.KeyType) {
+	t.Helper()
+	_, cmd := m.handleRunsKey(tea.KeyMsg{Type: key})
+	if cmd == nil {
+		t.Fatalf("expected a scan to start, status %q", m.status.text)
+	}
+	m.Update(cmd())
+}
+
+func TestRunsDialogNavigates(t *testing.T) {
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i) // no runs except those below
+	}
+	copy(data[8:], []byte{0, 0, 0, 0, 0})
+	copy(data[32:], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	copy(data[60:], []byte{0, 0, 0, 0})
+	m := newTestModel(data)
+	tab := m.currentTab()
+
+	typeKeys(m, "p4")
+	runScan(t, m, tea.KeyEnter)
+	if tab.Cursor != 8 || m.view != ViewMain {
+		t.Fatalf("expected the first run at 8, got %d", tab.Cursor)
+	}
+
+	typeKeys(m, "p")
+	runScan(t, m, tea.KeyDown)
+	if tab.Cursor != 32 {
+		t.Errorf("expected the next run at 32, got %d", tab.Cursor)
+	}
+	typeKeys(m, "p")
+	runScan(t, m, tea.KeyDown)
+	if tab.Cursor != 60 {
+		t.Errorf("expected the run reaching EOF at 60, got %d", tab.Cursor)
+	}
+	typeKeys(m, "p")
+	runScan(t, m, tea.KeyDown)
+	if m.view != ViewRuns || tab.Cursor != 60 {
+		t.Errorf("no further run should leave the dialog open and the cursor still, got %d", tab.Cursor)
+	}
+
+	runScan(t, m, tea.KeyUp)
+	if tab.Cursor != 32 {
+		t.Errorf("expected the previous run at 32, got %d", tab.Cursor)
+	}
+
+	// Only runs of the given value
+	typeKeys(m, "p")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeKeys(m, "4 0x00")
+	runScan(t, m, tea.KeyEnter)
+	if tab.Cursor != 60 {
+		t.Errorf("expected the 00 run at 60, got %d", tab.Cursor)
+	}
+}
+
+func TestRunsDialogRejectsBadInput(t *testing.T) {
+	m := newTestModel([]byte{0, 0, 0})
+	typeKeys(m, "p4 1FF")
+	if _, cmd := m.handleRunsKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("invalid input should not start a scan")
+	}
+	if m.status.sev != sevWarning {
+		t.Errorf("expected a warning, got %q", m.status.text)
+	}
+}
+
+func TestRunScanRestartsAfterAnEdit(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 0, 0, 0, 0, 3})
+	tab := m.currentTab()
+
+	typeKeys(m, "p4")
+	_, cmd := m.handleRunsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmd()
+	tab.Buffer.Insert(1, []byte{9, 9})
+
+	_, cmd = m.Update(msg)
+	if cmd == nil || tab.Cursor != 0 {
+		t.Fatalf("a run found before the insert was applied: cursor %d", tab.Cursor)
+	}
+	m.Update(cmd())
+	if tab.Cursor != 4 {
+		t.Errorf("expected the run at its new offset 4, got %d", tab.Cursor)
+	}
+}
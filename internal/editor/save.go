@@ -0,0 +1,124 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveProgressSize is the size from which S saves a step at a time,
+// showing progress and letting ESC cancel, instead of blocking until the
+// file is written. Cancelling leaves the file as it was.
+const saveProgressSize = 64 << 20
+
+// fileSave is a save in progress. Input is blocked while it runs, so the
+// buffer doesn't change under it.
+type fileSave struct {
+	tab   *Tab
+	saver *buffer.Saver
+}
+
+type saveStepMsg struct {
+	save *fileSave
+	done bool
+	err  error
+}
+
+func (s *fileSave) step() tea.Msg {
+	done, err := s.saver.Step()
+	return saveStepMsg{save: s, done: done, err: err}
+}
+
+// startSave saves tab's buffer, in steps when it is large.
+func (m *Model) startSave(tab *Tab) tea.Cmd {
+	if tab.Buffer.Size() < saveProgressSize || tab.Buffer.FixedSize() {
+		m.saveTab(tab)
+		return nil
+	}
+	saver, err := tab.Buffer.NewSaver()
+	if err != nil {
+		// Not a regular file: Save writes it in place
+		m.saveTab(tab)
+		return nil
+	}
+	m.saving = &fileSave{tab: tab, saver: saver}
+	return m.saving.step
+}
+
+func (m *Model) handleSaveStep(msg saveStepMsg) (tea.Model, tea.Cmd) {
+	s := msg.save
+	if s != m.saving {
+		// Cancelled
+		return m, nil
+	}
+	if !msg.done && msg.err == nil {
+		return m, s.step
+	}
+	m.saving = nil
+	err := msg.err
+	if err == nil {
+		err = s.saver.Commit()
+	}
+	if err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Error saving %s: %v", s.tab.Buffer.Filename(), err))
+		return m, nil
+	}
+	m.setStatus(sevInfo, "Saved "+s.tab.Buffer.Filename())
+	m.savedRangeLocks(s.tab)
+	return m, nil
+}
+
+func (m *Model) cancelSave() {
+	s := m.saving
+	m.saving = nil
+	s.saver.Cancel()
+	m.setStatus(sevInfo, fmt.Sprintf("Save cancelled; %s is unchanged", filepath.Base(s.tab.Buffer.Filename())))
+}
+
+func (m *Model) renderSaveProgress() string {
+	written, total := m.saving.saver.Progress()
+	return fmt.Sprintf("Saving %s... %d%% (ESC to cancel)", filepath.Base(m.saving.tab.Buffer.Filename()), written*100/max(total, 1))
+}
+
+// saveFailed reports a failed save of tab and offers Save As instead. then
+// continues whatever the save was part of (closing the tab, quitting) once
+// Save As succeeds.
+func (m *Model) saveFailed(tab *Tab, err error, then func() (tea.Model, tea.Cmd)) {
+	name := tab.Buffer.Filename()
+	m.openDialog(fmt.Sprintf("Could not save %s:\n%v", filepath.Base(name), err),
+		m.cancelButton(ViewMain),
+		dialogButton{label: "Save As…", action: func() (tea.Model, tea.Cmd) {
+			m.askSaveAs(name, then)
+			return m, nil
+		}})
+}
+
+func (m *Model) askSaveAs(name string, then func() (tea.Model, tea.Cmd)) {
+	m.view = ViewSaveAs
+	m.saveAsInput = name
+	m.afterSaveAs = then
+}
+
+// saveAllAndQuit saves every modified buffer and quits. It stops at the
+// first buffer that can't be saved, switching to its tab, so nothing is
+// lost; completing a Save As from there resumes the remaining saves.
+func (m *Model) saveAllAndQuit() (tea.Model, tea.Cmd) {
+	for i, tab := range m.tabs {
+		if !tab.Buffer.IsModified() {
+			continue
+		}
+		m.selectTab(i)
+		if tab.Buffer.IsNew() || tab.Buffer.Filename() == "" {
+			m.askSaveAs("", m.saveAllAndQuit)
+			return m, nil
+		}
+		if err := m.saveTab(tab); err != nil {
+			m.saveFailed(tab, err, m.saveAllAndQuit)
+			return m, nil
+		}
+	}
+	return m, tea.Quit
+}
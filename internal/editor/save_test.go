@@ -0,0 +1,139 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// readOnlyTarget returns an existing file that can't be written. Root ignores
+// file permissions, so there it falls back to a path whose parent is a
+// regular file.
+func readOnlyTarget(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "locked.bin")
+	if err := os.WriteFile(path, []byte{0xAA}, 0444); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		f.Close()
+		return filepath.Join(path, "child.bin")
+	}
+	return path
+}
+
+func openTestFile(t *testing.T, m *Model, path string) *Tab {
+	t.Helper()
+	buf := buffer.FromData(path, []byte{0xAA})
+	buf.Insert(0, []byte{0x01})
+	tab := m.newTab(buf)
+	m.tabs = append(m.tabs, tab)
+	m.selectTab(len(m.tabs) - 1)
+	return tab
+}
+
+func press(m *Model, keys ...tea.KeyType) {
+	for _, k := range keys {
+		m.Update(tea.KeyMsg{Type: k})
+	}
+}
+
+func TestCloseWithFailedSaveOffersSaveAs(t *testing.T) {
+	m := newTestModel(nil)
+	m.tabs = nil
+	tab := openTestFile(t, m, readOnlyTarget(t))
+
+	// Close -> Save
+	press(m, tea.KeyCtrlW, tea.KeyShiftTab, tea.KeyEnter)
+	if len(m.tabs) != 1 || !tab.Buffer.IsModified() {
+		t.Fatal("a failed save must keep the tab and its changes")
+	}
+	if m.view != ViewDialog || m.status.sev != sevError {
+		t.Fatalf("expected an error and the fallback dialog, view %v, status %q", m.view, m.status.text)
+	}
+
+	// Save As… to a writable path finishes the close
+	press(m, tea.KeyRight, tea.KeyEnter)
+	if m.view != ViewSaveAs {
+		t.Fatalf("expected Save As, got view %v", m.view)
+	}
+	m.saveAsInput = filepath.Join(t.TempDir(), "copy.bin")
+	press(m, tea.KeyEnter)
+	if len(m.tabs) != 0 {
+		t.Error("expected the tab to close after Save As")
+	}
+	if data, err := os.ReadFile(m.saveAsInput); err != nil || len(data) != 2 {
+		t.Errorf("expected the buffer saved to the new path, got %v, %v", data, err)
+	}
+}
+
+func TestSaveAllAndQuitStopsAtFailedSave(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestModel(nil)
+	m.tabs = nil
+	good := openTestFile(t, m, filepath.Join(dir, "good.bin"))
+	bad := openTestFile(t, m, readOnlyTarget(t))
+	openTestFile(t, m, filepath.Join(dir, "later.bin"))
+
+	_, cmd := m.saveAllAndQuit()
+	if cmd != nil {
+		t.Fatal("quit despite a failed save")
+	}
+	if good.Buffer.IsModified() {
+		t.Error("buffers before the failure should be saved")
+	}
+	if m.currentTab() != bad || !bad.Buffer.IsModified() {
+		t.Error("expected to stop on the failing tab with its changes intact")
+	}
+	if !m.tabs[2].Buffer.IsModified() {
+		t.Error("buffers after the failure should be left alone")
+	}
+	if bad.Buffer.Filename() == "" {
+		t.Error("failed save lost the filename")
+	}
+}
+
+func TestLargeSaveCanBeCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(path, make([]byte, saveProgressSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	tab := m.currentTab()
+	tab.Buffer.Replace(0, 0xFF)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.saving == nil || cmd == nil {
+		t.Fatal("expected a save in steps")
+	}
+	m.Update(cmd())
+	if !strings.Contains(m.renderStatus(), "Saving large.bin... ") {
+		t.Errorf("got %q", m.renderStatus())
+	}
+	press(m, tea.KeyEscape)
+	if m.saving != nil || !tab.Buffer.IsModified() {
+		t.Fatal("expected the save cancelled")
+	}
+	if data, _ := os.ReadFile(path); data[0] != 0 {
+		t.Error("a cancelled save changed the file")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	for cmd != nil {
+		_, cmd = m.Update(cmd())
+	}
+	if m.saving != nil || tab.Buffer.IsModified() || m.status.text != "Saved "+path {
+		t.Fatalf("expected the save to finish, got %q", m.status.text)
+	}
+	if data, _ := os.ReadFile(path); data[0] != 0xFF || len(data) != saveProgressSize {
+		t.Error("the saved file differs")
+	}
+}
@@ -0,0 +1,34 @@
+package editor
+
+// scrollbarMarks returns one mark per visible row for the indicator drawn
+// right of the hex view: the thumb covers the viewport's share of the file
+// and the cursor's row gets its own mark. It returns nil when the whole
+// file fits on screen or the terminal is too narrow for the extra column.
+func (m *Model) scrollbarMarks(tab *Tab, rows int) []string {
+	total := totalRows(max(tab.Buffer.Size(), tab.Cursor+1))
+	if total <= rows || rows <= 0 || m.width < minEditorWidth()+2 {
+		return nil
+	}
+
+	thumbStart := tab.ScrollY * rows / total
+	thumbLen := max(rows*rows/total, 1)
+	if thumbStart+thumbLen > rows {
+		thumbStart = rows - thumbLen
+	}
+	cursorMark := int(tab.Cursor/bytesPerRow) * rows / total
+
+	track := m.styles.Disabled.Render("│")
+	thumb := "┃"
+	marks := make([]string, rows)
+	for i := range marks {
+		switch {
+		case i == cursorMark:
+			marks[i] = m.styles.IndexMarker.Render("◆")
+		case i >= thumbStart && i < thumbStart+thumbLen:
+			marks[i] = thumb
+		default:
+			marks[i] = track
+		}
+	}
+	return marks
+}
@@ -0,0 +1,51 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScrollbarHiddenWhenFileFits(t *testing.T) {
+	m := newTestModel(make([]byte, 4*bytesPerRow))
+	if marks := m.scrollbarMarks(m.currentTab(), m.visibleRows()); marks != nil {
+		t.Errorf("expected no indicator for a file that fits, got %d marks", len(marks))
+	}
+	if strings.Contains(m.renderEditor(), "│") {
+		t.Error("editor should not draw a track for a file that fits")
+	}
+}
+
+func TestScrollbarFollowsViewport(t *testing.T) {
+	m := newTestModel(make([]byte, 1000*bytesPerRow))
+	tab := m.currentTab()
+	rows := m.visibleRows()
+
+	thumbAt := func() int {
+		for i, mark := range m.scrollbarMarks(tab, rows) {
+			if mark == "┃" {
+				return i
+			}
+		}
+		return -1
+	}
+
+	tab.Cursor = 0
+	tab.ScrollY = 0
+	if got := thumbAt(); got != 1 {
+		t.Errorf("expected the thumb right below the cursor mark at the top, got row %d", got)
+	}
+
+	m.setCursor(tab.Buffer.Size() - 1)
+	marks := m.scrollbarMarks(tab, rows)
+	if !strings.Contains(marks[rows-1], "◆") {
+		t.Error("cursor mark should be on the last row at EOF")
+	}
+	if got := thumbAt(); got < rows/2 {
+		t.Errorf("expected the thumb near the bottom at EOF, got row %d", got)
+	}
+
+	lines := strings.Split(m.renderEditor(), "\n")
+	if len(lines) != rows || !strings.Contains(lines[rows-1], "◆") {
+		t.Error("rendered rows should carry the indicator")
+	}
+}
@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// Alignments the find dialog's Ctrl+L steps through: off, then the sizes
+// table entries and sector-aligned signatures sit at.
+var findAlignments = []int64{0, 2, 4, 8, 16, 512}
+
+func (m *Model) cycleFindAlign() {
+	for i, a := range findAlignments {
+		if a == m.findAlign {
+			m.findAlign = findAlignments[(i+1)%len(findAlignments)]
+			return
+		}
+	}
+	m.findAlign = 0
+}
+
+// findAlignment is the find dialog's alignment, counted in the offsets
+// shown: a window's matches are aligned in its file unless base_address
+// says to number the window from 0.
+func (m *Model) findAlignment(tab *Tab) buffer.Alignment {
+	return buffer.Alignment{To: m.findAlign, Base: m.displayBase(tab)}
+}
+
+func (m *Model) findAlignLabel() string {
+	if m.findAlign <= 1 {
+		return "off"
+	}
+	return fmt.Sprintf("multiples of %d (0x%X)", m.findAlign, m.findAlign)
+}
+
+// selectToMatch extends the selection (or starts one at the cursor) up to
+// the byte before the next match of the last search, or through the match
+// when inclusive is set. Without a further match it selects to EOF.
+func (m *Model) selectToMatch(inclusive bool) {
+	tab := m.currentTab()
+	if tab == nil || tab.Buffer.Size() == 0 {
+		return
+	}
+	if len(m.lastFind) == 0 {
+		m.setStatus(sevWarning, "No previous search")
+		return
+	}
+
+	pos := tab.Buffer.FindAligned(m.lastFind, tab.Cursor+1, true, m.findAlignment(tab))
+	if pos < 0 {
+		m.selectTo(tab.Buffer.Size() - 1)
+		m.setStatus(sevInfo, "No further match, selected to end of file")
+		return
+	}
+	end := pos - 1
+	if inclusive {
+		end = pos + int64(len(m.lastFind)) - 1
+	}
+	m.selectTo(end)
+	m.setStatus(sevInfo, fmt.Sprintf("Selected to match at 0x%X", pos))
+}
+
+// selectionReadout describes the current selection for the decoder panel.
+func (m *Model) selectionReadout() string {
+	tab := m.currentTab()
+	if tab == nil || !tab.Selection.Active {
+		return ""
+	}
+	if tab.Selection.Block {
+		rowStart, rowEnd, colStart, colEnd := m.blockRect(tab)
+		return fmt.Sprintf("%dx%d columns", colEnd-colStart+1, rowEnd-rowStart+1)
+	}
+	start, end := m.getSelectedRange()
+	n := end - start + 1
+	readout := fmt.Sprintf("0x%X-0x%X, %d (0x%X) bytes", start, end, n, n)
+	if count := m.selCountReadout(); count != "" {
+		readout += ", " + count
+	}
+	return readout
+}
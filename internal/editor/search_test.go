@@ -0,0 +1,191 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectToMatch(t *testing.T) {
+	m := newTestModel([]byte("REC:aaaa REC:bb REC:c"))
+	tab := m.currentTab()
+
+	m.findInput = "REC:"
+	m.doFind(true)
+	if tab.Cursor != 9 {
+		t.Fatalf("expected the find to land on 9, got %d", tab.Cursor)
+	}
+	m.setCursor(0)
+
+	typeKeys(m, "]")
+	if start, end := m.getSelectedRange(); start != 0 || end != 8 {
+		t.Errorf("expected [0,8] up to the next record, got [%d,%d]", start, end)
+	}
+	if !strings.Contains(m.renderDecoder(), "9 (0x9) bytes") {
+		t.Error("decoder panel should show the selection length")
+	}
+
+	typeKeys(m, "}")
+	if start, end := m.getSelectedRange(); start != 0 || end != 12 {
+		t.Errorf("expected [0,12] through the match, got [%d,%d]", start, end)
+	}
+
+	typeKeys(m, "}")
+	typeKeys(m, "]")
+	if start, end := m.getSelectedRange(); start != 0 || end != 20 {
+		t.Errorf("expected the selection to reach EOF, got [%d,%d]", start, end)
+	}
+	if !strings.Contains(m.status.text, "No further match") {
+		t.Errorf("expected a no-match note, got %q", m.status.text)
+	}
+}
+
+func TestSelectToMatchWithoutSearch(t *testing.T) {
+	m := newTestModel([]byte("abc"))
+	typeKeys(m, "]")
+	if m.currentTab().Selection.Active {
+		t.Error("nothing should be selected without a previous search")
+	}
+	if m.status.sev != sevWarning {
+		t.Errorf("expected a warning, got %q", m.status.text)
+	}
+}
+
+func TestFindKeepsInputBetweenOpens(t *testing.T) {
+	m := newTestModel([]byte("ab-ab-ab-ab-ab"))
+	tab := m.currentTab()
+
+	typeKeys(m, "fab")
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	first := tab.Cursor
+
+	typeKeys(m, "f")
+	if m.findInput != "ab" {
+		t.Fatalf("expected the input to be kept, got %q", m.findInput)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != first+3 {
+		t.Errorf("Enter should find the next match at %d, got %d", first+3, tab.Cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if m.findInput != "" {
+		t.Errorf("Ctrl+U should clear the input, got %q", m.findInput)
+	}
+}
+
+func TestFindInTinyBuffers(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		input  string
+		cursor int64
+		status string
+	}{
+		{"empty buffer", "", "a", 0, "longer than the file"},
+		{"pattern longer than the file", "ab", "abc", 0, "longer than the file"},
+		{"whole buffer", "abc", "abc", 0, "this is the only one"},
+		{"single byte", "a", "a", 0, "this is the only one"},
+		{"no match", "abc", "x", 0, "not found"},
+	}
+	for _, tt := range tests {
+		m := newTestModel([]byte(tt.data))
+		m.findInput = tt.input
+		m.doFind(true)
+		if got := m.currentTab().Cursor; got != tt.cursor {
+			t.Errorf("%s: expected the cursor at %d, got %d", tt.name, tt.cursor, got)
+		}
+		if !strings.Contains(m.status.text, tt.status) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.status, m.status.text)
+		}
+	}
+}
+
+func TestFindNeedsAPattern(t *testing.T) {
+	m := newTestModel([]byte("abc"))
+	typeKeys(m, "f")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.status.text != "Enter a pattern" {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	// A half-typed hex byte isn't a pattern yet
+	m.findMode, m.findInput = "hex", "6"
+	m.doFind(true)
+	if m.status.text != "Enter a pattern" || m.currentTab().Cursor != 0 {
+		t.Errorf("got %q at %d", m.status.text, m.currentTab().Cursor)
+	}
+}
+
+func TestFindBackwardFromTheDialog(t *testing.T) {
+	m := newTestModel([]byte("ab..ab..ab"))
+	tab := m.currentTab()
+	m.setCursor(4)
+	m.view, m.findInput = ViewFind, "ab"
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 8 || !strings.Contains(m.renderFind(), "forward") {
+		t.Fatalf("Enter searches forward at first, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.renderFind(), "backward") {
+		t.Error("the dialog should show the direction")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 4 {
+		t.Errorf("expected the previous match at 4, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 8 || !strings.Contains(m.status.text, "from the end") {
+		t.Errorf("expected to wrap to the last match at 8, got %d (%q)", tab.Cursor, m.status.text)
+	}
+
+	m.config.Behavior.WrapSearch = false
+	m.setCursor(0)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 0 || !strings.Contains(m.status.text, "No more matches before the cursor") {
+		t.Errorf("expected no wrap, got %d (%q)", tab.Cursor, m.status.text)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.setCursor(8)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 8 || !strings.Contains(m.status.text, "No more matches after the cursor") {
+		t.Errorf("expected no wrap forward either, got %d (%q)", tab.Cursor, m.status.text)
+	}
+}
+
+func TestFindAlignedMatchesOnly(t *testing.T) {
+	// "AB" at 1, 4 and 6: only 4 is on a 4-byte boundary
+	m := newTestModel([]byte("xABxABAB"))
+	tab := m.currentTab()
+	m.view = ViewFind
+	m.findInput = "AB"
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if !strings.Contains(m.renderFind(), "multiples of 4") {
+		t.Fatalf("expected a 4-byte alignment, got %q", m.findAlignLabel())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 4 {
+		t.Errorf("expected the aligned match at 4, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 4 || m.status.text != "No more matches: this is the only one" {
+		t.Errorf("the unaligned matches should be skipped, got %d (%q)", tab.Cursor, m.status.text)
+	}
+
+	_, cmd := m.Update(findCountTickMsg{seq: m.findSeq})
+	m.Update(cmd())
+	if m.findMatches != 1 {
+		t.Errorf("expected 1 aligned match counted, got %d", m.findMatches)
+	}
+
+	m.findInput = "BA"
+	m.doFind(true)
+	if m.status.text != "Pattern not found at a multiple of 4" {
+		t.Errorf("got %q", m.status.text)
+	}
+}
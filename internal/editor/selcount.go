@@ -0,0 +1,127 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// While a selection and a search are both up, the selection readout counts
+// the matches of the last search lying wholly within the selection. The
+// count runs like the find dialog's: once the selection has settled, and
+// over a snapshot, so it never holds up the keys extending it.
+
+// selCountKey is what a selection count depends on; a change to any of it
+// starts a new count.
+type selCountKey struct {
+	buf        *buffer.Buffer
+	version    uint64
+	start, end int64
+	pattern    string
+	align      buffer.Alignment
+}
+
+type selCountState struct {
+	key      selCountKey
+	active   bool // there's a selection and a search to count
+	seq      int
+	counting bool
+	matches  int
+	cancel   context.CancelFunc
+}
+
+type selCountTickMsg struct {
+	seq int
+}
+
+type selCountMsg struct {
+	seq   int
+	count int
+}
+
+// selCountTarget returns what the current selection count is of, and
+// whether there is one to make.
+func (m *Model) selCountTarget() (selCountKey, bool) {
+	tab := m.currentTab()
+	if tab == nil || !tab.Selection.Active || tab.Selection.Block || len(m.lastFind) == 0 {
+		return selCountKey{}, false
+	}
+	start, end := m.getSelectedRange()
+	return selCountKey{
+		buf:     tab.Buffer,
+		version: tab.Buffer.Version(),
+		start:   start,
+		end:     end + 1,
+		pattern: string(m.lastFind),
+		align:   m.findAlignment(tab),
+	}, true
+}
+
+// updateSelCount schedules a recount when the selection, the buffer or the
+// search changed since the last update. It runs after every update.
+func (m *Model) updateSelCount() tea.Cmd {
+	key, ok := m.selCountTarget()
+	if ok == m.selCount.active && key == m.selCount.key {
+		return nil
+	}
+	m.selCount.seq++
+	if m.selCount.cancel != nil {
+		m.selCount.cancel()
+		m.selCount.cancel = nil
+	}
+	m.selCount.key, m.selCount.active = key, ok
+	m.selCount.counting = ok
+	m.selCount.matches = 0
+	if !ok {
+		return nil
+	}
+	seq := m.selCount.seq
+	return m.clock.Tick(findCountDelay, func(time.Time) tea.Msg {
+		return selCountTickMsg{seq: seq}
+	})
+}
+
+func (m *Model) handleSelCountTick(msg selCountTickMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.selCount.seq || !m.selCount.active {
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.selCount.cancel = cancel
+
+	key := m.selCount.key
+	snapshot := key.buf.Snapshot()
+	return m, func() tea.Msg {
+		count, err := snapshot.CountMatchesInContext(ctx, []byte(key.pattern), key.align, key.start, key.end)
+		if err != nil {
+			return nil
+		}
+		return selCountMsg{seq: msg.seq, count: count}
+	}
+}
+
+func (m *Model) handleSelCount(msg selCountMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.selCount.seq {
+		// Counted for a selection or search since changed
+		return m, nil
+	}
+	m.selCount.matches = msg.count
+	m.selCount.counting = false
+	m.selCount.cancel = nil
+	return m, nil
+}
+
+// selCountReadout is the selection readout's match count, or "" without a
+// search.
+func (m *Model) selCountReadout() string {
+	switch {
+	case !m.selCount.active:
+		return ""
+	case m.selCount.counting:
+		return "matches: counting…"
+	}
+	return fmt.Sprintf("matches: %d", m.selCount.matches)
+}
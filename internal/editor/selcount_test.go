@@ -0,0 +1,50 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectionCountsMatches(t *testing.T) {
+	m, clock := startEditor(t, writeTestFile(t, "MZ..MZ..MZ..MZ.."))
+	Drive(m, keys("fMZ")...)
+	Drive(m, key(tea.KeyEscape))
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight,
+		tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight) // 0-7
+	if got := m.selectionReadout(); !strings.HasSuffix(got, "matches: counting…") {
+		t.Fatalf("expected a pending count, got %q", got)
+	}
+
+	// Extending the selection restarts the count rather than blocking
+	press(m, tea.KeyShiftRight)
+	Drive(m, clock.advance(findCountDelay)...)
+	if got := m.selectionReadout(); !strings.HasSuffix(got, "9 (0x9) bytes, matches: 2") {
+		t.Errorf("got %q", got)
+	}
+	press(m, tea.KeyShiftRight)
+	Drive(m, clock.advance(findCountDelay)...)
+	if got := m.selectionReadout(); !strings.HasSuffix(got, "matches: 3") {
+		t.Errorf("the match ending the selection should count, got %q", got)
+	}
+
+	// Clearing the search takes the count away
+	Drive(m, keys("f")...)
+	Drive(m, key(tea.KeyCtrlU), key(tea.KeyEscape))
+	if got := m.selectionReadout(); strings.Contains(got, "matches") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSelectionCountIgnoresStaleResults(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "aaaa"))
+	m.lastFind = []byte("a")
+	press(m, tea.KeyShiftRight)
+	stale := m.selCount.seq
+	press(m, tea.KeyShiftRight)
+	Drive(m, selCountMsg{seq: stale, count: 2})
+	if !m.selCount.counting {
+		t.Error("a count for the old selection was applied")
+	}
+}
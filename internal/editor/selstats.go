@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// While a selection is active, the decoder's second bit row shows sums and
+// counts over it. They are kept as a histogram of the selected bytes, so
+// growing or shrinking the selection only reads the bytes gained or lost.
+
+// selStatsMax is the largest selection summarized from scratch; an edit or
+// a new selection beyond it would stall the frame.
+const selStatsMax = 64 << 20
+
+const selStatsChunk = 64 * 1024
+
+type selStats struct {
+	tab        *Tab
+	version    uint64
+	start, end int64 // inclusive
+	counts     [256]int64
+}
+
+// scan adds (sign 1) or removes (sign -1) the bytes from..to inclusive.
+func (s *selStats) scan(from, to int64, sign int64) {
+	s.tab.Buffer.Chunks(from, to+1, selStatsChunk, func(data []byte) error {
+		for _, b := range data {
+			s.counts[b] += sign
+		}
+		return nil
+	})
+}
+
+// moveEdge adjusts the histogram for an edge of the range moving from old
+// to now; grow is set when a larger value adds bytes.
+func (s *selStats) moveEdge(old, now int64, grow bool) {
+	switch {
+	case now > old && grow:
+		s.scan(old+1, now, 1)
+	case now > old:
+		s.scan(old, now-1, -1)
+	case now < old && grow:
+		s.scan(now+1, old, -1)
+	case now < old:
+		s.scan(now, old-1, 1)
+	}
+}
+
+// selectionStats returns the stats of the current linear selection, or nil.
+func (m *Model) selectionStats() *selStats {
+	tab := m.currentTab()
+	if tab == nil || !tab.Selection.Active || tab.Selection.Block {
+		return nil
+	}
+	start, end := m.getSelectedRange()
+	end = min(end, tab.Buffer.Size()-1)
+	if end < start {
+		return nil
+	}
+
+	s := m.selStats
+	version := tab.Buffer.Version()
+	reuse := s != nil && s.tab == tab && s.version == version &&
+		start <= s.end && end >= s.start && // overlapping
+		abs64(start-s.start)+abs64(end-s.end) < end-start+1
+	if !reuse {
+		if end-start+1 > selStatsMax {
+			return nil
+		}
+		s = &selStats{tab: tab, version: version, start: start, end: start - 1}
+		m.selStats = s
+	}
+	s.moveEdge(s.start, start, false)
+	s.moveEdge(s.end, end, true)
+	s.start, s.end = start, end
+	return s
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// items lists the stats for the decoder, most useful first.
+func (s *selStats) items() []string {
+	var sum uint64
+	zeros := s.counts[0]
+	lo, hi := -1, 0
+	for v, n := range s.counts {
+		if n == 0 {
+			continue
+		}
+		sum += uint64(v) * uint64(n)
+		if lo < 0 {
+			lo = v
+		}
+		hi = v
+	}
+	count := s.end - s.start + 1
+	return []string{
+		fmt.Sprintf("sum %d", sum),
+		fmt.Sprintf("sum32 0x%08X", uint32(sum)),
+		fmt.Sprintf("min %02X", lo),
+		fmt.Sprintf("max %02X", hi),
+		fmt.Sprintf("mean %.2f", float64(sum)/float64(count)),
+		fmt.Sprintf("zeros %d", zeros),
+		fmt.Sprintf("sum8 0x%02X", uint8(sum)),
+		fmt.Sprintf("sum16 0x%04X", uint16(sum)),
+	}
+}
+
+// writeSelectionStats writes the stats row for s, as many items as fit.
+func (m *Model) writeSelectionStats(b *strings.Builder, s *selStats) {
+	label := "Selection:     "
+	b.WriteString(m.styles.DecoderLabel.Render(label))
+	used := lipgloss.Width(label)
+	for i, item := range s.items() {
+		if i > 0 {
+			item = "  " + item
+		}
+		if used+lipgloss.Width(item) > m.width-1 {
+			break
+		}
+		b.WriteString(m.styles.DecoderValue.Render(item))
+		used += lipgloss.Width(item)
+	}
+}
@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectionStats(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0x10, 0xFF, 0x00, 0x21})
+	if m.selectionStats() != nil {
+		t.Fatal("no stats without a selection")
+	}
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight)
+
+	s := m.selectionStats()
+	if s == nil {
+		t.Fatal("expected stats for the selection")
+	}
+	want := []string{"sum 304", "sum32 0x00000130", "min 00", "max FF", "mean 60.80", "zeros 2", "sum8 0x30", "sum16 0x0130"}
+	if got := s.items(); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !strings.Contains(m.renderDecoder(), "sum16 0x0130") {
+		t.Error("the decoder should show the stats")
+	}
+}
+
+// Growing and shrinking the selection adjusts the histogram; it must match
+// a fresh count every time.
+func TestSelectionStatsIncremental(t *testing.T) {
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	m := newTestModel(data)
+	m.setCursor(100)
+	press(m, tea.KeyShiftRight)
+	first := m.selectionStats()
+	press(m, tea.KeyShiftDown)
+	if m.selectionStats() != first {
+		t.Fatal("growing the selection should not recount it")
+	}
+
+	moves := []tea.KeyType{tea.KeyShiftDown, tea.KeyShiftDown, tea.KeyShiftRight, tea.KeyShiftUp, tea.KeyShiftUp, tea.KeyShiftLeft, tea.KeyShiftLeft, tea.KeyShiftUp}
+	for _, k := range moves {
+		press(m, k)
+		got := m.selectionStats()
+		fresh := *got
+		fresh.counts = [256]int64{}
+		fresh.scan(fresh.start, fresh.end, 1)
+		if fresh.counts != got.counts {
+			t.Fatalf("after %v [%d,%d]: incremental counts differ from a rescan", k, got.start, got.end)
+		}
+	}
+
+	last := m.selectionStats()
+	m.currentTab().Buffer.Replace(last.start, 0x01)
+	if m.selectionStats() == last {
+		t.Error("an edit should recount")
+	}
+}
@@ -0,0 +1,186 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/protohuf/unhexed/internal/config"
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+D writes, once confirmed, what a rendering or navigation bug report
+// needs to reproduce the screen: the terminal size, the config, each tab's
+// view state and the bytes on screen around its cursor. Other bytes are
+// left out and read back as zeros, and file names are masked.
+// unhexed --replay FILE rebuilds the editor from the report at the size it
+// was captured at.
+
+const sessionVersion = 1
+
+// sessionMargin bytes around the cursor are kept besides those on screen.
+const sessionMargin = 256
+
+type sessionState struct {
+	Version       int            `json:"version"`
+	Width         int            `json:"width"`
+	Height        int            `json:"height"`
+	Config        *config.Config `json:"config"`
+	Mode          EditMode       `json:"mode"`
+	BigEndian     bool           `json:"big_endian"`
+	HighlightSame bool           `json:"highlight_same"`
+	ActiveTab     int            `json:"active_tab"`
+	Tabs          []tabState     `json:"tabs"`
+}
+
+type tabState struct {
+	Name       string   `json:"name"`
+	Size       int64    `json:"size"`
+	Cursor     int64    `json:"cursor"`
+	ScrollY    int      `json:"scroll_y"`
+	UTF8Text   bool     `json:"utf8_text"`
+	GroupWidth int      `json:"group_width"`
+	TextFocus  bool     `json:"text_focus"`
+	Nibbles    bool     `json:"nibbles"`
+	Nibble     int      `json:"nibble"`
+	Fold       foldMode `json:"fold"`
+	Selection  struct {
+		Active bool  `json:"active"`
+		Block  bool  `json:"block"`
+		Start  int64 `json:"start"`
+		End    int64 `json:"end"`
+	} `json:"selection"`
+	DataOffset int64  `json:"data_offset"`
+	Data       []byte `json:"data"` // base64
+}
+
+// maskName keeps the shape of a file name, its length and extension, and
+// hides the rest: letters become x and digits 0.
+func maskName(name string) string {
+	ext := filepath.Ext(name)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r):
+			return 'x'
+		case unicode.IsDigit(r):
+			return '0'
+		}
+		return r
+	}, strings.TrimSuffix(name, ext)) + ext
+}
+
+// captureSession returns the state a report holds.
+func (m *Model) captureSession() sessionState {
+	s := sessionState{
+		Version:       sessionVersion,
+		Width:         m.width,
+		Height:        m.height,
+		Config:        m.config,
+		Mode:          m.mode,
+		BigEndian:     m.bigEndian,
+		HighlightSame: m.highlightSame,
+		ActiveTab:     m.activeTab,
+	}
+	for i, tab := range m.tabs {
+		t := tabState{
+			Name:       maskName(m.tabLabels()[i]),
+			Size:       tab.Buffer.Size(),
+			Cursor:     tab.Cursor,
+			ScrollY:    tab.ScrollY,
+			UTF8Text:   tab.UTF8Text,
+			GroupWidth: tab.GroupWidth,
+			TextFocus:  tab.TextFocus,
+			Nibbles:    tab.Nibbles,
+			Nibble:     tab.nibble,
+			Fold:       tab.Fold,
+		}
+		t.Selection.Active, t.Selection.Block = tab.Selection.Active, tab.Selection.Block
+		t.Selection.Start, t.Selection.End = tab.Selection.Start, tab.Selection.End
+
+		start := max(min(int64(tab.ScrollY)*bytesPerRow, tab.Cursor-sessionMargin), 0)
+		end := min(max(int64(tab.ScrollY+m.visibleRows())*bytesPerRow, tab.Cursor+sessionMargin), t.Size)
+		if end > start {
+			t.DataOffset, t.Data = start, tab.Buffer.GetBytes(start, int(end-start))
+		}
+		s.Tabs = append(s.Tabs, t)
+	}
+	return s
+}
+
+// exportSession asks before writing a report to the temporary directory.
+func (m *Model) exportSession() {
+	path := filepath.Join(os.TempDir(), "unhexed-state-"+m.clock.Now().Format("20060102-150405")+".json")
+	m.openDialog(fmt.Sprintf("Write a diagnostic report to %s? It holds the window size, your settings, "+
+		"each tab's view and the bytes on screen around its cursor; other bytes and file names are left out.", path),
+		m.cancelButton(ViewMain),
+		dialogButton{label: "Write", action: func() (tea.Model, tea.Cmd) {
+			m.view = ViewMain
+			if err := m.writeSession(path); err != nil {
+				m.setStatus(sevError, "Diagnostic report failed: "+err.Error())
+			} else {
+				m.setStatus(sevInfo, "Wrote the diagnostic report to "+path)
+			}
+			return m, nil
+		}})
+}
+
+func (m *Model) writeSession(path string) error {
+	data, err := json.MarshalIndent(m.captureSession(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return m.fs.WriteFile(path, data, 0600)
+}
+
+// Replay builds an editor from a report written with Alt+D, ignoring the
+// terminal's size for the one captured. Options other than Monochrome,
+// Clock and FS are ignored.
+func Replay(path string, opts Options) (*Model, error) {
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = systemFS{}
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s sessionState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Version != sessionVersion || s.Config == nil {
+		return nil, fmt.Errorf("%s is not a diagnostic report this version reads", path)
+	}
+
+	m, err := NewModel(nil, Options{Monochrome: opts.Monochrome, Config: s.Config, Clock: opts.Clock, FS: fsys})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range s.Tabs {
+		if t.Size < 0 || t.DataOffset < 0 || t.DataOffset+int64(len(t.Data)) > t.Size {
+			return nil, fmt.Errorf("%s: tab %s holds bytes past its end", path, t.Name)
+		}
+		tab := m.newTab(buffer.FromExcerpt(t.Name, t.Size, t.DataOffset, t.Data))
+		tab.Cursor, tab.ScrollY = t.Cursor, t.ScrollY
+		tab.UTF8Text, tab.GroupWidth, tab.TextFocus = t.UTF8Text, t.GroupWidth, t.TextFocus
+		tab.Nibbles, tab.nibble, tab.Fold = t.Nibbles, t.Nibble, t.Fold
+		tab.Selection.Active, tab.Selection.Block = t.Selection.Active, t.Selection.Block
+		tab.Selection.Start, tab.Selection.End = t.Selection.Start, t.Selection.End
+		m.tabs = append(m.tabs, tab)
+	}
+	m.mode, m.bigEndian, m.highlightSame = s.Mode, s.BigEndian, s.HighlightSame
+	m.view = ViewMain
+	m.width, m.height = s.Width, s.Height
+	m.fixedSize = true
+	if s.ActiveTab >= 0 && s.ActiveTab < len(m.tabs) {
+		m.activeTab = s.ActiveTab
+	}
+	m.scrollTabs()
+	m.setStatus(sevInfo, fmt.Sprintf("Replaying %s at %dx%d", filepath.Base(path), s.Width, s.Height))
+	return m, nil
+}
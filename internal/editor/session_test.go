@@ -0,0 +1,109 @@
+package editor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMaskName(t *testing.T) {
+	for name, want := range map[string]string{
+		"firmware-v2.bin": "xxxxxxxx-x0.bin",
+		"Makefile":        "xxxxxxxx",
+		"[New File]":      "[xxx xxxx]",
+	} {
+		if got := maskName(name); got != want {
+			t.Errorf("maskName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSessionReplay(t *testing.T) {
+	data := make([]byte, 0x4000)
+	for i := range data {
+		data[i] = byte(i%255 + 1)
+	}
+	m, clock := startEditor(t, writeTestFile(t, string(data)))
+	Drive(m, append(keys("g0x2000"), key(tea.KeyEnter))...)
+	Drive(m, key(tea.KeyShiftRight), key(tea.KeyShiftRight))
+	tab := m.currentTab()
+	want := m.renderPane(tab)
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := m.writeSession(path); err != nil {
+		t.Fatal(err)
+	}
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(report), "data.bin") {
+		t.Error("the report names the file")
+	}
+
+	r, err := Replay(path, Options{Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := r.currentTab()
+	if rt.Cursor != tab.Cursor || rt.ScrollY != tab.ScrollY || rt.Selection != tab.Selection || rt.Buffer.Size() != 0x4000 {
+		t.Errorf("replayed at 0x%X/%d, was 0x%X/%d", rt.Cursor, rt.ScrollY, tab.Cursor, tab.ScrollY)
+	}
+	if got := rt.Buffer.GetBytes(0x2000-sessionMargin, 2*sessionMargin); !bytes.Equal(got, data[0x2000-sessionMargin:0x2000+sessionMargin]) {
+		t.Error("the bytes around the cursor weren't kept")
+	}
+	if got := rt.Buffer.GetBytes(0, 16); !bytes.Equal(got, make([]byte, 16)) {
+		t.Errorf("bytes far from the cursor should be zeros, got % X", got)
+	}
+
+	// The replay keeps its size and shows the same rows
+	Drive(r, tea.WindowSizeMsg{Width: 80, Height: 24})
+	if r.width != m.width || r.height != m.height {
+		t.Errorf("replayed at %dx%d, captured at %dx%d", r.width, r.height, m.width, m.height)
+	}
+	if got := r.renderPane(rt); got != want {
+		t.Errorf("the replay renders differently:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSessionExportAsks(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "abc"))
+	t.Setenv("TMPDIR", t.TempDir())
+	Drive(m, altKey('d'))
+	if m.view != ViewDialog {
+		t.Fatal("expected a consent dialog")
+	}
+	Drive(m, key(tea.KeyEscape))
+	if entries, _ := os.ReadDir(os.TempDir()); len(entries) != 0 {
+		t.Error("cancelling wrote the report")
+	}
+}
+
+func TestSessionReplayHugeTab(t *testing.T) {
+	m, clock := startEditor(t, writeTestFile(t, "abc"))
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := m.writeSession(path); err != nil {
+		t.Fatal(err)
+	}
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A report claiming a terabyte isn't allocated in full
+	report = bytes.Replace(report, []byte(`"size": 3,`), []byte(`"size": 1099511627776,`), 1)
+	if err := os.WriteFile(path, report, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Replay(path, Options{Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := r.currentTab().Buffer; b.Size() != 1<<40 || string(b.GetBytes(0, 3)) != "abc" {
+		t.Errorf("replayed %d bytes starting %q", b.Size(), b.GetBytes(0, 3))
+	}
+}
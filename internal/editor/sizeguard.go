@@ -0,0 +1,44 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+)
+
+// Inserting or deleting by mistake shifts everything after it, which in a
+// binary with offsets or a size field breaks the file. The status line
+// shows the net size change since the file was opened or saved (Δ+12),
+// and with confirm_size_change on, S spells it out and asks first.
+
+// sizeChange returns how much buf grew since it was last read or saved.
+func sizeChange(buf *buffer.Buffer) int64 {
+	if buf.IsNew() {
+		return 0
+	}
+	return buf.Size() - buf.SavedSize()
+}
+
+// sizeChangeGuarded reports whether saving buf needs confirming.
+func (m *Model) sizeChangeGuarded(buf *buffer.Buffer) bool {
+	return m.config.Behavior.ConfirmSizeChange && sizeChange(buf) != 0
+}
+
+// sizeChangeText describes buf's size change for the save prompt.
+func sizeChangeText(buf *buffer.Buffer) string {
+	d := sizeChange(buf)
+	how := "grow"
+	if d < 0 {
+		how, d = "shrink", -d
+	}
+	return fmt.Sprintf("The file will %s by %d bytes (was 0x%X, now 0x%X).", how, d, buf.SavedSize(), buf.Size())
+}
+
+// sizeChangeReadout is the status line's Δ+12, or "" when the size is
+// unchanged.
+func sizeChangeReadout(buf *buffer.Buffer) string {
+	if d := sizeChange(buf); d != 0 {
+		return fmt.Sprintf("Δ%+d", d)
+	}
+	return ""
+}
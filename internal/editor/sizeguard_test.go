@@ -0,0 +1,54 @@
+package editor
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSaveConfirmsSizeChange(t *testing.T) {
+	path := writeTestFile(t, "abcd")
+	m, _ := startEditor(t, path)
+	m.config.Behavior.ConfirmSizeChange = true
+
+	Drive(m, keys("i4142")...)
+	Drive(m, key(tea.KeyEscape))
+	m.status.text = ""
+	if got := m.renderStatus(); !strings.Contains(got, "Δ+2") {
+		t.Errorf("expected the size change on the status line, got %q", got)
+	}
+
+	_, view := Drive(m, keys("s")...)
+	if m.view != ViewDialog || !strings.Contains(view, "The file will grow by 2 bytes (was 0x4, now 0x6).") {
+		t.Fatalf("expected the prompt:\n%s", view)
+	}
+	Drive(m, key(tea.KeyEscape))
+	if data, _ := os.ReadFile(path); string(data) != "abcd" {
+		t.Fatalf("cancelling saved anyway: %q", data)
+	}
+
+	Drive(m, keys("s")...)
+	Drive(m, key(tea.KeyRight), key(tea.KeyEnter))
+	if data, _ := os.ReadFile(path); string(data) != "ABabcd" {
+		t.Errorf("got %q", data)
+	}
+	m.status.text = ""
+	if got := m.renderStatus(); strings.Contains(got, "Δ") {
+		t.Errorf("the change should be gone once saved, got %q", got)
+	}
+}
+
+func TestSaveWithoutSizeChangeDoesNotAsk(t *testing.T) {
+	path := writeTestFile(t, "abcd")
+	m, _ := startEditor(t, path)
+	m.config.Behavior.ConfirmSizeChange = true
+
+	Drive(m, keys("r41")...)
+	Drive(m, key(tea.KeyEscape))
+	Drive(m, keys("s")...)
+	if data, _ := os.ReadFile(path); m.view != ViewMain || string(data) != "Abcd" {
+		t.Errorf("view %v, got %q", m.view, data)
+	}
+}
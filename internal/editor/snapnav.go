@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/protohuf/unhexed/internal/diff"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// N in the snapshots view picks a snapshot to review against, and then
+// Alt+N and Alt+P step through the places the tab differs from it, like
+// + and - do for unsaved changes. The differences are computed on the
+// first step and kept until the buffer changes. The whole buffer is
+// compared at once rather than a block at a time: an insertion shifts
+// everything after it, so a block only lines up with the snapshot once the
+// comparison has resynchronized over what came before.
+
+type snapReview struct {
+	snap    *snapshot
+	version uint64 // of the tab's buffer the ranges describe
+	ranges  []diff.Range
+	ready   bool
+	seq     int
+}
+
+type snapDiffMsg struct {
+	tab     *Tab
+	seq     int
+	version uint64
+	ranges  []diff.Range
+	err     error
+	forward bool
+}
+
+// reviewSnapshot makes snap the one Alt+N and Alt+P compare with and
+// steps to the first difference after the cursor.
+func (m *Model) reviewSnapshot(tab *Tab, snap *snapshot) tea.Cmd {
+	tab.review = &snapReview{snap: snap}
+	m.view = ViewMain
+	return m.jumpToSnapshotDiff(true)
+}
+
+func (m *Model) jumpToSnapshotDiff(forward bool) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	r := tab.review
+	if r == nil {
+		m.setStatus(sevInfo, "Pick a snapshot to review against first: Z, then N")
+		return nil
+	}
+	if r.ready && r.version == tab.Buffer.Version() {
+		return m.stepSnapshotDiff(tab, forward)
+	}
+
+	r.ready = false
+	r.seq++
+	seq, old, current := r.seq, r.snap.buf, tab.Buffer.Snapshot()
+	m.showStatus(sevInfo, fmt.Sprintf("Comparing with snapshot %q…", r.snap.name))
+	return func() tea.Msg {
+		ranges, err := diff.Compare(context.Background(), old, current)
+		return snapDiffMsg{tab: tab, seq: seq, version: current.Version(), ranges: ranges, err: err, forward: forward}
+	}
+}
+
+func (m *Model) handleSnapDiff(msg snapDiffMsg) (tea.Model, tea.Cmd) {
+	tab, r := msg.tab, msg.tab.review
+	if r == nil || msg.seq != r.seq {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.setStatus(sevError, fmt.Sprintf("Compare failed: %v", msg.err))
+		return m, nil
+	}
+	r.ranges, r.version, r.ready = msg.ranges, msg.version, true
+	if tab != m.currentTab() {
+		return m, nil
+	}
+	// Edits made while comparing are compared again
+	return m, m.jumpToSnapshotDiff(msg.forward)
+}
+
+// stepSnapshotDiff moves to the next or previous difference from the
+// review snapshot and highlights it.
+func (m *Model) stepSnapshotDiff(tab *Tab, forward bool) tea.Cmd {
+	r := tab.review
+	if len(r.ranges) == 0 {
+		m.setStatus(sevInfo, fmt.Sprintf("No differences from snapshot %q", r.snap.name))
+		return nil
+	}
+	i := sort.Search(len(r.ranges), func(i int) bool { return r.ranges[i].BOff > tab.Cursor })
+	if !forward {
+		i = sort.Search(len(r.ranges), func(i int) bool { return r.ranges[i].BOff >= tab.Cursor }) - 1
+	}
+	if i < 0 || i >= len(r.ranges) {
+		where := "after"
+		if !forward {
+			where = "before"
+		}
+		m.setStatus(sevInfo, fmt.Sprintf("No more differences from snapshot %q %s the cursor (%d in all)", r.snap.name, where, len(r.ranges)))
+		return nil
+	}
+
+	d := r.ranges[i]
+	m.clearSelection()
+	tab.Cursor = min(d.BOff, m.maxCursor(tab))
+	tab.resetGoalColumn()
+	m.expandFold(tab, tab.Cursor)
+	m.centerCursor()
+	m.setStatus(sevInfo, fmt.Sprintf("Difference %d of %d from %q: %s", i+1, len(r.ranges), r.snap.name, describeRange(d)))
+	if d.BLen == 0 {
+		return nil
+	}
+	return m.flashRange(tab, d.BOff, d.BOff+d.BLen-1)
+}
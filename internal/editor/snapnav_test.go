@@ -0,0 +1,55 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStepThroughSnapshotDifferences(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, strings.Repeat("abcdefgh", 8)))
+	tab := m.currentTab()
+
+	Drive(m, altKey('n'))
+	if !strings.Contains(m.status.text, "Pick a snapshot") {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	Drive(m, keys("zt")...)
+	Drive(m, keys("good")...)
+	Drive(m, key(tea.KeyEnter), key(tea.KeyEscape))
+	m.setCursor(0x10)
+	Drive(m, keys("r4142")...)
+	Drive(m, key(tea.KeyEscape))
+	m.setCursor(0x30)
+	tab.Buffer.Insert(0x30, []byte{1, 2, 3})
+
+	m.setCursor(0)
+	Drive(m, keys("zn")...)
+	if m.view != ViewMain || tab.Cursor != 0x10 {
+		t.Fatalf("expected the first difference at 0x10, view %v, cursor 0x%X", m.view, tab.Cursor)
+	}
+	if m.status.text != `Difference 1 of 2 from "good": 2 bytes changed at 0x10` {
+		t.Errorf("got %q", m.status.text)
+	}
+	if f := m.flash; f == nil || f.start != 0x10 || f.end != 0x11 {
+		t.Errorf("expected the range highlighted, got %+v", f)
+	}
+
+	Drive(m, altKey('n'))
+	if tab.Cursor != 0x30 || !strings.HasPrefix(m.status.text, "Difference 2 of 2") {
+		t.Errorf("cursor 0x%X, status %q", tab.Cursor, m.status.text)
+	}
+	Drive(m, altKey('n'))
+	if tab.Cursor != 0x30 || !strings.Contains(m.status.text, "No more differences") {
+		t.Errorf("cursor 0x%X, status %q", tab.Cursor, m.status.text)
+	}
+
+	// An edit is picked up on the next step
+	tab.Buffer.Replace(0x38, 'Z')
+	Drive(m, altKey('p'))
+	if tab.Cursor != 0x10 || !strings.HasPrefix(m.status.text, "Difference 1 of 3") {
+		t.Errorf("cursor 0x%X, status %q", tab.Cursor, m.status.text)
+	}
+}
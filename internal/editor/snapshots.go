@@ -0,0 +1,143 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Snapshots are named copies of a tab's buffer to compare against or go
+// back to. Each is a Clone, so it shares the piece table's storage and
+// only the pieces edited afterwards cost memory. They belong to the tab
+// and are dropped with it.
+
+type snapshot struct {
+	name string
+	at   time.Time
+	buf  *buffer.Buffer
+}
+
+func (m *Model) openSnapshots() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	m.view = ViewSnapshots
+	m.snapNaming = false
+	m.snapSel = max(0, min(m.snapSel, len(tab.snapshots)-1))
+}
+
+func (m *Model) takeSnapshot(tab *Tab, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fmt.Sprintf("snapshot %d", len(tab.snapshots)+1)
+	}
+	tab.snapshots = append(tab.snapshots, &snapshot{name: name, at: m.clock.Now(), buf: tab.Buffer.Clone()})
+	m.snapSel = len(tab.snapshots) - 1
+	m.setStatus(sevInfo, fmt.Sprintf("Took snapshot %q", name))
+}
+
+func (m *Model) handleSnapshotsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tab := m.currentTab()
+	if tab == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	if m.snapNaming {
+		switch msg.Type {
+		case tea.KeyEscape:
+			m.snapNaming = false
+		case tea.KeyEnter:
+			m.snapNaming = false
+			m.takeSnapshot(tab, m.snapInput)
+		case tea.KeyBackspace:
+			if r := []rune(m.snapInput); len(r) > 0 {
+				m.snapInput = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.snapInput += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	var snap *snapshot
+	if m.snapSel < len(tab.snapshots) {
+		snap = tab.snapshots[m.snapSel]
+	}
+
+	switch msg.String() {
+	case "esc", "z", "Z":
+		m.view = ViewMain
+	case "up":
+		m.snapSel = max(m.snapSel-1, 0)
+	case "down":
+		m.snapSel = max(min(m.snapSel+1, len(tab.snapshots)-1), 0)
+	case "t", "T":
+		m.snapNaming = true
+		m.snapInput = ""
+	case "r", "R":
+		if snap != nil && !m.editBlocked(tab) {
+			if m.refused("restore the snapshot", tab.Buffer.Restore(snap.buf)) {
+				return m, nil
+			}
+			m.view = ViewMain
+			m.setCursor(tab.Cursor)
+			m.setStatus(sevInfo, fmt.Sprintf("Restored snapshot %q (U to undo)", snap.name))
+		}
+	case "d", "D":
+		if snap != nil {
+			return m, m.startDiff(tab, "Changes since "+snap.name, snap.buf)
+		}
+	case "n", "N":
+		if snap != nil {
+			return m, m.reviewSnapshot(tab, snap)
+		}
+	case "delete", "x", "X":
+		if snap != nil {
+			if tab.review != nil && tab.review.snap == snap {
+				tab.review = nil
+			}
+			tab.snapshots = append(tab.snapshots[:m.snapSel], tab.snapshots[m.snapSel+1:]...)
+			m.snapSel = max(min(m.snapSel, len(tab.snapshots)-1), 0)
+			m.setStatus(sevInfo, fmt.Sprintf("Dropped snapshot %q", snap.name))
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) renderSnapshots() string {
+	tab := m.currentTab()
+	if tab == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nSNAPSHOTS\n")
+	b.WriteString("=========\n\n")
+
+	if len(tab.snapshots) == 0 {
+		b.WriteString("No snapshots of this tab yet\n")
+	}
+	for i, snap := range tab.snapshots {
+		line := fmt.Sprintf("%-24s %s  %d bytes", truncateMiddle(snap.name, 24), snap.at.Format("15:04:05"), snap.buf.Size())
+		if i == m.snapSel {
+			b.WriteString("> " + m.styles.Selection.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if m.snapNaming {
+		b.WriteString("\nName: " + m.snapInput + "_\n")
+		b.WriteString("\nPress Enter to take the snapshot, ESC to cancel\n")
+	} else {
+		b.WriteString("\nT take snapshot, R restore, D diff against it, N step through the differences\n")
+		b.WriteString("(then Alt+N/Alt+P), X drop, ESC close\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/diff"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSnapshotRestoreAndDiff(t *testing.T) {
+	m := newTestModel([]byte("header:v1;the body of the record"))
+	tab := m.currentTab()
+
+	typeKeys(m, "zt")
+	typeKeys(m, "pre-fix")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(tab.snapshots) != 1 || tab.snapshots[0].name != "pre-fix" {
+		t.Fatalf("expected a snapshot named pre-fix, got %+v", tab.snapshots)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+
+	tab.Buffer.Replace(8, '2')
+	tab.Buffer.Insert(20, []byte("++"))
+
+	// Diff against it
+	typeKeys(m, "z")
+	_, cmd := m.handleSnapshotsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m.view != ViewDiff || !strings.Contains(m.renderDiff(), "Comparing") {
+		t.Fatal("expected the diff view while comparing")
+	}
+	m.Update(cmd())
+	want := []diff.Range{
+		{Kind: diff.Changed, AOff: 8, ALen: 1, BOff: 8, BLen: 1},
+		{Kind: diff.Inserted, AOff: 20, BOff: 20, BLen: 2},
+	}
+	if len(m.diff.ranges) != 2 || m.diff.ranges[0] != want[0] || m.diff.ranges[1] != want[1] {
+		t.Fatalf("unexpected ranges %+v", m.diff.ranges)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain || tab.Cursor != 20 {
+		t.Errorf("Enter should jump to the second difference, got %d", tab.Cursor)
+	}
+
+	// Restore is one undoable step
+	typeKeys(m, "zr")
+	if got := tab.Buffer.Data(); !bytes.Equal(got, []byte("header:v1;the body of the record")) {
+		t.Fatalf("restore gave %q", got)
+	}
+	typeKeys(m, "u")
+	if got := tab.Buffer.Data(); !bytes.Equal(got, []byte("header:v2;the body o++f the record")) {
+		t.Errorf("undo should bring back the edits, got %q", got)
+	}
+}
+
+func TestSnapshotsBelongToTheirTab(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 3})
+	typeKeys(m, "zt")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if name := m.currentTab().snapshots[0].name; name != "snapshot 1" {
+		t.Errorf("expected a default name, got %q", name)
+	}
+
+	typeKeys(m, "x")
+	if len(m.currentTab().snapshots) != 0 {
+		t.Error("X should drop the selected snapshot")
+	}
+}
@@ -0,0 +1,164 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Alt+S shows the next tab below the current one, to eyeball two related
+// files without comparing them. While the panes are linked the other pane
+// follows the focused one's cursor and scrolling, kept the same number of
+// bytes apart; Alt+Y unlinks them so each can be moved to the region to
+// line up, and relinking keeps them as far apart as they then are. Keys,
+// edits included, go to the focused pane, which is the current tab; Alt+W
+// or the tab keys switch it.
+
+type splitView struct {
+	panes  [2]*Tab // top and bottom
+	focus  int     // the pane showing the current tab
+	linked bool
+	delta  int64 // the bottom pane's cursor less the top's, while linked
+}
+
+func (m *Model) toggleSplit() {
+	if m.split != nil {
+		m.split = nil
+		m.ensureCursorVisible()
+		m.setStatus(sevInfo, "Split closed")
+		return
+	}
+	tab := m.currentTab()
+	if tab == nil || len(m.tabs) < 2 {
+		m.alert("Open a second tab to split with")
+		return
+	}
+	other := m.tabs[(m.activeTab+1)%len(m.tabs)]
+	m.split = &splitView{panes: [2]*Tab{tab, other}, linked: true}
+	m.ensureCursorVisible()
+	m.syncSplit()
+	m.setStatus(sevInfo, fmt.Sprintf("Split with %s, scrolling linked (Alt+W switches pane, Alt+Y unlinks)", m.tabLabel(other)))
+}
+
+// switchPane focuses the split's other pane.
+func (m *Model) switchPane() {
+	s := m.split
+	if s == nil {
+		m.alert("No split to switch pane in (Alt+S splits)")
+		return
+	}
+	if i := slices.Index(m.tabs, s.panes[1-s.focus]); i >= 0 {
+		m.selectTab(i)
+	}
+}
+
+func (m *Model) toggleSplitLink() {
+	s := m.split
+	if s == nil {
+		m.alert("No split to link (Alt+S splits)")
+		return
+	}
+	s.linked = !s.linked
+	if !s.linked {
+		m.setStatus(sevInfo, "Split scrolling unlinked (Alt+Y relinks at the panes' current offsets)")
+		return
+	}
+	s.delta = s.panes[1].Cursor - s.panes[0].Cursor
+	where := "at the same offsets"
+	switch {
+	case s.delta > 0:
+		where = fmt.Sprintf("the bottom pane 0x%X bytes ahead", s.delta)
+	case s.delta < 0:
+		where = fmt.Sprintf("the bottom pane 0x%X bytes behind", -s.delta)
+	}
+	m.setStatus(sevInfo, "Split scrolling linked, "+where)
+}
+
+// syncSplit follows the current tab into the focused pane, and moves the
+// other pane after it while linked. It runs after every update.
+func (m *Model) syncSplit() {
+	s := m.split
+	if s == nil {
+		return
+	}
+	cur := m.currentTab()
+	switch cur {
+	case nil:
+		m.split = nil
+		return
+	case s.panes[s.focus]:
+	case s.panes[1-s.focus]:
+		s.focus = 1 - s.focus
+	default:
+		s.panes[s.focus] = cur
+	}
+	other := s.panes[1-s.focus]
+	if !slices.Contains(m.tabs, other) {
+		// The other pane's tab was closed
+		m.split = nil
+		m.ensureCursorVisible()
+		return
+	}
+	if !s.linked {
+		return
+	}
+
+	d := s.delta
+	if s.focus == 1 {
+		d = -d
+	}
+	other.Cursor = max(0, min(cur.Cursor+d, m.maxCursor(other)))
+	rows := d / bytesPerRow
+	if d%bytesPerRow < 0 {
+		rows--
+	}
+	other.ScrollY = cur.ScrollY + int(rows)
+	m.clampScroll(other)
+}
+
+// tabLabel returns the tab strip's label for tab.
+func (m *Model) tabLabel(tab *Tab) string {
+	if i := slices.Index(m.tabs, tab); i >= 0 {
+		return m.tabLabels()[i]
+	}
+	return ""
+}
+
+// renderSplit renders both panes and the bar between them, the top pane
+// padded so the bar stays put.
+func (m *Model) renderSplit() string {
+	s := m.split
+	top := m.renderPane(s.panes[0])
+	if n := strings.Count(top, "\n") + 1; n < m.visibleRows() {
+		top += strings.Repeat("\n", m.visibleRows()-n)
+	}
+	return top + "\n" + m.renderSplitBar() + "\n" + m.renderPane(s.panes[1])
+}
+
+// renderSplitBar names the panes, the focused one highlighted, and says
+// whether they scroll together.
+func (m *Model) renderSplitBar() string {
+	s := m.split
+	names := [2]string{"▲ " + m.tabLabel(s.panes[0]), "▼ " + m.tabLabel(s.panes[1])}
+	for i := range names {
+		if i == s.focus {
+			names[i] = m.styles.ActiveTab.Render(names[i])
+		} else {
+			names[i] = m.styles.InactiveTab.Render(names[i])
+		}
+	}
+	link := "unlinked"
+	if s.linked {
+		link = "linked"
+		if s.delta != 0 {
+			link += fmt.Sprintf(" %+#x", s.delta)
+		}
+	}
+	bar := "── " + names[0] + "  " + names[1] + m.styles.Disabled.Render("  "+link) + " "
+	if fill := m.width - lipgloss.Width(bar); fill > 0 {
+		bar += strings.Repeat("─", fill)
+	}
+	return bar
+}
@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitScrollsLinked(t *testing.T) {
+	a := writeTestFile(t, strings.Repeat("A", 0x400))
+	b := writeTestFile(t, strings.Repeat("B", 0x200))
+	m, _ := startEditor(t, a, b)
+	m.selectTab(0)
+	top, bottom := m.tabs[0], m.tabs[1]
+
+	_, view := Drive(m, altKey('s'))
+	if m.split == nil || !strings.Contains(view, "41 41 41") || !strings.Contains(view, "42 42 42") {
+		t.Fatalf("expected both files:\n%s", view)
+	}
+	Drive(m, key(tea.KeyPgDown), key(tea.KeyPgDown))
+	// The shorter file scrolls as far as it can
+	row := int(bottom.Cursor / bytesPerRow)
+	if bottom.Cursor != top.Cursor || row < bottom.ScrollY || row >= bottom.ScrollY+m.visibleRows() {
+		t.Errorf("the bottom pane didn't follow: 0x%X/%d vs 0x%X/%d", bottom.Cursor, bottom.ScrollY, top.Cursor, top.ScrollY)
+	}
+
+	// Unlink, move the bottom pane on by 0x20 and relink
+	Drive(m, altKey('y'), altKey('w'), key(tea.KeyDown), key(tea.KeyDown))
+	if m.currentTab() != bottom || top.Cursor != bottom.Cursor-0x20 {
+		t.Fatalf("the panes should move apart while unlinked: 0x%X, 0x%X", top.Cursor, bottom.Cursor)
+	}
+	Drive(m, altKey('y'))
+	if !strings.Contains(m.renderSplitBar(), "linked +0x20") {
+		t.Errorf("got %q", m.renderSplitBar())
+	}
+	Drive(m, key(tea.KeyUp))
+	if top.Cursor != bottom.Cursor-0x20 {
+		t.Errorf("expected the top pane 0x20 behind, 0x%X, 0x%X", top.Cursor, bottom.Cursor)
+	}
+
+	// Edits go to the focused pane
+	Drive(m, keys("r00")...)
+	if !bytes.Contains(bottom.Buffer.GetBytes(0, 0x200), []byte{0}) || bytes.Contains(top.Buffer.GetBytes(0, 0x400), []byte{0}) {
+		t.Error("the edit went to the wrong pane")
+	}
+	Drive(m, key(tea.KeyEscape))
+
+	Drive(m, altKey('s'))
+	if m.split != nil {
+		t.Error("Alt+S should close the split")
+	}
+}
+
+func TestSplitEndsWithItsTab(t *testing.T) {
+	m, _ := startEditor(t, writeTestFile(t, "abc"), writeTestFile(t, "def"))
+	Drive(m, altKey('s'))
+	if m.split == nil {
+		t.Fatal("no split")
+	}
+	Drive(m, key(tea.KeyCtrlW))
+	if m.split != nil || len(m.tabs) != 1 {
+		t.Errorf("closing a pane's tab should end the split, %d tabs", len(m.tabs))
+	}
+}
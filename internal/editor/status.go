@@ -0,0 +1,214 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type severity int
+
+const (
+	sevInfo severity = iota
+	sevWarning
+	sevError
+)
+
+func (s severity) String() string {
+	switch s {
+	case sevWarning:
+		return "WARN"
+	case sevError:
+		return "ERROR"
+	default:
+		return "INFO"
+	}
+}
+
+// How long a message stays in the status bar. It is cleared by a tick
+// rather than the next key, so errors stay readable while typing.
+func (s severity) duration() time.Duration {
+	switch s {
+	case sevWarning:
+		return 5 * time.Second
+	case sevError:
+		return 8 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+const statusLogSize = 100
+
+type statusEntry struct {
+	text string
+	sev  severity
+	at   time.Time
+}
+
+type statusExpireMsg struct {
+	seq int
+}
+
+// setStatus shows text in the status bar and records it in the message log.
+func (m *Model) setStatus(sev severity, text string) {
+	m.showStatus(sev, text)
+	m.statusLog = append(m.statusLog, m.status)
+	if len(m.statusLog) > statusLogSize {
+		m.statusLog = m.statusLog[len(m.statusLog)-statusLogSize:]
+	}
+}
+
+// showStatus shows text without logging it, for prompts such as a pending
+// count that are only useful while they are on screen.
+func (m *Model) showStatus(sev severity, text string) {
+	m.status = statusEntry{text: text, sev: sev, at: m.clock.Now()}
+	m.statusSeq++
+}
+
+// statusTimer returns the tick that expires the current message, once per
+// message.
+func (m *Model) statusTimer() tea.Cmd {
+	if m.statusSeq == m.statusTimed || m.status.text == "" {
+		return nil
+	}
+	m.statusTimed = m.statusSeq
+	seq := m.statusSeq
+	return m.clock.Tick(m.status.sev.duration(), func(time.Time) tea.Msg {
+		return statusExpireMsg{seq: seq}
+	})
+}
+
+func (m *Model) handleStatusExpire(msg statusExpireMsg) (tea.Model, tea.Cmd) {
+	if msg.seq == m.statusSeq {
+		m.status = statusEntry{}
+	}
+	return m, nil
+}
+
+func (m *Model) renderStatus() string {
+	if m.job != nil {
+		return m.renderJobProgress()
+	}
+	if m.saving != nil {
+		return m.renderSaveProgress()
+	}
+	if m.status.text == "" {
+		// Idle: show where the active tab lives, since its label may be
+		// shortened
+		tab := m.currentTab()
+		if tab == nil || m.view != ViewMain {
+			return ""
+		}
+		readout := ""
+		if m.config.Behavior.ByteReadout {
+			readout = m.byteReadout(tab)
+		}
+		if s := m.strideReadout(); s != "" {
+			readout = strings.TrimSpace(s + "  " + readout)
+		}
+		if tab.Buffer.Filename() != "" {
+			path := tab.Buffer.Filename()
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			path += windowLabel(tab)
+			if tab.Buffer.IsDevice() {
+				path += "  " + m.sectorPosition(tab.Cursor)
+			}
+			if s := m.autosaveReadout(tab); s != "" {
+				path += "  " + s
+			}
+			if s := sizeChangeReadout(tab.Buffer); s != "" {
+				path += "  " + s
+			}
+			if readout == "" {
+				return m.styles.Disabled.Render(truncateMiddle(path, m.width))
+			}
+			if width := m.width - len(readout) - 2; width > 0 {
+				return readout + "  " + m.styles.Disabled.Render(truncateMiddle(path, width))
+			}
+		}
+		return readout
+	}
+	switch {
+	case m.ringing():
+		return m.styles.StatusWarning.Reverse(true).Width(m.width).Render(m.status.text)
+	case m.status.sev == sevWarning:
+		return m.styles.StatusWarning.Render(m.status.text)
+	case m.status.sev == sevError:
+		return m.styles.StatusError.Render(m.status.text)
+	default:
+		return m.styles.StatusInfo.Render(m.status.text)
+	}
+}
+
+func (m *Model) openMessageLog() {
+	m.view = ViewMessages
+	m.logScroll = len(m.statusLog) - m.logRows()
+	if m.logScroll < 0 {
+		m.logScroll = 0
+	}
+}
+
+func (m *Model) logRows() int {
+	rows := m.height - 8
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+func (m *Model) handleMessagesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := len(m.statusLog) - m.logRows()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	switch msg.String() {
+	case "esc", "m", "M":
+		m.view = ViewMain
+	case "up":
+		m.logScroll--
+	case "down":
+		m.logScroll++
+	case "pgup":
+		m.logScroll -= m.logRows()
+	case "pgdown":
+		m.logScroll += m.logRows()
+	case "home":
+		m.logScroll = 0
+	case "end":
+		m.logScroll = maxScroll
+	}
+	m.logScroll = max(0, min(m.logScroll, maxScroll))
+	return m, nil
+}
+
+func (m *Model) renderMessages() string {
+	var b strings.Builder
+	b.WriteString("\nMESSAGES\n")
+	b.WriteString("========\n\n")
+
+	if len(m.statusLog) == 0 {
+		b.WriteString("No messages\n")
+	}
+	end := min(m.logScroll+m.logRows(), len(m.statusLog))
+	for _, e := range m.statusLog[m.logScroll:end] {
+		line := fmt.Sprintf("%s %-5s %s", e.at.Format("15:04:05"), e.sev, e.text)
+		switch e.sev {
+		case sevWarning:
+			line = m.styles.StatusWarning.Render(line)
+		case sevError:
+			line = m.styles.StatusError.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString(fmt.Sprintf("\n%d of %d, ↑/↓ to scroll, ESC to close\n", end, len(m.statusLog)))
+	return b.String()
+}
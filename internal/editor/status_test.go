@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStatusSurvivesKeysAndExpiresByTick(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+
+	m.setStatus(sevError, "disk full")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.status.text != "disk full" {
+		t.Fatalf("status cleared by a key, got %q", m.status.text)
+	}
+	if cmd == nil {
+		t.Fatal("expected an expiry tick for the new message")
+	}
+
+	// A tick for a superseded message leaves the current one alone
+	seq := m.statusSeq
+	m.setStatus(sevInfo, "newer")
+	m.Update(statusExpireMsg{seq: seq})
+	if m.status.text != "newer" {
+		t.Errorf("stale tick cleared the status, got %q", m.status.text)
+	}
+
+	m.Update(statusExpireMsg{seq: m.statusSeq})
+	if m.status.text != "" {
+		t.Errorf("expected status to expire, got %q", m.status.text)
+	}
+}
+
+func TestStatusDoesNotShiftLayout(t *testing.T) {
+	m := newTestModel(make([]byte, 4096))
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	before := strings.Count(m.View(), "\n")
+	m.setStatus(sevWarning, "something happened")
+	if after := strings.Count(m.View(), "\n"); after != before {
+		t.Errorf("status changed the line count from %d to %d", before, after)
+	}
+}
+
+func TestStatusLogKeepsRecentMessages(t *testing.T) {
+	m := newTestModel(nil)
+
+	for i := 0; i < statusLogSize+20; i++ {
+		m.setStatus(sevInfo, fmt.Sprintf("message %d", i))
+	}
+	m.showStatus(sevInfo, "Count: 3")
+
+	if len(m.statusLog) != statusLogSize {
+		t.Fatalf("expected %d log entries, got %d", statusLogSize, len(m.statusLog))
+	}
+	if first := m.statusLog[0].text; first != "message 20" {
+		t.Errorf("expected oldest entry to be message 20, got %q", first)
+	}
+	if last := m.statusLog[len(m.statusLog)-1].text; last != fmt.Sprintf("message %d", statusLogSize+19) {
+		t.Errorf("prompt should not be logged, last entry %q", last)
+	}
+
+	typeKeys(m, "m")
+	if m.view != ViewMessages {
+		t.Fatalf("expected the message log view, got %v", m.view)
+	}
+	if !strings.Contains(m.View(), "INFO  message 119") {
+		t.Error("log view should open scrolled to the newest message")
+	}
+}
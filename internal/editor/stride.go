@@ -0,0 +1,58 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Replace mode can advance more than one byte after each byte typed, to
+// walk a column of fixed-size records: 8R, or Alt+A to enter the stride.
+// The status bar shows it while it isn't 1.
+
+// replaceWithStride enters Replace mode advancing stride bytes after each
+// byte.
+func (m *Model) replaceWithStride(stride int64) {
+	m.setMode(ModeReplace)
+	if stride > 1 {
+		m.stride = stride
+		m.setStatus(sevInfo, fmt.Sprintf("Replace mode, advancing %d bytes after each byte", stride))
+	}
+}
+
+func (m *Model) openStridePrompt() {
+	m.view = ViewStride
+	m.strideInput = ""
+}
+
+func (m *Model) handleStrideKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleSizeInputKey(msg, &m.strideInput, func() (bool, tea.Cmd) {
+		stride, err := parseOffset(strings.TrimSpace(m.strideInput))
+		if err != nil || stride < 1 {
+			m.setStatus(sevWarning, fmt.Sprintf("Invalid stride %q: enter a number of bytes, 1 or more", m.strideInput))
+			return false, nil
+		}
+		m.replaceWithStride(stride)
+		return true, nil
+	})
+}
+
+// strideReadout is the stride for the status bar, or "" when it is 1.
+func (m *Model) strideReadout() string {
+	if m.mode != ModeReplace || m.stride <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("stride %d", m.stride)
+}
+
+func (m *Model) renderStride() string {
+	var b strings.Builder
+	b.WriteString("\nREPLACE WITH STRIDE\n")
+	b.WriteString("===================\n\n")
+	b.WriteString("Stride: " + m.strideInput + "_\n\n")
+	b.WriteString("Bytes to advance after each byte typed, in decimal or 0x hex:\n")
+	b.WriteString("\"8\" changes every 8th byte, walking a column of 8-byte records\n")
+	b.WriteString("\nPress Enter to enter Replace mode, ESC to cancel\n")
+	return b.String()
+}
@@ -0,0 +1,263 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Several tabs may view the same *Buffer, each with its own cursor, scroll
+// position and selection. Edits made through one view show up in all of
+// them, and the buffer is only offered for saving when its last view closes.
+
+func samePath(fsys FS, a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	ia, errA := fsys.Stat(a)
+	ib, errB := fsys.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(ia, ib)
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// findOpenTab returns the index of a tab viewing path, or -1.
+func (m *Model) findOpenTab(path string) int {
+	for i, tab := range m.tabs {
+		if _, windowed := tab.Buffer.Window(); !windowed && !tab.Buffer.IsNew() && samePath(m.fs, tab.Buffer.Filename(), path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// viewCount returns how many tabs view tab's buffer, including tab itself.
+func (m *Model) viewCount(tab *Tab) int {
+	n := 0
+	for _, t := range m.tabs {
+		if t.Buffer == tab.Buffer {
+			n++
+		}
+	}
+	return n
+}
+
+// selectTab activates tab i. Another view may have shrunk the shared buffer
+// meanwhile, so the cursor and selection are clamped to its current size.
+func (m *Model) selectTab(i int) {
+	if i < 0 || i >= len(m.tabs) {
+		return
+	}
+	m.activeTab = i
+	m.scrollTabs()
+	tab := m.tabs[i]
+
+	maxPos := max(tab.Buffer.Size()-1, 0)
+	tab.Cursor = min(tab.Cursor, m.maxCursor(tab))
+	tab.Selection.Start = min(tab.Selection.Start, maxPos)
+	tab.Selection.End = min(tab.Selection.End, maxPos)
+	m.clampScroll(tab)
+	m.ensureTabCursorVisible(tab)
+}
+
+// jumpToOpenTab activates the tab already viewing path, if any.
+func (m *Model) jumpToOpenTab(path string) bool {
+	i := m.findOpenTab(path)
+	if i < 0 {
+		return false
+	}
+	m.selectTab(i)
+	m.view = ViewMain
+	m.setStatus(sevInfo, fmt.Sprintf("%s is already open", filepath.Base(path)))
+	return true
+}
+
+// duplicateView opens a second tab on the current buffer.
+func (m *Model) duplicateView() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	dup := m.newTab(tab.Buffer)
+	dup.Cursor = tab.Cursor
+	dup.ScrollY = tab.ScrollY
+	dup.UTF8Text = tab.UTF8Text
+	dup.GroupWidth = tab.GroupWidth
+	dup.Bitfield = tab.Bitfield
+	dup.CopyOf = tab.CopyOf
+	m.tabs = append(m.tabs[:m.activeTab+1], append([]*Tab{dup}, m.tabs[m.activeTab+1:]...)...)
+	m.selectTab(m.activeTab + 1)
+	m.setStatus(sevInfo, fmt.Sprintf("%d views of this buffer", m.viewCount(dup)))
+}
+
+// duplicateBuffer opens a new tab on an unsaved copy of the current
+// buffer's contents, with its own undo history.
+func (m *Model) duplicateBuffer() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	dup := m.newTab(tab.Buffer.Copy())
+	dup.Cursor = tab.Cursor
+	dup.ScrollY = tab.ScrollY
+	dup.UTF8Text = tab.UTF8Text
+	dup.GroupWidth = tab.GroupWidth
+	dup.Bitfield = tab.Bitfield
+	dup.CopyOf = tab.Buffer.Filename()
+	if dup.CopyOf == "" {
+		dup.CopyOf = tab.CopyOf
+	}
+	m.tabs = append(m.tabs[:m.activeTab+1], append([]*Tab{dup}, m.tabs[m.activeTab+1:]...)...)
+	m.selectTab(m.activeTab + 1)
+	m.setStatus(sevInfo, "Duplicated into a new buffer; save it with Save As")
+}
+
+const maxTabLabel = 24
+
+// tabLabels names every tab by its file's basename. Files sharing a
+// basename get as many parent directories as it takes to tell them apart,
+// e.g. "a/config.bin" and "b/config.bin".
+func (m *Model) tabLabels() []string {
+	labels := make([]string, len(m.tabs))
+	parts := make([][]string, len(m.tabs))
+	byBase := make(map[string][]int)
+	for i, tab := range m.tabs {
+		name := tab.Buffer.Filename()
+		if name == "" {
+			labels[i] = "[New File]"
+			if tab.CopyOf != "" {
+				labels[i] = "copy of " + filepath.Base(tab.CopyOf)
+			}
+			continue
+		}
+		if abs, err := filepath.Abs(name); err == nil {
+			name = abs
+		}
+		parts[i] = strings.Split(filepath.ToSlash(name), "/")
+		base := filepath.Base(name)
+		labels[i] = base
+		byBase[base] = append(byBase[base], i)
+	}
+
+	for _, group := range byBase {
+		for _, i := range group {
+			for n := 1; n <= len(parts[i]); n++ {
+				suffix := pathSuffix(parts[i], n)
+				unique := true
+				for _, j := range group {
+					if m.tabs[j].Buffer != m.tabs[i].Buffer && pathSuffix(parts[j], n) == suffix {
+						unique = false
+						break
+					}
+				}
+				if unique {
+					if n > 1 {
+						labels[i] = suffix
+					}
+					break
+				}
+			}
+		}
+	}
+
+	for i := range labels {
+		if w, ok := m.tabs[i].Buffer.Window(); ok {
+			labels[i] += fmt.Sprintf("@0x%X", w.Offset)
+		}
+		labels[i] = truncateMiddle(labels[i], maxTabLabel)
+	}
+	return labels
+}
+
+func pathSuffix(parts []string, n int) string {
+	if n > len(parts) {
+		n = len(parts)
+	}
+	return strings.Join(parts[len(parts)-n:], "/")
+}
+
+// truncateMiddle shortens s to at most width runes by replacing its middle
+// with an ellipsis, keeping both the leading directory and the extension.
+func truncateMiddle(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(r[:head]) + "…" + string(r[len(r)-tail:])
+}
+
+const tabSeparator = " | "
+
+func (m *Model) tabWidths(labels []string) []int {
+	widths := make([]int, len(labels))
+	for i, label := range labels {
+		widths[i] = runewidth.StringWidth(label)
+		if m.tabs[i].Buffer.IsModified() {
+			widths[i]++ // "*"
+		}
+	}
+	return widths
+}
+
+func moreLeft(n int) string  { return fmt.Sprintf("‹ %d more", n) }
+func moreRight(n int) string { return fmt.Sprintf("%d more ›", n) }
+
+// lastVisibleTab returns the last tab that fits in the strip when it starts
+// at first, leaving room for the overflow indicators. The first tab is
+// always shown, even when it alone is too wide.
+func lastVisibleTab(widths []int, first, width int) int {
+	used := 0
+	if first > 0 {
+		used = runewidth.StringWidth(moreLeft(first)) + len(tabSeparator)
+	}
+	last := first
+	for i := first; i < len(widths); i++ {
+		if i > first {
+			used += len(tabSeparator)
+		}
+		used += widths[i]
+		need := used
+		if rest := len(widths) - 1 - i; rest > 0 {
+			need += len(tabSeparator) + runewidth.StringWidth(moreRight(rest))
+		}
+		if need > width && i > first {
+			break
+		}
+		last = i
+	}
+	return last
+}
+
+// tabStripStart returns the first tab to draw: the scroll position,
+// advanced if needed so the active tab fits.
+func (m *Model) tabStripStart(widths []int) int {
+	first := max(min(m.tabScroll, m.activeTab), 0)
+	for first < m.activeTab && lastVisibleTab(widths, first, m.width) < m.activeTab {
+		first++
+	}
+	return first
+}
+
+// scrollTabs moves the tab strip so the active tab is on screen. It only
+// scrolls as far as needed, so switching between visible tabs keeps the
+// strip still.
+func (m *Model) scrollTabs() {
+	if m.width <= 0 {
+		m.tabScroll = min(m.tabScroll, max(m.activeTab, 0))
+		return
+	}
+	widths := m.tabWidths(m.tabLabels())
+	m.tabScroll = m.tabStripStart(widths)
+	// After closing tabs, pull earlier ones back in if everything fits
+	for m.tabScroll > 0 && lastVisibleTab(widths, m.tabScroll-1, m.width) == len(widths)-1 {
+		m.tabScroll--
+	}
+}
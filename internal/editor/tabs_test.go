@@ -0,0 +1,179 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestOpenFileJumpsToExistingTab(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.bin")
+	if err := os.WriteFile(path, []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(nil)
+	if err := m.openFile(path); err != nil {
+		t.Fatal(err)
+	}
+	m.selectTab(0)
+
+	// A different spelling of the same path still matches
+	if err := m.openFile(filepath.Join(dir, ".", "a.bin")); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.tabs) != 2 {
+		t.Fatalf("expected 2 tabs, got %d", len(m.tabs))
+	}
+	if m.activeTab != 1 {
+		t.Errorf("expected to jump to tab 1, active is %d", m.activeTab)
+	}
+}
+
+func TestDuplicateViewSharesBuffer(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0x11, 0x22, 0x33})
+	first := m.currentTab()
+	first.Cursor = 3
+
+	typeKeys(m, "v")
+	second := m.currentTab()
+	if len(m.tabs) != 2 || second == first || second.Buffer != first.Buffer {
+		t.Fatal("expected a second tab on the same buffer")
+	}
+
+	// Edits through one view are visible in the other
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlEnd})
+	m.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	m.selectTab(0)
+	if first.Buffer.Size() != 3 {
+		t.Errorf("expected shared size 3, got %d", first.Buffer.Size())
+	}
+
+	// Closing one view of a modified buffer doesn't prompt
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if m.view != ViewMain || len(m.tabs) != 1 {
+		t.Fatalf("expected the view to close without a prompt, view %v, %d tabs", m.view, len(m.tabs))
+	}
+	if m.currentTab().Cursor > m.currentTab().Buffer.Size()-1 {
+		t.Errorf("cursor %d past the end of the shared buffer", m.currentTab().Cursor)
+	}
+
+	// The last view does
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if m.view != ViewDialog {
+		t.Errorf("expected a save prompt for the last view, got view %v", m.view)
+	}
+}
+
+func TestTabLabelsDisambiguateBasenames(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, p := range []string{"a/config.bin", "b/config.bin", "b/other.bin", "x/sub/data.bin", "y/sub/data.bin"} {
+		path := filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	m := newTestModel(nil)
+	m.tabs = nil
+	for _, p := range paths {
+		if err := m.openFile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.selectTab(0)
+	m.duplicateView()
+
+	want := []string{"a/config.bin", "a/config.bin", "b/config.bin", "other.bin", "x/sub/data.bin", "y/sub/data.bin"}
+	got := m.tabLabels()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tab %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	// Closing one of the pair drops the extra directory from the other
+	m.selectTab(2)
+	m.closeCurrentTab()
+	if got := m.tabLabels()[0]; got != "config.bin" {
+		t.Errorf("expected plain basename after close, got %q", got)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	if got := truncateMiddle("short.bin", 24); got != "short.bin" {
+		t.Errorf("short label changed to %q", got)
+	}
+	got := truncateMiddle("a_very_long_firmware_image_name.bin", 15)
+	if got != "a_very_…ame.bin" {
+		t.Errorf("unexpected truncation %q", got)
+	}
+}
+
+func TestTabStripScrollsToActiveTab(t *testing.T) {
+	m := newTestModel(nil)
+	for i := 0; i < 11; i++ {
+		m.newFile()
+	}
+	m.Update(tea.WindowSizeMsg{Width: minEditorWidth(), Height: 30})
+
+	strip := m.renderTabs()
+	if w := lipgloss.Width(strip); w > m.width {
+		t.Fatalf("tab strip is %d columns wide, terminal has %d", w, m.width)
+	}
+	if !strings.HasPrefix(strip, "‹ ") {
+		t.Errorf("expected a left overflow indicator with the last tab active, got %q", strip)
+	}
+
+	m.selectTab(0)
+	strip = m.renderTabs()
+	if !strings.HasPrefix(strip, "*[New File]") || !strings.HasSuffix(strip, "more ›") {
+		t.Errorf("expected the strip to scroll back to the first tab, got %q", strip)
+	}
+
+	// Moving between tabs that are already visible keeps the strip still
+	m.nextTab()
+	if m.tabScroll != 0 {
+		t.Errorf("strip scrolled to %d for a visible tab", m.tabScroll)
+	}
+}
+
+func TestDuplicateBuffer(t *testing.T) {
+	m := newTestModel(nil)
+	m.tabs = []*Tab{m.newTab(buffer.FromData("/fw/firmware.bin", []byte{1, 2, 3}))}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+
+	if len(m.tabs) != 2 || m.activeTab != 1 {
+		t.Fatalf("expected a second, active tab, got %d tabs", len(m.tabs))
+	}
+	src, dup := m.tabs[0].Buffer, m.tabs[1].Buffer
+	if dup == src || !dup.IsNew() {
+		t.Fatal("expected a separate new buffer")
+	}
+	if got := m.tabLabels()[1]; got != "copy of firmware.bin" {
+		t.Errorf("got label %q", got)
+	}
+
+	typeKeys(m, "rff")
+	if src.IsModified() || src.GetBytes(0, 1)[0] != 1 || dup.GetBytes(0, 1)[0] != 0xFF {
+		t.Error("edits to the copy should leave the original alone")
+	}
+
+	// A view of the copy keeps its label
+	m.duplicateView()
+	if got := m.tabLabels()[2]; got != "copy of firmware.bin" {
+		t.Errorf("got label %q for a second view", got)
+	}
+}
@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/bitfield"
+	"github.com/protohuf/unhexed/internal/config"
+)
+
+// unhexed --template NAME --offset N opens with the first template of
+// NAME applied at offset N and the bitfield view open on its first field,
+// so the editor works as a one-shot inspector from scripts and aliases.
+// NAME is a file in the templates directory, with or without .toml, or a
+// path. A template that can't be applied is reported and the file opens
+// as usual.
+
+// findTemplateFile returns the path of the template file name.
+func findTemplateFile(name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	candidates := []string{filepath.Join(config.TemplateDir(), name)}
+	if filepath.Ext(name) == "" {
+		candidates = append(candidates, candidates[0]+".toml")
+	}
+	candidates = append(candidates, name)
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("template %s not found in %s", name, config.TemplateDir())
+}
+
+// openAtTemplate moves the current tab to offset and applies the first
+// template of the file name there, if name is set.
+func (m *Model) openAtTemplate(name string, offset int64) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	m.setCursor(offset)
+	if tab.Cursor != offset {
+		m.setStatus(sevWarning, fmt.Sprintf("Offset 0x%X is past the end of the file (0x%X bytes)", offset, tab.Buffer.Size()))
+		return
+	}
+	if name == "" {
+		return
+	}
+
+	path, err := findTemplateFile(name)
+	if err != nil {
+		m.setStatus(sevError, "Template: "+err.Error())
+		return
+	}
+	templates, err := bitfield.LoadFile(path)
+	if len(templates) == 0 {
+		if err == nil {
+			err = fmt.Errorf("%s has no bitfield templates", filepath.Base(path))
+		}
+		m.setStatus(sevError, "Template: "+strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	}
+	m.addBitfields(templates)
+	tab.Bitfield = templates[0]
+	if err != nil {
+		m.setStatus(sevWarning, "Template: "+strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+
+	t := tab.Bitfield
+	if _, ok := m.bitfieldWord(tab); !ok {
+		m.setStatus(sevWarning, fmt.Sprintf("%s needs %d bytes at 0x%X, past the end of the file", t.Name, t.Size, offset))
+		return
+	}
+	m.bitfieldField = 0
+	m.bitfieldInput = ""
+	m.view = ViewBitfield
+}
+
+// addBitfields adds templates to the loaded ones, replacing those of the
+// same name.
+func (m *Model) addBitfields(templates []*bitfield.Template) {
+	for _, t := range templates {
+		i := slices.IndexFunc(m.bitfields, func(b *bitfield.Template) bool { return b.Name == t.Name })
+		if i < 0 {
+			m.bitfields = append(m.bitfields, t)
+		} else {
+			m.bitfields[i] = t
+		}
+	}
+}
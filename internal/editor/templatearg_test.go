@@ -0,0 +1,72 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/config"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "header.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpenAtTemplate(t *testing.T) {
+	tmpl := writeTemplate(t, `
+[[bitfield]]
+name = "header"
+size = 2
+fields = "bits 8-15: major, bits 0-7: minor"
+`)
+	m, err := NewModel([]string{writeTestFile(t, "\x00\x00\x03\x07\x00")},
+		Options{Config: config.DefaultConfig(), Clock: &testClock{}, Template: tmpl, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab := m.currentTab()
+	if tab.Cursor != 2 || tab.Bitfield == nil || tab.Bitfield.Name != "header" {
+		t.Fatalf("cursor 0x%X, template %v", tab.Cursor, tab.Bitfield)
+	}
+	if m.view != ViewBitfield || m.bitfieldField != 0 {
+		t.Errorf("expected the bitfield view on the first field, view %v", m.view)
+	}
+	if view := m.renderBitfield(); !strings.Contains(view, "> major") || !strings.Contains(view, "3 (0x3)") {
+		t.Errorf("got\n%s", view)
+	}
+}
+
+func TestOpenAtTemplateReportsErrors(t *testing.T) {
+	fits := writeTemplate(t, `
+[[bitfield]]
+name = "word"
+size = 4
+fields = "bits 0-31: all"
+`)
+	tests := []struct {
+		template string
+		offset   int64
+		status   string
+	}{
+		{fits, 2, "word needs 4 bytes at 0x2, past the end of the file"},
+		{fits, 9, "Offset 0x9 is past the end of the file"},
+		{writeTemplate(t, "[[bitfield]\n"), 0, "Template: header.toml:"},
+		{"no-such-template", 0, "Template: template no-such-template not found"},
+	}
+	for _, tt := range tests {
+		m, err := NewModel([]string{writeTestFile(t, "abcde")},
+			Options{Config: config.DefaultConfig(), Clock: &testClock{}, Template: tt.template, Offset: tt.offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.view != ViewMain || len(m.tabs) != 1 || !strings.HasPrefix(m.status.text, tt.status) {
+			t.Errorf("%s at %d: view %v, status %q", tt.template, tt.offset, m.view, m.status.text)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type textRegionMsg struct {
+	seq     int
+	tab     *Tab
+	version uint64 // of the tab's buffer when scanned
+	from    int64
+	forward bool
+	start   int64
+	length  int64
+}
+
+// isPrintable reports whether b belongs in a text region: printable ASCII
+// and tab, the same bytes the strings tool counts.
+func isPrintable(b byte) bool {
+	return (b >= 0x20 && b < 0x7F) || b == '\t'
+}
+
+// textRegionMin returns the configured minimum text region length.
+func (m *Model) textRegionMin() int64 {
+	return max(int64(m.config.Behavior.TextRegionMin), 1)
+}
+
+// jumpTextRegion moves the cursor to the first byte of the next or previous
+// run of printable bytes, scanning a snapshot in the background.
+func (m *Model) jumpTextRegion(forward bool) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	m.textRegionSeq++
+	if m.textRegionCancel != nil {
+		m.textRegionCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.textRegionCancel = cancel
+
+	snapshot := tab.Buffer.Snapshot()
+	seq, from, minLen := m.textRegionSeq, tab.Cursor, m.textRegionMin()
+	return func() tea.Msg {
+		start, length, err := snapshot.FindSpanContext(ctx, from, minLen, isPrintable, forward)
+		if err != nil {
+			return nil
+		}
+		return textRegionMsg{seq: seq, tab: tab, version: snapshot.Version(), from: from, forward: forward, start: start, length: length}
+	}
+}
+
+func (m *Model) handleTextRegion(msg textRegionMsg) (tea.Model, tea.Cmd) {
+	tab := msg.tab
+	if msg.seq != m.textRegionSeq || tab != m.currentTab() || tab.Cursor != msg.from {
+		// Superseded, or the cursor moved on meanwhile
+		return m, nil
+	}
+	if tab.Buffer.Version() != msg.version {
+		// The buffer changed during the scan; the offsets may be wrong
+		return m, m.jumpTextRegion(msg.forward)
+	}
+	m.textRegionCancel = nil
+
+	if msg.start < 0 {
+		where := "after"
+		if !msg.forward {
+			where = "before"
+		}
+		m.setStatus(sevInfo, fmt.Sprintf("No text region of ≥%d bytes %s the cursor", m.textRegionMin(), where))
+		return m, nil
+	}
+	m.setCursor(msg.start)
+	m.setStatus(sevInfo, fmt.Sprintf("Text region 0x%X–0x%X (%d bytes)", msg.start, msg.start+msg.length-1, msg.length))
+	return m, nil
+}
+
+// classChange is where bytes switch between printable and not.
+func classChange(prev, cur byte) bool {
+	return isPrintable(prev) != isPrintable(cur)
+}
+
+// stringStart is the first byte of a null-terminated string.
+func stringStart(prev, cur byte) bool {
+	return prev == 0 && isPrintable(cur)
+}
+
+// jumpBoundary moves the cursor, or extends the selection, to the next or
+// previous offset where at holds for the byte before it and the byte at
+// it. Without one it stops at the end or start of the file.
+func (m *Model) jumpBoundary(at func(prev, cur byte) bool, forward, extend bool) {
+	tab := m.currentTab()
+	if tab == nil || tab.Buffer.Size() == 0 {
+		return
+	}
+	pos, _ := tab.Buffer.FindBoundary(context.Background(), tab.Cursor, at, forward)
+	if pos < 0 {
+		pos = 0
+		if forward {
+			pos = tab.Buffer.Size() - 1
+		}
+	}
+	if extend {
+		m.selectTo(pos)
+	} else {
+		m.setCursor(pos)
+	}
+}
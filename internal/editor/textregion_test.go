@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jumpTextRegion jumps as ) or ( does, waiting for the scan.
+func jumpTextRegion(m *Model, forward bool) {
+	m.Update(m.jumpTextRegion(forward)())
+}
+
+func TestJumpTextRegion(t *testing.T) {
+	m := newTestModel([]byte("\x00\x00short\x00\x00a longer string\x00\x01"))
+	m.config.Behavior.TextRegionMin = 8
+	tab := m.currentTab()
+
+	jumpTextRegion(m, true)
+	if tab.Cursor != 9 {
+		t.Fatalf("expected the long region at 9, got %d", tab.Cursor)
+	}
+	if m.status.text != "Text region 0x9–0x17 (15 bytes)" {
+		t.Errorf("unexpected status %q", m.status.text)
+	}
+
+	m.config.Behavior.TextRegionMin = 4
+	jumpTextRegion(m, false)
+	if tab.Cursor != 2 {
+		t.Errorf("expected the short region at 2 with a lower threshold, got %d", tab.Cursor)
+	}
+	jumpTextRegion(m, false)
+	if tab.Cursor != 2 || !strings.Contains(m.status.text, "No text region") {
+		t.Errorf("expected to stay put with a note, got %d %q", tab.Cursor, m.status.text)
+	}
+}
+
+func TestJumpTextRegionDropsStaleScans(t *testing.T) {
+	m := newTestModel([]byte("\x00\x00a longer string\x00\x00another string"))
+	m.config.Behavior.TextRegionMin = 4
+	tab := m.currentTab()
+
+	// The cursor moving on before the scan ends drops it
+	scan := m.jumpTextRegion(true)
+	m.setCursor(1)
+	m.Update(scan())
+	if tab.Cursor != 1 {
+		t.Fatalf("expected a stale jump to be dropped, cursor at %d", tab.Cursor)
+	}
+
+	// An edit during the scan scans again
+	scan = m.jumpTextRegion(true)
+	tab.Buffer.Insert(tab.Buffer.Size(), []byte{0})
+	_, rescan := m.handleTextRegion(scan().(textRegionMsg))
+	if rescan == nil {
+		t.Fatal("expected the edit to start another scan")
+	}
+	m.Update(rescan())
+	if tab.Cursor != 2 {
+		t.Errorf("expected the rescan to land on the region at 2, got %d", tab.Cursor)
+	}
+}
+
+func TestBoundaryMotions(t *testing.T) {
+	// "ab" isn't null-terminated; "cd" and "ef" are strings
+	m := newTestModel([]byte("ab\x01\x00cd\x00\x00ef\x00"))
+	tab := m.currentTab()
+
+	steps := []struct {
+		key  tea.KeyType
+		want int64
+	}{
+		{tea.KeyCtrlRight, 2},
+		{tea.KeyCtrlRight, 4},
+		{tea.KeyCtrlLeft, 2},
+		{tea.KeyCtrlLeft, 0},
+		{tea.KeyCtrlDown, 4},
+		{tea.KeyCtrlDown, 8},
+		{tea.KeyCtrlDown, 10}, // no further string: end of file
+		{tea.KeyCtrlUp, 8},
+	}
+	for i, s := range steps {
+		m.Update(tea.KeyMsg{Type: s.key})
+		if tab.Cursor != s.want {
+			t.Fatalf("step %d (%v): expected %d, got %d", i, s.key, s.want, tab.Cursor)
+		}
+	}
+
+	m.setCursor(4)
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlShiftRight})
+	if !tab.Selection.Active || tab.Selection.Start != 4 || tab.Selection.End != 6 {
+		t.Errorf("expected Shift to select 4-6, got %+v", tab.Selection)
+	}
+}
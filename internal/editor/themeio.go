@@ -0,0 +1,128 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The Config view can export the theme being edited, import a shared theme
+// under a name, and write the commented default config. Each asks for a
+// path on the view's bottom line first.
+
+type configPrompt int
+
+const (
+	promptNone configPrompt = iota
+	promptExportTheme
+	promptImportTheme
+	promptImportName
+	promptDefaultConfig
+)
+
+var configPromptLabels = map[configPrompt]string{
+	promptExportTheme:   "Export theme to: ",
+	promptImportTheme:   "Import theme from: ",
+	promptImportName:    "Save the theme as: ",
+	promptDefaultConfig: "Write default config to: ",
+}
+
+// startConfigPrompt handles the Config view's commands, reporting whether
+// key was one.
+func (m *Model) startConfigPrompt(key string) bool {
+	switch key {
+	case "ctrl+e":
+		m.configPrompt, m.configPromptInput = promptExportTheme, "theme.toml"
+	case "ctrl+t":
+		m.configPrompt, m.configPromptInput = promptImportTheme, ""
+	case "ctrl+d":
+		m.configPrompt, m.configPromptInput = promptDefaultConfig, "unhexed.default.toml"
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Model) handleConfigPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.configPrompt = promptNone
+	case tea.KeyEnter:
+		m.runConfigPrompt(strings.TrimSpace(m.configPromptInput))
+	case tea.KeyBackspace:
+		if len(m.configPromptInput) > 0 {
+			m.configPromptInput = m.configPromptInput[:len(m.configPromptInput)-1]
+		}
+	case tea.KeyCtrlU:
+		m.configPromptInput = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.configPromptInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m *Model) runConfigPrompt(input string) {
+	if input == "" {
+		return
+	}
+	prompt := m.configPrompt
+	m.configPrompt = promptNone
+	switch prompt {
+	case promptExportTheme:
+		if err := config.ExportTheme(m.themeFromInputs(), input); err != nil {
+			m.setStatus(sevError, "Error exporting theme: "+err.Error())
+			return
+		}
+		m.setStatus(sevInfo, "Exported theme to "+input)
+	case promptDefaultConfig:
+		if err := config.WriteDefault(input); err != nil {
+			m.setStatus(sevError, "Error writing default config: "+err.Error())
+			return
+		}
+		m.setStatus(sevInfo, "Wrote default config to "+input)
+	case promptImportTheme:
+		if _, err := config.LoadTheme(input); err != nil {
+			m.setStatus(sevError, "Error importing theme: "+err.Error())
+			return
+		}
+		m.importPath = input
+		m.configPrompt = promptImportName
+		m.configPromptInput = strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	case promptImportName:
+		m.importTheme(m.importPath, input)
+	}
+}
+
+// importTheme adds the theme at path to the config as name, makes it the
+// current theme and saves the config. Unsaved edits in the view are
+// replaced by the imported colors.
+func (m *Model) importTheme(path, name string) {
+	theme, err := config.LoadTheme(path)
+	if err != nil {
+		m.setStatus(sevError, "Error importing theme: "+err.Error())
+		return
+	}
+	m.config.ImportTheme(name, theme)
+	if err := m.config.Save(); err != nil {
+		m.setStatus(sevError, "Error saving config: "+err.Error())
+		return
+	}
+	m.configMtime = configModTime()
+	m.buildStyles()
+	index := m.configIndex
+	m.loadConfigInputs()
+	m.configIndex = index
+	m.setStatus(sevInfo, fmt.Sprintf("Imported theme %q", name))
+}
+
+func (m *Model) renderConfigPrompt() string {
+	if m.configPrompt == promptNone {
+		return "\nCtrl+E export theme, Ctrl+T import theme, Ctrl+D write default config\n"
+	}
+	return "\n" + configPromptLabels[m.configPrompt] + m.configPromptInput + "_\n" +
+		"Press Enter to confirm, ESC to cancel\n"
+}
@@ -0,0 +1,59 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/protohuf/unhexed/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typePath(m *Model, path string) {
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(path)})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestConfigViewExportsAndImportsThemes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	m := newTestModel(nil)
+
+	typeKeys(m, "c")
+	m.configInputs["background"] = "#202020"
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	typePath(m, filepath.Join(dir, "mine.toml"))
+	exported, err := config.LoadTheme(filepath.Join(dir, "mine.toml"))
+	if err != nil || exported.Background != "#202020" {
+		t.Fatalf("export should include unsaved edits, got %q, %v (status %q)", exported.Background, err, m.status.text)
+	}
+
+	shared := filepath.Join(dir, "shared.toml")
+	if err := os.WriteFile(shared, []byte("[theme]\nactive_tab = \"#00FF00\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	typePath(m, shared)
+	if m.configPrompt != promptImportName || m.configPromptInput != "shared" {
+		t.Fatalf("expected a name prompt defaulting to the file name, got %v %q", m.configPrompt, m.configPromptInput)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.config.Theme.ActiveTab != "#00FF00" || m.config.Theme.Background != config.DefaultConfig().Theme.Background {
+		t.Errorf("imported theme not applied: %+v", m.config.Theme)
+	}
+	saved, err := config.Load()
+	if err != nil || saved.Themes["shared"] != m.config.Theme {
+		t.Errorf("imported theme should be saved by name, got %+v, %v", saved.Themes, err)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	typePath(m, filepath.Join(dir, "default.toml"))
+	if _, err := os.Stat(filepath.Join(dir, "default.toml")); err != nil {
+		t.Errorf("default config not written: %v", err)
+	}
+	if m.view != ViewConfig {
+		t.Error("commands should stay in the Config view")
+	}
+}
@@ -0,0 +1,88 @@
+package editor
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// The decoder panel guesses whether the bytes at the cursor hold a
+// timestamp by trying common encodings and keeping those that land in a
+// plausible range. Random bytes often pass for a u32 Unix time, so wider
+// encodings, which rarely match by chance, are listed first.
+
+var (
+	plausibleFrom  = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	plausibleUntil = time.Date(2041, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// filetimeToUnix is the number of seconds from 1601, where FILETIME's
+// 100ns ticks start, to the Unix epoch.
+const filetimeToUnix = 11644473600
+
+type timeGuess struct {
+	label string
+	t     time.Time
+	local bool // no time zone is recorded, as in DOS times
+}
+
+func (g timeGuess) String() string {
+	if g.local {
+		return g.t.Format("2006-01-02 15:04:05") + " (" + g.label + ")"
+	}
+	return g.t.Format("2006-01-02 15:04:05") + " UTC (" + g.label + ")"
+}
+
+// guessTimes returns the plausible timestamps encoded at the start of b,
+// read with order, best first. A time found by more than one encoding is
+// listed once.
+func guessTimes(b []byte, order binary.ByteOrder) []timeGuess {
+	var guesses []timeGuess
+	add := func(label string, t time.Time, ok bool, local bool) {
+		if !ok || t.Before(plausibleFrom) || !t.Before(plausibleUntil) {
+			return
+		}
+		for _, g := range guesses {
+			if g.t.Equal(t) {
+				return
+			}
+		}
+		guesses = append(guesses, timeGuess{label: label, t: t, local: local})
+	}
+
+	if len(b) >= 8 {
+		v := order.Uint64(b)
+		add("FILETIME", filetime(v), true, false)
+		add("u64 Unix ms", time.UnixMilli(int64(v)).UTC(), v < 1<<62, false)
+		add("u64 Unix s", time.Unix(int64(v), 0).UTC(), v < 1<<40, false)
+	}
+	if len(b) >= 4 {
+		v := order.Uint32(b)
+		add("u32 Unix s", time.Unix(int64(v), 0).UTC(), true, false)
+		t, ok := dosTime(v)
+		add("DOS", t, ok, true)
+	}
+	return guesses
+}
+
+func filetime(ticks uint64) time.Time {
+	return time.Unix(int64(ticks/1e7)-filetimeToUnix, int64(ticks%1e7)*100).UTC()
+}
+
+// dosTime decodes an MS-DOS date and time packed as date<<16 | time, as
+// FAT and ZIP store them.
+func dosTime(v uint32) (time.Time, bool) {
+	date, clock := v>>16, v&0xFFFF
+	year := int(date>>9) + 1980
+	month := time.Month(date >> 5 & 0xF)
+	day := int(date & 0x1F)
+	hour, minute, sec := int(clock>>11), int(clock>>5&0x3F), int(clock&0x1F)*2
+	if month < 1 || month > 12 || day < 1 || hour > 23 || minute > 59 || sec > 59 {
+		return time.Time{}, false
+	}
+	t := time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
+	if t.Day() != day {
+		// Normalized past the end of the month, e.g. February 30th
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,58 @@
+package editor
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuessTimes(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 34, 56, 0, time.UTC)
+	le, be := binary.LittleEndian, binary.BigEndian
+
+	dos := uint32(2024-1980)<<25 | 3<<21 | 1<<16 | 12<<11 | 34<<5 | 56/2
+	filetime := uint64(ref.Unix()+filetimeToUnix) * 1e7
+
+	tests := []struct {
+		name  string
+		data  []byte
+		order binary.ByteOrder
+		label string
+	}{
+		{"u32 seconds", le.AppendUint32(nil, uint32(ref.Unix())), le, "u32 Unix s"},
+		{"u32 seconds big endian", be.AppendUint32(nil, uint32(ref.Unix())), be, "u32 Unix s"},
+		{"u64 seconds", le.AppendUint64(nil, uint64(ref.Unix())), le, "u64 Unix s"},
+		{"u64 millis", le.AppendUint64(nil, uint64(ref.UnixMilli())), le, "u64 Unix ms"},
+		{"FILETIME", le.AppendUint64(nil, filetime), le, "FILETIME"},
+		{"DOS", le.AppendUint32(nil, dos), le, "DOS"},
+	}
+	for _, tt := range tests {
+		found := false
+		for _, g := range guessTimes(tt.data, tt.order) {
+			found = found || g.label == tt.label && g.t.Equal(ref)
+		}
+		if !found {
+			t.Errorf("%s: guesses %v", tt.name, guessTimes(tt.data, tt.order))
+		}
+	}
+
+	for _, data := range [][]byte{make([]byte, 8), {0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, {1, 2}} {
+		if guesses := guessTimes(data, le); len(guesses) != 0 {
+			t.Errorf("% X: implausible guesses %v", data, guesses)
+		}
+	}
+}
+
+func TestDecoderShowsTimesOnlyWhenFound(t *testing.T) {
+	m := newTestModel(make([]byte, 16))
+	if strings.Contains(m.renderDecoder(), "time:") {
+		t.Error("zeros should not show a time")
+	}
+
+	ms := time.Date(2024, 3, 1, 12, 34, 56, 0, time.UTC).UnixMilli()
+	m = newTestModel(binary.BigEndian.AppendUint64(nil, uint64(ms)))
+	if panel := m.renderDecoder(); !strings.Contains(panel, "2024-03-01 12:34:56 UTC (u64 Unix ms)") {
+		t.Errorf("panel should show the timestamp:\n%s", panel)
+	}
+}
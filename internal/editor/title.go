@@ -0,0 +1,35 @@
+package editor
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// TitleEnabled reports whether the editor sets the terminal title, so the
+// caller can save the previous one and restore it on exit.
+func (m *Model) TitleEnabled() bool {
+	return m.config.Behavior.SetTitle
+}
+
+func (m *Model) windowTitle() string {
+	tab := m.currentTab()
+	if tab == nil {
+		return "unhexed"
+	}
+	title := "unhexed — " + m.tabLabels()[m.activeTab]
+	if tab.Buffer.IsModified() {
+		title += " +"
+	}
+	return title
+}
+
+// titleCmd updates the terminal title when the active file or its modified
+// state changed since the last update.
+func (m *Model) titleCmd() tea.Cmd {
+	if !m.TitleEnabled() {
+		return nil
+	}
+	title := m.windowTitle()
+	if title == m.title {
+		return nil
+	}
+	m.title = title
+	return tea.SetWindowTitle(title)
+}
@@ -0,0 +1,35 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWindowTitleTracksModifiedState(t *testing.T) {
+	m := newTestModel([]byte{0x00})
+	m.currentTab().Buffer.SetFilename("dump.bin")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if cmd == nil || m.title != "unhexed — dump.bin +" {
+		t.Fatalf("expected a title update, got %q", m.title)
+	}
+
+	// Unchanged title: nothing to send
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyLeft}); cmd != nil {
+		t.Error("title resent although it did not change")
+	}
+
+	m.newFile()
+	m.currentTab().Buffer.SetFilename("other.bin")
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.title != "unhexed — other.bin" {
+		t.Errorf("expected the new tab's title, got %q", m.title)
+	}
+
+	m.config.Behavior.SetTitle = false
+	m.prevTab()
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight}); cmd != nil {
+		t.Error("title sent while disabled")
+	}
+}
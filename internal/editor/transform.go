@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Byte transforms rewrite every selected byte through a function, or the
+// byte at the cursor when nothing is selected. A column selection is
+// transformed row by row; either way the result is one undo step.
+
+func swapNibbles(b byte) byte {
+	return b<<4 | b>>4
+}
+
+func (m *Model) transformSelection(label string, fn func(byte) byte) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil || m.editBlocked(tab) {
+		return nil
+	}
+
+	if !tab.Selection.Active {
+		m.refused(strings.ToLower(label), tab.Buffer.Transform(tab.Cursor, 1, fn))
+		return nil
+	}
+
+	if tab.Selection.Block {
+		return m.runEdit(m.blockTransformJob(tab, label, fn))
+	}
+
+	start, end := m.getSelectedRange()
+	end = min(end, tab.Buffer.Size()-1)
+	if end < start {
+		return nil
+	}
+	job := &editJob{
+		tab:    tab,
+		label:  label,
+		offset: start,
+		total:  end - start + 1,
+	}
+	job.step = func(done int64) int64 {
+		n := chunkLen(done, job.total)
+		if job.refused(tab.Buffer.Transform(start+done, n, fn)) {
+			return 0
+		}
+		return n
+	}
+	return m.runEdit(job)
+}
+
+func (m *Model) blockTransformJob(tab *Tab, label string, fn func(byte) byte) *editJob {
+	rowStart, rowEnd, colStart, colEnd := m.blockRect(tab)
+	width := colEnd - colStart + 1
+
+	job := &editJob{
+		tab:    tab,
+		label:  label + " (column)",
+		offset: rowStart*bytesPerRow + colStart,
+		total:  (rowEnd - rowStart + 1) * width,
+	}
+	job.step = func(done int64) int64 {
+		rows := max(int64(jobChunk)/width, 1)
+		first := rowStart + done/width
+		var n int64
+		for row := first; row < first+rows && row <= rowEnd; row++ {
+			if job.refused(tab.Buffer.Transform(row*bytesPerRow+colStart, width, fn)) {
+				return 0
+			}
+			n += width
+		}
+		return n
+	}
+	return job
+}
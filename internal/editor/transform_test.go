@@ -0,0 +1,26 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSwapNibbles(t *testing.T) {
+	m := newTestModel([]byte{0x4A, 0x12, 0x34, 0x56})
+	tab := m.currentTab()
+
+	typeKeys(m, "~")
+	if got := tab.Buffer.GetBytes(0, 4); !bytes.Equal(got, []byte{0xA4, 0x12, 0x34, 0x56}) {
+		t.Fatalf("expected the cursor byte swapped, got % X", got)
+	}
+
+	tab.Selection.Active, tab.Selection.Start, tab.Selection.End = true, 1, 3
+	typeKeys(m, "~")
+	if got := tab.Buffer.GetBytes(0, 4); !bytes.Equal(got, []byte{0xA4, 0x21, 0x43, 0x65}) {
+		t.Fatalf("expected the selection swapped, got % X", got)
+	}
+	typeKeys(m, "u")
+	if got := tab.Buffer.GetBytes(0, 4); !bytes.Equal(got, []byte{0xA4, 0x12, 0x34, 0x56}) {
+		t.Errorf("one undo should restore the whole selection, got % X", got)
+	}
+}
@@ -0,0 +1,156 @@
+package editor
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Undo and redo go to the bytes they changed: the cursor lands on the
+// start of the step (the first byte of a group) unless undo brings back
+// the selection the edit was made with, and the changed bytes flash
+// briefly.
+
+const flashDuration = 600 * time.Millisecond
+
+type flash struct {
+	tab        *Tab
+	start, end int64 // inclusive
+}
+
+type flashExpireMsg struct {
+	seq int
+}
+
+// viewState is the cursor and selection an edit was made with, which undo
+// brings back. The current tab's is noted before each message is handled
+// and kept if the message edited its buffer, under the Version the buffer
+// had: that of the step's first operation. Those of operations no longer
+// in the buffer's history are dropped.
+type viewState struct {
+	cursor     int64
+	selected   bool
+	start, end int64
+}
+
+func (m *Model) viewState() viewState {
+	tab := m.currentTab()
+	if tab == nil {
+		return viewState{}
+	}
+	v := viewState{cursor: tab.Cursor}
+	if tab.Selection.Active && !tab.Selection.Block {
+		v.selected = true
+		v.start, v.end = tab.Selection.Start, tab.Selection.End
+	}
+	return v
+}
+
+// noteView keeps v for the edit tab's buffer made from version, if the
+// tab is still open.
+func (m *Model) noteView(tab *Tab, version uint64, v viewState) {
+	if tab == nil || !slices.Contains(m.tabs, tab) {
+		return
+	}
+	if tab.Buffer.Version() != version {
+		if m.editViews == nil {
+			m.editViews = make(map[*buffer.Buffer]map[uint64]viewState)
+		}
+		views := m.editViews[tab.Buffer]
+		if views == nil {
+			views = make(map[uint64]viewState)
+			m.editViews[tab.Buffer] = views
+		}
+		views[version] = v
+	}
+	m.pruneViews(tab.Buffer)
+}
+
+// pruneViews drops the view states of buf's edits that can no longer be
+// undone or redone: those cut off by a new edit, saved, reverted or lost
+// with a broken history. Each operation has at most one, so there are
+// stale ones only when there are more than operations.
+func (m *Model) pruneViews(buf *buffer.Buffer) {
+	views := m.editViews[buf]
+	undo, redo := buf.History()
+	if len(views) <= len(undo)+len(redo) {
+		return
+	}
+	kept := make(map[uint64]bool, len(undo)+len(redo))
+	for _, op := range undo {
+		kept[op.Version] = true
+	}
+	for _, op := range redo {
+		kept[op.Version] = true
+	}
+	maps.DeleteFunc(views, func(version uint64, _ viewState) bool { return !kept[version] })
+}
+
+// stepRange returns the bytes ops cover once undone or redone; end is
+// below start when they only removed bytes.
+func stepRange(ops []buffer.Operation, undo bool) (start, end int64) {
+	start, end = -1, -1
+	for _, op := range ops {
+		n := len(op.NewData)
+		if undo {
+			n = len(op.OldData)
+		}
+		if op.Type == buffer.OpInsert && undo || op.Type == buffer.OpDelete && !undo {
+			n = 0
+		}
+		if start < 0 || op.Offset < start {
+			start = op.Offset
+		}
+		end = max(end, op.Offset+int64(n)-1)
+	}
+	return start, end
+}
+
+// showStep moves to what the last undo or redo of tab changed.
+func (m *Model) showStep(tab *Tab, undo bool) tea.Cmd {
+	ops := tab.Buffer.LastStep()
+	if len(ops) == 0 {
+		return nil
+	}
+	start, end := stepRange(ops, undo)
+	pos := start
+	// The cursor stays at the moving end of a selection it brings back
+	if before := m.editViews[tab.Buffer][ops[0].Version]; undo && before.selected && max(before.start, before.end, before.cursor) < tab.Buffer.Size() {
+		tab.Selection.Active, tab.Selection.Block = true, false
+		tab.Selection.Start, tab.Selection.End = before.start, before.end
+		pos = before.cursor
+	}
+	tab.Cursor = min(pos, m.maxCursor(tab))
+	tab.resetGoalColumn()
+	m.ensureCursorVisible()
+
+	if end < start {
+		return nil
+	}
+	return m.flashRange(tab, start, end)
+}
+
+// flashRange highlights the bytes from start to end of tab for a moment.
+func (m *Model) flashRange(tab *Tab, start, end int64) tea.Cmd {
+	m.flash = &flash{tab: tab, start: start, end: end}
+	m.flashSeq++
+	seq := m.flashSeq
+	return m.clock.Tick(flashDuration, func(time.Time) tea.Msg { return flashExpireMsg{seq} })
+}
+
+func (m *Model) handleFlashExpire(msg flashExpireMsg) (tea.Model, tea.Cmd) {
+	if msg.seq == m.flashSeq {
+		m.flash = nil
+	}
+	return m, nil
+}
+
+// flashed reports whether offset of tab is flashing.
+func (m *Model) flashed(tab *Tab, offset int64) bool {
+	f := m.flash
+	return f != nil && f.tab == tab && offset >= f.start && offset <= f.end
+}
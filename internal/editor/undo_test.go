@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoReturnsToTheEdit(t *testing.T) {
+	m := newTestModel(make([]byte, 4096))
+	tab := m.currentTab()
+
+	// Fill a selection far from where undo is pressed
+	m.setCursor(100)
+	press(m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight)
+	typeKeys(m, "L05")
+	press(m, tea.KeyEnter)
+	if got := tab.Buffer.GetBytes(100, 4); string(got) != "\x05\x05\x05\x05" {
+		t.Fatalf("fill wrote % X", got)
+	}
+	m.setCursor(4000)
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if tab.Cursor != 103 || !tab.Selection.Active || tab.Selection.Start != 100 || tab.Selection.End != 103 {
+		t.Fatalf("expected the cursor and selection back at 100-103, got %d %+v", tab.Cursor, tab.Selection)
+	}
+	if tab.ScrollY > 100/bytesPerRow || !m.flashed(tab, 103) || m.flashed(tab, 104) {
+		t.Errorf("expected the range on screen and flashing, scroll %d flash %+v", tab.ScrollY, m.flash)
+	}
+	if cmd == nil {
+		t.Fatal("expected a timer to end the flash")
+	}
+	m.Update(flashExpireMsg{m.flashSeq})
+	if m.flash != nil {
+		t.Error("the flash should end")
+	}
+
+	// Redo lands on the group's start
+	m.setCursor(0)
+	typeKeys(m, "d")
+	if tab.Cursor != 100 || !m.flashed(tab, 100) {
+		t.Errorf("expected redo at 100, got %d", tab.Cursor)
+	}
+}
+
+func TestUndoInsertMovesToIt(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+	tab := m.currentTab()
+	tab.Buffer.Insert(40, []byte{1, 2, 3})
+	m.setCursor(0)
+
+	typeKeys(m, "u")
+	if tab.Cursor != 40 || m.flash != nil {
+		t.Errorf("expected the cursor at the removed bytes without a flash, got %d %+v", tab.Cursor, m.flash)
+	}
+}
+
+func TestUndoViewsArePruned(t *testing.T) {
+	m := newTestModel(make([]byte, 64))
+	tab := m.currentTab()
+	views := func() int { return len(m.editViews[tab.Buffer]) }
+
+	typeKeys(m, "r11223344")
+	if views() != 4 {
+		t.Fatalf("expected a view noted per typed byte, got %d", views())
+	}
+	// Undone edits cut off by a new one go
+	typeKeys(m, "uu")
+	press(m, tea.KeyEscape)
+	typeKeys(m, "r55")
+	if views() != 3 {
+		t.Errorf("expected the redo views dropped, got %d", views())
+	}
+
+	tab.Buffer.Revert()
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if views() != 0 {
+		t.Errorf("expected no views once the history is gone, got %d", views())
+	}
+}
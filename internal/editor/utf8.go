@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	"github.com/mattn/go-runewidth"
+)
+
+type textCellKind int
+
+const (
+	textCellGlyph textCellKind = iota
+	textCellContinuation
+	textCellInvalid
+)
+
+const (
+	utf8Filler      = "·"
+	utf8Placeholder = "□"
+)
+
+// utf8Cell decodes the text-column cell for the byte at offset. A valid
+// multi-byte sequence puts its glyph in the cell of its lead byte and marks
+// the following cells as continuations, even across row boundaries.
+func utf8Cell(buf *buffer.Buffer, offset int64) (string, textCellKind) {
+	b, ok := buf.GetByte(offset)
+	if !ok {
+		return " ", textCellInvalid
+	}
+
+	if b < utf8.RuneSelf {
+		if b >= 32 && b < 127 {
+			return string(b), textCellGlyph
+		}
+		return ".", textCellInvalid
+	}
+
+	if utf8.RuneStart(b) {
+		r, size := utf8.DecodeRune(buf.GetBytes(offset, utf8.UTFMax))
+		if r == utf8.RuneError && size <= 1 {
+			return ".", textCellInvalid
+		}
+		if !unicode.IsPrint(r) {
+			return ".", textCellInvalid
+		}
+		if runewidth.RuneWidth(r) != 1 {
+			return utf8Placeholder, textCellGlyph
+		}
+		return string(r), textCellGlyph
+	}
+
+	// Continuation byte: look back for the lead byte of a sequence covering it
+	for back := int64(1); back < utf8.UTFMax && offset-back >= 0; back++ {
+		lead, _ := buf.GetByte(offset - back)
+		if !utf8.RuneStart(lead) {
+			continue
+		}
+		r, size := utf8.DecodeRune(buf.GetBytes(offset-back, utf8.UTFMax))
+		if r != utf8.RuneError && int64(size) > back && unicode.IsPrint(r) {
+			return utf8Filler, textCellContinuation
+		}
+		break
+	}
+	return ".", textCellInvalid
+}
@@ -0,0 +1,163 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// In value column mode (W) the hex column shows each row as u16, u32 or
+// u64 values in the current byte order instead of single bytes. Only the
+// display changes: each byte keeps its own two digits and style, so the
+// cursor, selection and nibble editing still work on bytes. The text
+// column stays byte by byte. After u64 values W shows nibbles (see
+// nibbles.go), then bytes again.
+
+var groupWidths = []int{1, 2, 4, 8}
+
+func (m *Model) cycleGroupWidth() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if tab.Nibbles {
+		// Back on the byte the cursor is in
+		tab.Nibbles, tab.nibble = false, 0
+		tab.GroupWidth = 1
+		m.setStatus(sevInfo, "Hex column: bytes")
+		return
+	}
+	if groupWidth(tab) == groupWidths[len(groupWidths)-1] {
+		tab.Nibbles, tab.GroupWidth = true, 1
+		m.setStatus(sevInfo, "Hex column: nibbles (Replace mode writes one per key)")
+		return
+	}
+	next := groupWidths[0]
+	for i, w := range groupWidths {
+		if w == groupWidth(tab) && i+1 < len(groupWidths) {
+			next = groupWidths[i+1]
+		}
+	}
+	tab.GroupWidth = next
+	if next == 1 {
+		m.setStatus(sevInfo, "Hex column: bytes")
+		return
+	}
+	m.setStatus(sevInfo, fmt.Sprintf("Hex column: u%d values, %s", next*8, m.byteOrderName()))
+}
+
+func (m *Model) byteOrderName() string {
+	if m.bigEndian {
+		return "big endian"
+	}
+	return "little endian"
+}
+
+// groupWidth returns the tab's hex column group size in bytes.
+func groupWidth(tab *Tab) int {
+	return max(tab.GroupWidth, 1)
+}
+
+// displayIndex maps byte k of a w-byte value to its position on screen,
+// most significant byte first. The mapping is its own inverse.
+func (m *Model) displayIndex(k, w int) int {
+	if m.bigEndian {
+		return k
+	}
+	return w - 1 - k
+}
+
+// hexGap returns the spacing written before the hex cell at col, wider
+// after every fourth and eighth byte.
+func hexGap(col int) string {
+	switch {
+	case col == 0:
+		return ""
+	case col%8 == 0:
+		return "   "
+	case col%4 == 0:
+		return "  "
+	default:
+		return " "
+	}
+}
+
+// hexColumnWidth is the width of a row's hex cells in byte mode. Grouped
+// rows are narrower and padded to it.
+func hexColumnWidth() int {
+	return bytesPerRow*3 - 1 + (bytesPerRow-1)/4 + (bytesPerRow-1)/8
+}
+
+// horizontalDelta converts a move of n cells left or right into a change
+// of offset. In value column mode cells are walked in screen order, which
+// within a little-endian value is backwards through memory.
+func (m *Model) horizontalDelta(tab *Tab, n int64) int64 {
+	if tab == nil {
+		return n
+	}
+	// A fold is a single stop: the next move leaves it
+	if f, ok := m.cursorFold(tab); ok && n > 0 {
+		return (f.last+1)*bytesPerRow + n - 1 - tab.Cursor
+	} else if ok && n < 0 {
+		return f.first*bytesPerRow + n - tab.Cursor
+	}
+	if groupWidth(tab) == 1 {
+		return n
+	}
+	w := int64(groupWidth(tab))
+	visual := func(pos int64) int64 {
+		col := pos % w
+		return pos - col + int64(m.displayIndex(int(col), int(w)))
+	}
+	target := visual(tab.Cursor) + n
+	if target < 0 {
+		target = 0
+	}
+	return visual(target) - tab.Cursor
+}
+
+type hexCell struct {
+	text  string
+	style *lipgloss.Style
+}
+
+// writeGroupedHex writes a row's hex cells as w-byte values.
+func (m *Model) writeGroupedHex(line *styleRun, tab *Tab, rowOffset int64, cells []hexCell, w int) {
+	width := 0
+	var prev *lipgloss.Style
+	for g := 0; g < bytesPerRow; g += w {
+		if gap := hexGap(g); gap != "" {
+			style := m.crosshairStyle(tab, rowOffset+int64(g))
+			if first := cells[g+m.displayIndex(0, w)].style; first == prev {
+				style = prev
+			}
+			line.write(style, gap)
+			width += len(gap)
+		}
+		for p := 0; p < w; p++ {
+			cell := cells[g+m.displayIndex(p, w)]
+			line.write(cell.style, cell.text)
+			prev = cell.style
+		}
+		width += 2 * w
+	}
+	if pad := hexColumnWidth() - width; pad > 0 {
+		line.write(m.crosshairStyle(tab, rowOffset), strings.Repeat(" ", pad))
+	}
+}
+
+// groupedColumnHeader labels each value with the offset of its first byte.
+func (m *Model) groupedColumnHeader(tab *Tab, w int) string {
+	var b strings.Builder
+	cursorGroup := int(tab.Cursor%bytesPerRow) / w * w
+	for g := 0; g < bytesPerRow; g += w {
+		gap := hexGap(g)
+		label := fmt.Sprintf("%-*s", 2*w, fmt.Sprintf("%02X", g))
+		if g == cursorGroup {
+			label = m.styles.IndexMarker.Render(label)
+		}
+		b.WriteString(gap + label)
+	}
+	return b.String()
+}
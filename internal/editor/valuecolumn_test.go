@@ -0,0 +1,84 @@
+package editor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestValueColumnRendering(t *testing.T) {
+	data := make([]byte, 2*bytesPerRow)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	m := newTestModel(data)
+	tab := m.currentTab()
+	tab.Cursor = bytesPerRow
+	bytesRow := m.renderRow(tab, 0, -1)
+
+	typeKeys(m, "ww")
+	if tab.GroupWidth != 4 {
+		t.Fatalf("expected u32 values, got width %d", tab.GroupWidth)
+	}
+	m.bigEndian = false
+	row := m.renderRow(tab, 0, -1)
+	if want := "00000000  03020100  07060504   0B0A0908  0F0E0D0C"; !strings.HasPrefix(row, want) {
+		t.Errorf("little-endian u32 row\n got %q\nwant %q", row, want)
+	}
+	if lipgloss.Width(row) != lipgloss.Width(bytesRow) {
+		t.Errorf("grouped row is %d wide, byte row %d", lipgloss.Width(row), lipgloss.Width(bytesRow))
+	}
+	if !strings.HasSuffix(row, "  ................") {
+		t.Errorf("text column should stay byte by byte: %q", row)
+	}
+
+	m.bigEndian = true
+	if want := "00000000  00010203  04050607   08090A0B  0C0D0E0F"; !strings.HasPrefix(m.renderRow(tab, 0, -1), want) {
+		t.Errorf("big-endian u32 row should read in memory order")
+	}
+	if header := m.renderColumnHeader(); !strings.Contains(header, "00        04         08") {
+		t.Errorf("unexpected header %q", header)
+	}
+
+	typeKeys(m, "ww")
+	if tab.GroupWidth != 1 {
+		t.Errorf("W should cycle back to bytes, got %d", tab.GroupWidth)
+	}
+}
+
+func TestValueColumnCursorMovesInScreenOrder(t *testing.T) {
+	m := newTestModel(make([]byte, 2*bytesPerRow))
+	tab := m.currentTab()
+	typeKeys(m, "w") // u16
+	m.bigEndian = false
+
+	// Screen order of the first row is 1 0 3 2 5 4 ...
+	tab.Cursor = 1
+	var got []int64
+	for i := 0; i < 4; i++ {
+		press(m, tea.KeyRight)
+		got = append(got, tab.Cursor)
+	}
+	if want := []int64{0, 3, 2, 5}; !slices.Equal(got, want) {
+		t.Fatalf("cursor went %v, want %v", got, want)
+	}
+	tab.Cursor = bytesPerRow - 2 // last value's low byte, shown rightmost
+	press(m, tea.KeyRight)
+	if tab.Cursor != bytesPerRow+1 {
+		t.Errorf("moving off the row should land on the next row's first cell, got %d", tab.Cursor)
+	}
+	press(m, tea.KeyLeft)
+	if tab.Cursor != bytesPerRow-2 {
+		t.Errorf("moving back should return, got %d", tab.Cursor)
+	}
+
+	m.bigEndian = true
+	tab.Cursor = 4
+	press(m, tea.KeyRight)
+	if tab.Cursor != 5 {
+		t.Errorf("big-endian values move in memory order, got %d", tab.Cursor)
+	}
+}
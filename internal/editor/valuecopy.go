@@ -0,0 +1,82 @@
+package editor
+
+import (
+	"fmt"
+)
+
+// Alt+C copies the selected 1, 2, 4 or 8 bytes as the integer they decode
+// to at the current endianness, and Alt+V writes that integer back at the
+// cursor, re-encoded at the width it was copied at or at the width given
+// as a count (2 Alt+V for a u16), so a length field can move between
+// fields of different sizes. A value too big for the width isn't written.
+
+type copiedValue struct {
+	value uint64
+	width int
+}
+
+func (m *Model) copyValue() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if !tab.Selection.Active || tab.Selection.Block {
+		m.setStatus(sevWarning, "Select the 1, 2, 4 or 8 bytes of a value to copy it")
+		return
+	}
+	start, end := m.getSelectedRange()
+	width := end - start + 1
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		m.setStatus(sevWarning, fmt.Sprintf("Can't copy a %d-byte value; select 1, 2, 4 or 8 bytes", width))
+		return
+	}
+	v := decodeUint(tab.Buffer.GetBytes(start, int(width)), m.bigEndian)
+	m.copiedValue = &copiedValue{value: v, width: int(width)}
+	m.setStatus(sevInfo, fmt.Sprintf("Copied value %d (%s)", v, m.valueWidthLabel(int(width))))
+}
+
+// pasteValue writes the copied value at the cursor as a width-byte
+// integer, or at the width it was copied at when width is 1 or less.
+func (m *Model) pasteValue(width int64) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	cv := m.copiedValue
+	if cv == nil {
+		m.setStatus(sevWarning, "No value copied; select one and press Alt+C")
+		return
+	}
+	w := cv.width
+	if width > 1 {
+		w = int(width)
+	}
+	if w != 1 && w != 2 && w != 4 && w != 8 {
+		m.setStatus(sevWarning, fmt.Sprintf("Can't paste a %d-byte value; use a count of 2, 4 or 8", w))
+		return
+	}
+	if w < 8 && cv.value >= 1<<(8*w) {
+		m.setStatus(sevWarning, fmt.Sprintf("%d doesn't fit in a u%d (at most %d)", cv.value, w*8, uint64(1)<<(8*w)-1))
+		return
+	}
+
+	data := encodeUint(cv.value, w, m.bigEndian)
+	insert := m.mode == ModeInsert
+	if !m.putBytes(tab, data, insert) {
+		return
+	}
+	m.recordPut("pasted value", data, insert)
+	m.setStatus(sevInfo, fmt.Sprintf("Wrote %d as %s: % X", cv.value, m.valueWidthLabel(w), data))
+}
+
+// valueWidthLabel names a width-byte integer, e.g. "u16 LE".
+func (m *Model) valueWidthLabel(width int) string {
+	if width == 1 {
+		return "u8"
+	}
+	order := "LE"
+	if m.bigEndian {
+		order = "BE"
+	}
+	return fmt.Sprintf("u%d %s", width*8, order)
+}
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func altKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true}
+}
+
+func TestCopyValueIntoAWiderField(t *testing.T) {
+	m := newTestModel([]byte{0x01, 0x2C, 0, 0, 0, 0, 0xFF})
+	tab := m.currentTab()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	m.Update(altKey('c'))
+	if !strings.Contains(m.status.text, "300 (u16 BE)") {
+		t.Fatalf("expected 300 copied, got %q", m.status.text)
+	}
+
+	m.clearSelection()
+	m.setCursor(2)
+	typeKeys(m, "4")
+	m.Update(altKey('v'))
+	if got := tab.Buffer.GetBytes(0, 7); !bytes.Equal(got, []byte{0x01, 0x2C, 0, 0, 0x01, 0x2C, 0xFF}) {
+		t.Fatalf("expected 300 as a big-endian u32, got % X", got)
+	}
+	if tab.Cursor != 6 {
+		t.Errorf("the cursor should move past the value, got %d", tab.Cursor)
+	}
+
+	// The whole value is one undo step
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if got := tab.Buffer.GetBytes(2, 4); !bytes.Equal(got, []byte{0, 0, 0, 0}) {
+		t.Errorf("undo should restore the field, got % X", got)
+	}
+}
+
+func TestPasteValueMustFit(t *testing.T) {
+	m := newTestModel([]byte{0, 0x01, 0x2C, 0, 0xAA, 0xAA})
+	tab := m.currentTab()
+
+	m.Update(altKey('v'))
+	if !strings.Contains(m.status.text, "No value copied") {
+		t.Errorf("got %q", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	m.Update(altKey('c'))
+	if m.status.sev != sevWarning || m.copiedValue != nil {
+		t.Errorf("3 bytes aren't a value, got %q", m.status.text)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	m.Update(altKey('c'))
+	m.clearSelection()
+	m.setCursor(4)
+	typeKeys(m, "2")
+	m.Update(altKey('v'))
+	if !strings.Contains(m.status.text, "76800 doesn't fit in a u16") || !bytes.Equal(tab.Buffer.GetBytes(4, 2), []byte{0xAA, 0xAA}) {
+		t.Errorf("76800 must not be written as a u16, got %q", m.status.text)
+	}
+}
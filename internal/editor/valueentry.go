@@ -0,0 +1,135 @@
+package editor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The value prompt (=) writes a byte typed as a number or character
+// instead of hex digits: "200", "0xC8", "0o310", "0b11001000" or "'Z'".
+// A width prefix such as "u16:1000" or "i32:-1" writes several bytes in
+// the current endianness.
+
+var valueWidths = map[string]int{
+	"u8": 1, "i8": 1, "u16": 2, "i16": 2, "u32": 4, "i32": 4, "u64": 8, "i64": 8,
+}
+
+// parseIntLiteral parses a decimal number or one with a 0x, 0o or 0b
+// prefix. Unlike strconv's base 0, a leading zero doesn't mean octal.
+func parseIntLiteral(s string, bits int, signed bool) (uint64, error) {
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			base = 0
+		}
+	}
+	if signed {
+		v, err := strconv.ParseInt(s, base, bits)
+		return uint64(v), err
+	}
+	return strconv.ParseUint(s, base, bits)
+}
+
+// parseValueInput returns the bytes the value prompt input stands for.
+func parseValueInput(input string, bigEndian bool) ([]byte, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return nil, fmt.Errorf("enter a value")
+	}
+
+	if r := []rune(s); len(r) == 3 && r[0] == '\'' && r[2] == '\'' {
+		if r[1] > 0xFF {
+			return nil, fmt.Errorf("%q is not a single byte", r[1])
+		}
+		return []byte{byte(r[1])}, nil
+	}
+
+	width, signed := 1, false
+	if typ, rest, ok := strings.Cut(s, ":"); ok {
+		w, known := valueWidths[strings.ToLower(typ)]
+		if !known {
+			return nil, fmt.Errorf("unknown type %q (u8-u64, i8-i64)", typ)
+		}
+		width, signed, s = w, typ[0] == 'i' || typ[0] == 'I', strings.TrimSpace(rest)
+	}
+
+	v, err := parseIntLiteral(s, width*8, signed)
+	if err != nil {
+		if width == 1 && !signed {
+			return nil, fmt.Errorf("value must be 0–255, 'c' or a typed value like u16:1000")
+		}
+		return nil, fmt.Errorf("invalid %d-byte value %q", width, s)
+	}
+
+	data := make([]byte, 8)
+	if bigEndian {
+		binary.BigEndian.PutUint64(data, v)
+		return data[8-width:], nil
+	}
+	binary.LittleEndian.PutUint64(data, v)
+	return data[:width], nil
+}
+
+func (m *Model) handleValueKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewMain
+	case tea.KeyEnter:
+		// Keep the prompt open to correct an invalid value
+		if m.writeValue() {
+			m.view = ViewMain
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.valueInput); len(r) > 0 {
+			m.valueInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.valueInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// writeValue writes the prompt's value at the cursor, inserting in insert
+// mode and overwriting otherwise, and moves past it. It reports false when
+// the input doesn't parse.
+func (m *Model) writeValue() bool {
+	tab := m.currentTab()
+	if tab == nil {
+		return true
+	}
+	data, err := parseValueInput(m.valueInput, m.bigEndian)
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return false
+	}
+	if m.editBlocked(tab) {
+		return true
+	}
+
+	if !m.putBytes(tab, data, m.mode == ModeInsert) {
+		return false
+	}
+	m.recordPut("value", data, m.mode == ModeInsert)
+	m.setStatus(sevInfo, fmt.Sprintf("Wrote % X", data))
+	return true
+}
+
+func (m *Model) renderValue() string {
+	var b strings.Builder
+	b.WriteString("\nENTER VALUE\n")
+	b.WriteString("===========\n\n")
+	b.WriteString("Value: ")
+	b.WriteString(m.valueInput)
+	b.WriteString("_\n\n")
+	b.WriteString("200, 0xC8, 0o310, 0b11001000 or 'Z' for one byte;\n")
+	b.WriteString("u16:1000, i32:-1 etc. for wider values in the current endianness\n")
+	b.WriteString("\nPress Enter to write, ESC to cancel\n")
+
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseValueInput(t *testing.T) {
+	tests := []struct {
+		in        string
+		bigEndian bool
+		want      []byte
+	}{
+		{"200", true, []byte{0xC8}},
+		{"0xC8", true, []byte{0xC8}},
+		{"0o310", true, []byte{0xC8}},
+		{"0b11001000", true, []byte{0xC8}},
+		{"010", true, []byte{10}},
+		{"'Z'", true, []byte{'Z'}},
+		{"u16:1000", true, []byte{0x03, 0xE8}},
+		{"u16:1000", false, []byte{0xE8, 0x03}},
+		{"i32:-1", false, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{"U32: 0x10", true, []byte{0, 0, 0, 0x10}},
+	}
+	for _, tt := range tests {
+		got, err := parseValueInput(tt.in, tt.bigEndian)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("parseValueInput(%q) = % X, %v; want % X", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "256", "-1", "'ab'", "u12:5", "u8:300", "i8:200", "0xZZ"} {
+		if _, err := parseValueInput(in, true); err == nil {
+			t.Errorf("parseValueInput(%q) should fail", in)
+		}
+	}
+}
+
+func TestValuePromptWrites(t *testing.T) {
+	m := newTestModel([]byte{0, 0, 0, 0})
+	tab := m.currentTab()
+
+	typeKeys(m, "=300")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewValue || m.status.sev != sevWarning {
+		t.Fatal("an out-of-range value should keep the prompt open with a warning")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeKeys(m, "'Z'")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewMain || !bytes.Equal(tab.Buffer.GetBytes(0, 4), []byte{'Z', 0, 0, 0}) {
+		t.Fatalf("expected 'Z' written over the first byte, got % X", tab.Buffer.GetBytes(0, 4))
+	}
+	if tab.Cursor != 1 {
+		t.Errorf("cursor should move past the value, got %d", tab.Cursor)
+	}
+
+	m.setMode(ModeInsert)
+	typeKeys(m, "=u16:1")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := tab.Buffer.GetBytes(0, 6); !bytes.Equal(got, []byte{'Z', 0, 1, 0, 0, 0}) {
+		t.Errorf("expected a big-endian u16 inserted, got % X", got)
+	}
+}
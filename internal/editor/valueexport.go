@@ -0,0 +1,327 @@
+package editor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+E exports the selection as a table of decoded values, one element
+// per line, optionally next to its offset: to a CSV or TSV file, or as
+// text into a register. Elements are decoded at the current endianness
+// with the same formatting as the value decoder. A selection that isn't
+// a whole number of elements asks whether to drop the odd bytes.
+
+type exportType struct {
+	name   string // u16, i32, f64...
+	width  int
+	signed bool
+	float  bool
+}
+
+var exportTypes = []exportType{
+	{name: "u8", width: 1},
+	{name: "u16", width: 2},
+	{name: "u32", width: 4},
+	{name: "u64", width: 8},
+	{name: "i8", width: 1, signed: true},
+	{name: "i16", width: 2, signed: true},
+	{name: "i32", width: 4, signed: true},
+	{name: "i64", width: 8, signed: true},
+	{name: "f32", width: 4, float: true},
+	{name: "f64", width: 8, float: true},
+}
+
+type valueExportForm struct {
+	tab     *Tab
+	start   int64
+	end     int64
+	typ     int
+	offsets bool
+	tsv     bool
+	path    string
+	reg     rune
+	focus   int // valueExportField*
+	button  int // valueExportButton*
+}
+
+const (
+	valueExportFieldType = iota
+	valueExportFieldOffsets
+	valueExportFieldFormat
+	valueExportFieldPath
+	valueExportFieldButtons
+)
+
+const (
+	valueExportButtonFile = iota
+	valueExportButtonRegister
+)
+
+func (m *Model) openValueExport(reg rune) {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	if !tab.Selection.Active || tab.Selection.Block {
+		m.setStatus(sevWarning, "Select the values to export first")
+		return
+	}
+	start, end := m.getSelectedRange()
+	path := "values.csv"
+	if name := tab.Buffer.Filename(); name != "" {
+		path = name + ".csv"
+	}
+	m.valueExport = &valueExportForm{
+		tab:   tab,
+		start: start,
+		end:   min(end, tab.Buffer.Size()-1),
+		path:  path,
+		reg:   reg,
+		focus: valueExportFieldType,
+	}
+	m.view = ViewValueExport
+}
+
+// formatElement decodes one element of type t at the current endianness.
+func (m *Model) formatElement(data []byte, t exportType) string {
+	switch {
+	case t.float && t.width == 4:
+		return m.formatFloat32(data)
+	case t.float:
+		return m.formatFloat64(data)
+	}
+	return m.formatInt(data, t.signed)
+}
+
+// label names t with the byte order it's decoded in, e.g. "i32 LE".
+func (t exportType) label(bigEndian bool) string {
+	if t.width == 1 {
+		return t.name
+	}
+	if bigEndian {
+		return t.name + " BE"
+	}
+	return t.name + " LE"
+}
+
+// writeValues writes the elements of f's range, count of them, to w.
+func (m *Model) writeValues(w io.Writer, f *valueExportForm, count int64) error {
+	t := exportTypes[f.typ]
+	sep := ","
+	if f.tsv {
+		sep = "\t"
+	}
+	bw := bufio.NewWriter(w)
+	base := m.displayBase(f.tab)
+	offset := f.start
+	var carry []byte
+	end := f.start + count*int64(t.width)
+	err := f.tab.Buffer.Chunks(f.start, end, jobChunk, func(data []byte) error {
+		data = append(carry, data...)
+		for len(data) >= t.width {
+			if f.offsets {
+				fmt.Fprintf(bw, "0x%X%s", offset+base, sep)
+			}
+			bw.WriteString(m.formatElement(data[:t.width], t))
+			bw.WriteByte('\n')
+			data, offset = data[t.width:], offset+int64(t.width)
+		}
+		carry = append([]byte(nil), data...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// runValueExport exports f with the button's destination, first asking
+// what to do with a partial element at the end of the range.
+func (m *Model) runValueExport(button int) {
+	f := m.valueExport
+	t := exportTypes[f.typ]
+	size := f.end - f.start + 1
+	count, rest := size/int64(t.width), size%int64(t.width)
+	if count == 0 {
+		m.setStatus(sevWarning, fmt.Sprintf("The selection is shorter than one %s", t.name))
+		return
+	}
+	if rest == 0 {
+		m.exportValues(button, count, 0)
+		return
+	}
+	m.openDialog(fmt.Sprintf("%d bytes aren't a whole number of %ss; drop the last %d?", size, t.name, rest),
+		m.cancelButton(ViewValueExport),
+		dialogButton{label: "Truncate", action: func() (tea.Model, tea.Cmd) {
+			m.view = ViewValueExport
+			m.exportValues(button, count, rest)
+			return m, nil
+		}})
+}
+
+func (m *Model) exportValues(button int, count, dropped int64) {
+	f := m.valueExport
+	t := exportTypes[f.typ]
+	what := fmt.Sprintf("%d %s values", count, t.label(m.bigEndian))
+
+	switch button {
+	case valueExportButtonFile:
+		path := strings.TrimSpace(f.path)
+		if path == "" {
+			m.setStatus(sevWarning, "Enter a file to export to")
+			return
+		}
+		out, err := m.fs.Create(path)
+		if err == nil {
+			err = m.writeValues(out, f, count)
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			m.setStatus(sevError, "Error exporting values: "+err.Error())
+			return
+		}
+		what += " to " + filepath.Base(path)
+	case valueExportButtonRegister:
+		var b strings.Builder
+		if err := m.writeValues(&b, f, count); err != nil {
+			m.setStatus(sevError, "Error exporting values: "+err.Error())
+			return
+		}
+		m.storeRegister(f.reg, &register{data: []byte(b.String())})
+		what += " into register " + registerName(f.reg)
+	}
+
+	msg, sev := "Exported "+what, sevInfo
+	if dropped > 0 {
+		msg += fmt.Sprintf(" (dropped the last %d bytes)", dropped)
+		sev = sevWarning
+	}
+	m.valueExport = nil
+	m.view = ViewMain
+	m.setStatus(sev, msg)
+}
+
+func (m *Model) handleValueExportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.valueExport
+	if f == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.valueExport = nil
+		m.view = ViewMain
+	case "up", "shift+tab":
+		f.focus = (f.focus + valueExportFieldButtons) % (valueExportFieldButtons + 1)
+	case "down", "tab":
+		f.focus = (f.focus + 1) % (valueExportFieldButtons + 1)
+	case "left", "right":
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		switch f.focus {
+		case valueExportFieldType:
+			n := len(exportTypes)
+			f.typ = (f.typ + step + n) % n
+		case valueExportFieldOffsets:
+			f.offsets = !f.offsets
+		case valueExportFieldFormat:
+			f.tsv = !f.tsv
+			f.path = swapExportExt(f.path, f.tsv)
+		case valueExportFieldButtons:
+			f.button = 1 - f.button
+		}
+	case "backspace":
+		if r := []rune(f.path); f.focus == valueExportFieldPath && len(r) > 0 {
+			f.path = string(r[:len(r)-1])
+		}
+	case "ctrl+u":
+		if f.focus == valueExportFieldPath {
+			f.path = ""
+		}
+	case "enter":
+		button := valueExportButtonFile
+		if f.focus == valueExportFieldButtons {
+			button = f.button
+		}
+		m.runValueExport(button)
+	default:
+		if f.focus == valueExportFieldPath && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+			f.path += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// swapExportExt switches a .csv path to .tsv or back, leaving other
+// names alone.
+func swapExportExt(path string, tsv bool) string {
+	from, to := ".tsv", ".csv"
+	if tsv {
+		from, to = to, from
+	}
+	if strings.HasSuffix(path, from) {
+		return strings.TrimSuffix(path, from) + to
+	}
+	return path
+}
+
+func (m *Model) renderValueExport() string {
+	f := m.valueExport
+	if f == nil {
+		return ""
+	}
+	t := exportTypes[f.typ]
+	size := f.end - f.start + 1
+
+	var b strings.Builder
+	b.WriteString("\nEXPORT VALUES\n")
+	b.WriteString("=============\n\n")
+	b.WriteString(fmt.Sprintf("  Region:   0x%X–0x%X (%d bytes, %d values)\n\n", f.start, f.end, size, size/int64(t.width)))
+
+	field := func(i int, label, value string) {
+		prefix := "  "
+		if f.focus == i {
+			prefix = "> "
+		}
+		b.WriteString(fmt.Sprintf("%s%-9s %s\n", prefix, label, value))
+	}
+	field(valueExportFieldType, "Type:", "◂ "+t.label(m.bigEndian)+" ▸")
+	offsets := "Values only"
+	if f.offsets {
+		offsets = "Offset and value"
+	}
+	field(valueExportFieldOffsets, "Columns:", "◂ "+offsets+" ▸")
+	format := "CSV"
+	if f.tsv {
+		format = "TSV"
+	}
+	field(valueExportFieldFormat, "Format:", "◂ "+format+" ▸")
+	path := f.path
+	if f.focus == valueExportFieldPath {
+		path += "_"
+	}
+	field(valueExportFieldPath, "File:", path)
+
+	var buttons []string
+	for i, label := range []string{"Save to file", "Copy to register " + registerName(f.reg)} {
+		label = "[ " + label + " ]"
+		if f.focus == valueExportFieldButtons && i == f.button {
+			label = m.styles.Legend.Render(label)
+		}
+		buttons = append(buttons, label)
+	}
+	b.WriteString("\n  " + strings.Join(buttons, "  ") + "\n")
+	b.WriteString("\n↑/↓ to choose a field, ←/→ to change it, Enter to save, ESC to cancel\n")
+	b.WriteString("(E in the main view switches the byte order)\n")
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestExportValuesToFile(t *testing.T) {
+	m := newTestModel([]byte{0x00, 0x01, 0xFF, 0xFE, 0x12, 0x34})
+	m.bigEndian = false
+	for range 5 {
+		m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	}
+	m.Update(altKey('e'))
+	if m.view != ViewValueExport {
+		t.Fatalf("expected the export form, got view %d", m.view)
+	}
+
+	path := filepath.Join(t.TempDir(), "values.tsv")
+	f := m.valueExport
+	f.typ, f.offsets, f.tsv, f.path = 5, true, true, path // i16
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x0\t256\n0x2\t-257\n0x4\t13330\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+	if m.view != ViewMain || !strings.Contains(m.status.text, "Exported 3 i16 LE values") {
+		t.Errorf("got %q", m.status.text)
+	}
+}
+
+func TestExportValuesAsksToTruncate(t *testing.T) {
+	m := newTestModel([]byte{0, 0, 0x80, 0x3F, 0xAA})
+	m.bigEndian = false
+	for range 4 {
+		m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	}
+	m.Update(altKey('e'))
+	m.valueExport.typ = 8 // f32
+	m.valueExport.focus = valueExportFieldButtons
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewDialog || !strings.Contains(m.dialog.message, "drop the last 1") {
+		t.Fatalf("expected a truncate prompt, got view %d", m.view)
+	}
+
+	// Cancel goes back to the form
+	m.activateButton(0)
+	if m.view != ViewValueExport {
+		t.Fatalf("expected the form again, got view %d", m.view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.activateButton(1)
+	if got := string(m.registers[defaultRegister].data); got != "1\n" {
+		t.Errorf("got %q", got)
+	}
+	if m.status.sev != sevWarning || !strings.Contains(m.status.text, "dropped the last 1 bytes") {
+		t.Errorf("got %q", m.status.text)
+	}
+}
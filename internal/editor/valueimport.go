@@ -0,0 +1,218 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+I is the inverse of the value export: it reads a file of numbers
+// separated by commas, semicolons or whitespace, encodes each as the
+// chosen element type and writes them at the cursor, inserting in Insert
+// mode and overwriting otherwise. Nothing is written unless every value
+// fits, and the whole import is one undo step.
+
+type valueImportForm struct {
+	path      string
+	typ       int
+	bigEndian bool
+	focus     int // valueImportField*
+}
+
+const (
+	valueImportFieldPath = iota
+	valueImportFieldType
+	valueImportFieldOrder
+	valueImportFields
+)
+
+func (m *Model) openValueImport() {
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	path := "values.csv"
+	if name := tab.Buffer.Filename(); name != "" {
+		path = name + ".csv"
+	}
+	m.valueImport = &valueImportForm{path: path, bigEndian: m.bigEndian}
+	m.view = ViewValueImport
+}
+
+// parseValues encodes the numbers in text as elements of type t. Lines
+// starting with # are comments.
+func parseValues(text string, t exportType, bigEndian bool) ([]byte, error) {
+	var data []byte
+	for i, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ';' || unicode.IsSpace(r)
+		})
+		for _, field := range fields {
+			v, err := parseElement(field, t)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			data = append(data, encodeUint(v, t.width, bigEndian)...)
+		}
+	}
+	return data, nil
+}
+
+// parseElement returns the bits of s as an element of type t.
+func parseElement(s string, t exportType) (uint64, error) {
+	bits := t.width * 8
+	var v uint64
+	var err error
+	switch {
+	case t.float:
+		var f float64
+		f, err = strconv.ParseFloat(s, bits)
+		if bits == 32 {
+			v = uint64(math.Float32bits(float32(f)))
+		} else {
+			v = math.Float64bits(f)
+		}
+	case t.signed:
+		v, err = parseIntLiteral(s, bits, true)
+	default:
+		v, err = parseIntLiteral(s, bits, false)
+		// A negative number is out of range rather than not a number
+		if _, serr := parseIntLiteral(s, 64, true); err != nil && serr == nil {
+			err = strconv.ErrRange
+		}
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("%s doesn't fit in a %s", s, t.name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", s)
+	}
+	return v, nil
+}
+
+// importValues writes the values in f's file at the cursor. Errors keep
+// the form open.
+func (m *Model) importValues() {
+	f := m.valueImport
+	tab := m.currentTab()
+	if tab == nil {
+		return
+	}
+	path := strings.TrimSpace(f.path)
+	text, err := m.fs.ReadFile(path)
+	if err != nil {
+		m.setStatus(sevError, "Error importing values: "+err.Error())
+		return
+	}
+	t := exportTypes[f.typ]
+	data, err := parseValues(string(text), t, f.bigEndian)
+	if err != nil {
+		m.setStatus(sevWarning, fmt.Sprintf("%s, %v; nothing written", filepath.Base(path), err))
+		return
+	}
+	if len(data) == 0 {
+		m.setStatus(sevWarning, "No values in "+filepath.Base(path))
+		return
+	}
+	at := tab.Cursor
+	if !m.putBytes(tab, data, m.mode == ModeInsert) {
+		return
+	}
+
+	m.valueImport = nil
+	m.view = ViewMain
+	m.setStatus(sevInfo, fmt.Sprintf("Imported %d %s values from %s at 0x%X (%d bytes)", len(data)/t.width, t.label(f.bigEndian), filepath.Base(path), at+m.displayBase(tab), len(data)))
+}
+
+func (m *Model) handleValueImportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.valueImport
+	if f == nil {
+		m.view = ViewMain
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.valueImport = nil
+		m.view = ViewMain
+	case "up", "shift+tab":
+		f.focus = (f.focus + valueImportFields - 1) % valueImportFields
+	case "down", "tab":
+		f.focus = (f.focus + 1) % valueImportFields
+	case "left", "right":
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		switch f.focus {
+		case valueImportFieldType:
+			n := len(exportTypes)
+			f.typ = (f.typ + step + n) % n
+		case valueImportFieldOrder:
+			f.bigEndian = !f.bigEndian
+		}
+	case "backspace":
+		if r := []rune(f.path); f.focus == valueImportFieldPath && len(r) > 0 {
+			f.path = string(r[:len(r)-1])
+		}
+	case "ctrl+u":
+		if f.focus == valueImportFieldPath {
+			f.path = ""
+		}
+	case "enter":
+		m.importValues()
+	default:
+		if f.focus == valueImportFieldPath && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+			f.path += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) renderValueImport() string {
+	f := m.valueImport
+	if f == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nIMPORT VALUES\n")
+	b.WriteString("=============\n\n")
+
+	field := func(i int, label, value string) {
+		prefix := "  "
+		if f.focus == i {
+			prefix = "> "
+		}
+		b.WriteString(fmt.Sprintf("%s%-12s %s\n", prefix, label, value))
+	}
+	path := f.path
+	if f.focus == valueImportFieldPath {
+		path += "_"
+	}
+	field(valueImportFieldPath, "File:", path)
+	field(valueImportFieldType, "Type:", "◂ "+exportTypes[f.typ].name+" ▸")
+	order := "Little endian"
+	if f.bigEndian {
+		order = "Big endian"
+	}
+	field(valueImportFieldOrder, "Byte order:", "◂ "+order+" ▸")
+
+	how := "overwriting"
+	if m.mode == ModeInsert {
+		how = "inserting"
+	}
+	b.WriteString(fmt.Sprintf("\nWrites at the cursor, %s (per the current mode)\n", how))
+	b.WriteString("\n↑/↓ to choose a field, ←/→ to change it, Enter to import, ESC to cancel\n")
+	b.WriteString("(Numbers separated by commas, semicolons or spaces; 0x for hex)\n")
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package editor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseValues(t *testing.T) {
+	u16, i8, f32 := exportTypes[1], exportTypes[4], exportTypes[8]
+	data, err := parseValues("# table\n1, 0x100;\t65535\n\n", u16, true)
+	if err != nil || !bytes.Equal(data, []byte{0, 1, 1, 0, 0xFF, 0xFF}) {
+		t.Errorf("got % X, %v", data, err)
+	}
+	if data, err = parseValues("-1 -128", i8, false); err != nil || !bytes.Equal(data, []byte{0xFF, 0x80}) {
+		t.Errorf("got % X, %v", data, err)
+	}
+	// Zero-padded numbers are decimal; only 0x, 0o and 0b change the base
+	if data, err = parseValues("0010 0o10", u16, true); err != nil || !bytes.Equal(data, []byte{0, 10, 0, 8}) {
+		t.Errorf("got % X, %v", data, err)
+	}
+	if data, err = parseValues("010 0b11 -010", i8, true); err != nil || !bytes.Equal(data, []byte{10, 3, 0xF6}) {
+		t.Errorf("got % X, %v", data, err)
+	}
+	if data, err = parseValues("1.0", f32, false); err != nil || !bytes.Equal(data, []byte{0, 0, 0x80, 0x3F}) {
+		t.Errorf("got % X, %v", data, err)
+	}
+
+	for _, c := range []struct {
+		text string
+		typ  exportType
+		want string
+	}{
+		{"1\n2\n65536", u16, "line 3: 65536 doesn't fit in a u16"},
+		{"-1", u16, "line 1: -1 doesn't fit in a u16"},
+		{"128", i8, "line 1: 128 doesn't fit in a i8"},
+		{"1e39", f32, "line 1: 1e39 doesn't fit in a f32"},
+		{"1 two", u16, `line 1: "two" isn't a number`},
+	} {
+		if _, err := parseValues(c.text, c.typ, true); err == nil || err.Error() != c.want {
+			t.Errorf("%q: got %v, want %q", c.text, err, c.want)
+		}
+	}
+}
+
+func TestImportValuesAtTheCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.csv")
+	if err := os.WriteFile(path, []byte("10,20\n30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel([]byte{0xAA, 0xAA, 0xAA, 0xAA, 0xAA})
+	tab := m.currentTab()
+	m.setCursor(1)
+	m.Update(altKey('i'))
+	f := m.valueImport
+	f.path = path
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := tab.Buffer.GetBytes(0, 5); !bytes.Equal(got, []byte{0xAA, 10, 20, 30, 0xAA}) {
+		t.Fatalf("got % X", got)
+	}
+	if !strings.Contains(m.status.text, "Imported 3 u8 values") || tab.Cursor != 4 {
+		t.Errorf("got %q, cursor %d", m.status.text, tab.Cursor)
+	}
+
+	// Undone in one step
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if got := tab.Buffer.GetBytes(0, 5); !bytes.Equal(got, bytes.Repeat([]byte{0xAA}, 5)) {
+		t.Errorf("undo should restore the bytes, got % X", got)
+	}
+
+	// Out of range: nothing written, the form stays open
+	if err := os.WriteFile(path, []byte("1\n256\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.setCursor(0)
+	m.Update(altKey('i'))
+	m.valueImport.path = path
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewValueImport || !strings.Contains(m.status.text, "line 2: 256 doesn't fit in a u8") {
+		t.Errorf("got %q", m.status.text)
+	}
+	if got := tab.Buffer.GetBytes(0, 1); got[0] != 0xAA {
+		t.Errorf("nothing should be written, got % X", got)
+	}
+}
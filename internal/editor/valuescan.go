@@ -0,0 +1,206 @@
+package editor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+F finds where a number is stored when its encoding isn't known, the
+// usual way to find a size or address field: "u32 0x1000" lists every
+// offset, or every offset aligned to the value's size, where the bytes
+// decode to it, little-endian, big-endian or either, each hit saying which
+// matched. The scan runs in the background over a snapshot and the hits go
+// to the find-all list, where Enter jumps to one and Esc cancels.
+
+// valueScanLimit is how many hits a scan lists in each byte order.
+const valueScanLimit = 100000
+
+// Byte orders Tab steps through in the prompt.
+const (
+	scanBoth = iota
+	scanLittle
+	scanBig
+)
+
+var valueScanOrders = []string{"either byte order", "little-endian", "big-endian"}
+
+type valueScan struct {
+	typ     string // as typed, e.g. "u32"
+	width   int
+	value   uint64
+	order   int
+	aligned bool
+}
+
+// parseValueScan parses "[TYPE] VALUE" as the value prompt does, the type
+// separated by a space, ":" or "==" and u32 when left out.
+func parseValueScan(input string) (valueScan, error) {
+	s := strings.NewReplacer("==", " ", ":", " ").Replace(input)
+	fields := strings.Fields(s)
+	typ := "u32"
+	if len(fields) == 2 {
+		typ, fields = strings.ToLower(fields[0]), fields[1:]
+	}
+	if len(fields) != 1 {
+		return valueScan{}, fmt.Errorf("enter a type and a value, e.g. u32 0x1000")
+	}
+	width, known := valueWidths[typ]
+	if !known {
+		return valueScan{}, fmt.Errorf("unknown type %q (u8-u64, i8-i64)", typ)
+	}
+	v, err := parseIntLiteral(fields[0], width*8, typ[0] == 'i')
+	if err != nil {
+		return valueScan{}, fmt.Errorf("invalid %s value %q", typ, fields[0])
+	}
+	if width < 8 {
+		v &= 1<<(width*8) - 1
+	}
+	return valueScan{typ: typ, width: width, value: v}, nil
+}
+
+func (v valueScan) String() string {
+	s := fmt.Sprintf("%s 0x%0*X (%d), %s", v.typ, v.width*2, v.value, v.value, valueScanOrders[v.order])
+	if v.aligned && v.width > 1 {
+		s += fmt.Sprintf(", aligned to %d", v.width)
+	}
+	return s
+}
+
+// patterns returns the bytes to look for, each with the note its hits get.
+// Bytes that read the same in both orders are looked for once.
+func (v valueScan) patterns() (patterns [][]byte, notes []string) {
+	le, be := encodeUint(v.value, v.width, false), encodeUint(v.value, v.width, true)
+	switch {
+	case v.order == scanLittle:
+		return [][]byte{le}, []string{"LE"}
+	case v.order == scanBig:
+		return [][]byte{be}, []string{"BE"}
+	case slices.Equal(le, be):
+		return [][]byte{le}, []string{"LE+BE"}
+	}
+	return [][]byte{le, be}, []string{"LE", "BE"}
+}
+
+func (m *Model) openValueScan() {
+	if m.currentTab() == nil {
+		return
+	}
+	m.view = ViewValueScan
+}
+
+func (m *Model) handleValueScanKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewMain
+	case tea.KeyEnter:
+		return m, m.startValueScan()
+	case tea.KeyTab:
+		m.valueScanOrder = (m.valueScanOrder + 1) % len(valueScanOrders)
+	case tea.KeyCtrlL:
+		m.valueScanAligned = !m.valueScanAligned
+	case tea.KeyCtrlU:
+		m.valueScanInput = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.valueScanInput); len(r) > 0 {
+			m.valueScanInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.valueScanInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// startValueScan lists the offsets of the prompt's value in the current
+// tab.
+func (m *Model) startValueScan() tea.Cmd {
+	scan, err := parseValueScan(m.valueScanInput)
+	if err != nil {
+		m.setStatus(sevWarning, err.Error())
+		return nil
+	}
+	scan.order, scan.aligned = m.valueScanOrder, m.valueScanAligned
+	if m.findAll != nil && m.findAll.cancel != nil {
+		m.findAll.cancel()
+	}
+	m.findAll = &findAllState{scan: &scan, tab: m.currentTab()}
+	m.view = ViewFindAll
+	return m.scanValue()
+}
+
+// scanValue scans a snapshot of the tab for the value in the background.
+func (m *Model) scanValue() tea.Cmd {
+	s := m.findAll
+	m.findAllSeq++
+	s.seq = m.findAllSeq
+	s.scanning = true
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	tab, scan, seq := s.tab, *s.scan, s.seq
+	snap := tab.Buffer.Snapshot()
+	versions := map[*buffer.Buffer]uint64{tab.Buffer: tab.Buffer.Version()}
+	var align buffer.Alignment
+	if scan.aligned {
+		align = buffer.Alignment{To: int64(scan.width), Base: m.displayBase(tab)}
+	}
+	return func() tea.Msg {
+		group := &findAllGroup{tab: tab}
+		notes := make(map[int64]string)
+		cutoff := snap.Size()
+		patterns, labels := scan.patterns()
+		for i, pattern := range patterns {
+			hits, err := snap.FindEachContext(ctx, pattern, align, valueScanLimit)
+			if err != nil {
+				return nil
+			}
+			if len(hits) == valueScanLimit {
+				// Hits past the last one listed may be missing
+				group.truncated = true
+				cutoff = min(cutoff, hits[len(hits)-1])
+			}
+			for _, pos := range hits {
+				if notes[pos] != "" {
+					notes[pos] += "+" + labels[i]
+				} else {
+					notes[pos] = labels[i]
+				}
+			}
+		}
+		for pos := range notes {
+			if pos <= cutoff {
+				group.hits = append(group.hits, pos)
+			}
+		}
+		slices.Sort(group.hits)
+		for _, pos := range group.hits {
+			group.notes = append(group.notes, notes[pos])
+		}
+		var groups []*findAllGroup
+		if len(group.hits) > 0 {
+			groups = append(groups, group)
+		}
+		return findAllMsg{seq: seq, groups: groups, versions: versions}
+	}
+}
+
+func (m *Model) renderValueScan() string {
+	var b strings.Builder
+	b.WriteString("\nFIND VALUE AT EVERY OFFSET\n")
+	b.WriteString("==========================\n\n")
+	b.WriteString("Value:      " + m.valueScanInput + "_\n")
+	b.WriteString("Byte order: " + valueScanOrders[m.valueScanOrder] + "\n")
+	aligned := "off"
+	if m.valueScanAligned {
+		aligned = "to the value's size"
+	}
+	b.WriteString("Aligned:    " + aligned + "\n\n")
+	b.WriteString("A type and a value: u32 0x1000, i16:-2, u64 == 4096 (u32 if left out)\n")
+	b.WriteString("\nPress Enter to list the offsets, Tab to change the byte order, Ctrl+L to toggle alignment, ESC to close\n")
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseValueScan(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		value uint64
+		err   bool
+	}{
+		{"u32 0x0000_1000", 4, 0x1000, false},
+		{"u16:1000", 2, 1000, false},
+		{"i16 == -2", 2, 0xFFFE, false},
+		{"4096", 4, 4096, false},
+		{"u8 256", 0, 0, true},
+		{"f32 1", 0, 0, true},
+		{"", 0, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseValueScan(tt.input)
+		if (err != nil) != tt.err || err == nil && (got.width != tt.width || got.value != tt.value) {
+			t.Errorf("%q: got %+v, %v", tt.input, got, err)
+		}
+	}
+}
+
+// scanFor runs a value scan for input and returns the results view.
+func scanFor(t *testing.T, m *Model, input string) string {
+	t.Helper()
+	m.valueScanInput = input
+	cmd := m.startValueScan()
+	if cmd == nil {
+		t.Fatalf("expected a scan, status %q", m.status.text)
+	}
+	m.Update(cmd())
+	return m.renderFindAll()
+}
+
+func TestValueScanFlagsByteOrder(t *testing.T) {
+	// u32 0x1000 little-endian at 0 and 9, big-endian at 4
+	m := newTestModel([]byte("\x00\x10\x00\x00\x00\x00\x10\x00.\x00\x10\x00\x00"))
+	view := scanFor(t, m, "u32 0x1000")
+	for _, want := range []string{"3 matches", "0x00000000  00 10 00 00 00 00 10 00 2E 00 10 00 00  LE", "0x00000004  00 00 10 00 2E 00 10 00 00  BE", "0x00000009  00 10 00 00  LE"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in:\n%s", want, view)
+		}
+	}
+
+	m.valueScanAligned = true
+	if view := scanFor(t, m, "u32 0x1000"); !strings.Contains(view, "2 matches") || strings.Contains(view, "0x00000009") {
+		t.Errorf("expected the aligned hits only:\n%s", view)
+	}
+
+	press(m, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	if m.view != ViewMain || m.currentTab().Cursor != 4 {
+		t.Errorf("view %v, cursor %d", m.view, m.currentTab().Cursor)
+	}
+}
+
+func TestValueScanByteOrders(t *testing.T) {
+	m := newTestModel([]byte("\x01\x00\x00\x01\x00\x01"))
+	if view := scanFor(t, m, "u32 0x01000001"); !strings.Contains(view, "LE+BE") {
+		t.Errorf("expected both byte orders flagged:\n%s", view)
+	}
+	m.valueScanOrder = scanBig
+	if view := scanFor(t, m, "u16 1"); !strings.Contains(view, "2 matches") || strings.Contains(view, "LE") {
+		t.Errorf("expected the big-endian hits only:\n%s", view)
+	}
+}
@@ -0,0 +1,51 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// "*" searches for the value under the cursor: it decodes the bytes there
+// at the current endianness and starts a decimal search for the same value
+// at the same width, so Enter in the find view steps through the other
+// places it is stored. A count of 2, 4 or 8 picks the width; otherwise the
+// tab's group width is used, or 4 (u32).
+
+const defaultValueWidth = 4
+
+func (m *Model) searchValueAtCursor(count int64) tea.Cmd {
+	tab := m.currentTab()
+	if tab == nil {
+		return nil
+	}
+	width := int64(defaultValueWidth)
+	switch {
+	case count > 1:
+		width = count
+	case tab.GroupWidth > 1:
+		width = int64(tab.GroupWidth)
+	}
+	if width != 2 && width != 4 && width != 8 {
+		m.setStatus(sevWarning, fmt.Sprintf("Can't search for a %d-byte value; use a count of 2, 4 or 8", width))
+		return nil
+	}
+	if left := tab.Buffer.Size() - tab.Cursor; left < width {
+		m.setStatus(sevWarning, fmt.Sprintf("Only %d bytes from the cursor to the end; a u%d needs %d", max(left, 0), width*8, width))
+		return nil
+	}
+
+	value := decodeUint(tab.Buffer.GetBytes(tab.Cursor, int(width)), m.bigEndian)
+	m.findMode = "decimal"
+	m.findWidth = int(width)
+	m.findInput = strconv.FormatUint(value, 10)
+	m.doFind(true)
+	m.view = ViewFind
+	return m.updateFindMatches()
+}
+
+// findWidthLabel describes the width of a decimal search, e.g. "u32 BE".
+func (m *Model) findWidthLabel() string {
+	return m.valueWidthLabel(max(m.findWidth, 1))
+}
@@ -0,0 +1,48 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchValueAtCursor(t *testing.T) {
+	data := make([]byte, 32)
+	for _, off := range []int{0, 9, 20} {
+		copy(data[off:], []byte{0x12, 0x34, 0x56, 0x78})
+	}
+	m := newTestModel(data)
+
+	typeKeys(m, "*")
+	tab := m.currentTab()
+	if m.view != ViewFind || m.findInput != "305419896" || m.findWidthLabel() != "u32 BE" {
+		t.Fatalf("expected a u32 search, got view %d %q %s", m.view, m.findInput, m.findWidthLabel())
+	}
+	if tab.Cursor != 9 {
+		t.Errorf("expected the next copy at 9, got %d", tab.Cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 20 {
+		t.Errorf("expected Enter to find the copy at 20, got %d", tab.Cursor)
+	}
+
+	// A count picks the width
+	m.view = ViewMain
+	m.setCursor(0)
+	typeKeys(m, "2*")
+	if m.findInput != "4660" || tab.Cursor != 9 {
+		t.Errorf("expected a u16 search finding 9, got %q at %d", m.findInput, tab.Cursor)
+	}
+}
+
+func TestSearchValueNeedsEnoughBytes(t *testing.T) {
+	m := newTestModel([]byte{1, 2, 3})
+	typeKeys(m, "*")
+	if m.view != ViewMain || m.status.sev != sevWarning {
+		t.Errorf("expected a warning, got %q", m.status.text)
+	}
+	typeKeys(m, "3*")
+	if m.status.sev != sevWarning {
+		t.Errorf("a 3-byte value can't be searched, got %q", m.status.text)
+	}
+}
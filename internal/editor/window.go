@@ -0,0 +1,158 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A tab can hold just a window of a large file, opened from the command
+// line as file@OFFSET+LENGTH or with the browser's Open range button. The
+// window can't grow or shrink, saving writes it back in place, and its
+// offsets are shown relative to the file or to the window per the
+// base_address setting.
+
+// parseWindowSpec parses "OFFSET+LENGTH", each hex with 0x or decimal.
+func parseWindowSpec(spec string) (buffer.Window, error) {
+	offStr, lenStr, ok := strings.Cut(spec, "+")
+	if !ok {
+		return buffer.Window{}, fmt.Errorf("expected OFFSET+LENGTH, got %q", spec)
+	}
+	off, err := parseOffset(strings.TrimSpace(offStr))
+	if err != nil || off < 0 {
+		return buffer.Window{}, fmt.Errorf("invalid window offset %q", offStr)
+	}
+	length, err := parseOffset(strings.TrimSpace(lenStr))
+	if err != nil || length <= 0 {
+		return buffer.Window{}, fmt.Errorf("invalid window length %q", lenStr)
+	}
+	return buffer.Window{Offset: off, Length: length}, nil
+}
+
+// splitWindowArg splits a command line argument of the form
+// path@OFFSET+LENGTH. Existing files whose names merely contain an @ are
+// opened whole.
+func splitWindowArg(arg string) (string, buffer.Window, bool, error) {
+	i := strings.LastIndex(arg, "@")
+	if i <= 0 || !strings.Contains(arg[i:], "+") {
+		return arg, buffer.Window{}, false, nil
+	}
+	if _, err := os.Stat(arg); err == nil {
+		return arg, buffer.Window{}, false, nil
+	}
+	w, err := parseWindowSpec(arg[i+1:])
+	return arg[:i], w, true, err
+}
+
+// findOpenWindow returns the index of a tab holding window w of path, or -1.
+func (m *Model) findOpenWindow(path string, w buffer.Window) int {
+	for i, tab := range m.tabs {
+		if tw, ok := tab.Buffer.Window(); ok && tw == w && samePath(m.fs, tab.Buffer.Filename(), path) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) openWindow(path string, w buffer.Window) error {
+	if i := m.findOpenWindow(path, w); i >= 0 {
+		m.selectTab(i)
+		m.view = ViewMain
+		return nil
+	}
+	buf, err := buffer.OpenWindow(path, w)
+	if err != nil {
+		return err
+	}
+	tab := m.newTab(buf)
+	m.tabs = append(m.tabs, tab)
+	m.selectTab(len(m.tabs) - 1)
+	m.view = ViewMain
+	m.lockTab(tab)
+	return nil
+}
+
+// resizeBlocked reports, with a warning, whether tab is a window or device,
+// whose size edits must not change.
+func (m *Model) resizeBlocked(tab *Tab) bool {
+	if !tab.Buffer.FixedSize() {
+		return false
+	}
+	name := filepath.Base(tab.Buffer.Filename())
+	if w, ok := tab.Buffer.Window(); ok {
+		m.setStatus(sevWarning, fmt.Sprintf("This tab is a fixed %d-byte window of %s; edits can't change its size", w.Length, name))
+	} else {
+		m.setStatus(sevWarning, fmt.Sprintf("%s is a device; edits can't change its size", name))
+	}
+	return true
+}
+
+// displayBase is added to tab's buffer offsets when they are shown or typed.
+func (m *Model) displayBase(tab *Tab) int64 {
+	if w, ok := tab.Buffer.Window(); ok && m.config.Behavior.BaseAddress != "window" {
+		return w.Offset
+	}
+	return 0
+}
+
+// windowLabel describes tab's window, or "" for a whole file.
+func windowLabel(tab *Tab) string {
+	if w, ok := tab.Buffer.Window(); ok {
+		return fmt.Sprintf("@0x%X+0x%X", w.Offset, w.Length)
+	}
+	return ""
+}
+
+func (m *Model) openRangePrompt() {
+	if m.browserIndex >= len(m.browserItems) || m.browserItems[m.browserIndex].IsDir() {
+		m.setStatus(sevWarning, "Select a file to open a range of")
+		return
+	}
+	m.rangeInput = ""
+	m.view = ViewOpenRange
+}
+
+func (m *Model) handleOpenRangeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.view = ViewOpen
+	case tea.KeyEnter:
+		w, err := parseWindowSpec(m.rangeInput)
+		if err != nil {
+			m.setStatus(sevWarning, err.Error())
+			return m, nil
+		}
+		path := filepath.Join(m.browserPath, m.browserItems[m.browserIndex].Name())
+		if err := m.openWindow(path, w); err != nil {
+			m.setStatus(sevError, fmt.Sprintf("Error opening %s: %v", filepath.Base(path), err))
+		}
+	case tea.KeyBackspace:
+		if len(m.rangeInput) > 0 {
+			m.rangeInput = m.rangeInput[:len(m.rangeInput)-1]
+		}
+	default:
+		char := msg.String()
+		if isHexChar(char) || char == "x" || char == "X" || char == "+" {
+			m.rangeInput += char
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) renderOpenRange() string {
+	var b strings.Builder
+	b.WriteString("\nOPEN RANGE\n")
+	b.WriteString("==========\n\n")
+	if m.browserIndex < len(m.browserItems) {
+		b.WriteString("File:  " + m.browserItems[m.browserIndex].Name() + "\n")
+	}
+	b.WriteString("Range: " + m.rangeInput + "_\n\n")
+	b.WriteString("(OFFSET+LENGTH, e.g. 0x40000000+0x100000; prefix with 0x for hex)\n")
+	b.WriteString("\nPress Enter to open, ESC to go back\n")
+	return b.String()
+}
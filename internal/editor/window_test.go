@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protohuf/unhexed/pkg/buffer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitWindowArg(t *testing.T) {
+	dir := t.TempDir()
+	odd := filepath.Join(dir, "mail@host+1")
+	if err := os.WriteFile(odd, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, w, ok, err := splitWindowArg("disk.img@0x40000000+0x100000")
+	if err != nil || !ok || path != "disk.img" || w != (buffer.Window{Offset: 0x40000000, Length: 0x100000}) {
+		t.Errorf("got %q %+v %v %v", path, w, ok, err)
+	}
+	if _, _, ok, _ := splitWindowArg(odd); ok {
+		t.Error("an existing file with @ in its name should open whole")
+	}
+	if _, _, ok, _ := splitWindowArg("plain.bin"); ok {
+		t.Error("a plain path is not a window")
+	}
+	if _, _, _, err := splitWindowArg("disk.img@0x10+0"); err == nil {
+		t.Error("an empty window should be rejected")
+	}
+}
+
+func TestWindowTab(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	data := make([]byte, 0x200)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(nil)
+	if err := m.openWindow(path, buffer.Window{Offset: 0x100, Length: 0x40}); err != nil {
+		t.Fatal(err)
+	}
+	tab := m.currentTab()
+
+	if row := m.renderRow(tab, 0x10, -1); !strings.HasPrefix(row, "00000110") {
+		t.Errorf("offsets should be absolute by default: %q", row[:8])
+	}
+	m.config.Behavior.BaseAddress = "window"
+	if row := m.renderRow(tab, 0x10, -1); !strings.HasPrefix(row, "00000010") {
+		t.Errorf("base_address = window should show window offsets: %q", row[:8])
+	}
+	m.config.Behavior.BaseAddress = "file"
+
+	typeKeys(m, "g0x120")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if tab.Cursor != 0x20 {
+		t.Errorf("goto should take file offsets, cursor at 0x%X", tab.Cursor)
+	}
+
+	// Nothing may change the window's size
+	typeKeys(m, "i")
+	if m.mode == ModeInsert || !strings.Contains(m.status.text, "fixed") {
+		t.Errorf("insert mode should be refused, status %q", m.status.text)
+	}
+	press(m, tea.KeyDelete)
+	tab.Cursor = 0x3F
+	typeKeys(m, "=u32:1")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.view != ViewValue {
+		t.Error("a value running past the window should keep the prompt open")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if tab.Buffer.Size() != 0x40 {
+		t.Fatalf("window resized to 0x%X", tab.Buffer.Size())
+	}
+
+	typeKeys(m, "r")
+	tab.Cursor = 0
+	typeKeys(m, "ab")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	got, _ := os.ReadFile(path)
+	want := make([]byte, 0x200)
+	want[0x100] = 0xAB
+	if !bytes.Equal(got, want) {
+		t.Errorf("save should write only the window back (status %q)", m.status.text)
+	}
+}
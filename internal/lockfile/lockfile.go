@@ -0,0 +1,167 @@
+// Package lockfile marks files as being edited, so a second editor opening
+// one can warn before two sets of edits race to be saved. The locks are
+// advisory: small files in a runtime directory, named after a hash of the
+// locked path and recording the process holding it. A lock whose process
+// is gone is stale and is removed when found, as is one whose process ID
+// now belongs to a process started after the lock was taken. Where start
+// times can't be read, a lock older than maxAge is taken to be stale.
+package lockfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Info describes the process holding a lock.
+type Info struct {
+	PID     int       `toml:"pid"`
+	Started time.Time `toml:"started"`
+	Path    string    `toml:"path"`
+}
+
+// Lock is a lock held by this process.
+type Lock struct {
+	path string // of the locked file
+	file string // the lock itself
+}
+
+var started = time.Now()
+
+// maxAge is how long a lock is trusted when its process's start time
+// can't be read.
+const maxAge = 24 * time.Hour
+
+// startSlack allows for the rounding of process start times.
+const startSlack = 2 * time.Second
+
+// held reports whether the process that took the lock info describes still
+// runs.
+func held(info *Info) bool {
+	if !alive(info.PID) {
+		return false
+	}
+	if start, ok := processStart(info.PID); ok {
+		// A later process given the same ID doesn't hold it
+		return !start.After(info.Started.Add(startSlack))
+	}
+	return time.Since(info.Started) < maxAge
+}
+
+// Dir returns the directory locks are kept in.
+func Dir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "unhexed")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("unhexed-%d", os.Getuid()))
+}
+
+// File returns the lock file of path.
+func File(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(Dir(), hex.EncodeToString(sum[:8])+".lock")
+}
+
+// Acquire locks path for this process. When another live process holds
+// the lock, it returns a nil Lock and that process's Info.
+func Acquire(path string) (*Lock, *Info, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return nil, nil, err
+	}
+
+	l := &Lock{path: abs, file: File(abs)}
+	data, err := encode(Info{PID: os.Getpid(), Started: started, Path: abs})
+	if err != nil {
+		return nil, nil, err
+	}
+	// A stale lock is removed and the create retried, once
+	for range 2 {
+		f, err := os.OpenFile(l.file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = f.Write(data)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return l, nil, err
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, err
+		}
+
+		holder, err := read(l.file)
+		switch {
+		case err != nil:
+			// Unreadable, e.g. written halfway by a crash
+			os.Remove(l.file)
+		case holder.PID == os.Getpid():
+			return l, nil, nil
+		case held(holder):
+			return nil, holder, nil
+		default:
+			os.Remove(l.file)
+		}
+	}
+	return nil, nil, fmt.Errorf("can't create lock %s", l.file)
+}
+
+// Path returns the locked file's absolute path.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Release removes the lock, unless another process has taken it over.
+func (l *Lock) Release() error {
+	if info, err := read(l.file); err != nil || info.PID != os.Getpid() {
+		return err
+	}
+	return os.Remove(l.file)
+}
+
+// Clean removes the stale locks left by processes that are gone.
+func Clean() {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".lock") {
+			continue
+		}
+		file := filepath.Join(Dir(), e.Name())
+		if info, err := read(file); err == nil && !held(info) {
+			os.Remove(file)
+		}
+	}
+}
+
+func encode(info Info) ([]byte, error) {
+	var buf bytes.Buffer
+	err := toml.NewEncoder(&buf).Encode(info)
+	return buf.Bytes(), err
+}
+
+func read(file string) (*Info, error) {
+	info := &Info{}
+	if _, err := toml.DecodeFile(file, info); err != nil {
+		return nil, err
+	}
+	if info.PID <= 0 {
+		return nil, fmt.Errorf("%s records no process", file)
+	}
+	return info, nil
+}
@@ -0,0 +1,92 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// holdAs writes the lock of path as held by pid since started.
+func holdAs(t *testing.T, path string, pid int, started time.Time) string {
+	t.Helper()
+	abs, _ := filepath.Abs(path)
+	data, err := encode(Info{PID: pid, Started: started, Path: abs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	file := File(abs)
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestAcquireAndRelease(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "a.bin")
+
+	l, holder, err := Acquire(path)
+	if err != nil || l == nil || holder != nil {
+		t.Fatalf("expected the lock, got %v %v %v", l, holder, err)
+	}
+	if again, _, _ := Acquire(path); again == nil {
+		t.Error("this process may lock a file it holds again")
+	}
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(File(l.Path())); !os.IsNotExist(err) {
+		t.Errorf("lock should be removed, stat: %v", err)
+	}
+}
+
+func TestAcquireHeldElsewhere(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "a.bin")
+	file := holdAs(t, path, os.Getppid(), time.Now())
+
+	l, holder, err := Acquire(path)
+	if err != nil || l != nil || holder == nil || holder.PID != os.Getppid() {
+		t.Fatalf("expected the parent to hold the lock, got %v %+v %v", l, holder, err)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("a live lock must be kept: %v", err)
+	}
+}
+
+func TestStaleLocksAreRemoved(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	const gone = 1 << 30 // beyond any pid
+	dir := t.TempDir()
+
+	holdAs(t, filepath.Join(dir, "a.bin"), gone, time.Now())
+	l, holder, err := Acquire(filepath.Join(dir, "a.bin"))
+	if err != nil || l == nil || holder != nil {
+		t.Fatalf("a stale lock should be taken over, got %v %+v %v", l, holder, err)
+	}
+
+	stale := holdAs(t, filepath.Join(dir, "b.bin"), gone, time.Now())
+	Clean()
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("Clean should remove the stale lock, stat: %v", err)
+	}
+	if _, err := os.Stat(File(l.Path())); err != nil {
+		t.Errorf("Clean must keep live locks: %v", err)
+	}
+}
+
+func TestReusedPIDDoesNotHoldLock(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "a.bin")
+	// The parent is alive, but started long after this lock was taken
+	holdAs(t, path, os.Getppid(), time.Now().Add(-2*maxAge))
+
+	l, holder, err := Acquire(path)
+	if err != nil || l == nil || holder != nil {
+		t.Fatalf("a lock from before its process started should be taken over, got %v %+v %v", l, holder, err)
+	}
+}
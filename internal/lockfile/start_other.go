@@ -0,0 +1,10 @@
+//go:build !linux
+
+package lockfile
+
+import "time"
+
+// processStart can't tell here; locks age out instead.
+func processStart(pid int) (time.Time, bool) {
+	return time.Time{}, false
+}
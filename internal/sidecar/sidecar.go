@@ -0,0 +1,73 @@
+// Package sidecar stores per-file settings in a TOML file next to the file
+// they describe, so they travel with it.
+package sidecar
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Checksum describes a checksum stored in the file: Algorithm computed
+// over Start..End (inclusive) and written at Dest.
+type Checksum struct {
+	Algorithm string `toml:"algorithm"`
+	Start     int64  `toml:"start"`
+	End       int64  `toml:"end"`
+	Dest      int64  `toml:"dest"`
+	BigEndian bool   `toml:"big_endian"`
+}
+
+// Lock is a range of the file locked against edits, Start to End
+// inclusive.
+type Lock struct {
+	Start int64 `toml:"start"`
+	End   int64 `toml:"end"`
+}
+
+type Sidecar struct {
+	Checksums []Checksum `toml:"checksum"`
+	Locks     []Lock     `toml:"lock"`
+}
+
+// Path returns the sidecar path for file.
+func Path(file string) string {
+	return file + ".unhexed.toml"
+}
+
+// Load reads the sidecar of file. A missing sidecar is an empty one.
+func Load(file string) (*Sidecar, error) {
+	s := &Sidecar{}
+	data, err := os.ReadFile(Path(file))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(data), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the sidecar of file.
+func (s *Sidecar) Save(file string) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return os.WriteFile(Path(file), buf.Bytes(), 0644)
+}
+
+// AddChecksum records c, replacing a definition writing to the same place.
+func (s *Sidecar) AddChecksum(c Checksum) {
+	for i, old := range s.Checksums {
+		if old.Dest == c.Dest {
+			s.Checksums[i] = c
+			return
+		}
+	}
+	s.Checksums = append(s.Checksums, c)
+}
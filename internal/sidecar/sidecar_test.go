@@ -0,0 +1,54 @@
+package sidecar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fw.bin")
+
+	s, err := Load(file)
+	if err != nil || len(s.Checksums) != 0 {
+		t.Fatalf("a missing sidecar should load empty, got %+v, %v", s, err)
+	}
+
+	s.AddChecksum(Checksum{Algorithm: "CRC32", Start: 0x10, End: 0xFF, Dest: 0x0C})
+	s.AddChecksum(Checksum{Algorithm: "SUM8", Start: 0, End: 0x0E, Dest: 0x0F})
+	s.AddChecksum(Checksum{Algorithm: "CRC16/XMODEM", Start: 0x10, End: 0x1FF, Dest: 0x0C, BigEndian: true})
+	if err := s.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file + ".unhexed.toml"); err != nil {
+		t.Fatalf("expected the sidecar next to the file: %v", err)
+	}
+
+	loaded, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Checksum{
+		{Algorithm: "CRC16/XMODEM", Start: 0x10, End: 0x1FF, Dest: 0x0C, BigEndian: true},
+		{Algorithm: "SUM8", Start: 0, End: 0x0E, Dest: 0x0F},
+	}
+	if !reflect.DeepEqual(loaded.Checksums, want) {
+		t.Errorf("got %+v, want %+v", loaded.Checksums, want)
+	}
+}
+
+func TestLocksRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fw.bin")
+	s := &Sidecar{Locks: []Lock{{Start: 0, End: 0x3F}, {Start: 0x100, End: 0x10F}}}
+	if err := s.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Locks, s.Locks) || len(loaded.Checksums) != 0 {
+		t.Errorf("got %+v", loaded)
+	}
+}
@@ -1,27 +1,147 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
-	"unhexed/internal/editor"
+	"github.com/protohuf/unhexed/internal/config"
+	"github.com/protohuf/unhexed/internal/editor"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 func main() {
-	files := os.Args[1:]
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
+	}
+
+	noAltScreen := flag.Bool("no-altscreen", false, "draw in the main screen so the final view stays in scrollback")
+	monochrome := flag.Bool("monochrome", false, "render without colors (also enabled by NO_COLOR)")
+	colors := flag.String("color", "auto", "color support: auto, truecolor, 256, 16 or none")
+	writeDefault := flag.String("write-default-config", "", "write the default config, with comments, to `path` and exit")
+	exportTheme := flag.String("export-theme", "", "write the current theme to `path` and exit")
+	importTheme := flag.String("import-theme", "", "add the theme file at `path` to the config, make it current and exit")
+	themeName := flag.String("theme-name", "", "name for --import-theme (default: the file's base name)")
+	glob := flag.String("glob", "", "open the files matching `pattern` in directory arguments (default: ask)")
+	template := flag.String("template", "", "apply the first template of the file `name` (in the templates directory, or a path) at --offset")
+	offset := flag.String("offset", "", "start the cursor at `offset` (decimal or 0x hex)")
+	replay := flag.String("replay", "", "rebuild the editor from the diagnostic report `file` written with Alt+D, at its size")
+	flag.Parse()
+	files := flag.Args()
+
+	if *writeDefault != "" || *exportTheme != "" || *importTheme != "" {
+		if err := runConfigCommand(*writeDefault, *exportTheme, *importTheme, *themeName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts := editor.Options{
+		Monochrome: *monochrome || os.Getenv("NO_COLOR") != "",
+		Glob:       *glob,
+		Template:   *template,
+	}
+	if *offset != "" {
+		n, err := strconv.ParseInt(*offset, 0, 64)
+		if err != nil || n < 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid --offset %q\n", *offset)
+			os.Exit(2)
+		}
+		opts.Offset = n
+	}
+
+	// Theme colors are hex values; on terminals without truecolor they are
+	// downgraded to the nearest color the detected profile supports.
+	switch *colors {
+	case "auto":
+		lipgloss.SetColorProfile(termenv.NewOutput(os.Stdout).EnvColorProfile())
+	case "truecolor":
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case "256":
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case "16":
+		lipgloss.SetColorProfile(termenv.ANSI)
+	case "none":
+		opts.Monochrome = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --color value %q\n", *colors)
+		os.Exit(2)
+	}
+	if opts.Monochrome {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
 
-	model, err := editor.NewModel(files)
+	var model *editor.Model
+	var err error
+	if *replay != "" {
+		model, err = editor.Replay(*replay, opts)
+	} else {
+		model, err = editor.NewModel(files, opts)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	var programOpts []tea.ProgramOption
+	if !*noAltScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(model, programOpts...)
 
-	if _, err := p.Run(); err != nil {
+	// Save the terminal's title on its title stack (XTWINOPS), to be
+	// restored once the editor is done changing it
+	if model.TitleEnabled() {
+		fmt.Print("\x1b[22;0t")
+	}
+	_, err = p.Run()
+	model.ReleaseLocks()
+	if model.TitleEnabled() {
+		fmt.Print("\x1b[23;0t")
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runConfigCommand runs the config and theme flags that don't start the
+// editor.
+func runConfigCommand(writeDefault, exportTheme, importTheme, themeName string) error {
+	if writeDefault != "" {
+		if err := config.WriteDefault(writeDefault); err != nil {
+			return err
+		}
+	}
+	if exportTheme == "" && importTheme == "" {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if exportTheme != "" {
+		if err := config.ExportTheme(cfg.Theme, exportTheme); err != nil {
+			return err
+		}
+	}
+	if importTheme != "" {
+		theme, err := config.LoadTheme(importTheme)
+		if err != nil {
+			return err
+		}
+		if themeName == "" {
+			themeName = strings.TrimSuffix(filepath.Base(importTheme), filepath.Ext(importTheme))
+		}
+		cfg.ImportTheme(themeName, theme)
+		return cfg.Save()
+	}
+	return nil
+}
@@ -0,0 +1,1192 @@
+package buffer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// An Operation is an edit as recorded for undo: at Offset, OldData was
+// replaced by NewData. LastStep returns the ones the last Undo or Redo
+// applied.
+type Operation struct {
+	Type    OpType
+	Offset  int64
+	OldData []byte // nil for an insert
+	NewData []byte // nil for a delete
+	Group   int    // operations sharing a non-zero group undo and redo together
+	// Version is the buffer's Version just before the operation was made,
+	// which tells it apart from every other operation on the buffer.
+	Version uint64
+
+	oldTable, newTable pieceTable // the contents a restore swapped
+}
+
+// OpType is the kind of edit an Operation records.
+type OpType int
+
+// EditStats counts the bytes changed since the buffer was opened or
+// reverted. Undoing an edit takes its bytes back off the counts and redoing
+// it adds them again, so they describe the edits still in effect.
+type EditStats struct {
+	Replaced, Inserted, Deleted int64
+	Undos, Redos                int
+	OpenedSize                  int64 // when opened or reverted
+	First, Last                 time.Time
+}
+
+func (s *EditStats) count(op Operation, sign int64) {
+	switch op.Type {
+	case OpInsert:
+		s.Inserted += sign * int64(len(op.NewData))
+	case OpDelete:
+		s.Deleted += sign * int64(len(op.OldData))
+	case OpReplace:
+		s.Replaced += sign * int64(len(op.NewData))
+	case OpRestore:
+		if op.oldTable.size == op.newTable.size {
+			s.Replaced += sign * op.newTable.size
+		} else {
+			s.Deleted += sign * op.oldTable.size
+			s.Inserted += sign * op.newTable.size
+		}
+	}
+}
+
+func (s *EditStats) touch(now time.Time) {
+	s.Last = now
+	if s.First.IsZero() {
+		s.First = s.Last
+	}
+}
+
+// Change describes an edit: Removed bytes at Offset were replaced by
+// Inserted new ones, moving the bytes after them, or Overwritten bytes
+// there were changed in place, which moves nothing. When Swapped, the whole
+// contents were replaced by others, as Revert does; offsets then stay
+// where they are, within the new size.
+type Change struct {
+	Offset      int64 // where the edit starts
+	Removed     int64 // bytes removed at Offset
+	Inserted    int64 // bytes inserted at Offset in their place
+	Overwritten int64 // bytes changed in place at Offset
+	Swapped     bool  // Removed and Inserted are the old and new sizes
+}
+
+const (
+	OpInsert  OpType = iota // bytes were inserted, moving the rest up
+	OpDelete                // bytes were removed, moving the rest down
+	OpReplace               // bytes were overwritten in place
+	OpRestore               // the whole contents were swapped for others
+)
+
+// A Buffer holds the contents of a file being edited, with their undo
+// history. Create one with New, Open, FromData, OpenDevice or OpenWindow.
+// A Buffer is not safe for concurrent use; take a Snapshot to read it from
+// another goroutine.
+type Buffer struct {
+	filename     string
+	table        pieceTable
+	saved        pieceTable // contents as last read or saved
+	originalHash string
+	modified     bool
+	undoStack    []Operation
+	redoStack    []Operation
+	isNew        bool
+	groupDepth   int
+	groupSeq     int
+	curGroup     int
+	version      uint64
+	listeners    []*listener
+	window       *Window // set when only part of the file is loaded
+	device       bool    // read on demand and saved in place (see device.go)
+	readOnly     bool
+	lastStep     []Operation // applied by the last Undo or Redo
+	mods         modsCache   // see Modifications
+	stats        EditStats
+	locked       []Range          // see locked.go
+	holdsOrigin  bool             // see Close
+	now          func() time.Time // see SetClock
+}
+
+type listener struct {
+	fn func(Change)
+}
+
+// New returns an empty buffer for a file that doesn't exist yet.
+func New() *Buffer {
+	return &Buffer{
+		filename: "",
+		modified: false,
+		isNew:    true,
+	}
+}
+
+// Open reads filename into a new buffer. Devices and parts of files are
+// opened with OpenDevice and OpenWindow.
+func Open(filename string) (*Buffer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromData(filename, data), nil
+}
+
+// FromData wraps contents already read from filename, for callers that load
+// the file themselves (e.g. in chunks with progress reporting).
+func FromData(filename string, data []byte) *Buffer {
+	hash := sha256.Sum256(data)
+	table := newPieceTable(data)
+
+	return &Buffer{
+		filename:     filename,
+		table:        table,
+		saved:        table.clone(),
+		originalHash: hex.EncodeToString(hash[:]),
+		modified:     false,
+		isNew:        false,
+		stats:        EditStats{OpenedSize: table.size},
+	}
+}
+
+// FromExcerpt returns a buffer of size bytes holding data at off and zeros
+// everywhere else, without allocating the zeros, for rebuilding a file of
+// which only part is known. data must lie within size bytes.
+func FromExcerpt(filename string, size, off int64, data []byte) *Buffer {
+	table := newOriginTable(excerptOrigin{off: off, data: data}, size)
+	return &Buffer{
+		filename: filename,
+		table:    table,
+		saved:    table.clone(),
+		stats:    EditStats{OpenedSize: size},
+	}
+}
+
+// Clone returns an independent buffer with the same contents and filename
+// but no undo history. It shares storage with b instead of copying it, so
+// it is cheap enough to take as a consistent snapshot for background work.
+func (b *Buffer) Clone() *Buffer {
+	return &Buffer{
+		filename:     b.filename,
+		table:        b.table.clone(),
+		saved:        b.saved,
+		originalHash: b.originalHash,
+		modified:     b.modified,
+		isNew:        b.isNew,
+		version:      b.version,
+		window:       b.window,
+		device:       b.device,
+		readOnly:     b.readOnly,
+		stats:        b.stats,
+		now:          b.now,
+	}
+}
+
+// Snapshot returns a read-only Clone for reading in the background while b
+// is edited. Its Version is b's when it was taken, so a result computed
+// from it still describes b only while b.Version() is the same.
+func (b *Buffer) Snapshot() *Buffer {
+	s := b.Clone()
+	s.readOnly = true
+	return s
+}
+
+// SavedSize returns the size of the contents as they were last read from
+// or written to disk.
+func (b *Buffer) SavedSize() int64 {
+	return b.saved.size
+}
+
+// Saved returns the contents as they were last read from or written to
+// disk, as a buffer without undo history.
+func (b *Buffer) Saved() *Buffer {
+	return &Buffer{filename: b.filename, table: b.saved.clone(), readOnly: true}
+}
+
+// Copy returns a new, unnamed buffer with b's contents, sharing storage
+// with b until either is edited. It has no undo history and counts as
+// modified until saved, unless it is empty.
+func (b *Buffer) Copy() *Buffer {
+	c := &Buffer{table: b.table.clone(), modified: b.table.size > 0, isNew: true}
+	b.retainOrigin(c)
+	return c
+}
+
+// Filename is the file the buffer saves to, or "" for a new buffer.
+func (b *Buffer) Filename() string {
+	return b.filename
+}
+
+// SetFilename names the file Save writes, without saving.
+func (b *Buffer) SetFilename(name string) {
+	b.filename = name
+	b.isNew = false
+}
+
+// IsNew reports whether the buffer has never been read from or saved to a
+// file.
+func (b *Buffer) IsNew() bool {
+	return b.isNew
+}
+
+// IsModified reports whether the contents differ from the file's, as far
+// as edits since the last read or save go.
+func (b *Buffer) IsModified() bool {
+	return b.modified
+}
+
+// Size is the length of the contents in bytes.
+func (b *Buffer) Size() int64 {
+	return b.table.size
+}
+
+// Data returns a copy of the whole contents.
+//
+// Deprecated: it holds a second copy of the file in memory. Use Chunks or
+// ReadAt to go through the contents.
+func (b *Buffer) Data() []byte {
+	result := make([]byte, b.table.size)
+	b.table.readAt(result, 0)
+	return result
+}
+
+// GetByte returns the byte at offset, or false past the end.
+func (b *Buffer) GetByte(offset int64) (byte, bool) {
+	if offset < 0 || offset >= b.table.size {
+		return 0, false
+	}
+	return b.table.byteAt(offset), true
+}
+
+// GetBytes returns a copy of up to count bytes from offset, fewer at the
+// end of the contents.
+func (b *Buffer) GetBytes(offset int64, count int) []byte {
+	if offset < 0 || offset >= b.table.size {
+		return nil
+	}
+	end := offset + int64(count)
+	if end > b.table.size {
+		end = b.table.size
+	}
+	result := make([]byte, end-offset)
+	b.table.readAt(result, offset)
+	return result
+}
+
+// Chunks calls fn with the bytes from start up to end in order, at most
+// size bytes at a time, without copying them; fn must not modify or keep
+// them. It stops at the first error from fn and returns it.
+func (b *Buffer) Chunks(start, end int64, size int, fn func([]byte) error) error {
+	start, end = max(start, 0), min(end, b.table.size)
+	if err := b.table.rangeChunks(start, end, int64(size), fn); err != nil {
+		return err
+	}
+	return b.table.err()
+}
+
+// ReadAt implements io.ReaderAt over the current contents.
+func (b *Buffer) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+	if off >= b.table.size {
+		return 0, io.EOF
+	}
+	n := b.table.readAt(p, off)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Restore replaces the whole contents with src's as a single undo step.
+// When src is a Clone of b it shares b's storage, and its contents are
+// swapped in without copying them. A buffer whose size is fixed can only
+// be restored to contents of the same size.
+func (b *Buffer) Restore(src *Buffer) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if b.FixedSize() && src.table.size != b.table.size {
+		return ErrFixedSize
+	}
+	if err := b.checkLocked(0, b.table.size-1); err != nil {
+		return err
+	}
+	table := src.table.clone()
+	if !sameOrigin(table.original, b.table.original) {
+		table = pieceTable{original: b.table.original}
+		err := src.Chunks(0, src.table.size, hashChunk, func(data []byte) error {
+			table.insert(table.size, data)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	op := Operation{Type: OpRestore, oldTable: b.table.clone(), newTable: table}
+	b.pushUndo(op)
+
+	b.table = table.clone()
+	b.modified = true
+	b.notify(restoreChange(op.oldTable.size, op.newTable.size))
+	return nil
+}
+
+// restoreChange is the Change swapping from bytes of contents for to bytes.
+func restoreChange(from, to int64) Change {
+	if from == to {
+		return Change{Overwritten: to}
+	}
+	return Change{Removed: from, Inserted: to, Swapped: true}
+}
+
+// BeginGroup starts a compound operation: every edit until the matching
+// EndGroup is undone and redone as a single step. Groups may nest.
+func (b *Buffer) BeginGroup() {
+	if b.groupDepth == 0 {
+		b.groupSeq++
+		b.curGroup = b.groupSeq
+	}
+	b.groupDepth++
+}
+
+// EndGroup ends the group BeginGroup started.
+func (b *Buffer) EndGroup() {
+	if b.groupDepth == 0 {
+		return
+	}
+	b.groupDepth--
+	if b.groupDepth == 0 {
+		b.curGroup = 0
+	}
+}
+
+func (b *Buffer) pushUndo(op Operation) {
+	op.Group = b.curGroup
+	op.Version = b.version
+	b.undoStack = append(b.undoStack, op)
+	b.redoStack = nil
+	b.version++
+	b.stats.count(op, 1)
+	b.stats.touch(b.clock())
+}
+
+// SetClock sets the clock EditStats times edits by; time.Now is used
+// until it is set.
+func (b *Buffer) SetClock(now func() time.Time) {
+	b.now = now
+}
+
+func (b *Buffer) clock() time.Time {
+	if b.now == nil {
+		return time.Now()
+	}
+	return b.now()
+}
+
+// Stats returns the edit counts.
+func (b *Buffer) Stats() EditStats {
+	return b.stats
+}
+
+// Revert discards every change since the file was last read or saved,
+// with the undo history, and resets the edit counts.
+func (b *Buffer) Revert() {
+	if b.readOnly {
+		return
+	}
+	removed := b.table.size
+	b.table = b.saved.clone()
+	b.undoStack, b.redoStack, b.lastStep = nil, nil, nil
+	b.forgetModifications()
+	b.modified = false
+	b.stats = EditStats{OpenedSize: b.table.size}
+	b.version++
+	b.notify(Change{Offset: 0, Removed: removed, Inserted: b.table.size, Swapped: true})
+}
+
+// Listen calls fn after every edit, including those made by undo and redo,
+// so offsets held outside the buffer can follow the data and what is
+// cached about it be refreshed.
+// The returned function stops the notifications.
+func (b *Buffer) Listen(fn func(Change)) (cancel func()) {
+	l := &listener{fn: fn}
+	b.listeners = append(b.listeners, l)
+	return func() {
+		for i, other := range b.listeners {
+			if other == l {
+				b.listeners = append(b.listeners[:i:i], b.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *Buffer) notify(c Change) {
+	b.shiftLocked(c)
+	for _, l := range b.listeners {
+		l.fn(c)
+	}
+}
+
+// Version increases with every change to the contents, including undo and
+// redo, so callers can cheaply tell whether cached views are stale.
+func (b *Buffer) Version() uint64 {
+	return b.version
+}
+
+// Insert inserts data before offset, or appends it when offset is the
+// size. Like every edit it can be undone, and it fails while the buffer is
+// read-only or if its size is fixed.
+func (b *Buffer) Insert(offset int64, data []byte) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if b.FixedSize() {
+		return ErrFixedSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > b.table.size {
+		offset = b.table.size
+	}
+	if err := b.checkInsert(offset); err != nil {
+		return err
+	}
+
+	op := Operation{
+		Type:    OpInsert,
+		Offset:  offset,
+		NewData: make([]byte, len(data)),
+	}
+	copy(op.NewData, data)
+	b.pushUndo(op)
+
+	b.table.insert(offset, data)
+	b.modified = true
+	b.notify(Change{Offset: offset, Inserted: int64(len(data))})
+	return nil
+}
+
+// ErrOutOfRange is returned by edits of bytes the buffer doesn't have.
+var ErrOutOfRange = errors.New("offset is past the end of the buffer")
+
+// Delete removes up to count bytes from offset. It fails if the buffer's
+// size is fixed.
+func (b *Buffer) Delete(offset int64, count int) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if b.FixedSize() {
+		return ErrFixedSize
+	}
+	if offset < 0 || offset >= b.table.size || count <= 0 {
+		return ErrOutOfRange
+	}
+	if offset+int64(count) > b.table.size {
+		count = int(b.table.size - offset)
+	}
+	if err := b.checkLocked(offset, offset+int64(count)-1); err != nil {
+		return err
+	}
+
+	op := Operation{
+		Type:    OpDelete,
+		Offset:  offset,
+		OldData: make([]byte, count),
+	}
+	b.table.readAt(op.OldData, offset)
+	b.pushUndo(op)
+
+	b.table.remove(offset, int64(count))
+	b.modified = true
+	b.notify(Change{Offset: offset, Removed: int64(count)})
+	return nil
+}
+
+// Replace overwrites the byte at offset. ReplaceBytes overwrites several.
+func (b *Buffer) Replace(offset int64, newByte byte) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if offset < 0 || offset >= b.table.size {
+		return ErrOutOfRange
+	}
+	if err := b.checkLocked(offset, offset); err != nil {
+		return err
+	}
+
+	op := Operation{
+		Type:    OpReplace,
+		Offset:  offset,
+		OldData: []byte{b.table.byteAt(offset)},
+		NewData: []byte{newByte},
+	}
+	b.pushUndo(op)
+
+	b.table.overwrite(offset, op.NewData)
+	b.modified = true
+	b.notify(Change{Offset: offset, Overwritten: 1})
+	return nil
+}
+
+// Amend overwrites the byte at offset as part of the last undo step when
+// that step wrote it, so a byte typed one nibble at a time undoes at once.
+// Otherwise it is a Replace.
+func (b *Buffer) Amend(offset int64, newByte byte) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if offset < 0 || offset >= b.table.size {
+		return ErrOutOfRange
+	}
+	if err := b.checkLocked(offset, offset); err != nil {
+		return err
+	}
+	if n := len(b.undoStack); n > 0 {
+		op := &b.undoStack[n-1]
+		if (op.Type == OpReplace || op.Type == OpInsert) && offset >= op.Offset && offset < op.Offset+int64(len(op.NewData)) {
+			op.NewData = append([]byte(nil), op.NewData...)
+			op.NewData[offset-op.Offset] = newByte
+			b.table.overwrite(offset, []byte{newByte})
+			b.redoStack = nil
+			b.version++
+			b.modified = true
+			b.notify(Change{Offset: offset, Overwritten: 1})
+			b.stats.touch(b.clock())
+			return nil
+		}
+	}
+	return b.Replace(offset, newByte)
+}
+
+// ReplaceBytes overwrites data starting at offset as a single undo step,
+// extending the file when the data runs past the end.
+func (b *Buffer) ReplaceBytes(offset int64, data []byte) error {
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > b.table.size {
+		offset = b.table.size
+	}
+
+	overlap := int64(len(data))
+	if offset+overlap > b.table.size {
+		overlap = b.table.size - offset
+	}
+	if err := b.checkLocked(offset, offset+overlap-1); overlap > 0 && err != nil {
+		return err
+	}
+
+	b.BeginGroup()
+	defer b.EndGroup()
+
+	if overlap > 0 {
+		op := Operation{
+			Type:    OpReplace,
+			Offset:  offset,
+			OldData: make([]byte, overlap),
+			NewData: make([]byte, overlap),
+		}
+		b.table.readAt(op.OldData, offset)
+		copy(op.NewData, data[:overlap])
+		b.pushUndo(op)
+
+		b.table.overwrite(offset, op.NewData)
+		b.modified = true
+		b.notify(Change{Offset: offset, Overwritten: int64(len(op.NewData))})
+	}
+
+	if overlap < int64(len(data)) {
+		// Extend file
+		return b.Insert(b.table.size, data[overlap:])
+	}
+	return nil
+}
+
+// Transform replaces count bytes from offset with fn applied to each, as a
+// single undo step. Bytes past the end of the buffer are left alone.
+func (b *Buffer) Transform(offset, count int64, fn func(byte) byte) error {
+	if offset < 0 {
+		count += offset
+		offset = 0
+	}
+	count = min(count, b.table.size-offset)
+	if count <= 0 {
+		return nil
+	}
+	data := make([]byte, count)
+	b.table.readAt(data, offset)
+	for i, c := range data {
+		data[i] = fn(c)
+	}
+	return b.ReplaceBytes(offset, data)
+}
+
+// History returns the operations Undo can take back, oldest first, and
+// those Redo can apply again, the next one last. They are the buffer's
+// own and must not be modified.
+func (b *Buffer) History() (undo, redo []Operation) {
+	return b.undoStack, b.redoStack
+}
+
+// LastStep returns the operations the last Undo or Redo applied, in the
+// order they were first made.
+func (b *Buffer) LastStep() []Operation {
+	return b.lastStep
+}
+
+// Undo reverts the last step, reporting whether there was one. An
+// operation that no longer fits the buffer can't be undone: the step is
+// rolled back, the history from it on is dropped and an error returned.
+func (b *Buffer) Undo() (bool, error) {
+	if b.readOnly || len(b.undoStack) == 0 {
+		return false, nil
+	}
+
+	b.lastStep = nil
+	group := b.undoStack[len(b.undoStack)-1].Group
+	for len(b.undoStack) > 0 {
+		op := b.undoStack[len(b.undoStack)-1]
+		if op.Group != group {
+			break
+		}
+		if err := b.check(op, true); err != nil {
+			// lastStep starts with the operation undone last
+			for _, done := range b.lastStep {
+				b.redoOp(done)
+			}
+			b.undoStack = nil
+			b.redoStack = nil
+			b.lastStep = nil
+			b.forgetModifications()
+			return false, err
+		}
+		b.undoStack = b.undoStack[:len(b.undoStack)-1]
+		b.undoOp(op)
+		b.redoStack = append(b.redoStack, op)
+		b.lastStep = append([]Operation{op}, b.lastStep...)
+		if group == 0 {
+			break
+		}
+	}
+
+	b.forgetModifications()
+	b.modified = len(b.undoStack) > 0
+	b.stats.Undos++
+	b.stats.touch(b.clock())
+	return true, nil
+}
+
+// check reports an error when op can't be undone, or redone, at its
+// offsets in the buffer as it is now.
+func (b *Buffer) check(op Operation, undo bool) error {
+	if op.Type == OpRestore {
+		size := op.oldTable.size
+		if undo {
+			size = op.newTable.size
+		}
+		if size != b.table.size {
+			return fmt.Errorf("%w: restoring %d bytes in a %d-byte buffer", ErrBadHistory, size, b.table.size)
+		}
+		return nil
+	}
+
+	// The bytes that must exist at op.Offset, or -1 when it only inserts
+	need := int64(-1)
+	switch {
+	case op.Type == OpInsert && undo:
+		need = int64(len(op.NewData))
+	case op.Type == OpDelete && !undo:
+		need = int64(len(op.OldData))
+	case op.Type == OpReplace && undo:
+		need = int64(len(op.OldData))
+	case op.Type == OpReplace:
+		need = int64(len(op.NewData))
+	}
+	if op.Offset < 0 || op.Offset > b.table.size || op.Offset+max(need, 0) > b.table.size {
+		return fmt.Errorf("%w: %d bytes at 0x%X in a %d-byte buffer", ErrBadHistory, max(need, 0), op.Offset, b.table.size)
+	}
+	return nil
+}
+
+// ErrBadHistory is returned when an undo or redo step doesn't fit the
+// buffer any more.
+var ErrBadHistory = errors.New("edit history doesn't match the buffer")
+
+func (b *Buffer) undoOp(op Operation) {
+	b.version++
+	b.stats.count(op, -1)
+	switch op.Type {
+	case OpInsert:
+		// Undo insert = delete
+		b.table.remove(op.Offset, int64(len(op.NewData)))
+		b.notify(Change{Offset: op.Offset, Removed: int64(len(op.NewData))})
+	case OpDelete:
+		// Undo delete = insert
+		b.table.insert(op.Offset, op.OldData)
+		b.notify(Change{Offset: op.Offset, Inserted: int64(len(op.OldData))})
+	case OpReplace:
+		// Undo replace = restore old bytes
+		b.table.overwrite(op.Offset, op.OldData)
+		b.notify(Change{Offset: op.Offset, Overwritten: int64(len(op.OldData))})
+	case OpRestore:
+		b.table = op.oldTable.clone()
+		b.notify(restoreChange(op.newTable.size, op.oldTable.size))
+	}
+}
+
+// Redo applies the last undone step again, with the same checks as Undo.
+func (b *Buffer) Redo() (bool, error) {
+	if b.readOnly || len(b.redoStack) == 0 {
+		return false, nil
+	}
+
+	b.lastStep = nil
+	group := b.redoStack[len(b.redoStack)-1].Group
+	for len(b.redoStack) > 0 {
+		op := b.redoStack[len(b.redoStack)-1]
+		if op.Group != group {
+			break
+		}
+		if err := b.check(op, false); err != nil {
+			for i := len(b.lastStep) - 1; i >= 0; i-- {
+				b.undoOp(b.lastStep[i])
+			}
+			b.undoStack = nil
+			b.redoStack = nil
+			b.lastStep = nil
+			b.forgetModifications()
+			return false, err
+		}
+		b.redoStack = b.redoStack[:len(b.redoStack)-1]
+		b.redoOp(op)
+		b.undoStack = append(b.undoStack, op)
+		b.lastStep = append(b.lastStep, op)
+		if group == 0 {
+			break
+		}
+	}
+
+	b.modified = true
+	b.stats.Redos++
+	b.stats.touch(b.clock())
+	return true, nil
+}
+
+func (b *Buffer) redoOp(op Operation) {
+	b.version++
+	b.stats.count(op, 1)
+	switch op.Type {
+	case OpInsert:
+		b.table.insert(op.Offset, op.NewData)
+		b.notify(Change{Offset: op.Offset, Inserted: int64(len(op.NewData))})
+	case OpDelete:
+		b.table.remove(op.Offset, int64(len(op.OldData)))
+		b.notify(Change{Offset: op.Offset, Removed: int64(len(op.OldData))})
+	case OpReplace:
+		b.table.overwrite(op.Offset, op.NewData)
+		b.notify(Change{Offset: op.Offset, Overwritten: int64(len(op.NewData))})
+	case OpRestore:
+		b.table = op.newTable.clone()
+		b.notify(restoreChange(op.oldTable.size, op.newTable.size))
+	}
+}
+
+// CanUndo reports whether Undo has an edit to undo.
+func (b *Buffer) CanUndo() bool {
+	return len(b.undoStack) > 0
+}
+
+// CanRedo reports whether Redo has an undone edit to apply again.
+func (b *Buffer) CanRedo() bool {
+	return len(b.redoStack) > 0
+}
+
+// HasChangedOnDisk reports whether the file differs from the contents it
+// had when it was last read or saved.
+func (b *Buffer) HasChangedOnDisk() (bool, error) {
+	return b.HasChangedOnDiskContext(context.Background())
+}
+
+// HasChangedOnDiskContext is HasChangedOnDisk for large files: it gives up
+// and returns ctx.Err() once ctx is cancelled.
+func (b *Buffer) HasChangedOnDiskContext(ctx context.Context) (bool, error) {
+	// Devices aren't hashed: reading one whole would take too long
+	if b.isNew || b.filename == "" || b.device {
+		return false, nil
+	}
+
+	currentHash, err := b.DiskHashContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return currentHash != b.originalHash, nil
+}
+
+// OriginalHash returns the SHA-256 of the file as last opened or saved, or
+// "" for a new buffer.
+func (b *Buffer) OriginalHash() string {
+	return b.originalHash
+}
+
+// hashChunk bounds how much is hashed between cancellation checks.
+const hashChunk = 1 << 20
+
+// HashContext returns the SHA-256 of the current contents.
+func (b *Buffer) HashContext(ctx context.Context) (string, error) {
+	h := sha256.New()
+	err := b.table.chunks(hashChunk, func(data []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		h.Write(data)
+		return nil
+	})
+	if err == nil {
+		err = b.table.err()
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFileContext returns the SHA-256 of the file at path.
+func HashFileContext(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hashReader(ctx, f)
+}
+
+func hashReader(ctx context.Context, r io.Reader) (string, error) {
+	h := sha256.New()
+	chunk := make([]byte, hashChunk)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := r.Read(chunk)
+		h.Write(chunk[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeTo streams the contents piece by piece to every writer.
+func (b *Buffer) writeTo(writers ...io.Writer) error {
+	w := io.MultiWriter(writers...)
+	err := b.table.chunks(hashChunk, func(data []byte) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return b.table.err()
+}
+
+// Save writes the contents to the buffer's file and clears the undo
+// history. Files are replaced through a temporary file (see save.go);
+// devices, windows and other files that aren't regular, such as pipes,
+// are written in place.
+func (b *Buffer) Save() error {
+	if b.filename == "" {
+		return fmt.Errorf("no filename set")
+	}
+	if b.readOnly {
+		return ErrReadOnly
+	}
+	if b.window != nil {
+		return b.saveWindow()
+	}
+	if b.device {
+		return b.saveInPlace()
+	}
+	if info, err := os.Stat(b.filename); err == nil && !info.Mode().IsRegular() {
+		return b.saveStream()
+	}
+
+	s, err := b.NewSaver()
+	if err != nil {
+		return err
+	}
+	return s.Commit()
+}
+
+// saveStream writes the contents over the file from the start.
+func (b *Buffer) saveStream() error {
+	f, err := os.OpenFile(b.filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	sparse := newSparseWriter(f)
+	out := bufio.NewWriterSize(sparse, 64*1024)
+	h := sha256.New()
+	if err := b.writeTo(out, h); err != nil {
+		f.Close()
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := sparse.finish(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	b.markSaved(hex.EncodeToString(h.Sum(nil)))
+	return nil
+}
+
+// markSaved records that the contents, hashing to hash, are now on disk.
+func (b *Buffer) markSaved(hash string) {
+	b.saved = b.table.clone()
+	b.originalHash = hash
+	b.modified = false
+	b.undoStack = nil
+	b.redoStack = nil
+	b.forgetModifications()
+	b.isNew = false
+}
+
+// SaveAs writes the contents to filename and makes it the buffer's file.
+// A window or device buffer becomes a whole, ordinary file of its own. On
+// failure the buffer keeps its previous filename and kind.
+func (b *Buffer) SaveAs(filename string) error {
+	prev, prevWindow, prevDevice, prevReadOnly := b.filename, b.window, b.device, b.readOnly
+	b.filename, b.window, b.device, b.readOnly = filename, nil, false, false
+	if err := b.Save(); err != nil {
+		b.filename, b.window, b.device, b.readOnly = prev, prevWindow, prevDevice, prevReadOnly
+		return err
+	}
+	return nil
+}
+
+const searchChunk = 64 * 1024
+
+// Alignment restricts searches to matches starting where Base+offset is a
+// multiple of To, Base being where offset 0 sits, e.g. a window's offset in
+// its file. The zero Alignment allows any offset.
+type Alignment struct {
+	To   int64
+	Base int64
+}
+
+func (a Alignment) allows(offset int64) bool {
+	return a.To <= 1 || (a.Base+offset)%a.To == 0
+}
+
+// Find returns the offset of the first match of pattern at or after
+// startOffset, or with forward unset the last one starting before it, or
+// -1.
+func (b *Buffer) Find(pattern []byte, startOffset int64, forward bool) int64 {
+	return b.FindAligned(pattern, startOffset, forward, Alignment{})
+}
+
+// FindAligned is Find for matches that a allows only.
+func (b *Buffer) FindAligned(pattern []byte, startOffset int64, forward bool, a Alignment) int64 {
+	size := b.table.size
+	plen := int64(len(pattern))
+	if plen == 0 || size == 0 {
+		return -1
+	}
+
+	// Matches are searched in windows overlapping by len(pattern)-1 bytes,
+	// so a match spanning two chunks is still seen whole.
+	window := make([]byte, searchChunk+plen-1)
+
+	if forward {
+		if startOffset < 0 {
+			startOffset = 0
+		}
+		for pos := startOffset; pos <= size-plen; pos += searchChunk {
+			n := b.table.readAt(window, pos)
+			for idx := 0; ; {
+				i := bytes.Index(window[idx:n], pattern)
+				if i < 0 {
+					break
+				}
+				if a.allows(pos + int64(idx+i)) {
+					return pos + int64(idx+i)
+				}
+				idx += i + 1
+			}
+		}
+	} else {
+		start := startOffset - 1
+		if start > size-plen {
+			start = size - plen
+		}
+		for hi := start; hi >= 0; hi -= searchChunk {
+			lo := hi - searchChunk + 1
+			if lo < 0 {
+				lo = 0
+			}
+			n := b.table.readAt(window[:hi-lo+plen], lo)
+			for n > 0 {
+				i := bytes.LastIndex(window[:n], pattern)
+				if i < 0 {
+					break
+				}
+				if a.allows(lo + int64(i)) {
+					return lo + int64(i)
+				}
+				n = i + len(pattern) - 1
+			}
+		}
+	}
+
+	return -1
+}
+
+// CountMatches counts every occurrence of pattern, including overlapping
+// ones: "aa" occurs three times in "aaaa".
+func (b *Buffer) CountMatches(pattern []byte) int {
+	count, _ := b.CountMatchesContext(context.Background(), pattern)
+	return count
+}
+
+// CountMatchesContext is CountMatches for long scans: it gives up and
+// returns ctx.Err() once ctx is cancelled.
+func (b *Buffer) CountMatchesContext(ctx context.Context, pattern []byte) (int, error) {
+	return b.CountMatchesAlignedContext(ctx, pattern, Alignment{})
+}
+
+// CountMatchesAlignedContext is CountMatchesContext for matches that a
+// allows only.
+func (b *Buffer) CountMatchesAlignedContext(ctx context.Context, pattern []byte, a Alignment) (int, error) {
+	return b.CountMatchesInContext(ctx, pattern, a, 0, b.table.size)
+}
+
+// CountMatchesInContext is CountMatchesAlignedContext for the matches that
+// lie wholly within [start, end).
+func (b *Buffer) CountMatchesInContext(ctx context.Context, pattern []byte, a Alignment, start, end int64) (int, error) {
+	start, end = max(start, 0), min(end, b.table.size)
+	plen := int64(len(pattern))
+	if plen == 0 || end-start < plen {
+		return 0, nil
+	}
+
+	count := 0
+	window := make([]byte, searchChunk+plen-1)
+	for pos := start; pos <= end-plen; pos += searchChunk {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		n := min(b.table.readAt(window, pos), int(end-pos))
+		for idx := 0; ; {
+			i := bytes.Index(window[idx:n], pattern)
+			if i < 0 || idx+i >= searchChunk {
+				break
+			}
+			if a.allows(pos + int64(idx+i)) {
+				count++
+			}
+			idx += i + 1
+		}
+	}
+	return count, nil
+}
+
+// FindAllContext returns the offsets of the matches of pattern that don't
+// overlap, first to last, the ones a replace-all changes: "aa" is found
+// twice in "aaaaa". It gives up and returns ctx.Err() once ctx is
+// cancelled.
+func (b *Buffer) FindAllContext(ctx context.Context, pattern []byte) ([]int64, error) {
+	size := b.table.size
+	plen := int64(len(pattern))
+	if plen == 0 || size == 0 {
+		return nil, nil
+	}
+
+	var offsets []int64
+	next := int64(0) // where the next match may start
+	window := make([]byte, searchChunk+plen-1)
+	for pos := int64(0); pos <= size-plen; pos += searchChunk {
+		if err := ctx.Err(); err != nil {
+			return offsets, err
+		}
+		n := b.table.readAt(window, pos)
+		for idx := max(next-pos, 0); idx < searchChunk && idx < int64(n); {
+			i := bytes.Index(window[idx:n], pattern)
+			if i < 0 || idx+int64(i) >= searchChunk {
+				break
+			}
+			offsets = append(offsets, pos+idx+int64(i))
+			idx += int64(i) + plen
+			next = pos + idx
+		}
+	}
+	return offsets, nil
+}
+
+// FindEachContext returns the offsets of the first limit matches of
+// pattern that a allows, first to last, overlapping ones included: "aa" is
+// found three times in "aaaa". It gives up and returns ctx.Err() once ctx
+// is cancelled.
+func (b *Buffer) FindEachContext(ctx context.Context, pattern []byte, a Alignment, limit int) ([]int64, error) {
+	size := b.table.size
+	plen := int64(len(pattern))
+	if plen == 0 || size == 0 {
+		return nil, nil
+	}
+
+	var offsets []int64
+	window := make([]byte, searchChunk+plen-1)
+	for pos := int64(0); pos <= size-plen; pos += searchChunk {
+		if err := ctx.Err(); err != nil {
+			return offsets, err
+		}
+		n := b.table.readAt(window, pos)
+		for idx := 0; ; {
+			i := bytes.Index(window[idx:n], pattern)
+			if i < 0 || idx+i >= searchChunk {
+				break
+			}
+			if a.allows(pos + int64(idx+i)) {
+				if len(offsets) == limit {
+					return offsets, nil
+				}
+				offsets = append(offsets, pos+int64(idx+i))
+			}
+			idx += i + 1
+		}
+	}
+	return offsets, nil
+}
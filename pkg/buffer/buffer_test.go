@@ -0,0 +1,993 @@
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	b := New()
+	if b.Size() != 0 {
+		t.Errorf("expected size 0, got %d", b.Size())
+	}
+	if !b.IsNew() {
+		t.Error("expected IsNew to be true")
+	}
+}
+
+func TestFromExcerpt(t *testing.T) {
+	b := FromExcerpt("x", 1<<40, 1<<30, []byte("abc"))
+	if b.Size() != 1<<40 || b.IsModified() {
+		t.Fatalf("size %d, modified %v", b.Size(), b.IsModified())
+	}
+	if got := b.GetBytes(1<<30-2, 7); !bytes.Equal(got, []byte{0, 0, 'a', 'b', 'c', 0, 0}) {
+		t.Errorf("around the excerpt: % X", got)
+	}
+	b.Insert(1<<30+1, []byte("-"))
+	if got := b.GetBytes(1<<30, 4); string(got) != "a-bc" {
+		t.Errorf("after an insert: %q", got)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41, 0x42, 0x43})
+
+	if b.Size() != 3 {
+		t.Errorf("expected size 3, got %d", b.Size())
+	}
+
+	if val, ok := b.GetByte(0); !ok || val != 0x41 {
+		t.Errorf("expected 0x41 at offset 0, got %02X", val)
+	}
+	if val, ok := b.GetByte(1); !ok || val != 0x42 {
+		t.Errorf("expected 0x42 at offset 1, got %02X", val)
+	}
+	if val, ok := b.GetByte(2); !ok || val != 0x43 {
+		t.Errorf("expected 0x43 at offset 2, got %02X", val)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41, 0x42, 0x43, 0x44})
+	b.Delete(1, 2) // Delete 0x42 and 0x43
+
+	if b.Size() != 2 {
+		t.Errorf("expected size 2, got %d", b.Size())
+	}
+
+	if val, ok := b.GetByte(0); !ok || val != 0x41 {
+		t.Errorf("expected 0x41 at offset 0, got %02X", val)
+	}
+	if val, ok := b.GetByte(1); !ok || val != 0x44 {
+		t.Errorf("expected 0x44 at offset 1, got %02X", val)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41, 0x42, 0x43})
+	b.Replace(1, 0xFF)
+
+	if val, ok := b.GetByte(1); !ok || val != 0xFF {
+		t.Errorf("expected 0xFF at offset 1, got %02X", val)
+	}
+}
+
+func TestRefusedEdits(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41, 0x42})
+	version := b.Version()
+
+	if err := b.Replace(2, 0xFF); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Replace past the end: got %v", err)
+	}
+	if err := b.Amend(-1, 0xFF); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Amend before the start: got %v", err)
+	}
+	if err := b.Delete(2, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Delete past the end: got %v", err)
+	}
+	b.SetReadOnly(true)
+	if err := b.Replace(0, 0xFF); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Replace while read-only: got %v", err)
+	}
+	if err := b.Delete(0, 1); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete while read-only: got %v", err)
+	}
+	if b.Version() != version || b.Size() != 2 {
+		t.Error("a refused edit changed the buffer")
+	}
+}
+
+func TestUndo(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41})
+
+	if !b.CanUndo() {
+		t.Error("expected CanUndo to be true")
+	}
+
+	b.Undo()
+
+	if b.Size() != 0 {
+		t.Errorf("expected size 0 after undo, got %d", b.Size())
+	}
+}
+
+func TestRedo(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x41})
+	b.Undo()
+
+	if !b.CanRedo() {
+		t.Error("expected CanRedo to be true")
+	}
+
+	b.Redo()
+
+	if b.Size() != 1 {
+		t.Errorf("expected size 1 after redo, got %d", b.Size())
+	}
+}
+
+func TestFind(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("Hello, World!"))
+
+	pos := b.Find([]byte("World"), 0, true)
+	if pos != 7 {
+		t.Errorf("expected position 7, got %d", pos)
+	}
+
+	pos = b.Find([]byte("xyz"), 0, true)
+	if pos != -1 {
+		t.Errorf("expected -1 for not found, got %d", pos)
+	}
+}
+
+func TestOpenAndSave(t *testing.T) {
+	// Create temp file
+	f, err := os.CreateTemp("", "unhexed_test_*.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	testData := []byte{0x01, 0x02, 0x03, 0x04, 0x05}
+	f.Write(testData)
+	f.Close()
+
+	// Open file
+	b, err := Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Size() != 5 {
+		t.Errorf("expected size 5, got %d", b.Size())
+	}
+
+	// Modify and save
+	b.Replace(2, 0xFF)
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen and verify
+	b2, err := Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val, ok := b2.GetByte(2); !ok || val != 0xFF {
+		t.Errorf("expected 0xFF at offset 2, got %02X", val)
+	}
+}
+
+func TestSavedTracksLastSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saved.bin")
+	b := FromData(path, []byte{1, 2, 3})
+	b.Replace(0, 9)
+	if got := b.Saved().GetBytes(0, 3); !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("before saving, expected the contents read, got % X", got)
+	}
+
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b.Insert(0, []byte{7})
+	if got := b.Saved().GetBytes(0, 4); !bytes.Equal(got, []byte{9, 2, 3}) {
+		t.Errorf("after saving, expected the contents saved, got % X", got)
+	}
+}
+
+func TestSaveAsFailureKeepsFilename(t *testing.T) {
+	b := FromData("original.bin", []byte{0x01})
+	b.Replace(0, 0x02)
+
+	// A directory can't be written as a file
+	if err := b.SaveAs(t.TempDir()); err == nil {
+		t.Fatal("expected SaveAs to a directory to fail")
+	}
+	if b.Filename() != "original.bin" {
+		t.Errorf("expected filename to stay original.bin, got %q", b.Filename())
+	}
+	if !b.IsModified() {
+		t.Error("expected buffer to stay modified")
+	}
+}
+
+func TestGetBytes(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x01, 0x02, 0x03, 0x04, 0x05})
+
+	bytes := b.GetBytes(1, 3)
+	if len(bytes) != 3 {
+		t.Errorf("expected 3 bytes, got %d", len(bytes))
+	}
+	if bytes[0] != 0x02 || bytes[1] != 0x03 || bytes[2] != 0x04 {
+		t.Errorf("unexpected bytes: %v", bytes)
+	}
+}
+
+func TestCountMatches(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("ababab"))
+
+	count := b.CountMatches([]byte("ab"))
+	if count != 3 {
+		t.Errorf("expected 3 matches, got %d", count)
+	}
+}
+
+func TestGroupUndoRedo(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x01, 0x02, 0x03, 0x04})
+
+	b.BeginGroup()
+	b.Replace(0, 0xAA)
+	b.Replace(2, 0xBB)
+	b.Delete(3, 1)
+	b.EndGroup()
+
+	if ok, err := b.Undo(); !ok || err != nil {
+		t.Fatalf("expected Undo to succeed, got %v", err)
+	}
+	if b.Size() != 4 {
+		t.Errorf("expected size 4 after group undo, got %d", b.Size())
+	}
+	if v, _ := b.GetByte(0); v != 0x01 {
+		t.Errorf("expected 0x01 at offset 0, got %02X", v)
+	}
+	if v, _ := b.GetByte(2); v != 0x03 {
+		t.Errorf("expected 0x03 at offset 2, got %02X", v)
+	}
+
+	// The initial insert is still on the undo stack as its own step
+	if !b.CanUndo() {
+		t.Error("expected the ungrouped insert to remain undoable")
+	}
+
+	b.Redo()
+	if b.Size() != 3 {
+		t.Errorf("expected size 3 after group redo, got %d", b.Size())
+	}
+	if v, _ := b.GetByte(2); v != 0xBB {
+		t.Errorf("expected 0xBB at offset 2, got %02X", v)
+	}
+}
+
+func TestReplaceBytes(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte{0x01, 0x02, 0x03})
+	b.ReplaceBytes(1, []byte{0xAA, 0xBB, 0xCC})
+
+	expected := []byte{0x01, 0xAA, 0xBB, 0xCC}
+	if b.Size() != int64(len(expected)) {
+		t.Fatalf("expected size %d, got %d", len(expected), b.Size())
+	}
+	for i, want := range expected {
+		if got, _ := b.GetByte(int64(i)); got != want {
+			t.Errorf("offset %d: expected %02X, got %02X", i, want, got)
+		}
+	}
+
+	// Overwrite and extension undo as one step
+	b.Undo()
+	if b.Size() != 3 {
+		t.Errorf("expected size 3 after undo, got %d", b.Size())
+	}
+	if v, _ := b.GetByte(1); v != 0x02 {
+		t.Errorf("expected 0x02 at offset 1 after undo, got %02X", v)
+	}
+}
+
+func TestPieceTableMatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	b := New()
+	var ref []byte
+	var history [][]byte // reference contents before each undoable edit
+
+	for step := 0; step < 2000; step++ {
+		before := append([]byte{}, ref...)
+
+		switch op := rng.Intn(10); {
+		case op < 4 || len(ref) == 0:
+			off := rng.Intn(len(ref) + 1)
+			data := make([]byte, rng.Intn(8)+1)
+			rng.Read(data)
+			b.Insert(int64(off), data)
+			ref = append(ref[:off:off], append(data, ref[off:]...)...)
+			history = append(history, before)
+		case op < 6:
+			off := rng.Intn(len(ref))
+			n := rng.Intn(8) + 1
+			if off+n > len(ref) {
+				n = len(ref) - off
+			}
+			b.Delete(int64(off), n)
+			ref = append(ref[:off:off], ref[off+n:]...)
+			history = append(history, before)
+		case op < 8:
+			off := rng.Intn(len(ref))
+			v := byte(rng.Intn(256))
+			b.Replace(int64(off), v)
+			ref[off] = v
+			history = append(history, before)
+		case len(history) > 0:
+			b.Undo()
+			ref = history[len(history)-1]
+			history = history[:len(history)-1]
+		}
+
+		if b.Size() != int64(len(ref)) {
+			t.Fatalf("step %d: expected size %d, got %d", step, len(ref), b.Size())
+		}
+		if !bytes.Equal(b.Data(), ref) {
+			t.Fatalf("step %d: contents diverged from reference", step)
+		}
+	}
+}
+
+func TestFindAligned(t *testing.T) {
+	data := make([]byte, 2*searchChunk)
+	pattern := []byte{0xAB, 0xAB}
+	for _, off := range []int{3, 8, 10, searchChunk - 1, searchChunk + 4} {
+		copy(data[off:], pattern)
+	}
+	b := New()
+	b.Insert(0, data)
+
+	a := Alignment{To: 4}
+	if pos := b.FindAligned(pattern, 0, true, a); pos != 8 {
+		t.Errorf("expected the first aligned match at 8, got %d", pos)
+	}
+	if pos := b.FindAligned(pattern, 9, true, a); pos != searchChunk+4 {
+		t.Errorf("expected the next aligned match at %d, got %d", searchChunk+4, pos)
+	}
+	if pos := b.FindAligned(pattern, searchChunk+4, false, a); pos != 8 {
+		t.Errorf("expected the previous aligned match at 8, got %d", pos)
+	}
+	if pos := b.FindAligned(pattern, 8, false, a); pos != -1 {
+		t.Errorf("expected nothing aligned before 8, got %d", pos)
+	}
+	if n, _ := b.CountMatchesAlignedContext(context.Background(), pattern, a); n != 2 {
+		t.Errorf("expected 2 aligned matches, got %d", n)
+	}
+
+	// Aligned in the file a window was cut from, 1 byte in
+	a.Base = 1
+	if pos := b.FindAligned(pattern, 0, true, a); pos != 3 {
+		t.Errorf("expected the match at file offset 4, got %d", pos)
+	}
+	if n, _ := b.CountMatchesAlignedContext(context.Background(), pattern, a); n != 2 {
+		t.Errorf("expected 2 aligned matches, got %d", n)
+	}
+}
+
+func TestFindAcrossChunks(t *testing.T) {
+	data := make([]byte, 3*searchChunk)
+	pattern := []byte("MAGIC")
+	// Straddle the first chunk boundary, and place one near the end
+	copy(data[searchChunk-2:], pattern)
+	copy(data[len(data)-len(pattern):], pattern)
+
+	b := New()
+	b.Insert(0, data)
+
+	if pos := b.Find(pattern, 0, true); pos != searchChunk-2 {
+		t.Errorf("expected forward match at %d, got %d", searchChunk-2, pos)
+	}
+	if pos := b.Find(pattern, searchChunk, true); pos != int64(len(data)-len(pattern)) {
+		t.Errorf("expected second match at %d, got %d", len(data)-len(pattern), pos)
+	}
+	if pos := b.Find(pattern, b.Size(), false); pos != int64(len(data)-len(pattern)) {
+		t.Errorf("expected backward match at %d, got %d", len(data)-len(pattern), pos)
+	}
+	if pos := b.Find(pattern, int64(len(data)-len(pattern)), false); pos != searchChunk-2 {
+		t.Errorf("expected previous match at %d, got %d", searchChunk-2, pos)
+	}
+	if count := b.CountMatches(pattern); count != 2 {
+		t.Errorf("expected 2 matches, got %d", count)
+	}
+}
+
+func TestFindAllSkipsOverlaps(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("aaaaa"))
+	got, _ := b.FindAllContext(context.Background(), []byte("aa"))
+	if len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("expected matches at 0 and 2, got %v", got)
+	}
+
+	// A match straddling a chunk boundary is found once
+	data := make([]byte, 2*searchChunk)
+	copy(data[searchChunk-2:], "xyzw")
+	copy(data[searchChunk+10:], "xyzw")
+	b = FromData("", data)
+	got, _ = b.FindAllContext(context.Background(), []byte("xyzw"))
+	if len(got) != 2 || got[0] != searchChunk-2 || got[1] != searchChunk+10 {
+		t.Errorf("expected matches at %d and %d, got %v", searchChunk-2, searchChunk+10, got)
+	}
+}
+
+func TestFindEach(t *testing.T) {
+	b := FromData("", []byte("aaaaa"))
+	ctx := context.Background()
+	if got, _ := b.FindEachContext(ctx, []byte("aa"), Alignment{}, 10); !slices.Equal(got, []int64{0, 1, 2, 3}) {
+		t.Errorf("expected overlapping matches, got %v", got)
+	}
+	if got, _ := b.FindEachContext(ctx, []byte("aa"), Alignment{To: 2}, 10); !slices.Equal(got, []int64{0, 2}) {
+		t.Errorf("expected aligned matches, got %v", got)
+	}
+	if got, _ := b.FindEachContext(ctx, []byte("aa"), Alignment{To: 2, Base: 1}, 10); !slices.Equal(got, []int64{1, 3}) {
+		t.Errorf("expected matches aligned from the base, got %v", got)
+	}
+	if got, _ := b.FindEachContext(ctx, []byte("a"), Alignment{}, 3); len(got) != 3 {
+		t.Errorf("expected the limit kept, got %v", got)
+	}
+
+	// A match straddling a chunk boundary is found once
+	data := make([]byte, 2*searchChunk)
+	copy(data[searchChunk-2:], "xyzw")
+	b = FromData("", data)
+	if got, _ := b.FindEachContext(ctx, []byte("xyzw"), Alignment{}, 10); !slices.Equal(got, []int64{searchChunk - 2}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestCountMatchesOverlapping(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("aaaa"))
+
+	if count := b.CountMatches([]byte("aa")); count != 3 {
+		t.Errorf("expected 3 overlapping matches, got %d", count)
+	}
+}
+
+func benchmarkBuffer(size int) *Buffer {
+	return FromData("", make([]byte, size))
+}
+
+func BenchmarkInsertFront(b *testing.B) {
+	buf := benchmarkBuffer(256 << 20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Typing in insert mode near the start of a large file
+		buf.Insert(int64(i), []byte{0x41})
+	}
+}
+
+func BenchmarkReplaceSequential(b *testing.B) {
+	buf := benchmarkBuffer(256 << 20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Replace(int64(i)%buf.Size(), 0x41)
+	}
+}
+
+// exportBuffer is a large buffer with a few edits, as an exporter sees one.
+func exportBuffer() *Buffer {
+	buf := benchmarkBuffer(64 << 20)
+	for i := range int64(16) {
+		buf.ReplaceBytes(i<<22, []byte("edit"))
+	}
+	return buf
+}
+
+// BenchmarkExportChunks streams the contents without copying them; compare
+// the bytes allocated per op with BenchmarkExportData.
+func BenchmarkExportChunks(b *testing.B) {
+	buf := exportBuffer()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Chunks(0, buf.Size(), hashChunk, func(data []byte) error {
+			_, err := io.Discard.Write(data)
+			return err
+		})
+	}
+}
+
+func BenchmarkExportData(b *testing.B) {
+	buf := exportBuffer()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		io.Discard.Write(buf.Data())
+	}
+}
+
+func TestChunksCoverARange(t *testing.T) {
+	b := FromData("", []byte("0123456789"))
+	b.Insert(5, []byte("abc"))
+	b.Delete(0, 1)
+	want := string(b.Data())
+
+	for start := int64(-1); start <= b.Size()+1; start++ {
+		for end := start; end <= b.Size()+1; end++ {
+			var got []byte
+			b.Chunks(start, end, 2, func(data []byte) error {
+				if len(data) == 0 || len(data) > 2 {
+					t.Fatalf("chunk of %d bytes", len(data))
+				}
+				got = append(got, data...)
+				return nil
+			})
+			lo := min(max(start, 0), b.Size())
+			hi := max(min(end, b.Size()), lo)
+			if string(got) != want[lo:hi] {
+				t.Errorf("Chunks(%d, %d) = %q, want %q", start, end, got, want[lo:hi])
+			}
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := b.Chunks(0, b.Size(), 1, func([]byte) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("expected Chunks to stop at the first error, got %v after %d calls", err, calls)
+	}
+	if err := New().Chunks(0, 10, 4, func([]byte) error { return stop }); err != nil {
+		t.Errorf("an empty buffer has no chunks, got %v", err)
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("abcd"))
+
+	c := b.Clone()
+	if c.CanUndo() {
+		t.Error("expected clone to start without undo history")
+	}
+
+	b.Replace(0, 'X')
+	b.Insert(4, []byte("ef"))
+	c.Insert(4, []byte("gh"))
+
+	if got := string(b.Data()); got != "Xbcdef" {
+		t.Errorf("expected original %q, got %q", "Xbcdef", got)
+	}
+	if got := string(c.Data()); got != "abcdgh" {
+		t.Errorf("expected clone %q, got %q", "abcdgh", got)
+	}
+}
+
+func TestCountMatchesIn(t *testing.T) {
+	data := bytes.Repeat([]byte("ab"), searchChunk)
+	b := FromData("", data)
+	ctx := context.Background()
+
+	tests := []struct {
+		start, end int64
+		align      int64
+		want       int
+	}{
+		{0, int64(len(data)), 0, searchChunk},
+		{1, 5, 0, 1},                             // "baba": the match cut by either end doesn't count
+		{0, 3, 0, 1},                             // "aba"
+		{0, 1, 0, 0},                             // shorter than the pattern
+		{searchChunk - 2, searchChunk + 4, 0, 3}, // across a chunk boundary
+		{0, 16, 4, 4},
+		{-5, 1 << 40, 0, searchChunk},
+	}
+	for _, tt := range tests {
+		got, err := b.CountMatchesInContext(ctx, []byte("ab"), Alignment{To: tt.align}, tt.start, tt.end)
+		if err != nil || got != tt.want {
+			t.Errorf("[%d, %d) aligned to %d: got %d, %v, want %d", tt.start, tt.end, tt.align, got, err, tt.want)
+		}
+	}
+}
+
+func TestCountMatchesContextCancelled(t *testing.T) {
+	b := New()
+	b.Insert(0, []byte("aaaa"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.CountMatchesContext(ctx, []byte("a")); err == nil {
+		t.Error("expected an error from a cancelled count")
+	}
+}
+
+func TestListenReportsEdits(t *testing.T) {
+	b := New()
+	var got []Change
+	stop := b.Listen(func(c Change) { got = append(got, c) })
+
+	b.Insert(0, []byte{1, 2, 3})
+	b.Replace(1, 9)
+	b.Delete(0, 2)
+	b.Undo()
+	b.Redo()
+	stop()
+	b.Insert(0, []byte{4})
+
+	want := []Change{
+		{Offset: 0, Inserted: 3},
+		{Offset: 1, Overwritten: 1},
+		{Offset: 0, Removed: 2},
+		{Offset: 0, Inserted: 2},
+		{Offset: 0, Removed: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestTransformTwiceIsIdentity(t *testing.T) {
+	data := []byte{0x4A, 0x00, 0xFF, 0x12, 0x9C}
+	b := New()
+	b.Insert(0, data)
+	swap := func(c byte) byte { return c<<4 | c>>4 }
+
+	b.Transform(1, 3, swap)
+	if got := b.GetBytes(0, 5); !bytes.Equal(got, []byte{0x4A, 0x00, 0xFF, 0x21, 0x9C}) {
+		t.Fatalf("unexpected transform result % X", got)
+	}
+	b.Transform(1, 3, swap)
+	if got := b.GetBytes(0, 5); !bytes.Equal(got, data) {
+		t.Errorf("applying the swap twice should be identity, got % X", got)
+	}
+
+	// One undo per application, and nothing written past EOF
+	b.Transform(3, 10, swap)
+	if b.Size() != 5 {
+		t.Errorf("transform past EOF should not grow the buffer, size %d", b.Size())
+	}
+	b.Undo()
+	b.Undo()
+	if got := b.GetBytes(0, 5); !bytes.Equal(got, []byte{0x4A, 0x00, 0xFF, 0x21, 0x9C}) {
+		t.Errorf("expected each transform to undo in one step, got % X", got)
+	}
+}
+
+func TestCopyIsNewAndUnnamed(t *testing.T) {
+	b := FromData("firmware.bin", []byte("abcd"))
+	b.Replace(0, 'X')
+
+	c := b.Copy()
+	if c.Filename() != "" || !c.IsNew() || !c.IsModified() || c.CanUndo() {
+		t.Errorf("expected an unnamed, modified buffer without history, got %q new=%v modified=%v",
+			c.Filename(), c.IsNew(), c.IsModified())
+	}
+	c.Insert(4, []byte("ef"))
+	b.Undo()
+	if string(b.Data()) != "abcd" || string(c.Data()) != "Xbcdef" {
+		t.Errorf("expected independent contents, got %q and %q", b.Data(), c.Data())
+	}
+	if New().Copy().IsModified() {
+		t.Error("a copy of an empty buffer has nothing to save")
+	}
+}
+
+func TestAmendJoinsTheLastStep(t *testing.T) {
+	b := FromData("", []byte{0x00, 0x00})
+	b.Replace(1, 0xA0)
+	b.Amend(1, 0xAB)
+	b.Amend(0, 0x11) // not written by the last step
+	if got := b.GetBytes(0, 2); got[0] != 0x11 || got[1] != 0xAB {
+		t.Fatalf("got % X", got)
+	}
+	b.Undo()
+	b.Undo()
+	if got := b.GetBytes(0, 2); got[0] != 0 || got[1] != 0 || b.CanUndo() {
+		t.Errorf("expected two undo steps back to zeros, got % X", got)
+	}
+	b.Redo()
+	if got := b.GetBytes(0, 2); got[1] != 0xAB {
+		t.Errorf("redo should restore the amended byte, got % X", got)
+	}
+}
+
+func TestLastStepRecordsVersions(t *testing.T) {
+	b := FromData("", make([]byte, 8))
+	before := b.Version()
+	b.BeginGroup()
+	b.Replace(5, 1)
+	b.Replace(6, 2)
+	b.EndGroup()
+
+	b.Undo()
+	step := b.LastStep()
+	if len(step) != 2 || step[0].Offset != 5 || step[0].Version != before || step[1].Version == before {
+		t.Errorf("expected both replaces oldest first with their versions, got %+v", step)
+	}
+	b.Redo()
+	if step := b.LastStep(); len(step) != 2 || step[0].Offset != 5 {
+		t.Errorf("expected redo to report the same step, got %+v", step)
+	}
+}
+
+func TestUndoRejectsOperationsOutsideTheBuffer(t *testing.T) {
+	b := FromData("", []byte("abcdef"))
+	b.Replace(1, 'X')
+	b.undoStack[0].Offset = 100
+
+	ok, err := b.Undo()
+	if ok || !errors.Is(err, ErrBadHistory) {
+		t.Fatalf("expected ErrBadHistory, got %v %v", ok, err)
+	}
+	if string(b.Data()) != "aXcdef" || b.CanUndo() || b.CanRedo() {
+		t.Errorf("expected the buffer untouched and the history dropped, got %q", b.Data())
+	}
+}
+
+func TestUndoRollsBackAPartlyUndoneGroup(t *testing.T) {
+	b := FromData("", []byte("abcdef"))
+	b.BeginGroup()
+	b.Replace(0, 'X')
+	b.Insert(6, []byte("gh"))
+	b.Delete(2, 1)
+	b.EndGroup()
+	want := string(b.Data())
+
+	// The oldest operation of the group is corrupt; the others undo first
+	b.undoStack[0].Offset = -1
+	if _, err := b.Undo(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := string(b.Data()); got != want {
+		t.Errorf("expected the group rolled back to %q, got %q", want, got)
+	}
+
+	b.Replace(0, 'Y')
+	b.Undo()
+	b.redoStack[0].Offset = 50
+	if _, err := b.Redo(); !errors.Is(err, ErrBadHistory) {
+		t.Errorf("expected redo to check too, got %v", err)
+	}
+}
+
+func TestFindDegenerateSizes(t *testing.T) {
+	ctx := context.Background()
+	empty := New()
+	if empty.Find([]byte("a"), 0, true) != -1 || empty.CountMatches([]byte("a")) != 0 {
+		t.Error("an empty buffer has no matches")
+	}
+
+	b := FromData("", []byte("abc"))
+	if got := b.Find([]byte("abc"), 0, true); got != 0 {
+		t.Errorf("expected the whole buffer at 0, got %d", got)
+	}
+	if got := b.Find([]byte("abc"), 3, false); got != 0 {
+		t.Errorf("expected the whole buffer backward at 0, got %d", got)
+	}
+	if got := b.Find([]byte("abcd"), 0, true); got != -1 {
+		t.Errorf("a longer pattern can't match, got %d", got)
+	}
+	if got := b.Find(nil, 0, true); got != -1 {
+		t.Errorf("an empty pattern can't match, got %d", got)
+	}
+	if n := b.CountMatches([]byte("abc")); n != 1 {
+		t.Errorf("expected one whole-buffer match, got %d", n)
+	}
+	if hits, _ := b.FindAllContext(ctx, []byte("abcd")); len(hits) != 0 {
+		t.Errorf("expected no hits for a longer pattern, got %v", hits)
+	}
+}
+
+func TestEditStatsFollowUndoAndRedo(t *testing.T) {
+	b := FromData("", []byte("abcdef"))
+	b.ReplaceBytes(0, []byte("XY"))
+	b.Insert(6, []byte("gh"))
+	b.Delete(2, 1)
+	s := b.Stats()
+	if s.Replaced != 2 || s.Inserted != 2 || s.Deleted != 1 || s.OpenedSize != 6 || s.First.IsZero() || s.Last.Before(s.First) {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	b.Undo()
+	b.Undo()
+	b.Redo()
+	s = b.Stats()
+	if s.Replaced != 2 || s.Inserted != 2 || s.Deleted != 0 || s.Undos != 2 || s.Redos != 1 {
+		t.Errorf("expected the undone delete off the counts, got %+v", s)
+	}
+
+	b.Revert()
+	if s = b.Stats(); s != (EditStats{OpenedSize: 6}) || string(b.Data()) != "abcdef" || b.CanUndo() || b.IsModified() {
+		t.Errorf("revert should restore the file and reset the stats, got %+v %q", s, b.Data())
+	}
+}
+
+func TestModifications(t *testing.T) {
+	b := FromData("", []byte("0123456789abcdef"))
+	b.ReplaceBytes(2, []byte("2X4")) // only 3 changes
+	b.Insert(8, []byte("ins"))
+	b.Delete(14, 2) // "bc"
+	b.Insert(0, []byte("Z"))
+	want := []Modification{{0, 1}, {4, 1}, {9, 3}, {15, 0}}
+	if got := b.Modifications(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Deleting the inserted bytes leaves where they were
+	b.Delete(9, 3)
+	if got := b.Modifications(); !slices.Equal(got, []Modification{{0, 1}, {4, 1}, {9, 0}, {12, 0}}) {
+		t.Errorf("got %v", got)
+	}
+
+	for b.CanUndo() {
+		b.Undo()
+	}
+	if got := b.Modifications(); len(got) != 0 {
+		t.Errorf("undoing everything should leave no changes, got %v", got)
+	}
+	b.Redo()
+	if got := b.Modifications(); !slices.Equal(got, []Modification{{3, 1}}) {
+		t.Errorf("expected the redone replace, got %v", got)
+	}
+
+	b.ReplaceBytes(15, []byte("FGH")) // overwrites the last byte, then appends
+	if got := b.Modifications(); !slices.Equal(got, []Modification{{3, 1}, {15, 3}}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	b := FromData("", []byte("original"))
+	snap := b.Clone()
+	b.Insert(0, []byte(">>"))
+	b.ReplaceBytes(4, []byte("XY"))
+
+	var changes []Change
+	b.Listen(func(c Change) { changes = append(changes, c) })
+	if err := b.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+	if string(b.Data()) != "original" || !b.IsModified() {
+		t.Fatalf("expected the snapshot's contents, got %q", b.Data())
+	}
+	if want := []Change{{Removed: 10, Inserted: 8, Swapped: true}}; !slices.Equal(changes, want) {
+		t.Errorf("expected one change %v, got %v", want, changes)
+	}
+	if got := b.Modifications(); !slices.Equal(got, []Modification{{0, 8}}) {
+		t.Errorf("expected the whole buffer modified, got %v", got)
+	}
+
+	// One step each way
+	b.Undo()
+	if string(b.Data()) != ">>orXYinal" {
+		t.Errorf("undo: got %q", b.Data())
+	}
+	b.Redo()
+	b.Insert(8, []byte("!"))
+	if string(b.Data()) != "original!" || string(snap.Data()) != "original" {
+		t.Errorf("editing after a restore: got %q, snapshot %q", b.Data(), snap.Data())
+	}
+
+	// Contents from another buffer are copied in
+	if err := b.Restore(FromData("", []byte("other"))); err != nil || string(b.Data()) != "other" {
+		t.Errorf("got %q (%v)", b.Data(), err)
+	}
+}
+
+func TestModificationsKeptAcrossEdits(t *testing.T) {
+	b := FromData("", []byte("0123456789abcdef"))
+	check := func(step string) {
+		t.Helper()
+		got := b.Modifications()
+		kept := b.mods
+		b.mods = modsCache{}
+		if want := b.Modifications(); !slices.Equal(got, want) {
+			t.Fatalf("after %s: kept runs give %v, replaying gives %v", step, got, want)
+		}
+		b.mods = kept
+	}
+
+	b.Insert(4, []byte("ins"))
+	check("insert")
+	b.ReplaceBytes(0, []byte("X"))
+	check("replace")
+	b.Amend(1, 'Y')
+	b.Amend(1, '1') // back to what it was
+	check("amend")
+	b.Delete(5, 4)
+	check("delete")
+	b.Undo()
+	b.Undo() // cuts the history below the kept runs
+	b.Insert(10, []byte("new"))
+	b.Insert(0, []byte("Z"))
+	check("edits after undo")
+	b.Undo()
+	b.Redo()
+	check("redo")
+}
+
+func TestSnapshotsStayConsistentWhileEditing(t *testing.T) {
+	data := bytes.Repeat([]byte("....needle......"), 4096)
+	b := FromData("", data)
+
+	type scan struct {
+		snap *Buffer
+		want []byte
+	}
+	scans := make(chan scan)
+	failed := make(chan string, 1)
+	go func() {
+		defer close(failed)
+		for s := range scans {
+			hits, err := s.snap.FindAllContext(context.Background(), []byte("needle"))
+			var want []int64
+			for i := 0; i+6 <= len(s.want); i++ {
+				if string(s.want[i:i+6]) == "needle" {
+					want = append(want, int64(i))
+				}
+			}
+			if err != nil || !slices.Equal(hits, want) {
+				failed <- "a scan of a snapshot saw the edits made after it was taken"
+				for range scans {
+				}
+			}
+		}
+	}()
+
+	for i := range 200 {
+		snap := b.Snapshot()
+		scans <- scan{snap, snap.Data()}
+		// Edit while the scan runs
+		for j := range 50 {
+			off := int64((i*7919 + j*104729) % int(b.Size()))
+			switch j % 3 {
+			case 0:
+				b.Insert(off, []byte("needle"))
+			case 1:
+				b.Delete(off, 5)
+			default:
+				b.ReplaceBytes(off, []byte("xx"))
+			}
+		}
+		if snap.Version() == b.Version() {
+			t.Fatal("edits should change the version")
+		}
+	}
+	close(scans)
+	if msg, ok := <-failed; ok {
+		t.Fatal(msg)
+	}
+
+	snap := b.Snapshot()
+	snap.Insert(0, []byte("x"))
+	if snap.Size() != b.Size() || snap.Version() != b.Version() {
+		t.Error("a snapshot should be read-only")
+	}
+}
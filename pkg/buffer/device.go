@@ -0,0 +1,223 @@
+package buffer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Devices such as /dev/sdb are too large to read into memory. OpenDevice
+// reads their data on demand instead, starts read-only and can't change
+// size. Saving writes only the changed bytes over the device in place.
+
+// ErrReadOnly is returned when editing or saving a buffer that is read-only.
+var ErrReadOnly = errors.New("buffer is read-only")
+
+// ErrFixedSize is returned when inserting into or deleting from a buffer
+// whose size can't change (see FixedSize).
+var ErrFixedSize = errors.New("buffer size is fixed")
+
+const (
+	originBlock       = 64 * 1024
+	originCacheBlocks = 64
+)
+
+// fileOrigin reads a file's data on demand, keeping the blocks read most
+// recently. Clones share it, so it is safe for concurrent use. The file is
+// closed once the buffers holding it (see Close) have all been closed.
+type fileOrigin struct {
+	f    *os.File
+	size int64
+
+	mu       sync.Mutex
+	refs     int
+	blocks   map[int64][]byte
+	order    []int64 // cached block numbers, oldest first
+	firstErr error
+}
+
+func (o *fileOrigin) span(off, n int64) []byte {
+	if n >= originBlock {
+		// Bulk reads (hashing, saving) bypass the cache
+		data := make([]byte, n)
+		o.read(data, off)
+		return data
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	first, last := off/originBlock, (off+n-1)/originBlock
+	if first == last {
+		block := o.block(first)
+		return block[off-first*originBlock : off-first*originBlock+n]
+	}
+	data := make([]byte, 0, n)
+	for i := first; i <= last; i++ {
+		block := o.block(i)
+		from := max(off-i*originBlock, 0)
+		to := min(off+n-i*originBlock, int64(len(block)))
+		data = append(data, block[from:to]...)
+	}
+	return data
+}
+
+// block returns block i, reading it if it isn't cached. o.mu must be held.
+func (o *fileOrigin) block(i int64) []byte {
+	if block, ok := o.blocks[i]; ok {
+		return block
+	}
+	block := make([]byte, min(originBlock, o.size-i*originBlock))
+	o.readLocked(block, i*originBlock)
+	if len(o.order) >= originCacheBlocks {
+		delete(o.blocks, o.order[0])
+		o.order = o.order[1:]
+	}
+	o.blocks[i] = block
+	o.order = append(o.order, i)
+	return block
+}
+
+func (o *fileOrigin) read(p []byte, off int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.readLocked(p, off)
+}
+
+// readLocked fills p from off. Unreadable bytes read as zero and the first
+// error is kept for err.
+func (o *fileOrigin) readLocked(p []byte, off int64) {
+	if _, err := o.f.ReadAt(p, off); err != nil && o.firstErr == nil {
+		o.firstErr = fmt.Errorf("reading 0x%X: %w", off, err)
+	}
+}
+
+func (o *fileOrigin) err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.firstErr
+}
+
+// forget drops the cached blocks, which may be stale once written over.
+func (o *fileOrigin) forget() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.blocks = make(map[int64][]byte)
+	o.order = nil
+}
+
+// OpenDevice opens filename for on-demand reading. The buffer is
+// read-only until SetReadOnly(false).
+func OpenDevice(filename string) (*Buffer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	// Stat reports a size of 0 for block devices
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	o := &fileOrigin{f: f, size: size, refs: 1, blocks: make(map[int64][]byte)}
+	table := newOriginTable(o, size)
+	return &Buffer{
+		filename:    filename,
+		table:       table,
+		saved:       table.clone(),
+		device:      true,
+		readOnly:    true,
+		holdsOrigin: true,
+	}, nil
+}
+
+// Close releases the device b reads on demand once no Copy of b reads it
+// either. It does nothing for buffers read into memory, or for clones and
+// snapshots, which must not be read after the buffer they came from is
+// closed. b must not be used afterwards.
+func (b *Buffer) Close() error {
+	if !b.holdsOrigin {
+		return nil
+	}
+	b.holdsOrigin = false
+	o := b.table.original.(*fileOrigin)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.refs--; o.refs > 0 {
+		return nil
+	}
+	o.blocks, o.order = make(map[int64][]byte), nil
+	return o.f.Close()
+}
+
+// retainOrigin makes c, a Copy of b, hold the device b reads on demand
+// until c is closed as well.
+func (b *Buffer) retainOrigin(c *Buffer) {
+	o, ok := b.table.original.(*fileOrigin)
+	if !ok {
+		return
+	}
+	o.mu.Lock()
+	o.refs++
+	o.mu.Unlock()
+	c.holdsOrigin = true
+}
+
+// IsDevice reports whether the buffer was opened with OpenDevice.
+func (b *Buffer) IsDevice() bool {
+	return b.device
+}
+
+// FixedSize reports whether edits must keep the buffer's size, because it
+// is a window or a device. Insert and Delete then fail with ErrFixedSize.
+func (b *Buffer) FixedSize() bool {
+	return b.window != nil || b.device
+}
+
+// ReadOnly reports whether edits are refused (see SetReadOnly).
+func (b *Buffer) ReadOnly() bool {
+	return b.readOnly
+}
+
+// SetReadOnly allows or refuses edits. While read-only, edits, undo and
+// redo do nothing and Save fails with ErrReadOnly.
+func (b *Buffer) SetReadOnly(readOnly bool) {
+	b.readOnly = readOnly
+}
+
+// saveInPlace writes every piece that no longer matches the device over
+// it, leaving the rest untouched.
+func (b *Buffer) saveInPlace() error {
+	o := b.table.original.(*fileOrigin)
+	if b.table.size != o.size {
+		return fmt.Errorf("device is 0x%X bytes, buffer 0x%X", o.size, b.table.size)
+	}
+	f, err := os.OpenFile(b.filename, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	for _, p := range b.table.pieces {
+		if p.src == srcOriginal && p.off == p.start {
+			continue
+		}
+		if p.src == srcOriginal {
+			// Fixed-size edits only overwrite, so device data never moves
+			f.Close()
+			return fmt.Errorf("data at 0x%X moved; can't write it in place", p.start)
+		}
+		if _, err := f.WriteAt(b.table.bytes(p, 0, p.len), p.start); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	o.forget()
+	b.markSaved("")
+	return nil
+}
@@ -0,0 +1,143 @@
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenDeviceReadsOnDemand(t *testing.T) {
+	data := make([]byte, 3*originBlock+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := OpenDevice(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Size() != int64(len(data)) || !b.FixedSize() || !b.ReadOnly() {
+		t.Fatalf("size %d, fixed %v, read-only %v", b.Size(), b.FixedSize(), b.ReadOnly())
+	}
+	for _, off := range []int64{0, originBlock - 5, 2*originBlock - 1, int64(len(data)) - 10} {
+		if got := b.GetBytes(off, 16); !bytes.Equal(got, data[off:min(off+16, int64(len(data)))]) {
+			t.Errorf("bytes at 0x%X differ", off)
+		}
+	}
+	hash, err := b.HashContext(context.Background())
+	sum := sha256.Sum256(data)
+	if err != nil || hash != hex.EncodeToString(sum[:]) {
+		t.Errorf("hash %s, %v", hash, err)
+	}
+}
+
+func TestDeviceWritesInPlace(t *testing.T) {
+	data := bytes.Repeat([]byte{0x11}, 2*originBlock)
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenDevice(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.ReplaceBytes(10, []byte{0xFF})
+	if b.IsModified() {
+		t.Fatal("a read-only buffer should ignore edits")
+	}
+	if err := b.Save(); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	b.SetReadOnly(false)
+	b.ReplaceBytes(originBlock-1, []byte{0xAA, 0xBB})
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data[originBlock-1], data[originBlock] = 0xAA, 0xBB
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Error("device contents differ after saving")
+	}
+	if got := b.GetBytes(originBlock-2, 4); !bytes.Equal(got, []byte{0x11, 0xAA, 0xBB, 0x11}) {
+		t.Errorf("buffer reads % X after saving", got)
+	}
+
+	if err := b.Insert(0, []byte{0}); !errors.Is(err, ErrFixedSize) {
+		t.Errorf("inserting into a device should fail with ErrFixedSize, got %v", err)
+	}
+	if err := b.Delete(0, 1); !errors.Is(err, ErrFixedSize) || b.Size() != int64(len(data)) {
+		t.Errorf("deleting from a device should fail with ErrFixedSize, got %v", err)
+	}
+}
+
+func TestDeviceRestoresSameSize(t *testing.T) {
+	data := bytes.Repeat([]byte{0x11}, 2*originBlock)
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenDevice(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.SetReadOnly(false)
+
+	b.ReplaceBytes(5, []byte{0xAA})
+	snap := b.Clone()
+	b.ReplaceBytes(originBlock, []byte{0xBB})
+	if err := b.Restore(snap); err != nil {
+		t.Fatalf("restoring the same size should work, got %v", err)
+	}
+	if err := b.Restore(FromData("", []byte{1, 2, 3})); !errors.Is(err, ErrFixedSize) {
+		t.Errorf("restoring another size should fail with ErrFixedSize, got %v", err)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data[5] = 0xAA
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Error("device contents differ after saving the restore")
+	}
+}
+
+func TestCloseReleasesDeviceWithLastCopy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sdx")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{0x22}, originBlock), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenDevice(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := b.table.original.(*fileOrigin)
+	c := b.Copy()
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("closing twice: %v", err)
+	}
+	if got := c.GetBytes(100, 1); !bytes.Equal(got, []byte{0x22}) {
+		t.Fatalf("copy reads %X after the original closed", got)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.f.Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("device still open after the last copy closed: %v", err)
+	}
+	if err := FromData("x", []byte{1}).Close(); err != nil {
+		t.Fatalf("closing a buffer in memory: %v", err)
+	}
+}
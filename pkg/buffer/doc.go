@@ -0,0 +1,28 @@
+// Package buffer holds the contents of a file being edited as a piece
+// table: opening a file reads it once, and edits record only what changed,
+// so inserting into a large file doesn't copy it. Every edit can be undone
+// and redone, Snapshot gives a consistent read-only view for background
+// work while editing goes on, and Save writes the result back.
+//
+// A typical use:
+//
+//	buf, err := buffer.Open("disk.img")
+//	if err != nil {
+//		return err
+//	}
+//	if at := buf.Find([]byte("MAGIC"), 0, true); at >= 0 {
+//		buf.ReplaceBytes(at, []byte("magic"))
+//	}
+//	err = buf.Chunks(0, buf.Size(), 64*1024, func(data []byte) error {
+//		_, err := h.Write(data)
+//		return err
+//	})
+//	...
+//	err = buf.Save()
+//
+// Devices are opened with OpenDevice, which reads them on demand, and a
+// part of a file with OpenWindow. Saving either writes over the bytes it
+// was read from, so it must keep its size: FixedSize reports this, and
+// Insert and Delete fail with ErrFixedSize. The unhexed editor
+// (internal/editor) is built on this package.
+package buffer
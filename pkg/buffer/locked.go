@@ -0,0 +1,177 @@
+package buffer
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Locked ranges guard bytes against edits: Insert, Delete, Replace and the
+// rest refuse to change them with a LockedError until they are unlocked.
+// Undo and redo aren't refused, since they restore history rather than
+// make new edits. The ranges follow the data through insertions and
+// deletions made elsewhere, like the offsets listeners keep.
+
+// ErrLocked is returned by edits of bytes in a locked range.
+var ErrLocked = errors.New("range is locked")
+
+// Range is a span of bytes, Start to End inclusive.
+type Range struct {
+	Start, End int64
+}
+
+// LockedError names the locked range an edit would have changed.
+type LockedError struct {
+	Range Range
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("0x%X-0x%X is locked", e.Range.Start, e.Range.End)
+}
+
+func (e *LockedError) Unwrap() error { return ErrLocked }
+
+// Lock locks start..end (inclusive), merging it with locked ranges it
+// overlaps or touches.
+func (b *Buffer) Lock(start, end int64) {
+	start, end = max(min(start, end), 0), min(max(start, end), b.table.size-1)
+	if start > end {
+		return
+	}
+	kept := b.locked[:0]
+	for _, r := range b.locked {
+		if r.End+1 < start || r.Start > end+1 {
+			kept = append(kept, r)
+			continue
+		}
+		start, end = min(start, r.Start), max(end, r.End)
+	}
+	b.locked = append(kept, Range{start, end})
+	slices.SortFunc(b.locked, func(a, b Range) int { return cmp.Compare(a.Start, b.Start) })
+}
+
+// Unlock unlocks start..end (inclusive), splitting locked ranges it cuts,
+// and reports whether any byte was locked.
+func (b *Buffer) Unlock(start, end int64) bool {
+	start, end = min(start, end), max(start, end)
+	var kept []Range
+	found := false
+	for _, r := range b.locked {
+		if r.End < start || r.Start > end {
+			kept = append(kept, r)
+			continue
+		}
+		found = true
+		if r.Start < start {
+			kept = append(kept, Range{r.Start, start - 1})
+		}
+		if r.End > end {
+			kept = append(kept, Range{end + 1, r.End})
+		}
+	}
+	b.locked = kept
+	return found
+}
+
+// LockedRanges returns the locked ranges in order.
+func (b *Buffer) LockedRanges() []Range {
+	return slices.Clone(b.locked)
+}
+
+// LockedAt returns the first locked range overlapping start..end
+// (inclusive).
+func (b *Buffer) LockedAt(start, end int64) (Range, bool) {
+	for _, r := range b.locked {
+		if r.Start <= end && r.End >= start {
+			return r, true
+		}
+	}
+	return Range{}, false
+}
+
+// checkLocked returns a LockedError when changing start..end would change
+// a locked byte.
+func (b *Buffer) checkLocked(start, end int64) error {
+	if r, ok := b.LockedAt(start, end); ok {
+		return &LockedError{r}
+	}
+	return nil
+}
+
+// checkInsert returns a LockedError when inserting at offset would split a
+// locked range. Inserting just before or after one is allowed.
+func (b *Buffer) checkInsert(offset int64) error {
+	for _, r := range b.locked {
+		if r.Start < offset && offset <= r.End {
+			return &LockedError{r}
+		}
+	}
+	return nil
+}
+
+// shiftLocked moves the locked ranges through c, dropping those deleted.
+func (b *Buffer) shiftLocked(c Change) {
+	kept := b.locked[:0]
+	for _, r := range b.locked {
+		if start, end, ok := c.ShiftRange(r.Start, r.End); ok {
+			kept = append(kept, Range{start, end})
+		}
+	}
+	b.locked = kept
+}
+
+// ShiftRange maps the inclusive range [start, end] through c, keeping its
+// direction. Insertions inside the range grow it and deletions overlapping
+// it shrink it; ok is false when the whole range was deleted. A swap
+// keeps the range where it is, cut at the new end.
+func (c Change) ShiftRange(start, end int64) (newStart, newEnd int64, ok bool) {
+	lo, hi := min(start, end), max(start, end)
+
+	if c.Swapped {
+		hi = min(hi, c.Inserted-1)
+		if lo > hi {
+			return 0, 0, false
+		}
+		if start > end {
+			return hi, lo, true
+		}
+		return lo, hi, true
+	}
+
+	if c.Removed > 0 {
+		delEnd := c.Offset + c.Removed
+		switch {
+		case delEnd <= lo:
+			lo -= c.Removed
+			hi -= c.Removed
+		case c.Offset > hi:
+		case c.Offset <= lo && delEnd > hi:
+			return 0, 0, false
+		default:
+			if lo > c.Offset {
+				lo = c.Offset
+			}
+			if hi >= delEnd {
+				hi -= c.Removed
+			} else {
+				hi = c.Offset - 1
+			}
+		}
+	}
+
+	if c.Inserted > 0 {
+		switch {
+		case c.Offset <= lo:
+			lo += c.Inserted
+			hi += c.Inserted
+		case c.Offset <= hi:
+			hi += c.Inserted
+		}
+	}
+
+	if start > end {
+		return hi, lo, true
+	}
+	return lo, hi, true
+}
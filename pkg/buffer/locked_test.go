@@ -0,0 +1,100 @@
+package buffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLockedRangesRefuseEdits(t *testing.T) {
+	b := FromData("", []byte("0123456789ABCDEF"))
+	b.Lock(4, 7)
+
+	refused := map[string]error{
+		"replace":        b.Replace(5, 'x'),
+		"amend":          b.Amend(4, 'x'),
+		"delete through": b.Delete(2, 3),
+		"insert inside":  b.Insert(5, []byte("x")),
+		"replace bytes":  b.ReplaceBytes(6, []byte("xyz")),
+		"transform":      b.Transform(0, 16, func(c byte) byte { return c + 1 }),
+		"restore":        b.Restore(FromData("", []byte("other"))),
+	}
+	for name, err := range refused {
+		var locked *LockedError
+		if !errors.Is(err, ErrLocked) || !errors.As(err, &locked) || locked.Range != (Range{4, 7}) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+	if string(b.Data()) != "0123456789ABCDEF" || b.CanUndo() {
+		t.Fatalf("a refused edit changed the buffer: %q", b.Data())
+	}
+
+	// Around the range is fine, and moves it
+	if err := b.Insert(4, []byte("ab")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Insert(8, []byte("cd")); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LockedRanges(); !reflect.DeepEqual(got, []Range{{4, 7}}) {
+		t.Errorf("expected the range to follow its bytes, got %v", got)
+	}
+	if string(b.Data()[4:8]) != "4567" {
+		t.Errorf("got %q", b.Data())
+	}
+}
+
+func TestUndoGoesThroughLockedRanges(t *testing.T) {
+	b := FromData("", []byte("0123456789"))
+	b.Replace(5, 'x')
+	b.Insert(7, []byte("yy"))
+	b.Lock(4, 9)
+
+	for range 2 {
+		if _, err := b.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(b.Data()) != "0123456789" {
+		t.Errorf("got %q", b.Data())
+	}
+	if got := b.LockedRanges(); !reflect.DeepEqual(got, []Range{{4, 7}}) {
+		t.Errorf("undoing the insert should shrink the range, got %v", got)
+	}
+	if _, err := b.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Data()[5] != 'x' {
+		t.Error("redo should go through too")
+	}
+}
+
+func TestLockMergesAndUnlockSplits(t *testing.T) {
+	b := FromData("", make([]byte, 64))
+	b.Lock(10, 19)
+	b.Lock(30, 39)
+	b.Lock(20, 25) // touches the first
+	b.Lock(-5, 2)
+	if got := b.LockedRanges(); !reflect.DeepEqual(got, []Range{{0, 2}, {10, 25}, {30, 39}}) {
+		t.Fatalf("got %v", got)
+	}
+	if !b.Unlock(15, 16) || b.Unlock(40, 50) {
+		t.Error("Unlock should report whether anything was locked")
+	}
+	if got := b.LockedRanges(); !reflect.DeepEqual(got, []Range{{0, 2}, {10, 14}, {17, 25}, {30, 39}}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestRevertKeepsLockedRanges(t *testing.T) {
+	b := FromData("", []byte("0123456789"))
+	b.Insert(0, []byte("ab"))
+	b.Lock(6, 11)
+	b.Revert()
+	if got := b.LockedRanges(); !reflect.DeepEqual(got, []Range{{6, 9}}) {
+		t.Errorf("expected the range kept within the file, got %v", got)
+	}
+}
@@ -0,0 +1,126 @@
+package buffer
+
+import (
+	"slices"
+	"sort"
+)
+
+// Modification is a run of bytes changed since the file was last read or
+// saved: Len bytes at Offset inserted or overwritten, or with Len 0 the
+// place bytes were deleted from.
+type Modification struct {
+	Offset, Len int64
+}
+
+func (m Modification) end() int64 { return m.Offset + m.Len }
+
+// modsCache holds the runs changed by the first ops operations of the
+// undo history. The last operation is left out, as Amend changes it.
+type modsCache struct {
+	runs []Modification
+	ops  int
+}
+
+// Modifications returns the runs changed by the edits still in effect, in
+// order. They follow the undo history, so undoing an edit takes its run
+// back out. Bytes overwritten with their own value don't count. The runs
+// are kept as edits are made, so a call only adds the edits made since the
+// last one; after an undo they are worked out again.
+func (b *Buffer) Modifications() []Modification {
+	n := len(b.undoStack)
+	if n == 0 {
+		return nil
+	}
+	if b.mods.ops > n-1 {
+		b.mods = modsCache{}
+	}
+	for ; b.mods.ops < n-1; b.mods.ops++ {
+		b.mods.runs = applyModification(b.mods.runs, b.undoStack[b.mods.ops])
+	}
+	return applyModification(slices.Clone(b.mods.runs), b.undoStack[n-1])
+}
+
+// forgetModifications drops the kept runs once the undo history is cut
+// below them.
+func (b *Buffer) forgetModifications() {
+	if len(b.undoStack) < b.mods.ops {
+		b.mods = modsCache{}
+	}
+}
+
+// applyModification adds the run op changed to mods, moving the runs after
+// it, and returns the result. It may change mods.
+func applyModification(mods []Modification, op Operation) []Modification {
+	switch op.Type {
+	case OpInsert:
+		n := int64(len(op.NewData))
+		for i := range mods {
+			switch {
+			case mods[i].Offset >= op.Offset:
+				mods[i].Offset += n
+			case mods[i].end() > op.Offset:
+				mods[i].Len += n
+			}
+		}
+		mods = addModification(mods, Modification{op.Offset, n})
+	case OpDelete:
+		n := int64(len(op.OldData))
+		// Offsets past the deleted bytes move back over them
+		at := func(x int64) int64 {
+			switch {
+			case x <= op.Offset:
+				return x
+			case x <= op.Offset+n:
+				return op.Offset
+			}
+			return x - n
+		}
+		for i := range mods {
+			start := at(mods[i].Offset)
+			mods[i].Offset, mods[i].Len = start, at(mods[i].end())-start
+		}
+		mods = mergeModifications(mods)
+		mods = addModification(mods, Modification{op.Offset, 0})
+	case OpReplace:
+		for i := 0; i < len(op.NewData); i++ {
+			if op.NewData[i] == op.OldData[i] {
+				continue
+			}
+			j := i
+			for j < len(op.NewData) && op.NewData[j] != op.OldData[j] {
+				j++
+			}
+			mods = addModification(mods, Modification{op.Offset + int64(i), int64(j - i)})
+			i = j
+		}
+	case OpRestore:
+		// Every byte may have changed
+		mods = []Modification{{0, op.newTable.size}}
+	}
+	return mods
+}
+
+// addModification adds m to the ordered runs mods, merging the runs it
+// overlaps or touches.
+func addModification(mods []Modification, m Modification) []Modification {
+	i := sort.Search(len(mods), func(i int) bool { return mods[i].end() >= m.Offset })
+	j := i
+	for ; j < len(mods) && mods[j].Offset <= m.end(); j++ {
+		start := min(mods[j].Offset, m.Offset)
+		m = Modification{start, max(mods[j].end(), m.end()) - start}
+	}
+	return slices.Concat(mods[:i], []Modification{m}, mods[j:])
+}
+
+// mergeModifications merges the runs a deletion brought together.
+func mergeModifications(mods []Modification) []Modification {
+	out := mods[:0]
+	for _, m := range mods {
+		if n := len(out); n > 0 && m.Offset <= out[n-1].end() {
+			out[n-1].Len = max(out[n-1].end(), m.end()) - out[n-1].Offset
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+package buffer
+
+import "sort"
+
+// The buffer contents are stored as a piece table: the original file data
+// is never modified, inserted bytes are appended to an add buffer, and the
+// logical contents are the concatenation of a list of pieces referencing
+// spans of either. Edits split and splice pieces instead of copying data,
+// so their cost depends on the number of pieces rather than the file size.
+
+type source int
+
+const (
+	srcOriginal source = iota
+	srcAdd
+)
+
+// An origin supplies the bytes srcOriginal pieces refer to: the file read
+// into memory, or a device read on demand (see device.go).
+type origin interface {
+	// span returns the n bytes at off. Callers must not modify them.
+	span(off, n int64) []byte
+	// err returns the first error reading the data, if any.
+	err() error
+}
+
+// sameOrigin reports whether a and b are the same data, so that the
+// pieces of one table can be read from the other.
+func sameOrigin(a, b origin) bool {
+	switch a := a.(type) {
+	case memOrigin:
+		b, ok := b.(memOrigin)
+		return ok && len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+	case excerptOrigin:
+		b, ok := b.(excerptOrigin)
+		return ok && a.off == b.off && len(a.data) == len(b.data) && (len(a.data) == 0 || &a.data[0] == &b.data[0])
+	}
+	return a == b
+}
+
+type memOrigin []byte
+
+func (o memOrigin) span(off, n int64) []byte { return o[off : off+n] }
+
+func (o memOrigin) err() error { return nil }
+
+// excerptOrigin holds data at off and reads as zeros everywhere else.
+type excerptOrigin struct {
+	off  int64
+	data []byte
+}
+
+func (o excerptOrigin) span(off, n int64) []byte {
+	if off >= o.off && off+n <= o.off+int64(len(o.data)) {
+		return o.data[off-o.off : off-o.off+n]
+	}
+	span := make([]byte, n)
+	from, to := max(off, o.off), min(off+n, o.off+int64(len(o.data)))
+	if from < to {
+		copy(span[from-off:], o.data[from-o.off:to-o.off])
+	}
+	return span
+}
+
+func (o excerptOrigin) err() error { return nil }
+
+type piece struct {
+	src   source
+	off   int64 // offset into the source
+	len   int64
+	start int64 // logical offset of the piece within the buffer
+}
+
+type pieceTable struct {
+	original origin
+	add      []byte
+	pieces   []piece
+	size     int64
+}
+
+func newPieceTable(original []byte) pieceTable {
+	return newOriginTable(memOrigin(original), int64(len(original)))
+}
+
+func newOriginTable(o origin, size int64) pieceTable {
+	t := pieceTable{original: o}
+	if size > 0 {
+		t.pieces = []piece{{src: srcOriginal, len: size}}
+		t.size = size
+	}
+	return t
+}
+
+// clone returns a table sharing the immutable original and the existing add
+// bytes. Add bytes are never rewritten, only appended; capping the clone's
+// add slice makes its first append reallocate, so the two tables never
+// write into each other's storage.
+func (t *pieceTable) clone() pieceTable {
+	return pieceTable{
+		original: t.original,
+		add:      t.add[:len(t.add):len(t.add)],
+		pieces:   append([]piece(nil), t.pieces...),
+		size:     t.size,
+	}
+}
+
+// err returns the first error reading the original data, if any. Tables
+// of new buffers have no original.
+func (t *pieceTable) err() error {
+	if t.original == nil {
+		return nil
+	}
+	return t.original.err()
+}
+
+// bytes returns n bytes of p starting from its byte from.
+func (t *pieceTable) bytes(p piece, from, n int64) []byte {
+	if p.src == srcOriginal {
+		return t.original.span(p.off+from, n)
+	}
+	return t.add[p.off+from : p.off+from+n]
+}
+
+// chunks calls fn with the contents in order, at most size bytes at a time.
+func (t *pieceTable) chunks(size int64, fn func([]byte) error) error {
+	return t.rangeChunks(0, t.size, size, fn)
+}
+
+// rangeChunks is chunks for the bytes from start up to end.
+func (t *pieceTable) rangeChunks(start, end, size int64, fn func([]byte) error) error {
+	if start >= end {
+		return nil
+	}
+	for i := t.find(start); i < len(t.pieces) && t.pieces[i].start < end; i++ {
+		p := t.pieces[i]
+		to := min(p.len, end-p.start)
+		for from := max(start-p.start, 0); from < to; from += size {
+			if err := fn(t.bytes(p, from, min(size, to-from))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// find returns the index of the piece containing offset.
+func (t *pieceTable) find(offset int64) int {
+	return sort.Search(len(t.pieces), func(i int) bool {
+		return t.pieces[i].start+t.pieces[i].len > offset
+	})
+}
+
+// split ensures a piece boundary at offset and returns the index of the
+// piece starting there (len(pieces) when offset is the end).
+func (t *pieceTable) split(offset int64) int {
+	i := t.find(offset)
+	if i == len(t.pieces) {
+		return i
+	}
+	p := t.pieces[i]
+	if p.start == offset {
+		return i
+	}
+
+	head := p
+	head.len = offset - p.start
+	tail := piece{src: p.src, off: p.off + head.len, len: p.len - head.len, start: offset}
+
+	t.pieces = append(t.pieces, piece{})
+	copy(t.pieces[i+2:], t.pieces[i+1:])
+	t.pieces[i] = head
+	t.pieces[i+1] = tail
+	return i + 1
+}
+
+func (t *pieceTable) reindex(from int) {
+	start := int64(0)
+	if from > 0 {
+		prev := t.pieces[from-1]
+		start = prev.start + prev.len
+	}
+	for i := from; i < len(t.pieces); i++ {
+		t.pieces[i].start = start
+		start += t.pieces[i].len
+	}
+	t.size = start
+}
+
+func (t *pieceTable) insert(offset int64, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	i := t.split(offset)
+
+	p := piece{src: srcAdd, off: int64(len(t.add)), len: int64(len(data))}
+	t.add = append(t.add, data...)
+
+	// Typing appends to the add buffer in order, so extend the previous
+	// piece when it ends exactly where the new data begins.
+	if i > 0 {
+		prev := &t.pieces[i-1]
+		if prev.src == srcAdd && prev.off+prev.len == p.off {
+			prev.len += p.len
+			t.reindex(i)
+			return
+		}
+	}
+
+	t.pieces = append(t.pieces, piece{})
+	copy(t.pieces[i+1:], t.pieces[i:])
+	t.pieces[i] = p
+	t.reindex(i)
+}
+
+func (t *pieceTable) remove(offset, count int64) {
+	if count <= 0 {
+		return
+	}
+	i := t.split(offset)
+	j := t.split(offset + count)
+	t.pieces = append(t.pieces[:i], t.pieces[j:]...)
+	t.reindex(i)
+}
+
+func (t *pieceTable) overwrite(offset int64, data []byte) {
+	t.remove(offset, int64(len(data)))
+	t.insert(offset, data)
+}
+
+func (t *pieceTable) byteAt(offset int64) byte {
+	p := t.pieces[t.find(offset)]
+	return t.bytes(p, offset-p.start, 1)[0]
+}
+
+// readAt copies up to len(dst) bytes starting at offset into dst and
+// returns how many were copied.
+func (t *pieceTable) readAt(dst []byte, offset int64) int {
+	n := 0
+	for i := t.find(offset); i < len(t.pieces) && n < len(dst); i++ {
+		p := t.pieces[i]
+		from := max(offset-p.start, 0)
+		n += copy(dst[n:], t.bytes(p, from, min(p.len-from, int64(len(dst)-n))))
+	}
+	return n
+}
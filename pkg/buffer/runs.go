@@ -0,0 +1,224 @@
+package buffer
+
+import "context"
+
+// A spanRule reports whether byte c belongs to the same span as byte a
+// next to it. A byte the rule doesn't join to itself is in no span.
+type spanRule func(a, c byte) bool
+
+// runOf joins copies of one byte value, or only of value when it is 0-255.
+func runOf(value int) spanRule {
+	return func(a, c byte) bool {
+		return a == c && (value < 0 || int(c) == value)
+	}
+}
+
+// spanOf joins the bytes that satisfy in.
+func spanOf(in func(byte) bool) spanRule {
+	return func(_, c byte) bool { return in(c) }
+}
+
+// FindRunContext finds the nearest maximal run of at least minLen copies of
+// one byte value, or of value itself when value is 0-255, and returns its
+// start and full length. Forward it finds the first run starting after
+// from, so the run the cursor is in is skipped; backward, the last run
+// starting before from. It returns -1, 0 when there is no such run.
+func (b *Buffer) FindRunContext(ctx context.Context, from, minLen int64, value int, forward bool) (int64, int64, error) {
+	return b.findSpan(ctx, from, minLen, runOf(value), forward)
+}
+
+// RunsInContext returns the maximal runs of at least minLen copies of one
+// byte value, or of value itself when value is 0-255, within the bytes
+// from start to end, as if they were the whole buffer: runs are cut at
+// both ends. It gives up and returns ctx.Err() once ctx is cancelled.
+func (b *Buffer) RunsInContext(ctx context.Context, start, end, minLen int64, value int) ([]Range, error) {
+	var runs []Range
+	err := b.forwardSpans(ctx, max(start, 0), min(end, b.table.size), minLen, runOf(value), func(start, n int64) bool {
+		runs = append(runs, Range{start, start + n - 1})
+		return true
+	})
+	return runs, err
+}
+
+// FindSpanContext finds the nearest maximal span of at least minLen bytes
+// that all satisfy in, with the same direction rules as FindRunContext.
+func (b *Buffer) FindSpanContext(ctx context.Context, from, minLen int64, in func(byte) bool, forward bool) (int64, int64, error) {
+	return b.findSpan(ctx, from, minLen, spanOf(in), forward)
+}
+
+// findSpan finds the nearest maximal span of at least minLen bytes joined
+// by join, with the direction rules of FindRunContext.
+func (b *Buffer) findSpan(ctx context.Context, from, minLen int64, join spanRule, forward bool) (int64, int64, error) {
+	size := b.table.size
+	if size == 0 || minLen < 1 {
+		return -1, 0, nil
+	}
+
+	if forward {
+		found, foundLen := int64(-1), int64(0)
+		err := b.forwardSpans(ctx, max(from, 0), size, minLen, join, func(start, n int64) bool {
+			if start <= from {
+				return true
+			}
+			found, foundLen = start, n
+			return false
+		})
+		if err != nil {
+			return -1, 0, err
+		}
+		return found, foundLen, nil
+	}
+
+	chunk := make([]byte, searchChunk)
+	top := min(from, size)
+	hi := top
+	spanEnd, spanLen := hi, int64(0)
+	var last byte // the span's last byte
+	for hi > 0 {
+		if err := ctx.Err(); err != nil {
+			return -1, 0, err
+		}
+		lo := max(hi-searchChunk, 0)
+		n := b.table.readAt(chunk[:hi-lo], lo)
+		for i := n - 1; i >= 0; i-- {
+			c := chunk[i]
+			if spanLen > 0 && join(last, c) {
+				spanLen++
+				continue
+			}
+			if spanLen > 0 {
+				start, length, err := b.finishSpan(ctx, spanEnd, spanLen, last, join, spanEnd == top)
+				if err != nil || length >= minLen {
+					return start, length, err
+				}
+			}
+			spanLen = 0
+			if join(c, c) {
+				spanEnd, last, spanLen = lo+int64(i)+1, c, 1
+			}
+		}
+		hi = lo
+	}
+	if spanLen > 0 {
+		start, length, err := b.finishSpan(ctx, spanEnd, spanLen, last, join, spanEnd == top)
+		if err != nil || length >= minLen {
+			return start, length, err
+		}
+	}
+	return -1, 0, nil
+}
+
+// forwardSpans calls yield with the start and length of each span of at
+// least minLen bytes joined by join within [start, end), in order, until
+// yield returns false.
+func (b *Buffer) forwardSpans(ctx context.Context, start, end, minLen int64, join spanRule, yield func(start, n int64) bool) error {
+	if minLen < 1 {
+		return nil
+	}
+	chunk := make([]byte, searchChunk)
+	pos := start
+	spanStart, spanLen := pos, int64(0)
+	var first byte // the span's first byte
+	for pos < end {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := b.table.readAt(chunk[:min(int64(len(chunk)), end-pos)], pos)
+		for i, c := range chunk[:n] {
+			if spanLen > 0 && join(first, c) {
+				spanLen++
+				continue
+			}
+			if spanLen >= minLen && !yield(spanStart, spanLen) {
+				return nil
+			}
+			spanLen = 0
+			if join(c, c) {
+				spanStart, first, spanLen = pos+int64(i), c, 1
+			}
+		}
+		pos += int64(n)
+	}
+	// A span reaching the end stops there
+	if spanLen >= minLen {
+		yield(spanStart, spanLen)
+	}
+	return nil
+}
+
+// finishSpan completes a span found scanning backward, last being its last
+// byte. The first span seen may continue past where the scan started, so
+// its length is extended forward.
+func (b *Buffer) finishSpan(ctx context.Context, end, n int64, last byte, join spanRule, extend bool) (int64, int64, error) {
+	start := end - n
+	if !extend {
+		return start, n, nil
+	}
+	chunk := make([]byte, searchChunk)
+	for pos := end; pos < b.table.size; {
+		if err := ctx.Err(); err != nil {
+			return -1, 0, err
+		}
+		m := b.table.readAt(chunk, pos)
+		for _, c := range chunk[:m] {
+			if !join(last, c) {
+				return start, pos - start, nil
+			}
+			pos++
+		}
+		if m == 0 {
+			break
+		}
+	}
+	return start, b.table.size - start, nil
+}
+
+// boundaryLookahead is the first read of FindBoundary; motions usually stop
+// within a few rows, so it reads more only when they don't.
+const boundaryLookahead = 256
+
+// FindBoundary finds the nearest offset p after from, or before it going
+// backward, where at(byte p-1, byte p) holds. Offset 0 has no previous
+// byte and never matches. It returns -1 when there is no such offset.
+func (b *Buffer) FindBoundary(ctx context.Context, from int64, at func(prev, cur byte) bool, forward bool) (int64, error) {
+	size := b.table.size
+	chunk := make([]byte, boundaryLookahead)
+
+	if forward {
+		// Each read starts one byte early for the previous byte
+		for pos := max(from, 0); pos+1 < size; {
+			if err := ctx.Err(); err != nil {
+				return -1, err
+			}
+			n := b.table.readAt(chunk, pos)
+			for i := 1; i < n; i++ {
+				if at(chunk[i-1], chunk[i]) {
+					return pos + int64(i), nil
+				}
+			}
+			pos += int64(n) - 1
+			if len(chunk) < searchChunk {
+				chunk = make([]byte, len(chunk)*2)
+			}
+		}
+		return -1, nil
+	}
+
+	for hi := min(from, size); hi > 1; {
+		if err := ctx.Err(); err != nil {
+			return -1, err
+		}
+		lo := max(hi-int64(len(chunk)), 0)
+		n := b.table.readAt(chunk[:hi-lo], lo)
+		for i := n - 1; i >= 1; i-- {
+			if at(chunk[i-1], chunk[i]) {
+				return lo + int64(i), nil
+			}
+		}
+		hi = lo + 1
+		if len(chunk) < searchChunk {
+			chunk = make([]byte, len(chunk)*2)
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,147 @@
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFindRun(t *testing.T) {
+	// 0..9: ABCD, 4..9: six 00, 10: X, 11..14: four FF, 15..: three 00 to EOF
+	data := []byte("ABCD\x00\x00\x00\x00\x00\x00X\xff\xff\xff\xff\x00\x00\x00")
+	b := New()
+	b.Insert(0, data)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		from      int64
+		minLen    int64
+		value     int
+		forward   bool
+		wantStart int64
+		wantLen   int64
+	}{
+		{"any byte forward", -1, 4, -1, true, 4, 6},
+		{"skips the run under the cursor", 4, 4, -1, true, 11, 4},
+		{"specific value", 0, 4, 0xFF, true, 11, 4},
+		{"run at EOF", 11, 3, 0x00, true, 15, 3},
+		{"too short at EOF", 11, 4, 0x00, true, -1, 0},
+		{"backward", 15, 4, -1, false, 11, 4},
+		{"backward from inside a run", 7, 4, -1, false, 4, 6},
+		{"backward to the start", 4, 1, -1, false, 3, 1},
+		{"backward none", 4, 2, -1, false, -1, 0},
+		{"backward from EOF", b.Size(), 3, 0x00, false, 15, 3},
+	}
+	for _, tt := range tests {
+		start, length, err := b.FindRunContext(ctx, tt.from, tt.minLen, tt.value, tt.forward)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if start != tt.wantStart || length != tt.wantLen {
+			t.Errorf("%s: got run at %d len %d, want %d len %d", tt.name, start, length, tt.wantStart, tt.wantLen)
+		}
+	}
+}
+
+func TestRunsIn(t *testing.T) {
+	b := FromData("", []byte("\x00\x00AAAB\x00\x00\x00CC"))
+	runs, err := b.RunsInContext(context.Background(), 1, 10, 2, -1)
+	if want := []Range{{2, 4}, {6, 8}}; err != nil || !slices.Equal(runs, want) {
+		t.Errorf("got %v (%v), want %v", runs, err, want)
+	}
+	runs, _ = b.RunsInContext(context.Background(), 1, 10, 1, 0)
+	if !slices.Equal(runs, []Range{{1, 1}, {6, 8}}) {
+		t.Errorf("zeros cut at both ends: got %v", runs)
+	}
+}
+
+func TestFindRunAcrossChunks(t *testing.T) {
+	data := bytes.Repeat([]byte{0xAB}, 3*searchChunk+17)
+	data[0] = 0x00
+	b := New()
+	b.Insert(0, data)
+
+	start, length, err := b.FindRunContext(context.Background(), 0, searchChunk, -1, true)
+	if err != nil || start != 1 || length != int64(len(data)-1) {
+		t.Errorf("forward: got %d len %d (%v)", start, length, err)
+	}
+	start, length, err = b.FindRunContext(context.Background(), 2*searchChunk, searchChunk, -1, false)
+	if err != nil || start != 1 || length != int64(len(data)-1) {
+		t.Errorf("backward: got %d len %d (%v)", start, length, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := b.FindRunContext(ctx, 0, 2, -1, true); err == nil {
+		t.Error("expected a cancelled scan to fail")
+	}
+}
+
+func TestFindSpan(t *testing.T) {
+	data := []byte("\x00\x01hello\x00hi\x00world!\x00")
+	b := New()
+	b.Insert(0, data)
+	printable := func(c byte) bool { return c >= 0x20 && c < 0x7F }
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		from      int64
+		forward   bool
+		wantStart int64
+		wantLen   int64
+	}{
+		{"first span", -1, true, 2, 5},
+		{"skips short and current spans", 2, true, 11, 6},
+		{"none after the last", 11, true, -1, 0},
+		{"backward skips short spans", 11, false, 2, 5},
+		{"backward from inside a span", 14, false, 11, 6},
+		{"backward none", 2, false, -1, 0},
+	}
+	for _, tt := range tests {
+		start, length, err := b.FindSpanContext(ctx, tt.from, 3, printable, tt.forward)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if start != tt.wantStart || length != tt.wantLen {
+			t.Errorf("%s: got span at %d len %d, want %d len %d", tt.name, start, length, tt.wantStart, tt.wantLen)
+		}
+	}
+}
+
+func TestFindBoundary(t *testing.T) {
+	data := make([]byte, 3000)
+	copy(data[10:], "abc")
+	copy(data[2000:], "text")
+	b := New()
+	b.Insert(0, data)
+	printable := func(c byte) bool { return c >= 0x20 && c < 0x7F }
+	change := func(prev, cur byte) bool { return printable(prev) != printable(cur) }
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		from    int64
+		forward bool
+		want    int64
+	}{
+		{"into text", 0, true, 10},
+		{"out of text", 10, true, 13},
+		{"across chunks", 13, true, 2000},
+		{"none after", 2004, true, -1},
+		{"backward across chunks", 2000, false, 13},
+		{"backward to the run start", 2002, false, 2000},
+		{"backward none", 10, false, -1},
+	}
+	for _, tt := range tests {
+		got, err := b.FindBoundary(ctx, tt.from, change, tt.forward)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
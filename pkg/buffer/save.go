@@ -0,0 +1,147 @@
+package buffer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Saving a file writes the contents to a temporary file next to it and
+// renames that over the file once it is complete, so a failed or
+// cancelled save leaves the file as it was. The contents are streamed
+// piece by piece through a bounded buffer: saving a file of any size
+// takes a few megabytes. A Saver does this a step at a time, for callers
+// that show progress or let the save be cancelled.
+
+// saveStep is how much a Saver writes per Step.
+const saveStep = 8 << 20
+
+// A Saver writes a buffer to its file in steps. The buffer must not be
+// edited until the save is committed or cancelled.
+type Saver struct {
+	b      *Buffer
+	table  pieceTable
+	path   string // the file replaced, with symlinks resolved
+	tmp    *os.File
+	sparse *sparseWriter
+	out    *bufio.Writer
+	hash   hash.Hash
+	done   int64
+}
+
+// NewSaver starts saving b to its file. Windows, devices and files that
+// aren't regular files are written in place by Save instead.
+func (b *Buffer) NewSaver() (*Saver, error) {
+	if b.filename == "" {
+		return nil, fmt.Errorf("no filename set")
+	}
+	if b.readOnly {
+		return nil, ErrReadOnly
+	}
+	if b.window != nil || b.device {
+		return nil, fmt.Errorf("%s is saved in place", b.filename)
+	}
+
+	path := b.filename
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is saved in place", b.filename)
+		}
+		mode = info.Mode().Perm()
+		// Renaming would replace a file we may not write to
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	sparse := newSparseWriter(f)
+	return &Saver{
+		b:      b,
+		table:  b.table.clone(),
+		path:   path,
+		tmp:    f,
+		sparse: sparse,
+		out:    bufio.NewWriterSize(sparse, 64*1024),
+		hash:   sha256.New(),
+	}, nil
+}
+
+// Progress returns how many of the bytes to save are written.
+func (s *Saver) Progress() (written, total int64) {
+	return s.done, s.table.size
+}
+
+// Step writes the next part of the contents and reports whether all of
+// them are written. After an error the save is cancelled.
+func (s *Saver) Step() (bool, error) {
+	end := min(s.done+saveStep, s.table.size)
+	w := io.MultiWriter(s.out, s.hash)
+	err := s.table.rangeChunks(s.done, end, hashChunk, func(data []byte) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err == nil {
+		err = s.table.err()
+	}
+	if err != nil {
+		s.Cancel()
+		return false, err
+	}
+	s.done = end
+	return s.done == s.table.size, nil
+}
+
+// Commit writes whatever is left and replaces the buffer's file with the
+// temporary one.
+func (s *Saver) Commit() error {
+	for s.done < s.table.size {
+		if _, err := s.Step(); err != nil {
+			return err
+		}
+	}
+	err := s.out.Flush()
+	if err == nil {
+		err = s.sparse.finish()
+	}
+	if err == nil {
+		err = s.tmp.Sync()
+	}
+	if cerr := s.tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(s.tmp.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(s.tmp.Name())
+		return err
+	}
+	s.b.markSaved(hex.EncodeToString(s.hash.Sum(nil)))
+	return nil
+}
+
+// Cancel stops the save and removes the temporary file, leaving the
+// buffer's file untouched.
+func (s *Saver) Cancel() {
+	s.tmp.Close()
+	os.Remove(s.tmp.Name())
+}
@@ -0,0 +1,140 @@
+package buffer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSaveKeepsModeAndSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.bin")
+	if err := os.WriteFile(path, []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.bin")
+	if err := os.Symlink("data.bin", link); err != nil {
+		t.Skip("no symlinks:", err)
+	}
+
+	b, err := Open(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Replace(0, 'j')
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("the link was replaced: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected mode 0600 kept, got %v (%v)", info.Mode(), err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "jello" {
+		t.Errorf("got %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("temporary file left behind: %v", entries)
+	}
+}
+
+func TestCancelledSaveLeavesTheFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.bin")
+	data := make([]byte, 3*saveStep)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Replace(0, 0xFF)
+
+	s, err := b.NewSaver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done, err := s.Step(); done || err != nil {
+		t.Fatalf("expected more steps, got %v, %v", done, err)
+	}
+	if written, total := s.Progress(); written != saveStep || total != int64(len(data)) {
+		t.Errorf("got %d of %d", written, total)
+	}
+	s.Cancel()
+
+	if got, _ := os.ReadFile(path); len(got) != len(data) || got[0] != 0 {
+		t.Error("a cancelled save changed the file")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary file left behind: %v", entries)
+	}
+	if !b.IsModified() {
+		t.Error("the buffer should still be modified")
+	}
+}
+
+// saveSparse saves a copy of a size-byte sparse file with a few edits,
+// read on demand, and returns how much memory the process took on meanwhile.
+func saveSparse(tb testing.TB, size int64) uint64 {
+	dir := tb.TempDir()
+	path := filepath.Join(dir, "disk.img")
+	f, err := os.Create(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		tb.Fatal(err)
+	}
+
+	b, err := OpenDevice(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	b.SetReadOnly(false)
+	for _, off := range []int64{0, size / 3, size - 1} {
+		b.Replace(off, 0xEE)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	out := filepath.Join(dir, "copy.img")
+	if err := b.SaveAs(out); err != nil {
+		tb.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+
+	saved, err := os.Open(out)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer saved.Close()
+	got := make([]byte, 1)
+	for _, off := range []int64{0, size / 3, size - 1} {
+		if _, err := saved.ReadAt(got, off); err != nil || got[0] != 0xEE {
+			tb.Errorf("byte at 0x%X: %02X, %v", off, got[0], err)
+		}
+	}
+	return after.Sys - before.Sys
+}
+
+func TestSaveStreamsLargeFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes 512 MB")
+	}
+	if grew := saveSparse(t, 512<<20); grew > 64<<20 {
+		t.Errorf("saving 512 MB took %d MB of memory", grew>>20)
+	}
+}
+
+func BenchmarkSaveSparse8GB(b *testing.B) {
+	for range b.N {
+		b.ReportMetric(float64(saveSparse(b, 8<<30)>>20), "MB-grown/op")
+	}
+}
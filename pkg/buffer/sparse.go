@@ -0,0 +1,80 @@
+package buffer
+
+import (
+	"bytes"
+	"os"
+)
+
+// Disk images are often sparse: mostly holes that read as zeros and take
+// no space. Saves keep them sparse by seeking over every aligned block of
+// zeros instead of writing it, which leaves a hole on filesystems that
+// support them; elsewhere the skipped blocks read back as zeros all the
+// same.
+
+const sparseBlock = 4096
+
+var zeroBlock [sparseBlock]byte
+
+// sparseWriter writes to a new, empty file, skipping zero blocks. Other
+// files, such as pipes, are written plainly.
+type sparseWriter struct {
+	f       *os.File
+	plain   bool
+	off     int64 // bytes written or skipped
+	written int64 // end of the last bytes actually written
+}
+
+func newSparseWriter(f *os.File) *sparseWriter {
+	info, err := f.Stat()
+	return &sparseWriter{f: f, plain: err != nil || !info.Mode().IsRegular()}
+}
+
+func (w *sparseWriter) Write(p []byte) (int, error) {
+	if w.plain {
+		return w.f.Write(p)
+	}
+	start := 0 // of the bytes not yet written
+	for i := 0; i < len(p); {
+		n := min(len(p)-i, sparseBlock-int((w.off+int64(i))%sparseBlock))
+		if n == sparseBlock && bytes.Equal(p[i:i+n], zeroBlock[:]) {
+			if err := w.writeAt(p[start:i], w.off+int64(start)); err != nil {
+				return start, err
+			}
+			start = i + n
+		}
+		i += n
+	}
+	if err := w.writeAt(p[start:], w.off+int64(start)); err != nil {
+		return start, err
+	}
+	w.off += int64(len(p))
+	return len(p), nil
+}
+
+func (w *sparseWriter) writeAt(p []byte, off int64) error {
+	if len(p) == 0 {
+		return nil
+	}
+	if _, err := w.f.WriteAt(p, off); err != nil {
+		return err
+	}
+	w.written = off + int64(len(p))
+	return nil
+}
+
+// finish sizes the file to cover a hole at its end, writing the zeros out
+// where the file can't be extended without them.
+func (w *sparseWriter) finish() error {
+	if w.plain || w.written == w.off {
+		return nil
+	}
+	if err := w.f.Truncate(w.off); err == nil {
+		return nil
+	}
+	for w.written < w.off {
+		if err := w.writeAt(zeroBlock[:min(sparseBlock, w.off-w.written)], w.written); err != nil {
+			return err
+		}
+	}
+	return nil
+}
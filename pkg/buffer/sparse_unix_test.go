@@ -0,0 +1,75 @@
+//go:build unix
+
+package buffer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// allocated returns the bytes of disk path takes up.
+func allocated(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Sys().(*syscall.Stat_t).Blocks * 512
+}
+
+func TestSaveKeepsHoles(t *testing.T) {
+	const size = 64 << 20
+	path := filepath.Join(t.TempDir(), "disk.img")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteAt([]byte("boot"), 0)
+	f.WriteAt([]byte("tail"), size-4)
+	f.Close()
+	if allocated(t, path) >= size/2 {
+		t.Skip("the filesystem doesn't keep holes")
+	}
+
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Replace(size/2, 0xFF)
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := allocated(t, path); got > 1<<20 {
+		t.Errorf("saving allocated %d bytes for a sparse file", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != size || !bytes.Equal(data[:4], []byte("boot")) || data[size/2] != 0xFF || !bytes.Equal(data[size-4:], []byte("tail")) {
+		t.Error("saved contents differ")
+	}
+}
+
+func TestSaveEndingInAHole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zeros.bin")
+	data := make([]byte, 3*sparseBlock)
+	data[5] = 1
+	b := FromData(path, data)
+	b.Replace(6, 2)
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[6] = 2
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %d bytes, want %d", len(got), len(data))
+	}
+}
@@ -0,0 +1,100 @@
+package buffer
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// A Window is the part of a file a buffer was opened on: Length bytes from
+// Offset. A windowed buffer can't change size, and saving writes its bytes
+// back over the same region instead of rewriting the file.
+type Window struct {
+	Offset int64
+	Length int64
+}
+
+// ErrWindowSize is returned when saving a windowed buffer whose size no
+// longer matches its window.
+var ErrWindowSize = errors.New("buffer size no longer matches its window")
+
+// OpenWindow reads only w of filename.
+func OpenWindow(filename string, w Window) (*Buffer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Seeking works for devices too, whose Stat size is 0
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if w.Offset < 0 || w.Length <= 0 || w.Offset+w.Length > size {
+		return nil, fmt.Errorf("window 0x%X+0x%X is outside the file (0x%X bytes)", w.Offset, w.Length, size)
+	}
+
+	data := make([]byte, w.Length)
+	if _, err := f.ReadAt(data, w.Offset); err != nil {
+		return nil, err
+	}
+	b := FromData(filename, data)
+	b.window = &w
+	return b, nil
+}
+
+// Window returns the region of the file the buffer holds, if it was opened
+// with OpenWindow.
+func (b *Buffer) Window() (Window, bool) {
+	if b.window == nil {
+		return Window{}, false
+	}
+	return *b.window, true
+}
+
+func (b *Buffer) saveWindow() error {
+	w := *b.window
+	if b.table.size != w.Length {
+		return fmt.Errorf("%w (0x%X bytes, window 0x%X)", ErrWindowSize, b.table.size, w.Length)
+	}
+
+	f, err := os.OpenFile(b.filename, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(io.NewOffsetWriter(f, w.Offset))
+	h := sha256.New()
+	if err := b.writeTo(out, h); err != nil {
+		f.Close()
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	b.markSaved(hex.EncodeToString(h.Sum(nil)))
+	return nil
+}
+
+// DiskHashContext returns the SHA-256 of what the buffer was loaded from:
+// the whole file, or just its window.
+func (b *Buffer) DiskHashContext(ctx context.Context) (string, error) {
+	if b.window == nil {
+		return HashFileContext(ctx, b.filename)
+	}
+	f, err := os.Open(b.filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hashReader(ctx, io.NewSectionReader(f, b.window.Offset, b.window.Length))
+}
@@ -0,0 +1,80 @@
+package buffer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenWindowSavesInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, []byte("0123456789abcdef"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenWindow(path, Window{Offset: 12, Length: 8}); err == nil {
+		t.Error("a window past the end of the file should fail")
+	}
+
+	b, err := OpenWindow(path, Window{Offset: 4, Length: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Data(); !bytes.Equal(got, []byte("456789")) {
+		t.Fatalf("window holds %q", got)
+	}
+	if changed, err := b.HasChangedOnDisk(); err != nil || changed {
+		t.Fatalf("fresh window reported changed on disk: %v, %v", changed, err)
+	}
+
+	b.ReplaceBytes(1, []byte("XY"))
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	if !bytes.Equal(got, []byte("01234XY789abcdef")) {
+		t.Errorf("file after save is %q", got)
+	}
+	if changed, err := b.HasChangedOnDisk(); err != nil || changed {
+		t.Errorf("saved window reported changed on disk: %v, %v", changed, err)
+	}
+
+	// Edits outside the window don't count as changes to it
+	if err := os.WriteFile(path, []byte("Z1234XY789abcdef"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := b.HasChangedOnDisk(); changed {
+		t.Error("a change outside the window was reported")
+	}
+
+	if err := b.Insert(0, []byte{0}); !errors.Is(err, ErrFixedSize) {
+		t.Errorf("inserting into a window should fail with ErrFixedSize, got %v", err)
+	}
+	if err := b.Delete(0, 1); !errors.Is(err, ErrFixedSize) || b.Size() != 6 {
+		t.Errorf("deleting from a window should fail with ErrFixedSize, got %v", err)
+	}
+}
+
+func TestSaveAsDropsWindow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "disk.img")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenWindow(path, Window{Offset: 2, Length: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "part.bin")
+	if err := b.SaveAs(out); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Window(); ok {
+		t.Error("the saved copy should be a whole file")
+	}
+	if got, _ := os.ReadFile(out); !bytes.Equal(got, []byte("234")) {
+		t.Errorf("saved %q", got)
+	}
+}